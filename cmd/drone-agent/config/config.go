@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/kelseyhightower/envconfig"
@@ -41,6 +42,7 @@ type (
 		RPC        RPC
 		Server     Server
 		Secrets    Secrets
+		Tracing    Tracing
 	}
 
 	// Docker provides docker configuration
@@ -48,6 +50,13 @@ type (
 		Config string `envconfig:"DRONE_DOCKER_CONFIG"`
 	}
 
+	// Tracing provides the tracing configuration.
+	Tracing struct {
+		Endpoint string            `envconfig:"DRONE_TRACING_ENDPOINT"`
+		Service  string            `envconfig:"DRONE_TRACING_SERVICE" default:"drone-agent"`
+		Headers  map[string]string `envconfig:"DRONE_TRACING_HEADERS"`
+	}
+
 	// Logging provides the logging configuration.
 	Logging struct {
 		Debug  bool `envconfig:"DRONE_LOGS_DEBUG"`
@@ -83,6 +92,8 @@ type (
 
 	// Runner provides the runner configuration.
 	Runner struct {
+		Kind       string            `envconfig:"DRONE_RUNNER_KIND" default:"pipeline"`
+		Type       string            `envconfig:"DRONE_RUNNER_TYPE" default:"docker"`
 		Platform   string            `envconfig:"DRONE_RUNNER_PLATFORM" default:"linux/amd64"`
 		OS         string            `envconfig:"DRONE_RUNNER_OS"`
 		Arch       string            `envconfig:"DRONE_RUNNER_ARCH"`
@@ -96,6 +107,7 @@ type (
 		Devices    []string          `envconfig:"DRONE_RUNNER_DEVICES"`
 		Privileged []string          `envconfig:"DRONE_RUNNER_PRIVILEGED_IMAGES"`
 		Environ    map[string]string `envconfig:"DRONE_RUNNER_ENVIRON"`
+		Drain      time.Duration     `envconfig:"DRONE_RUNNER_DRAIN_TIMEOUT"`
 		Limits     struct {
 			MemSwapLimit Bytes  `envconfig:"DRONE_LIMIT_MEM_SWAP"`
 			MemLimit     Bytes  `envconfig:"DRONE_LIMIT_MEM"`
@@ -103,6 +115,11 @@ type (
 			CPUQuota     int64  `envconfig:"DRONE_LIMIT_CPU_QUOTA"`
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
+
+			LogStepSize   Bytes `envconfig:"DRONE_LIMIT_LOG_STEP_SIZE"`
+			LogStepLines  int   `envconfig:"DRONE_LIMIT_LOG_STEP_LINES"`
+			LogStageSize  Bytes `envconfig:"DRONE_LIMIT_LOG_STAGE_SIZE"`
+			LogStageLines int   `envconfig:"DRONE_LIMIT_LOG_STAGE_LINES"`
 		}
 	}
 
@@ -0,0 +1,251 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/drone/drone-runtime/engine/docker"
+	"github.com/drone/drone/cmd/drone-agent/config"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager/local"
+	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/plugin/registry"
+	"github.com/drone/drone/plugin/secret"
+	"github.com/drone/signal"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+)
+
+const execUsage = `Usage: drone-agent exec [options]
+
+Executes a pipeline from the local configuration file, using
+the current working directory as the pipeline workspace.
+
+Options:
+`
+
+// execute executes a pipeline from a local configuration file
+// and returns the process exit code.
+func execute(args []string) int {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), execUsage)
+		flags.PrintDefaults()
+	}
+
+	var (
+		file     = flags.String("file", ".drone.yml", "path to the configuration file")
+		name     = flags.String("pipeline", "", "name of the pipeline to execute")
+		envfile  = flags.String("secret-file", "", "path to a secret file in dotenv format")
+		branch   = flags.String("branch", "master", "build branch")
+		event    = flags.String("event", core.EventPush, "build event")
+		trusted  = flags.Bool("trusted", false, "build is trusted")
+		timeout  = flags.Duration("timeout", time.Hour, "build timeout")
+		machine  = flags.String("netrc-machine", "", "netrc machine")
+		username = flags.String("netrc-username", "", "netrc username")
+		password = flags.String("netrc-password", "", "netrc password")
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config, err := config.Environ()
+	if err != nil {
+		logrus.WithError(err).Errorln("invalid configuration")
+		return 1
+	}
+
+	initLogging(config)
+	ctx := signal.WithContext(
+		context.Background(),
+	)
+
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		logrus.WithError(err).Errorln("cannot read the configuration file")
+		return 1
+	}
+
+	stageName, err := local.Lookup(data, *name)
+	if err != nil {
+		logrus.WithError(err).Errorln("cannot find the pipeline")
+		return 1
+	}
+
+	var localSecrets []*core.Secret
+	if *envfile != "" {
+		env, err := godotenv.Read(*envfile)
+		if err != nil {
+			logrus.WithError(err).Errorln("cannot read the secret file")
+			return 1
+		}
+		for k, v := range env {
+			localSecrets = append(localSecrets, &core.Secret{
+				Name:        k,
+				Data:        v,
+				PullRequest: true,
+			})
+		}
+	}
+
+	var netrc *core.Netrc
+	if *machine != "" {
+		netrc = &core.Netrc{
+			Machine:  *machine,
+			Login:    *username,
+			Password: *password,
+		}
+	}
+
+	workspace, err := os.Getwd()
+	if err != nil {
+		logrus.WithError(err).Errorln("cannot get the working directory")
+		return 1
+	}
+
+	now := time.Now().Unix()
+	manager := &local.Manager{
+		Repo: &core.Repository{
+			ID:         1,
+			Namespace:  "local",
+			Name:       filepath.Base(workspace),
+			Slug:       "local/" + filepath.Base(workspace),
+			Branch:     *branch,
+			Config:     filepath.Base(*file),
+			Trusted:    *trusted,
+			Timeout:    int64(*timeout / time.Minute),
+			Visibility: core.VisibilityPrivate,
+		},
+		Build: &core.Build{
+			ID:      1,
+			RepoID:  1,
+			Number:  1,
+			Status:  core.StatusRunning,
+			Event:   *event,
+			Ref:     "refs/heads/" + *branch,
+			Source:  *branch,
+			Target:  *branch,
+			Created: now,
+			Started: now,
+		},
+		Stage: &core.Stage{
+			ID:        1,
+			RepoID:    1,
+			BuildID:   1,
+			Number:    1,
+			Name:      stageName,
+			Kind:      config.Runner.Kind,
+			Type:      config.Runner.Type,
+			Status:    core.StatusPending,
+			OS:        config.Runner.OS,
+			Arch:      config.Runner.Arch,
+			Variant:   config.Runner.Variant,
+			Kernel:    config.Runner.Kernel,
+			Machine:   config.Runner.Machine,
+			OnSuccess: true,
+			Created:   now,
+			Updated:   now,
+			Version:   1,
+		},
+		Config: &core.File{
+			Data: data,
+		},
+		Secrets: localSecrets,
+		System: &core.System{
+			Proto: "http",
+			Host:  "localhost",
+			Link:  "http://localhost",
+		},
+		Output:      os.Stdout,
+		Credentials: netrc,
+	}
+
+	secrets := secret.External(
+		config.Secrets.Endpoint,
+		config.Secrets.Password,
+		config.Secrets.SkipVerify,
+	)
+
+	auths := registry.Combine(
+		registry.FileSource(
+			config.Docker.Config,
+		),
+		registry.EndpointSource(
+			config.Registries.Endpoint,
+			config.Registries.Password,
+			config.Registries.SkipVerify,
+		),
+	)
+
+	engine, err := docker.NewEnv()
+	if err != nil {
+		logrus.WithError(err).
+			Errorln("cannot load the docker engine")
+		return 1
+	}
+	if err := docker.Ping(ctx, engine); err != nil {
+		logrus.WithError(err).
+			Errorln("cannot ping the docker daemon")
+		return 1
+	}
+
+	r := &runner.Runner{
+		Kind:       config.Runner.Kind,
+		Type:       config.Runner.Type,
+		Platform:   config.Runner.Platform,
+		OS:         config.Runner.OS,
+		Arch:       config.Runner.Arch,
+		Kernel:     config.Runner.Kernel,
+		Variant:    config.Runner.Variant,
+		Engine:     engine,
+		Manager:    manager,
+		Registry:   auths,
+		Secrets:    secrets,
+		Volumes:    config.Runner.Volumes,
+		Networks:   config.Runner.Networks,
+		Devices:    config.Runner.Devices,
+		Privileged: config.Runner.Privileged,
+		Machine:    config.Runner.Machine,
+		Labels:     config.Runner.Labels,
+		Environ:    config.Runner.Environ,
+		Workspace:  workspace,
+		LogLimits: core.LogLimits{
+			StepBytes:  int64(config.Runner.Limits.LogStepSize),
+			StepLines:  config.Runner.Limits.LogStepLines,
+			StageBytes: int64(config.Runner.Limits.LogStageSize),
+			StageLines: config.Runner.Limits.LogStageLines,
+		},
+		Limits: runner.Limits{
+			MemSwapLimit: int64(config.Runner.Limits.MemSwapLimit),
+			MemLimit:     int64(config.Runner.Limits.MemLimit),
+			ShmSize:      int64(config.Runner.Limits.ShmSize),
+			CPUQuota:     config.Runner.Limits.CPUQuota,
+			CPUShares:    config.Runner.Limits.CPUShares,
+			CPUSet:       config.Runner.Limits.CPUSet,
+		},
+	}
+	if err := r.Run(ctx, manager.Stage.ID); err != nil {
+		logrus.WithError(err).
+			Errorln("cannot execute the pipeline")
+		return 1
+	}
+
+	status := manager.Status()
+	fmt.Fprintf(os.Stdout, "pipeline %s: %s\n", stageName, status)
+	if status != core.StatusPassing {
+		return 1
+	}
+	return 0
+}
@@ -8,14 +8,17 @@ package main
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/drone/drone-runtime/engine/docker"
 	"github.com/drone/drone/cmd/drone-agent/config"
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/operator/manager/rpc"
 	"github.com/drone/drone/operator/runner"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
+	"github.com/drone/drone/trace"
 	"github.com/drone/signal"
 
 	"github.com/sirupsen/logrus"
@@ -24,12 +27,17 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "exec" {
+		os.Exit(execute(os.Args[2:]))
+	}
+
 	config, err := config.Environ()
 	if err != nil {
 		logrus.WithError(err).Fatalln("invalid configuration")
 	}
 
 	initLogging(config)
+	initTracing(config)
 	ctx := signal.WithContext(
 		context.Background(),
 	)
@@ -87,7 +95,21 @@ func main() {
 		}
 	}
 
+	sampler, err := runner.NewDockerSampler()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot sample container resource usage")
+	}
+
+	copier, err := runner.NewDockerCopier()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot copy files from containers")
+	}
+
 	r := &runner.Runner{
+		Kind:       config.Runner.Kind,
+		Type:       config.Runner.Type,
 		Platform:   config.Runner.Platform,
 		OS:         config.Runner.OS,
 		Arch:       config.Runner.Arch,
@@ -104,6 +126,16 @@ func main() {
 		Machine:    config.Runner.Machine,
 		Labels:     config.Runner.Labels,
 		Environ:    config.Runner.Environ,
+		Sampler:    sampler,
+		Copier:     copier,
+
+		DrainTimeout: config.Runner.Drain,
+		LogLimits: core.LogLimits{
+			StepBytes:  int64(config.Runner.Limits.LogStepSize),
+			StepLines:  config.Runner.Limits.LogStepLines,
+			StageBytes: int64(config.Runner.Limits.LogStageSize),
+			StageLines: config.Runner.Limits.LogStageLines,
+		},
 		Limits: runner.Limits{
 			MemSwapLimit: int64(config.Runner.Limits.MemSwapLimit),
 			MemLimit:     int64(config.Runner.Limits.MemLimit),
@@ -138,3 +170,14 @@ func initLogging(c config.Config) {
 		})
 	}
 }
+
+// helper function configures the tracing exporter. Tracing
+// is disabled unless the collector endpoint is configured.
+func initTracing(c config.Config) {
+	if c.Tracing.Endpoint == "" {
+		return
+	}
+	trace.SetExporter(
+		trace.OTLP(c.Tracing.Endpoint, c.Tracing.Service, c.Tracing.Headers),
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/kelseyhightower/envconfig"
@@ -95,6 +96,7 @@ type (
 		Devices    []string          `envconfig:"DRONE_RUNNER_DEVICES"`
 		Privileged []string          `envconfig:"DRONE_RUNNER_PRIVILEGED_IMAGES"`
 		Environ    map[string]string `envconfig:"DRONE_RUNNER_ENVIRON"`
+		Drain      time.Duration     `envconfig:"DRONE_RUNNER_DRAIN_TIMEOUT"`
 		Limits     struct {
 			MemSwapLimit Bytes  `envconfig:"DRONE_LIMIT_MEM_SWAP"`
 			MemLimit     Bytes  `envconfig:"DRONE_LIMIT_MEM"`
@@ -102,6 +104,11 @@ type (
 			CPUQuota     int64  `envconfig:"DRONE_LIMIT_CPU_QUOTA"`
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
+
+			LogStepSize   Bytes `envconfig:"DRONE_LIMIT_LOG_STEP_SIZE"`
+			LogStepLines  int   `envconfig:"DRONE_LIMIT_LOG_STEP_LINES"`
+			LogStageSize  Bytes `envconfig:"DRONE_LIMIT_LOG_STAGE_SIZE"`
+			LogStageLines int   `envconfig:"DRONE_LIMIT_LOG_STAGE_LINES"`
 		}
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/drone/drone-runtime/engine/docker"
 	"github.com/drone/drone-runtime/engine/kube"
 	"github.com/drone/drone/cmd/drone-controller/config"
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/operator/manager/rpc"
 	"github.com/drone/drone/operator/runner"
 	"github.com/drone/drone/plugin/registry"
@@ -71,6 +72,8 @@ func main() {
 	}
 
 	var engine engine.Engine
+	var sampler runner.Sampler
+	var copier runner.Copier
 
 	if isKubernetes() {
 		engine, err = kube.NewFile("", "", config.Runner.Machine)
@@ -84,6 +87,16 @@ func main() {
 			logrus.WithError(err).
 				Fatalln("cannot load the docker engine")
 		}
+		sampler, err = runner.NewDockerSampler()
+		if err != nil {
+			logrus.WithError(err).
+				Warnln("cannot sample container resource usage")
+		}
+		copier, err = runner.NewDockerCopier()
+		if err != nil {
+			logrus.WithError(err).
+				Warnln("cannot copy files from containers")
+		}
 	}
 
 	r := &runner.Runner{
@@ -103,6 +116,16 @@ func main() {
 		Machine:    config.Runner.Machine,
 		Labels:     config.Runner.Labels,
 		Environ:    config.Runner.Environ,
+		Sampler:    sampler,
+		Copier:     copier,
+
+		DrainTimeout: config.Runner.Drain,
+		LogLimits: core.LogLimits{
+			StepBytes:  int64(config.Runner.Limits.LogStepSize),
+			StepLines:  config.Runner.Limits.LogStepLines,
+			StageBytes: int64(config.Runner.Limits.LogStageSize),
+			StageLines: config.Runner.Limits.LogStageLines,
+		},
 		Limits: runner.Limits{
 			MemSwapLimit: int64(config.Runner.Limits.MemSwapLimit),
 			MemLimit:     int64(config.Runner.Limits.MemLimit),
@@ -48,14 +48,18 @@ type (
 
 		Authn        Authentication
 		Agent        Agent
+		Artifacts    Artifacts
+		Cache        Cache
 		Cron         Cron
 		Cloning      Cloning
+		Comments     Comments
 		Database     Database
 		Datadog      Datadog
 		Docker       Docker
 		HTTP         HTTP
 		Jsonnet      Jsonnet
 		Logging      Logging
+		Metrics      Metrics
 		Prometheus   Prometheus
 		Proxy        Proxy
 		Registration Registration
@@ -69,7 +73,9 @@ type (
 		Secrets      Secrets
 		Server       Server
 		Session      Session
+		SMTP         SMTP
 		Status       Status
+		Tracing      Tracing
 		Users        Users
 		Webhook      Webhook
 		Yaml         Yaml
@@ -92,6 +98,32 @@ type (
 		Pull       string `envconfig:"DRONE_GIT_IMAGE_PULL" default:"IfNotExists"`
 	}
 
+	// Artifacts provides the artifact storage configuration.
+	// The S3 endpoint and path style are shared with the log
+	// storage configuration.
+	Artifacts struct {
+		Path   string `envconfig:"DRONE_ARTIFACTS_PATH"`
+		Bucket string `envconfig:"DRONE_ARTIFACTS_S3_BUCKET"`
+		Prefix string `envconfig:"DRONE_ARTIFACTS_S3_PREFIX"`
+	}
+
+	// Cache provides the build cache storage configuration.
+	// The quota is the maximum combined size, in bytes, of the
+	// caches stored for a single repository.
+	Cache struct {
+		Path   string `envconfig:"DRONE_CACHE_PATH" default:"cache"`
+		Bucket string `envconfig:"DRONE_CACHE_S3_BUCKET"`
+		Prefix string `envconfig:"DRONE_CACHE_S3_PREFIX"`
+		Quota  int64  `envconfig:"DRONE_CACHE_QUOTA" default:"5368709120"`
+	}
+
+	// Comments provides the pull request comment configuration.
+	Comments struct {
+		Enabled     bool `envconfig:"DRONE_COMMENTS_ENABLED"`
+		FailureOnly bool `envconfig:"DRONE_COMMENTS_FAILURE_ONLY"`
+		Lines       int  `envconfig:"DRONE_COMMENTS_LOG_LINES" default:"10"`
+	}
+
 	// Cron provides the cron configuration.
 	Cron struct {
 		Disabled bool          `envconfig:"DRONE_CRON_DISABLED"`
@@ -117,6 +149,16 @@ type (
 		Token    string `envconfig:"DRONE_DATADOG_TOKEN"`
 	}
 
+	// Metrics provides the metric sink configuration.
+	Metrics struct {
+		Interval    time.Duration     `envconfig:"DRONE_METRICS_INTERVAL" default:"1m"`
+		Prefix      string            `envconfig:"DRONE_METRICS_PREFIX" default:"drone"`
+		StatsD      string            `envconfig:"DRONE_METRICS_STATSD_ADDRESS"`
+		OTLP        string            `envconfig:"DRONE_METRICS_OTLP_ENDPOINT"`
+		RemoteWrite string            `envconfig:"DRONE_METRICS_REMOTE_WRITE_URL"`
+		Headers     map[string]string `envconfig:"DRONE_METRICS_HEADERS"`
+	}
+
 	// Jsonnet configures the jsonnet plugin
 	Jsonnet struct {
 		Enabled bool `envconfig:"DRONE_JSONNET_ENABLED"`
@@ -164,7 +206,9 @@ type (
 
 	// Prometheus provides the prometheus configuration.
 	Prometheus struct {
-		EnableAnonymousAccess bool `envconfig:"DRONE_PROMETHEUS_ANONYMOUS_ACCESS" default:"false"`
+		EnableAnonymousAccess bool     `envconfig:"DRONE_PROMETHEUS_ANONYMOUS_ACCESS" default:"false"`
+		Repos                 []string `envconfig:"DRONE_PROMETHEUS_REPOS"`
+		LabelLimit            int      `envconfig:"DRONE_PROMETHEUS_LABEL_LIMIT" default:"100"`
 	}
 
 	// Repository provides the repository configuration.
@@ -217,6 +261,7 @@ type (
 		Devices    []string          `envconfig:"DRONE_RUNNER_DEVICES"`
 		Privileged []string          `envconfig:"DRONE_RUNNER_PRIVILEGED_IMAGES"`
 		Environ    map[string]string `envconfig:"DRONE_RUNNER_ENVIRON"`
+		Drain      time.Duration     `envconfig:"DRONE_RUNNER_DRAIN_TIMEOUT"`
 		Limits     struct {
 			MemSwapLimit Bytes  `envconfig:"DRONE_LIMIT_MEM_SWAP"`
 			MemLimit     Bytes  `envconfig:"DRONE_LIMIT_MEM"`
@@ -224,6 +269,11 @@ type (
 			CPUQuota     int64  `envconfig:"DRONE_LIMIT_CPU_QUOTA"`
 			CPUShares    int64  `envconfig:"DRONE_LIMIT_CPU_SHARES"`
 			CPUSet       string `envconfig:"DRONE_LIMIT_CPU_SET"`
+
+			LogStepSize   Bytes `envconfig:"DRONE_LIMIT_LOG_STEP_SIZE"`
+			LogStepLines  int   `envconfig:"DRONE_LIMIT_LOG_STEP_LINES"`
+			LogStageSize  Bytes `envconfig:"DRONE_LIMIT_LOG_STAGE_SIZE"`
+			LogStageLines int   `envconfig:"DRONE_LIMIT_LOG_STAGE_LINES"`
 		}
 	}
 
@@ -264,10 +314,29 @@ type (
 		Secure  bool          `envconfig:"DRONE_COOKIE_SECURE"`
 	}
 
+	// SMTP provides the smtp server configuration used to
+	// send email notifications.
+	SMTP struct {
+		Host       string `envconfig:"DRONE_SMTP_HOST"`
+		Port       int    `envconfig:"DRONE_SMTP_PORT" default:"587"`
+		Username   string `envconfig:"DRONE_SMTP_USERNAME"`
+		Password   string `envconfig:"DRONE_SMTP_PASSWORD"`
+		From       string `envconfig:"DRONE_SMTP_FROM"`
+		SkipVerify bool   `envconfig:"DRONE_SMTP_SKIP_VERIFY"`
+	}
+
 	// Status provides status configurations.
 	Status struct {
 		Disabled bool   `envconfig:"DRONE_STATUS_DISABLED"`
 		Name     string `envconfig:"DRONE_STATUS_NAME"`
+		Stages   bool   `envconfig:"DRONE_STATUS_STAGES"`
+	}
+
+	// Tracing provides the tracing configuration.
+	Tracing struct {
+		Endpoint string            `envconfig:"DRONE_TRACING_ENDPOINT"`
+		Service  string            `envconfig:"DRONE_TRACING_SERVICE" default:"drone-server"`
+		Headers  map[string]string `envconfig:"DRONE_TRACING_HEADERS"`
 	}
 
 	// Users provides the user configuration.
@@ -279,9 +348,14 @@ type (
 
 	// Webhook provides the webhook configuration.
 	Webhook struct {
-		Endpoint   []string `envconfig:"DRONE_WEBHOOK_ENDPOINT"`
-		Secret     string   `envconfig:"DRONE_WEBHOOK_SECRET"`
-		SkipVerify bool     `envconfig:"DRONE_WEBHOOK_SKIP_VERIFY"`
+		Endpoint   []string      `envconfig:"DRONE_WEBHOOK_ENDPOINT"`
+		Events     []string      `envconfig:"DRONE_WEBHOOK_EVENTS"`
+		Secret     string        `envconfig:"DRONE_WEBHOOK_SECRET"`
+		SkipVerify bool          `envconfig:"DRONE_WEBHOOK_SKIP_VERIFY"`
+		Attempts   int           `envconfig:"DRONE_WEBHOOK_ATTEMPTS" default:"5"`
+		Backoff    time.Duration `envconfig:"DRONE_WEBHOOK_BACKOFF" default:"30s"`
+		Interval   time.Duration `envconfig:"DRONE_WEBHOOK_INTERVAL" default:"5s"`
+		Retention  time.Duration `envconfig:"DRONE_WEBHOOK_RETENTION" default:"720h"`
 	}
 
 	// Yaml provides the yaml webhook configuration.
@@ -19,6 +19,7 @@ import (
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/plugin/admission"
 	"github.com/drone/drone/plugin/config"
+	"github.com/drone/drone/plugin/notify"
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
 	"github.com/drone/drone/plugin/webhook"
@@ -31,9 +32,11 @@ import (
 var pluginSet = wire.NewSet(
 	provideAdmissionPlugin,
 	provideConfigPlugin,
+	provideNotifyPlugin,
 	provideRegistryPlugin,
 	provideSecretPlugin,
 	provideWebhookPlugin,
+	provideWebhookDispatcher,
 )
 
 // provideAdmissionPlugin is a Wire provider function that
@@ -50,15 +53,19 @@ func provideAdmissionPlugin(client *scm.Client, orgs core.OrganizationService, u
 // provideConfigPlugin is a Wire provider function that returns
 // a yaml configuration plugin based on the environment
 // configuration.
-func provideConfigPlugin(client *scm.Client, contents core.FileService, conf spec.Config) core.ConfigService {
-	return config.Combine(
-		config.Global(
-			conf.Yaml.Endpoint,
-			conf.Yaml.Secret,
-			conf.Yaml.SkipVerify,
+func provideConfigPlugin(client *scm.Client, contents core.FileService, templates core.TemplateStore, central core.CentralConfigStore, conf spec.Config) core.ConfigService {
+	return config.Template(
+		config.Combine(
+			config.Global(
+				conf.Yaml.Endpoint,
+				conf.Yaml.Secret,
+				conf.Yaml.SkipVerify,
+			),
+			config.Multi(contents, conf.Jsonnet.Enabled),
+			config.Jsonnet(contents, conf.Jsonnet.Enabled),
+			config.Repository(contents, central),
 		),
-		config.Jsonnet(contents, conf.Jsonnet.Enabled),
-		config.Repository(contents),
+		templates,
 	)
 }
 
@@ -93,12 +100,41 @@ func provideSecretPlugin(config spec.Config) core.SecretService {
 	)
 }
 
+// provideNotifyPlugin is a Wire provider function that returns
+// a build notification plugin based on the environment
+// configuration.
+func provideNotifyPlugin(config spec.Config, system *core.System, subscriptions core.SubscriptionStore, builds core.BuildStore, users core.UserStore) core.Notifier {
+	return notify.New(notify.Config{
+		System:         system,
+		SMTPHost:       config.SMTP.Host,
+		SMTPPort:       config.SMTP.Port,
+		SMTPUsername:   config.SMTP.Username,
+		SMTPPassword:   config.SMTP.Password,
+		SMTPFrom:       config.SMTP.From,
+		SMTPSkipVerify: config.SMTP.SkipVerify,
+	}, subscriptions, builds, users)
+}
+
 // provideWebhookPlugin is a Wire provider function that returns
 // a webhook plugin based on the environment configuration.
-func provideWebhookPlugin(config spec.Config, system *core.System) core.WebhookSender {
+func provideWebhookPlugin(config spec.Config, system *core.System, deliveries core.WebhookDeliveryStore, webhooks core.WebhookStore) core.WebhookSender {
 	return webhook.New(webhook.Config{
 		Endpoint: config.Webhook.Endpoint,
+		Events:   config.Webhook.Events,
 		Secret:   config.Webhook.Secret,
 		System:   system,
-	})
+	}, deliveries, webhooks)
+}
+
+// provideWebhookDispatcher is a Wire provider function that
+// returns a webhook dispatcher based on the environment
+// configuration.
+func provideWebhookDispatcher(config spec.Config, deliveries core.WebhookDeliveryStore, webhooks core.WebhookStore) *webhook.Dispatcher {
+	return webhook.NewDispatcher(webhook.Config{
+		Secret:     config.Webhook.Secret,
+		SkipVerify: config.Webhook.SkipVerify,
+		Attempts:   config.Webhook.Attempts,
+		Backoff:    config.Webhook.Backoff,
+		Retention:  config.Webhook.Retention,
+	}, deliveries, webhooks)
 }
@@ -49,6 +49,16 @@ func provideRunner(
 			Fatalln("cannot load the docker engine")
 		return nil
 	}
+	sampler, err := runner.NewDockerSampler()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot sample container resource usage")
+	}
+	copier, err := runner.NewDockerCopier()
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("cannot copy files from containers")
+	}
 	return &runner.Runner{
 		Platform:   config.Runner.Platform,
 		OS:         config.Runner.OS,
@@ -66,6 +76,11 @@ func provideRunner(
 		Machine:    config.Runner.Machine,
 		Labels:     config.Runner.Labels,
 		Environ:    config.Runner.Environ,
+		Sampler:    sampler,
+		Copier:     copier,
+
+		DrainTimeout: config.Runner.Drain,
+		LogLimits:    provideLogLimits(config),
 		Limits: runner.Limits{
 			MemSwapLimit: int64(config.Runner.Limits.MemSwapLimit),
 			MemLimit:     int64(config.Runner.Limits.MemLimit),
@@ -17,6 +17,7 @@ package main
 import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/metric"
 	"github.com/drone/drone/scheduler/kube"
 	"github.com/drone/drone/scheduler/nomad"
 	"github.com/drone/drone/scheduler/queue"
@@ -126,5 +127,5 @@ func provideNomadScheduler(config config.Config) core.Scheduler {
 // docker runner, and by remote agents.
 func provideQueueScheduler(store core.StageStore, config config.Config) core.Scheduler {
 	logrus.Info("main: internal scheduler enabled")
-	return queue.New(store)
+	return metric.RequestWait(queue.New(store))
 }
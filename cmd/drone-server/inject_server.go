@@ -36,6 +36,7 @@ var serverSet = wire.NewSet(
 	manager.New,
 	api.New,
 	web.New,
+	provideLogLimits,
 	provideMetric,
 	provideRouter,
 	provideRPC,
@@ -54,6 +55,17 @@ func provideRouter(api api.Server, web web.Server, rpc http.Handler, metrics *me
 	return r
 }
 
+// provideLogLimits is a Wire provider function that returns
+// the step and stage log limits from the environment.
+func provideLogLimits(config config.Config) core.LogLimits {
+	return core.LogLimits{
+		StepBytes:  int64(config.Runner.Limits.LogStepSize),
+		StepLines:  config.Runner.Limits.LogStepLines,
+		StageBytes: int64(config.Runner.Limits.LogStageSize),
+		StageLines: config.Runner.Limits.LogStageLines,
+	}
+}
+
 // provideMetric is a Wire provider function that returns the
 // metrics server exposing metrics in prometheus format.
 func provideMetric(session core.Session, config config.Config) *metric.Server {
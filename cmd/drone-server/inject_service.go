@@ -18,8 +18,10 @@ import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/livelog"
+	"github.com/drone/drone/metric"
 	"github.com/drone/drone/metric/sink"
 	"github.com/drone/drone/pubsub"
+	"github.com/drone/drone/service/comment"
 	"github.com/drone/drone/service/commit"
 	"github.com/drone/drone/service/content"
 	"github.com/drone/drone/service/content/cache"
@@ -51,9 +53,10 @@ var serviceSet = wire.NewSet(
 	pubsub.New,
 	repo.New,
 	token.Renewer,
-	trigger.New,
 	user.New,
 
+	provideCollector,
+	provideCommentService,
 	provideContentService,
 	provideDatadog,
 	provideHookService,
@@ -62,8 +65,21 @@ var serviceSet = wire.NewSet(
 	provideStatusService,
 	provideSyncer,
 	provideSystem,
+	provideTriggerer,
 )
 
+// provideCommentService is a Wire provider function that
+// returns a pull request comment service based on the
+// environment configuration.
+func provideCommentService(client *scm.Client, renewer core.Renewer, logs core.LogStore, config config.Config) core.CommentService {
+	return comment.New(client, renewer, logs, comment.Config{
+		Base:        config.Server.Addr,
+		Enabled:     config.Comments.Enabled,
+		FailureOnly: config.Comments.FailureOnly,
+		Lines:       config.Comments.Lines,
+	})
+}
+
 // provideContentService is a Wire provider function that
 // returns a contents service wrapped with a simple LRU cache.
 func provideContentService(client *scm.Client, renewer core.Renewer) core.FileService {
@@ -107,6 +123,7 @@ func provideStatusService(client *scm.Client, renewer core.Renewer, config confi
 		Base:     config.Server.Addr,
 		Name:     config.Status.Name,
 		Disabled: config.Status.Disabled,
+		Stages:   config.Status.Stages,
 	})
 }
 
@@ -127,6 +144,23 @@ func provideSyncer(repoz core.RepositoryService,
 	return sync
 }
 
+// provideTriggerer is a Wire provider function that returns a
+// build triggerer, with metrics enabled.
+func provideTriggerer(
+	config core.ConfigService,
+	commits core.CommitService,
+	status core.StatusService,
+	builds core.BuildStore,
+	sched core.Scheduler,
+	repos core.RepositoryStore,
+	users core.UserStore,
+	hooks core.WebhookSender,
+) core.Triggerer {
+	return metric.TriggerErrors(
+		trigger.New(config, commits, status, builds, sched, repos, users, hooks),
+	)
+}
+
 // provideSyncer is a Wire provider function that returns the
 // system details structure.
 func provideSystem(config config.Config) *core.System {
@@ -138,12 +172,54 @@ func provideSystem(config config.Config) *core.System {
 	}
 }
 
+// provideCollector is a Wire provider function that returns
+// the metric collector, configured with a sink for each
+// enabled monitoring system.
+func provideCollector(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	config config.Config,
+) *sink.Collector {
+	var sinks []sink.Sink
+	if config.Metrics.StatsD != "" {
+		sinks = append(sinks, sink.StatsD(
+			config.Metrics.StatsD,
+			config.Metrics.Prefix,
+		))
+	}
+	if config.Metrics.OTLP != "" {
+		sinks = append(sinks, sink.OTLP(
+			config.Metrics.OTLP,
+			config.Metrics.Prefix,
+			config.Metrics.Headers,
+		))
+	}
+	if config.Metrics.RemoteWrite != "" {
+		sinks = append(sinks, sink.RemoteWrite(
+			config.Metrics.RemoteWrite,
+			config.Metrics.Prefix,
+			config.Metrics.Headers,
+		))
+	}
+	return sink.NewCollector(
+		users,
+		repos,
+		builds,
+		stages,
+		config.Metrics.Interval,
+		sinks...,
+	)
+}
+
 // provideDatadog is a Wire provider function that returns the
 // datadog sink.
 func provideDatadog(
 	users core.UserStore,
 	repos core.RepositoryStore,
 	builds core.BuildStore,
+	stages core.StageStore,
 	system *core.System,
 	license *core.License,
 	config config.Config,
@@ -152,6 +228,7 @@ func provideDatadog(
 		users,
 		repos,
 		builds,
+		stages,
 		*system,
 		sink.Config{
 			Endpoint:         config.Datadog.Endpoint,
@@ -18,10 +18,16 @@ import (
 	"github.com/drone/drone/cmd/drone-server/config"
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/metric"
+	"github.com/drone/drone/store/annotation"
+	"github.com/drone/drone/store/artifact"
 	"github.com/drone/drone/store/batch"
 	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/cache"
+	"github.com/drone/drone/store/central"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/delivery"
 	"github.com/drone/drone/store/logs"
+	"github.com/drone/drone/store/output"
 	"github.com/drone/drone/store/perm"
 	"github.com/drone/drone/store/repos"
 	"github.com/drone/drone/store/secret"
@@ -30,26 +36,40 @@ import (
 	"github.com/drone/drone/store/shared/encrypt"
 	"github.com/drone/drone/store/stage"
 	"github.com/drone/drone/store/step"
+	"github.com/drone/drone/store/subscription"
+	"github.com/drone/drone/store/template"
+	"github.com/drone/drone/store/testcase"
 	"github.com/drone/drone/store/user"
+	"github.com/drone/drone/store/webhook"
 
 	"github.com/google/wire"
 )
 
 // wire set for loading the stores.
 var storeSet = wire.NewSet(
+	provideArtifactStore,
+	provideCacheStore,
 	provideDatabase,
+	provideDeliveryStore,
 	provideEncrypter,
 	provideBuildStore,
 	provideLogStore,
 	provideRepoStore,
 	provideStageStore,
 	provideUserStore,
+	annotation.New,
 	batch.New,
+	central.New,
 	cron.New,
+	output.New,
 	perm.New,
 	secret.New,
 	global.New,
-	step.New,
+	provideStepStore,
+	subscription.New,
+	template.New,
+	testcase.New,
+	webhook.New,
 )
 
 // provideDatabase is a Wire provider function that provides a
@@ -75,7 +95,7 @@ func provideBuildStore(db *db.DB) core.BuildStore {
 	metric.BuildCount(builds)
 	metric.PendingBuildCount(builds)
 	metric.RunningBuildCount(builds)
-	return builds
+	return metric.BuildDuration(builds)
 }
 
 // provideLogStore is a Wire provider function that provides a
@@ -92,14 +112,65 @@ func provideLogStore(db *db.DB, config config.Config) core.LogStore {
 	)
 }
 
+// provideArtifactStore is a Wire provider function that provides
+// an artifact datastore, configured from the environment.
+func provideArtifactStore(db *db.DB, config config.Config) core.ArtifactStore {
+	switch {
+	case config.Artifacts.Bucket != "":
+		return artifact.NewS3Env(
+			db,
+			config.Artifacts.Bucket,
+			config.Artifacts.Prefix,
+			config.S3.Endpoint,
+			config.S3.PathStyle,
+		)
+	case config.Artifacts.Path != "":
+		return artifact.NewFS(db, config.Artifacts.Path)
+	default:
+		return artifact.New(db)
+	}
+}
+
+// provideCacheStore is a Wire provider function that provides
+// a build cache datastore, configured from the environment.
+func provideCacheStore(db *db.DB, config config.Config) core.CacheStore {
+	if config.Cache.Bucket != "" {
+		return cache.NewS3Env(
+			db,
+			config.Cache.Bucket,
+			config.Cache.Prefix,
+			config.S3.Endpoint,
+			config.S3.PathStyle,
+			config.Cache.Quota,
+		)
+	}
+	return cache.NewFS(db, config.Cache.Path, config.Cache.Quota)
+}
+
 // provideStageStore is a Wire provider function that provides a
 // stage datastore, configured from the environment, with metrics
 // enabled.
-func provideStageStore(db *db.DB) core.StageStore {
+func provideStageStore(db *db.DB, repos core.RepositoryStore, config config.Config) core.StageStore {
 	stages := stage.New(db)
 	metric.PendingJobCount(stages)
 	metric.RunningJobCount(stages)
-	return stages
+	return metric.StageDuration(stages, repos, metric.Config{
+		Repos: config.Prometheus.Repos,
+		Limit: config.Prometheus.LabelLimit,
+	})
+}
+
+// provideDeliveryStore is a Wire provider function that provides
+// a webhook delivery datastore, with metrics enabled.
+func provideDeliveryStore(db *db.DB) core.WebhookDeliveryStore {
+	return metric.WebhookLatency(delivery.New(db))
+}
+
+// provideStepStore is a Wire provider function that provides a
+// step datastore, configured from the environment, with metrics
+// enabled.
+func provideStepStore(db *db.DB) core.StepStore {
+	return metric.StepUsage(step.New(db))
 }
 
 // provideRepoStore is a Wire provider function that provides a
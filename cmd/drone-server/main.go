@@ -24,7 +24,9 @@ import (
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/metric/sink"
 	"github.com/drone/drone/operator/runner"
+	"github.com/drone/drone/plugin/webhook"
 	"github.com/drone/drone/server"
+	"github.com/drone/drone/trace"
 	"github.com/drone/drone/trigger/cron"
 	"github.com/drone/signal"
 
@@ -50,6 +52,7 @@ func main() {
 	}
 
 	initLogging(config)
+	initTracing(config)
 	ctx := signal.WithContext(
 		context.Background(),
 	)
@@ -102,6 +105,13 @@ func main() {
 		return app.sink.Start(ctx)
 	})
 
+	// launches the metric collector in a goroutine. If no
+	// metric sinks are configured, the goroutine exits
+	// immediately without error.
+	g.Go(func() (err error) {
+		return app.collector.Start(ctx)
+	})
+
 	// launches the cron runner in a goroutine. If the cron
 	// runner is disabled, the goroutine exits immediately
 	// without error.
@@ -114,6 +124,15 @@ func main() {
 		return app.cron.Start(ctx, config.Cron.Interval)
 	})
 
+	// launches the webhook dispatcher in a goroutine. The
+	// dispatcher delivers queued webhooks and retries failed
+	// deliveries.
+	g.Go(func() (err error) {
+		logrus.WithField("interval", config.Webhook.Interval.String()).
+			Infoln("main: starting the webhook dispatcher")
+		return app.webhooks.Start(ctx, config.Webhook.Interval)
+	})
+
 	// launches the build runner in a goroutine. If the local
 	// runner is disabled (because nomad or kubernetes is enabled)
 	// then the goroutine exits immediately without error.
@@ -151,27 +170,44 @@ func initLogging(c config.Config) {
 	}
 }
 
+// helper function configures the tracing exporter. Tracing
+// is disabled unless the collector endpoint is configured.
+func initTracing(c config.Config) {
+	if c.Tracing.Endpoint == "" {
+		return
+	}
+	trace.SetExporter(
+		trace.OTLP(c.Tracing.Endpoint, c.Tracing.Service, c.Tracing.Headers),
+	)
+}
+
 // application is the main struct for the Drone server.
 type application struct {
-	cron   *cron.Scheduler
-	sink   *sink.Datadog
-	runner *runner.Runner
-	server *server.Server
-	users  core.UserStore
+	cron      *cron.Scheduler
+	sink      *sink.Datadog
+	collector *sink.Collector
+	runner    *runner.Runner
+	server    *server.Server
+	users     core.UserStore
+	webhooks  *webhook.Dispatcher
 }
 
 // newApplication creates a new application struct.
 func newApplication(
 	cron *cron.Scheduler,
 	sink *sink.Datadog,
+	collector *sink.Collector,
 	runner *runner.Runner,
 	server *server.Server,
-	users core.UserStore) application {
+	users core.UserStore,
+	webhooks *webhook.Dispatcher) application {
 	return application{
-		users:  users,
-		cron:   cron,
-		sink:   sink,
-		server: server,
-		runner: runner,
+		users:     users,
+		cron:      cron,
+		sink:      sink,
+		collector: collector,
+		server:    server,
+		runner:    runner,
+		webhooks:  webhooks,
 	}
 }
@@ -19,13 +19,18 @@ import (
 	"github.com/drone/drone/service/repo"
 	"github.com/drone/drone/service/token"
 	"github.com/drone/drone/service/user"
+	"github.com/drone/drone/store/annotation"
 	"github.com/drone/drone/store/batch"
+	"github.com/drone/drone/store/central"
 	"github.com/drone/drone/store/cron"
+	"github.com/drone/drone/store/output"
 	"github.com/drone/drone/store/perm"
 	"github.com/drone/drone/store/secret"
 	"github.com/drone/drone/store/secret/global"
-	"github.com/drone/drone/store/step"
-	"github.com/drone/drone/trigger"
+	"github.com/drone/drone/store/subscription"
+	"github.com/drone/drone/store/template"
+	"github.com/drone/drone/store/testcase"
+	"github.com/drone/drone/store/webhook"
 	cron2 "github.com/drone/drone/trigger/cron"
 )
 
@@ -50,29 +55,43 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	cronStore := cron.New(db)
 	repositoryStore := provideRepoStore(db)
 	fileService := provideContentService(client, renewer)
-	configService := provideConfigPlugin(client, fileService, config2)
+	templateStore := template.New(db)
+	centralConfigStore := central.New(db)
+	configService := provideConfigPlugin(client, fileService, templateStore, centralConfigStore, config2)
 	statusService := provideStatusService(client, renewer, config2)
 	buildStore := provideBuildStore(db)
-	stageStore := provideStageStore(db)
+	stageStore := provideStageStore(db, repositoryStore, config2)
 	scheduler := provideScheduler(stageStore, config2)
 	system := provideSystem(config2)
-	webhookSender := provideWebhookPlugin(config2, system)
-	triggerer := trigger.New(configService, commitService, statusService, buildStore, scheduler, repositoryStore, userStore, webhookSender)
+	webhookDeliveryStore := provideDeliveryStore(db)
+	encrypter, err := provideEncrypter(config2)
+	if err != nil {
+		return application{}, err
+	}
+	webhookStore := webhook.New(db, encrypter)
+	webhookSender := provideWebhookPlugin(config2, system, webhookDeliveryStore, webhookStore)
+	triggerer := provideTriggerer(configService, commitService, statusService, buildStore, scheduler, repositoryStore, userStore, webhookSender)
 	cronScheduler := cron2.New(commitService, cronStore, repositoryStore, userStore, triggerer)
 	coreLicense := provideLicense(client, config2)
-	datadog := provideDatadog(userStore, repositoryStore, buildStore, system, coreLicense, config2)
+	datadog := provideDatadog(userStore, repositoryStore, buildStore, stageStore, system, coreLicense, config2)
+	collector := provideCollector(userStore, repositoryStore, buildStore, stageStore, config2)
 	corePubsub := pubsub.New()
+	logLimits := provideLogLimits(config2)
 	logStore := provideLogStore(db, config2)
+	annotationStore := annotation.New(db)
+	artifactStore := provideArtifactStore(db, config2)
+	cacheStore := provideCacheStore(db, config2)
+	testStore := testcase.New(db)
 	logStream := livelog.New()
 	netrcService := provideNetrcService(client, renewer, config2)
-	encrypter, err := provideEncrypter(config2)
-	if err != nil {
-		return application{}, err
-	}
 	secretStore := secret.New(db, encrypter)
 	globalSecretStore := global.New(db, encrypter)
-	stepStore := step.New(db)
-	buildManager := manager.New(buildStore, configService, corePubsub, logStore, logStream, netrcService, repositoryStore, scheduler, secretStore, globalSecretStore, statusService, stageStore, stepStore, system, userStore, webhookSender)
+	subscriptionStore := subscription.New(db, encrypter)
+	notifier := provideNotifyPlugin(config2, system, subscriptionStore, buildStore, userStore)
+	outputStore := output.New(db, encrypter)
+	stepStore := provideStepStore(db)
+	commentService := provideCommentService(client, renewer, logStore, config2)
+	buildManager := manager.New(annotationStore, artifactStore, buildStore, cacheStore, commentService, configService, corePubsub, logLimits, logStore, logStream, netrcService, notifier, outputStore, repositoryStore, scheduler, secretStore, globalSecretStore, statusService, stageStore, stepStore, system, testStore, userStore, webhookSender)
 	secretService := provideSecretPlugin(config2)
 	registryService := provideRegistryPlugin(config2)
 	runner := provideRunner(buildManager, secretService, registryService, config2)
@@ -83,8 +102,8 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	session := provideSession(userStore, config2)
 	batcher := batch.New(db)
 	syncer := provideSyncer(repositoryService, repositoryStore, userStore, batcher, config2)
-	server := api.New(buildStore, commitService, cronStore, corePubsub, globalSecretStore, hookService, logStore, coreLicense, licenseService, permStore, repositoryStore, repositoryService, scheduler, secretStore, stageStore, stepStore, statusService, session, logStream, syncer, system, triggerer, userStore, webhookSender)
 	organizationService := orgs.New(client, renewer)
+	server := api.New(annotationStore, artifactStore, buildStore, cacheStore, centralConfigStore, commitService, cronStore, webhookDeliveryStore, corePubsub, globalSecretStore, hookService, logStore, coreLicense, licenseService, organizationService, permStore, repositoryStore, repositoryService, scheduler, secretStore, stageStore, stepStore, statusService, session, logStream, subscriptionStore, syncer, system, templateStore, testStore, triggerer, userStore, webhookSender, webhookStore)
 	userService := user.New(client)
 	admissionService := provideAdmissionPlugin(client, organizationService, userService, config2)
 	hookParser := parser.New(client)
@@ -95,6 +114,7 @@ func InitializeApplication(config2 config.Config) (application, error) {
 	metricServer := provideMetric(session, config2)
 	mux := provideRouter(server, webServer, handler, metricServer)
 	serverServer := provideServer(mux, config2)
-	mainApplication := newApplication(cronScheduler, datadog, runner, serverServer, userStore)
+	dispatcher := provideWebhookDispatcher(config2, webhookDeliveryStore, webhookStore)
+	mainApplication := newApplication(cronScheduler, datadog, collector, runner, serverServer, userStore, dispatcher)
 	return mainApplication, nil
 }
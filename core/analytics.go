@@ -0,0 +1,71 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+type (
+	// AnalyticsFilter defines the repository, branch, pipeline
+	// and time window used to compute build analytics.
+	AnalyticsFilter struct {
+		Repo     int64
+		Branch   string
+		Pipeline string
+		Since    int64
+		Until    int64
+	}
+
+	// Percentiles provides the p50, p90 and p99 values of a
+	// duration distribution, in seconds.
+	Percentiles struct {
+		P50 int64 `json:"p50"`
+		P90 int64 `json:"p90"`
+		P99 int64 `json:"p99"`
+	}
+
+	// BuildStats provides aggregate build statistics.
+	BuildStats struct {
+		Total      int64       `json:"total"`
+		Passed     int64       `json:"passed"`
+		Failed     int64       `json:"failed"`
+		Duration   Percentiles `json:"duration"`
+		Recoveries int64       `json:"recoveries"`
+		MTTR       int64       `json:"mttr"`
+	}
+
+	// StageStats provides aggregate stage statistics.
+	StageStats struct {
+		Duration     Percentiles    `json:"duration"`
+		QueueTime    Percentiles    `json:"queue_time"`
+		FailingSteps []*StepFailure `json:"failing_steps"`
+	}
+
+	// StepFailure provides the number of times a pipeline
+	// step failed.
+	StepFailure struct {
+		Pipeline string `json:"pipeline"`
+		Step     string `json:"step"`
+		Count    int64  `json:"count"`
+	}
+
+	// Analytics provides build analytics for a repository.
+	Analytics struct {
+		Since       int64       `json:"since"`
+		Until       int64       `json:"until"`
+		Branch      string      `json:"branch,omitempty"`
+		Pipeline    string      `json:"pipeline,omitempty"`
+		SuccessRate float64     `json:"success_rate"`
+		Builds      *BuildStats `json:"builds"`
+		Stages      *StageStats `json:"stages"`
+	}
+)
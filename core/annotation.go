@@ -0,0 +1,108 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+// Annotation severity levels.
+const (
+	SeverityNotice  = "notice"
+	SeverityWarning = "warning"
+	SeverityFailure = "failure"
+)
+
+// MaxAnnotationSize is the maximum size, in bytes, of an
+// annotation message written by a build step.
+const MaxAnnotationSize = 4096
+
+// MaxCardSize is the maximum size, in bytes, of a summary
+// card written by a build step.
+const MaxCardSize = 65536
+
+var (
+	errAnnotationMessageEmpty = errors.New("Annotation Message is Empty")
+	errAnnotationMessageSize  = errors.New("Annotation Message Exceeds Maximum Size")
+	errAnnotationSeverity     = errors.New("Invalid Annotation Severity")
+	errCardSize               = errors.New("Card Exceeds Maximum Size")
+)
+
+type (
+	// Annotation represents a structured message written by
+	// a build step, optionally associated with a file and
+	// line number, such as a lint warning.
+	Annotation struct {
+		ID       int64  `json:"id"`
+		BuildID  int64  `json:"build_id"`
+		StageID  int64  `json:"stage_id"`
+		StepID   int64  `json:"step_id"`
+		File     string `json:"file,omitempty"`
+		Line     int    `json:"line,omitempty"`
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+	}
+
+	// Card represents a markdown summary written by a build
+	// step, such as a coverage report.
+	Card struct {
+		ID      int64  `json:"id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Data    string `json:"data"`
+	}
+
+	// AnnotationStore persists build annotations and summary
+	// cards to storage.
+	AnnotationStore interface {
+		// List returns a list of annotations for the build.
+		List(ctx context.Context, build int64) ([]*Annotation, error)
+
+		// Create persists a list of annotations to the datastore.
+		Create(ctx context.Context, annotations []*Annotation) error
+
+		// ListCards returns a list of summary cards for the build.
+		ListCards(ctx context.Context, build int64) ([]*Card, error)
+
+		// CreateCard persists a summary card to the datastore.
+		CreateCard(ctx context.Context, card *Card) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (a *Annotation) Validate() error {
+	switch {
+	case a.Message == "":
+		return errAnnotationMessageEmpty
+	case len(a.Message) > MaxAnnotationSize:
+		return errAnnotationMessageSize
+	}
+	switch a.Severity {
+	case SeverityNotice, SeverityWarning, SeverityFailure:
+		return nil
+	default:
+		return errAnnotationSeverity
+	}
+}
+
+// Validate validates the required fields and formats.
+func (c *Card) Validate() error {
+	if len(c.Data) > MaxCardSize {
+		return errCardSize
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotationValidate(t *testing.T) {
+	tests := []struct {
+		severity string
+		message  string
+		error    error
+	}{
+		{severity: SeverityNotice, message: "coverage 80%", error: nil},
+		{severity: SeverityWarning, message: "unused variable", error: nil},
+		{severity: SeverityFailure, message: "syntax error", error: nil},
+		{severity: SeverityWarning, message: "", error: errAnnotationMessageEmpty},
+		{severity: SeverityWarning, message: strings.Repeat("x", MaxAnnotationSize+1), error: errAnnotationMessageSize},
+		{severity: "", message: "unused variable", error: errAnnotationSeverity},
+		{severity: "error", message: "unused variable", error: errAnnotationSeverity},
+	}
+	for i, test := range tests {
+		annotation := &Annotation{Severity: test.severity, Message: test.message}
+		got, want := annotation.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestCardValidate(t *testing.T) {
+	card := &Card{Data: "# Coverage"}
+	if err := card.Validate(); err != nil {
+		t.Error(err)
+	}
+	card = &Card{Data: strings.Repeat("x", MaxCardSize+1)}
+	if got, want := card.Validate(), errCardSize; got != want {
+		t.Errorf("Want error %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var errArtifactNameInvalid = errors.New("Invalid Artifact Name")
+
+type (
+	// Artifact represents a file published by a build step.
+	Artifact struct {
+		ID      int64  `json:"id"`
+		RepoID  int64  `json:"repo_id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Name    string `json:"name"`
+		Size    int64  `json:"size"`
+		Created int64  `json:"created"`
+	}
+
+	// ArtifactStore persists build artifacts to storage.
+	ArtifactStore interface {
+		// List returns a list of artifacts for the build.
+		List(ctx context.Context, build int64) ([]*Artifact, error)
+
+		// ListStage returns a list of artifacts for the stage.
+		ListStage(ctx context.Context, stage int64) ([]*Artifact, error)
+
+		// Find returns an artifact from the datastore.
+		Find(ctx context.Context, id int64) (*Artifact, error)
+
+		// FindName returns an artifact from the datastore
+		// by stage and name.
+		FindName(ctx context.Context, stage int64, name string) (*Artifact, error)
+
+		// Open returns a reader for the artifact contents.
+		Open(ctx context.Context, artifact *Artifact) (io.ReadCloser, error)
+
+		// Create persists a new artifact to the datastore and
+		// copies the contents from Reader r to storage. An
+		// existing artifact with the same name is replaced.
+		Create(ctx context.Context, artifact *Artifact, r io.Reader) error
+
+		// Delete deletes an artifact and its contents.
+		Delete(ctx context.Context, artifact *Artifact) error
+
+		// Purge deletes the artifacts and contents for builds
+		// where the build number is less than n.
+		Purge(ctx context.Context, repo, number int64) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (a *Artifact) Validate() error {
+	switch {
+	case len(a.Name) == 0:
+		return errArtifactNameInvalid
+	case path.IsAbs(a.Name):
+		return errArtifactNameInvalid
+	case path.Clean(a.Name) != a.Name:
+		return errArtifactNameInvalid
+	case a.Name == ".." || strings.HasPrefix(a.Name, "../"):
+		return errArtifactNameInvalid
+	default:
+		return nil
+	}
+}
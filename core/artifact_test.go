@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestArtifactValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		error error
+	}{
+		{name: "dist/app.tar.gz", error: nil},
+		{name: "coverage.out", error: nil},
+		{name: "", error: errArtifactNameInvalid},
+		{name: "/etc/passwd", error: errArtifactNameInvalid},
+		{name: "../secret", error: errArtifactNameInvalid},
+		{name: "dist/../../secret", error: errArtifactNameInvalid},
+		{name: "dist//app", error: errArtifactNameInvalid},
+	}
+	for i, test := range tests {
+		artifact := &Artifact{Name: test.name}
+		got, want := artifact.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
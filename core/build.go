@@ -45,6 +45,7 @@ type Build struct {
 	Params       map[string]string `db:"build_params"         json:"params,omitempty"`
 	Cron         string            `db:"build_cron"           json:"cron,omitempty"`
 	Deploy       string            `db:"build_deploy"         json:"deploy_to,omitempty"`
+	Trace        string            `db:"build_trace"          json:"trace,omitempty"`
 	Started      int64             `db:"build_started"        json:"started"`
 	Finished     int64             `db:"build_finished"       json:"finished"`
 	Created      int64             `db:"build_created"        json:"created"`
@@ -53,6 +54,14 @@ type Build struct {
 	Stages       []*Stage          `db:"-"                    json:"stages,omitempty"`
 }
 
+// BuildCount represents a count of builds grouped by
+// status and event.
+type BuildCount struct {
+	Status string `db:"build_status" json:"status"`
+	Event  string `db:"build_event"  json:"event"`
+	Count  int64  `db:"build_count"  json:"count"`
+}
+
 // BuildStore defines operations for working with builds.
 type BuildStore interface {
 	// Find returns a build from the datastore.
@@ -92,4 +101,16 @@ type BuildStore interface {
 
 	// Count returns a count of builds.
 	Count(context.Context) (int64, error)
+
+	// CountStatus returns a count of builds created since
+	// the given timestamp, grouped by status and event.
+	CountStatus(context.Context, int64) ([]*BuildCount, error)
+
+	// CountRepos returns a count of distinct repositories
+	// with builds created since the given timestamp.
+	CountRepos(context.Context, int64) (int64, error)
+
+	// Stats returns aggregate build statistics for the
+	// repository, branch and time window of the filter.
+	Stats(context.Context, *AnalyticsFilter) (*BuildStats, error)
 }
@@ -0,0 +1,77 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+)
+
+var errCacheKeyInvalid = errors.New("Invalid Cache Key")
+
+// cacheKeyRE is a regular expression that matches a valid
+// cache key.
+var cacheKeyRE = regexp.MustCompile("^[a-zA-Z0-9_.\\-/]{1,250}$")
+
+type (
+	// Cache represents a build cache archive, saved by a
+	// build step and restored by subsequent builds.
+	Cache struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id"`
+		Key      string `json:"key"`
+		Size     int64  `json:"size"`
+		Created  int64  `json:"created"`
+		Accessed int64  `json:"accessed"`
+	}
+
+	// CacheStore persists build caches to storage.
+	CacheStore interface {
+		// List returns a list of caches for the repository.
+		List(ctx context.Context, repo int64) ([]*Cache, error)
+
+		// Find returns the cache that exactly matches the key.
+		// If no cache matches the key, the most recently saved
+		// cache matching the first matching fallback prefix is
+		// returned.
+		Find(ctx context.Context, repo int64, key string, prefixes []string) (*Cache, error)
+
+		// Open returns a reader for the cache contents, and
+		// updates the cache access time.
+		Open(ctx context.Context, cache *Cache) (io.ReadCloser, error)
+
+		// Save persists the cache to storage, replacing an
+		// existing cache with the same key. Least recently
+		// used caches are evicted when the repository exceeds
+		// its size quota.
+		Save(ctx context.Context, cache *Cache, r io.Reader) error
+
+		// Delete deletes the cache and its contents.
+		Delete(ctx context.Context, cache *Cache) error
+
+		// Clear deletes all caches for the repository.
+		Clear(ctx context.Context, repo int64) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (c *Cache) Validate() error {
+	if !cacheKeyRE.MatchString(c.Key) {
+		return errCacheKeyInvalid
+	}
+	return nil
+}
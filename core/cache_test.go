@@ -0,0 +1,29 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestCacheValidate(t *testing.T) {
+	tests := []struct {
+		key   string
+		error error
+	}{
+		{key: "node_modules-master-a1b2c3", error: nil},
+		{key: "go/pkg/mod-v1.12", error: nil},
+		{key: "", error: errCacheKeyInvalid},
+		{key: "key with spaces", error: errCacheKeyInvalid},
+		{key: "key?query", error: errCacheKeyInvalid},
+	}
+	for i, test := range tests {
+		cache := &Cache{Key: test.key}
+		got, want := cache.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
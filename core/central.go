@@ -0,0 +1,77 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+var errCentralConfigPathInvalid = errors.New("Invalid Central Configuration Path")
+
+// centralConfigRE is a regular expression that matches a
+// valid central configuration path, in the format
+// owner/name[@ref]/path.
+var centralConfigRE = regexp.MustCompile("^[^/@]+/[^/@]+(@[^/]+)?/.+$")
+
+type (
+	// CentralConfig represents the central configuration of
+	// a namespace. Repositories in the namespace that do not
+	// have a configuration file fetch the configuration file
+	// from the central configuration repository.
+	//
+	// The path is defined in the format owner/name[@ref]/path,
+	// where the path may reference the target repository using
+	// the DRONE_REPO, DRONE_REPO_NAMESPACE, DRONE_REPO_NAME and
+	// DRONE_REPO_BRANCH variables. For example:
+	//
+	//	octocat/drone-config@master/${DRONE_REPO_NAME}.yml
+	CentralConfig struct {
+		ID        int64  `json:"id"`
+		Namespace string `json:"namespace"`
+		Path      string `json:"path"`
+		Created   int64  `json:"created"`
+		Updated   int64  `json:"updated"`
+	}
+
+	// CentralConfigStore manages the central configuration
+	// of namespaces.
+	CentralConfigStore interface {
+		// Find returns the central configuration of the
+		// namespace from the datastore.
+		Find(ctx context.Context, namespace string) (*CentralConfig, error)
+
+		// Create persists a new central configuration to the
+		// datastore.
+		Create(ctx context.Context, config *CentralConfig) error
+
+		// Update persists an updated central configuration to
+		// the datastore.
+		Update(ctx context.Context, config *CentralConfig) error
+
+		// Delete deletes the central configuration from the
+		// datastore.
+		Delete(ctx context.Context, config *CentralConfig) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (c *CentralConfig) Validate() error {
+	if !centralConfigRE.MatchString(c.Path) {
+		return errCentralConfigPathInvalid
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestCentralConfigValidate(t *testing.T) {
+	tests := []struct {
+		path  string
+		error error
+	}{
+		{path: "octocat/drone-config/${DRONE_REPO_NAME}.yml", error: nil},
+		{path: "octocat/drone-config@master/.drone.yml", error: nil},
+		{path: "", error: errCentralConfigPathInvalid},
+		{path: "octocat/drone-config", error: errCentralConfigPathInvalid},
+		{path: "octocat/drone-config@/.drone.yml", error: errCentralConfigPathInvalid},
+		{path: "octocat@master/drone-config/.drone.yml", error: errCentralConfigPathInvalid},
+	}
+	for i, test := range tests {
+		config := &CentralConfig{Path: test.path}
+		got, want := config.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+type (
+	// CommentInput provides the necessary metadata to post
+	// a build summary comment to a pull request.
+	CommentInput struct {
+		Repo   *Repository
+		Build  *Build
+		Stages []*Stage
+	}
+
+	// CommentService posts a build summary comment to the
+	// pull request that triggered the build, and updates the
+	// comment as the build progresses.
+	CommentService interface {
+		Send(ctx context.Context, user *User, req *CommentInput) error
+	}
+)
@@ -17,6 +17,7 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gosimple/slug"
@@ -32,19 +33,20 @@ var (
 type (
 	// Cron defines a cron job.
 	Cron struct {
-		ID       int64  `json:"id"`
-		RepoID   int64  `json:"repo_id"`
-		Name     string `json:"name"`
-		Expr     string `json:"expr"`
-		Next     int64  `json:"next"`
-		Prev     int64  `json:"prev"`
-		Event    string `json:"event"`
-		Branch   string `json:"branch"`
-		Target   string `json:"target,omitempty"`
-		Disabled bool   `json:"disabled"`
-		Created  int64  `json:"created"`
-		Updated  int64  `json:"updated"`
-		Version  int64  `json:"version"`
+		ID       int64             `json:"id"`
+		RepoID   int64             `json:"repo_id"`
+		Name     string            `json:"name"`
+		Expr     string            `json:"expr"`
+		Next     int64             `json:"next"`
+		Prev     int64             `json:"prev"`
+		Event    string            `json:"event"`
+		Branch   string            `json:"branch"`
+		Target   string            `json:"target,omitempty"`
+		Params   map[string]string `json:"params,omitempty"`
+		Disabled bool              `json:"disabled"`
+		Created  int64             `json:"created"`
+		Updated  int64             `json:"updated"`
+		Version  int64             `json:"version"`
 	}
 
 	// CronStore persists cron information to storage.
@@ -115,3 +117,26 @@ func (c *Cron) Update() error {
 	c.Next = sched.Next(time.Now()).Unix()
 	return nil
 }
+
+// Hook returns the hook used to trigger a build for the
+// cronjob at the given commit.
+func (c *Cron) Hook(commit *Commit) *Hook {
+	return &Hook{
+		Trigger:      TriggerCron,
+		Event:        EventPush,
+		Link:         commit.Link,
+		Timestamp:    commit.Author.Date,
+		Message:      commit.Message,
+		After:        commit.Sha,
+		Ref:          fmt.Sprintf("refs/heads/%s", c.Branch),
+		Target:       c.Branch,
+		Author:       commit.Author.Login,
+		AuthorName:   commit.Author.Name,
+		AuthorEmail:  commit.Author.Email,
+		AuthorAvatar: commit.Author.Avatar,
+		Deployment:   c.Target,
+		Cron:         c.Name,
+		Sender:       commit.Author.Login,
+		Params:       c.Params,
+	}
+}
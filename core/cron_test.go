@@ -5,3 +5,51 @@
 // +build !oss
 
 package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCronHook(t *testing.T) {
+	cron := &Cron{
+		Name:   "nightly",
+		Branch: "master",
+		Target: "production",
+		Params: map[string]string{"foo": "bar"},
+	}
+	commit := &Commit{
+		Sha:     "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Message: "Merge pull request #6 from Spaceghost/patch-1",
+		Link:    "https://github.com/octocat/hello-world/commit/7fd1a60",
+		Author: &Committer{
+			Name:   "The Octocat",
+			Email:  "octocat@nowhere.com",
+			Date:   1331075210,
+			Login:  "octocat",
+			Avatar: "https://avatars.githubusercontent.com/u/583231",
+		},
+	}
+	want := &Hook{
+		Trigger:      TriggerCron,
+		Event:        EventPush,
+		Link:         commit.Link,
+		Timestamp:    1331075210,
+		Message:      commit.Message,
+		After:        commit.Sha,
+		Ref:          "refs/heads/master",
+		Target:       "master",
+		Author:       "octocat",
+		AuthorName:   "The Octocat",
+		AuthorEmail:  "octocat@nowhere.com",
+		AuthorAvatar: "https://avatars.githubusercontent.com/u/583231",
+		Deployment:   "production",
+		Cron:         "nightly",
+		Sender:       "octocat",
+		Params:       map[string]string{"foo": "bar"},
+	}
+	if diff := cmp.Diff(cron.Hook(commit), want); diff != "" {
+		t.Errorf(diff)
+	}
+}
@@ -24,6 +24,13 @@ type (
 		Hash []byte
 	}
 
+	// FileInfo represents a file or directory entry in the
+	// remote version control system.
+	FileInfo struct {
+		Path  string
+		IsDir bool
+	}
+
 	// FileArgs provides repository and commit details required
 	// to fetch the file from the  remote source code management
 	// service.
@@ -36,5 +43,6 @@ type (
 	// the remote source code management service (e.g. GitHub).
 	FileService interface {
 		Find(ctx context.Context, user *User, repo, commit, ref, path string) (*File, error)
+		List(ctx context.Context, user *User, repo, commit, ref, path string) ([]*FileInfo, error)
 	}
 )
@@ -26,6 +26,62 @@ type Line struct {
 	Timestamp int64  `json:"time"`
 }
 
+// LogTruncated is the message written to the end of the
+// build logs when the logs exceed the log limits and are
+// truncated.
+const LogTruncated = "warning: maximum log size exceeded, output truncated"
+
+// LogLimits defines the maximum size of the build logs. A
+// zero value indicates the logs are not limited.
+type LogLimits struct {
+	// StepBytes and StepLines limit the size of the logs
+	// written by a single step.
+	StepBytes int64
+	StepLines int
+
+	// StageBytes and StageLines limit the combined size of
+	// the logs written by all steps in a stage.
+	StageBytes int64
+	StageLines int
+}
+
+// Limited returns true if the step or stage logs are limited.
+func (l LogLimits) Limited() bool {
+	return l.StepBytes != 0 || l.StepLines != 0 ||
+		l.StageBytes != 0 || l.StageLines != 0
+}
+
+// LogCounter tracks the size of the logs written by a step
+// or a stage.
+type LogCounter struct {
+	Bytes     int64
+	Lines     int
+	Truncated bool
+}
+
+// Exceeds returns true if writing a line of the given size
+// exceeds the byte or line limit. A zero limit is unlimited.
+func (c *LogCounter) Exceeds(size, maxBytes int64, maxLines int) bool {
+	return exceeds(maxBytes, c.Bytes+size) ||
+		exceeds(int64(maxLines), int64(c.Lines+1))
+}
+
+// Add records a line of the given size.
+func (c *LogCounter) Add(size int64) {
+	c.Bytes += size
+	c.Lines++
+}
+
+// TruncatedLine returns the truncation marker line, using the
+// position and timestamp of the first rejected line.
+func TruncatedLine(number int, timestamp int64) *Line {
+	return &Line{
+		Number:    number,
+		Message:   LogTruncated + "\n",
+		Timestamp: timestamp,
+	}
+}
+
 // LogStore persists build output to storage.
 type LogStore interface {
 	// Find returns a log stream from the datastore.
@@ -68,3 +124,9 @@ type LogStreamInfo struct {
 	// streaming the logs.
 	Streams map[int64]int `json:"streams"`
 }
+
+// helper function returns true if the value exceeds the
+// limit. A zero limit is unlimited.
+func exceeds(limit, value int64) bool {
+	return limit > 0 && value > limit
+}
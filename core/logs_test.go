@@ -0,0 +1,39 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestLogLimitsLimited(t *testing.T) {
+	if (LogLimits{}).Limited() {
+		t.Errorf("Want zero limits unlimited")
+	}
+	if !(LogLimits{StageLines: 1}).Limited() {
+		t.Errorf("Want stage limits limited")
+	}
+}
+
+func TestLogCounterExceeds(t *testing.T) {
+	tests := []struct {
+		counter  LogCounter
+		size     int64
+		maxBytes int64
+		maxLines int
+		exceeds  bool
+	}{
+		{LogCounter{}, 100, 0, 0, false},
+		{LogCounter{Bytes: 5, Lines: 1}, 5, 10, 0, false},
+		{LogCounter{Bytes: 5, Lines: 1}, 6, 10, 0, true},
+		{LogCounter{Bytes: 5, Lines: 1}, 1, 0, 2, false},
+		{LogCounter{Bytes: 5, Lines: 2}, 1, 0, 2, true},
+	}
+	for i, test := range tests {
+		if got, want := test.counter.Exceeds(test.size, test.maxBytes, test.maxLines), test.exceeds; got != want {
+			t.Errorf("Want exceeds %v at index %d", want, i)
+		}
+	}
+}
@@ -27,5 +27,12 @@ type Organization struct {
 // team access in the external source code management system
 // (e.g. GitHub).
 type OrganizationService interface {
+	// List returns the organizations of which the user is
+	// a member.
 	List(context.Context, *User) ([]*Organization, error)
+
+	// Membership returns true if the user is a member of the
+	// named organization, and true if the user is an admin
+	// of the organization.
+	Membership(context.Context, *User, string) (bool, bool, error)
 }
@@ -0,0 +1,107 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// MaxOutputSize is the maximum size, in bytes, of an output
+// value written by a build step.
+const MaxOutputSize = 4096
+
+var (
+	errOutputNameInvalid  = errors.New("Invalid Output Name")
+	errOutputNameReserved = errors.New("Reserved Output Name")
+	errOutputValueSize    = errors.New("Output Value Exceeds Maximum Size")
+)
+
+// outputRE is a regular expression that matches a valid
+// output name, which must be a valid environment variable.
+var outputRE = regexp.MustCompile("^[A-Za-z_][A-Za-z0-9_]{0,249}$")
+
+// reserved output name prefixes, which are used by the
+// system and by plugins.
+var outputReservedPrefixes = []string{"DRONE_", "CI_", "PLUGIN_"}
+
+// reserved output names, which alter the behavior of the
+// step container.
+var outputReservedNames = map[string]struct{}{
+	"CI":              {},
+	"DRONE":           {},
+	"HOME":            {},
+	"HOSTNAME":        {},
+	"LD_LIBRARY_PATH": {},
+	"LD_PRELOAD":      {},
+	"PATH":            {},
+	"SHELL":           {},
+	"USER":            {},
+}
+
+type (
+	// Output represents a key value pair written by a build
+	// step, which is passed to later steps and dependent
+	// stages as an environment variable.
+	Output struct {
+		ID      int64  `json:"id"`
+		BuildID int64  `json:"build_id"`
+		StageID int64  `json:"stage_id"`
+		StepID  int64  `json:"step_id"`
+		Name    string `json:"name"`
+		Value   string `json:"value"`
+		Secret  bool   `json:"secret"`
+	}
+
+	// OutputStore persists step outputs to storage.
+	OutputStore interface {
+		// List returns a list of outputs for the build.
+		List(ctx context.Context, build int64) ([]*Output, error)
+
+		// Create persists a list of outputs to the datastore.
+		Create(ctx context.Context, outputs []*Output) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (o *Output) Validate() error {
+	switch {
+	case !outputRE.MatchString(o.Name):
+		return errOutputNameInvalid
+	case o.IsReserved():
+		return errOutputNameReserved
+	case len(o.Value) > MaxOutputSize:
+		return errOutputValueSize
+	default:
+		return nil
+	}
+}
+
+// IsReserved returns true if the output name is reserved
+// for use by the system.
+func (o *Output) IsReserved() bool {
+	name := strings.ToUpper(o.Name)
+	if _, ok := outputReservedNames[name]; ok {
+		return true
+	}
+	for _, prefix := range outputReservedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
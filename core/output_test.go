@@ -0,0 +1,38 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		error error
+	}{
+		{name: "VERSION", value: "1.0.0", error: nil},
+		{name: "_image_tag", value: "", error: nil},
+		{name: "", error: errOutputNameInvalid},
+		{name: "1VERSION", error: errOutputNameInvalid},
+		{name: "IMAGE-TAG", error: errOutputNameInvalid},
+		{name: "DRONE_BUILD_STATUS", error: errOutputNameReserved},
+		{name: "ci_job_status", error: errOutputNameReserved},
+		{name: "PLUGIN_PASSWORD", error: errOutputNameReserved},
+		{name: "PATH", error: errOutputNameReserved},
+		{name: "VERSION", value: strings.Repeat("x", MaxOutputSize+1), error: errOutputValueSize},
+	}
+	for i, test := range tests {
+		output := &Output{Name: test.name, Value: test.value}
+		got, want := output.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -32,35 +32,36 @@ const (
 type (
 	// Repository represents a source code repository.
 	Repository struct {
-		ID          int64  `json:"id"`
-		UID         string `json:"uid"`
-		UserID      int64  `json:"user_id"`
-		Namespace   string `json:"namespace"`
-		Name        string `json:"name"`
-		Slug        string `json:"slug"`
-		SCM         string `json:"scm"`
-		HTTPURL     string `json:"git_http_url"`
-		SSHURL      string `json:"git_ssh_url"`
-		Link        string `json:"link"`
-		Branch      string `json:"default_branch"`
-		Private     bool   `json:"private"`
-		Visibility  string `json:"visibility"`
-		Active      bool   `json:"active"`
-		Config      string `json:"config_path"`
-		Trusted     bool   `json:"trusted"`
-		Protected   bool   `json:"protected"`
-		IgnoreForks bool   `json:"ignore_forks"`
-		IgnorePulls bool   `json:"ignore_pull_requests"`
-		Timeout     int64  `json:"timeout"`
-		Counter     int64  `json:"counter"`
-		Synced      int64  `json:"synced"`
-		Created     int64  `json:"created"`
-		Updated     int64  `json:"updated"`
-		Version     int64  `json:"version"`
-		Signer      string `json:"-"`
-		Secret      string `json:"-"`
-		Build       *Build `json:"build,omitempty"`
-		Perms       *Perm  `json:"permissions,omitempty"`
+		ID          int64    `json:"id"`
+		UID         string   `json:"uid"`
+		UserID      int64    `json:"user_id"`
+		Namespace   string   `json:"namespace"`
+		Name        string   `json:"name"`
+		Slug        string   `json:"slug"`
+		SCM         string   `json:"scm"`
+		HTTPURL     string   `json:"git_http_url"`
+		SSHURL      string   `json:"git_ssh_url"`
+		Link        string   `json:"link"`
+		Branch      string   `json:"default_branch"`
+		Private     bool     `json:"private"`
+		Visibility  string   `json:"visibility"`
+		Active      bool     `json:"active"`
+		Config      string   `json:"config_path"`
+		Fallback    []string `json:"config_fallback,omitempty"`
+		Trusted     bool     `json:"trusted"`
+		Protected   bool     `json:"protected"`
+		IgnoreForks bool     `json:"ignore_forks"`
+		IgnorePulls bool     `json:"ignore_pull_requests"`
+		Timeout     int64    `json:"timeout"`
+		Counter     int64    `json:"counter"`
+		Synced      int64    `json:"synced"`
+		Created     int64    `json:"created"`
+		Updated     int64    `json:"updated"`
+		Version     int64    `json:"version"`
+		Signer      string   `json:"-"`
+		Secret      string   `json:"-"`
+		Build       *Build   `json:"build,omitempty"`
+		Perms       *Perm    `json:"permissions,omitempty"`
 	}
 
 	// RepositoryStore defines operations for working with repositories.
@@ -44,6 +44,14 @@ type Scheduler interface {
 	// returns true if the build has been cancelled.
 	Cancelled(context.Context, int64) (bool, error)
 
+	// Drain signals the named runner to stop requesting new
+	// stages, and to exit once its running stages complete.
+	Drain(context.Context, string) error
+
+	// Drained blocks and listens for a drain event and returns
+	// true if the named runner has been asked to drain.
+	Drained(context.Context, string) (bool, error)
+
 	// Pause pauses the scheduler and prevents new pipelines
 	// from being scheduled for execution.
 	Pause(context.Context) error
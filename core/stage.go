@@ -76,6 +76,11 @@ type (
 
 		// Update persists an updated stage to the datastore.
 		Update(context.Context, *Stage) error
+
+		// Stats returns aggregate stage and step statistics for
+		// the repository, branch, pipeline and time window of
+		// the filter.
+		Stats(context.Context, *AnalyticsFilter) (*StageStats, error)
 	}
 )
 
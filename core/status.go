@@ -40,10 +40,13 @@ type (
 	}
 
 	// StatusInput provides the necessary metadata to
-	// set the commit or deployment status.
+	// set the commit or deployment status. If the stage
+	// is non-nil the status is set for the individual
+	// stage (pipeline) instead of the build.
 	StatusInput struct {
 		Repo  *Repository
 		Build *Build
+		Stage *Stage
 	}
 
 	// StatusService sends the commit status to an external
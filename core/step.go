@@ -30,6 +30,18 @@ type (
 		Started   int64  `json:"started,omitempty"`
 		Stopped   int64  `json:"stopped,omitempty"`
 		Version   int64  `json:"version"`
+		Truncated bool   `json:"truncated,omitempty"`
+
+		// Resource usage sampled from the step container.
+		// CPU usage is measured in millicores, where 1000
+		// is equal to one full core. Memory and I/O usage
+		// is measured in bytes.
+		CPUPeak int64 `json:"cpu_peak,omitempty"`
+		CPUAvg  int64 `json:"cpu_avg,omitempty"`
+		MemPeak int64 `json:"mem_peak,omitempty"`
+		MemAvg  int64 `json:"mem_avg,omitempty"`
+		IORead  int64 `json:"io_read,omitempty"`
+		IOWrite int64 `json:"io_write,omitempty"`
 	}
 
 	// StepStore persists build step information to storage.
@@ -0,0 +1,181 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+var (
+	errSubscriptionKindInvalid     = errors.New("Invalid Subscription Kind")
+	errSubscriptionTargetInvalid   = errors.New("Invalid Subscription Target")
+	errSubscriptionTriggerInvalid  = errors.New("Invalid Subscription Trigger")
+	errSubscriptionTemplateInvalid = errors.New("Invalid Subscription Template")
+	errSubscriptionEmailForbidden  = errors.New("Email Subscriptions Must Target The User Email")
+	errSubscriptionChatForbidden   = errors.New("Chat Subscriptions Require Admin Access")
+)
+
+// Subscription kinds.
+const (
+	SubscriptionEmail = "email"
+	SubscriptionChat  = "chat"
+)
+
+// Subscription triggers.
+const (
+	NotifyAlways  = "always"
+	NotifyFailure = "failure"
+	NotifyFixed   = "fixed"
+)
+
+type (
+	// Subscription represents a subscription to build outcome
+	// notifications, delivered by email or to a chat webhook.
+	// A repository subscription receives notifications for all
+	// builds in the repository. A user subscription receives
+	// notifications for builds triggered by the user.
+	Subscription struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id,omitempty"`
+		UserID   int64  `json:"user_id,omitempty"`
+		Kind     string `json:"kind"`
+		Target   string `json:"target"`
+		Trigger  string `json:"trigger"`
+		Template string `json:"template,omitempty"`
+		Disabled bool   `json:"disabled"`
+		Created  int64  `json:"created"`
+		Updated  int64  `json:"updated"`
+	}
+
+	// SubscriptionStore manages notification subscriptions.
+	SubscriptionStore interface {
+		// List returns a list of repository subscriptions
+		// from the datastore.
+		List(ctx context.Context, repo int64) ([]*Subscription, error)
+
+		// ListUser returns a list of user subscriptions from
+		// the datastore.
+		ListUser(ctx context.Context, user int64) ([]*Subscription, error)
+
+		// Find returns a subscription from the datastore.
+		Find(ctx context.Context, id int64) (*Subscription, error)
+
+		// Create persists a new subscription to the datastore.
+		Create(ctx context.Context, subscription *Subscription) error
+
+		// Update persists an updated subscription to the
+		// datastore.
+		Update(ctx context.Context, subscription *Subscription) error
+
+		// Delete deletes a subscription from the datastore.
+		Delete(ctx context.Context, subscription *Subscription) error
+	}
+
+	// NotifyInput provides the build notification data.
+	NotifyInput struct {
+		Repo  *Repository
+		Build *Build
+	}
+
+	// Notifier sends build outcome notifications to the
+	// repository and user subscriptions.
+	Notifier interface {
+		Notify(ctx context.Context, in *NotifyInput) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (s *Subscription) Validate() error {
+	switch s.Kind {
+	case SubscriptionEmail:
+		if _, err := mail.ParseAddress(s.Target); err != nil {
+			return errSubscriptionTargetInvalid
+		}
+	case SubscriptionChat:
+		uri, err := url.Parse(s.Target)
+		if err != nil || uri.Host == "" {
+			return errSubscriptionTargetInvalid
+		}
+		if uri.Scheme != "http" && uri.Scheme != "https" {
+			return errSubscriptionTargetInvalid
+		}
+	default:
+		return errSubscriptionKindInvalid
+	}
+	switch s.Trigger {
+	case NotifyAlways, NotifyFailure, NotifyFixed:
+	default:
+		return errSubscriptionTriggerInvalid
+	}
+	if s.Template != "" {
+		if _, err := template.New("_").Parse(s.Template); err != nil {
+			return errSubscriptionTemplateInvalid
+		}
+	}
+	return nil
+}
+
+// ValidateUser validates the target of a user subscription.
+// Email notifications are only sent to the email address of
+// the user account, and chat notifications, which are sent
+// to arbitrary urls, require admin access.
+func (s *Subscription) ValidateUser(user *User) error {
+	switch s.Kind {
+	case SubscriptionEmail:
+		if user.Email == "" || !strings.EqualFold(s.Target, user.Email) {
+			return errSubscriptionEmailForbidden
+		}
+	case SubscriptionChat:
+		if !user.Admin {
+			return errSubscriptionChatForbidden
+		}
+	}
+	return nil
+}
+
+// Match returns true if the subscription should be notified
+// of the build outcome. The previous build is the most recent
+// completed build for the same ref, and may be nil.
+func (s *Subscription) Match(build, prev *Build) bool {
+	if s.Disabled {
+		return false
+	}
+	switch s.Trigger {
+	case NotifyAlways:
+		return true
+	case NotifyFailure:
+		return isBuildFailed(build)
+	case NotifyFixed:
+		return build.Status == StatusPassing &&
+			prev != nil && isBuildFailed(prev)
+	default:
+		return false
+	}
+}
+
+// helper function returns true if the build failed.
+func isBuildFailed(build *Build) bool {
+	switch build.Status {
+	case StatusFailing, StatusError:
+		return true
+	default:
+		return false
+	}
+}
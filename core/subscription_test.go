@@ -0,0 +1,128 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestSubscriptionValidate(t *testing.T) {
+	tests := []struct {
+		subscription *Subscription
+		error        error
+	}{
+		{
+			subscription: &Subscription{Kind: "email", Target: "octocat@github.com", Trigger: "failure"},
+			error:        nil,
+		},
+		{
+			subscription: &Subscription{Kind: "chat", Target: "https://chat.company.com/hooks/1", Trigger: "always", Template: "{{ .Build.Status }}"},
+			error:        nil,
+		},
+		{
+			subscription: &Subscription{Kind: "sms", Target: "octocat@github.com", Trigger: "always"},
+			error:        errSubscriptionKindInvalid,
+		},
+		{
+			subscription: &Subscription{Kind: "email", Target: "octocat", Trigger: "always"},
+			error:        errSubscriptionTargetInvalid,
+		},
+		{
+			subscription: &Subscription{Kind: "chat", Target: "ftp://chat.company.com", Trigger: "always"},
+			error:        errSubscriptionTargetInvalid,
+		},
+		{
+			subscription: &Subscription{Kind: "email", Target: "octocat@github.com", Trigger: "sometimes"},
+			error:        errSubscriptionTriggerInvalid,
+		},
+		{
+			subscription: &Subscription{Kind: "email", Target: "octocat@github.com", Trigger: "always", Template: "{{ .Build"},
+			error:        errSubscriptionTemplateInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.subscription.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestSubscriptionValidateUser(t *testing.T) {
+	var (
+		user  = &User{Login: "octocat", Email: "octocat@github.com"}
+		admin = &User{Login: "spaceghost", Email: "spaceghost@github.com", Admin: true}
+	)
+	tests := []struct {
+		subscription *Subscription
+		user         *User
+		error        error
+	}{
+		{
+			subscription: &Subscription{Kind: "email", Target: "Octocat@GitHub.com"},
+			user:         user,
+			error:        nil,
+		},
+		{
+			subscription: &Subscription{Kind: "email", Target: "spaceghost@github.com"},
+			user:         user,
+			error:        errSubscriptionEmailForbidden,
+		},
+		{
+			subscription: &Subscription{Kind: "email", Target: "octocat@github.com"},
+			user:         &User{Login: "octocat"},
+			error:        errSubscriptionEmailForbidden,
+		},
+		{
+			subscription: &Subscription{Kind: "chat", Target: "https://chat.company.com/hooks/1"},
+			user:         user,
+			error:        errSubscriptionChatForbidden,
+		},
+		{
+			subscription: &Subscription{Kind: "chat", Target: "https://chat.company.com/hooks/1"},
+			user:         admin,
+			error:        nil,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.subscription.ValidateUser(test.user), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestSubscriptionMatch(t *testing.T) {
+	var (
+		passing = &Build{Status: StatusPassing}
+		failing = &Build{Status: StatusFailing}
+		errored = &Build{Status: StatusError}
+		killed  = &Build{Status: StatusKilled}
+	)
+	tests := []struct {
+		trigger  string
+		disabled bool
+		build    *Build
+		prev     *Build
+		match    bool
+	}{
+		{NotifyAlways, false, passing, nil, true},
+		{NotifyAlways, true, passing, nil, false},
+		{NotifyFailure, false, failing, nil, true},
+		{NotifyFailure, false, errored, passing, true},
+		{NotifyFailure, false, passing, failing, false},
+		{NotifyFailure, false, killed, nil, false},
+		{NotifyFixed, false, passing, failing, true},
+		{NotifyFixed, false, passing, passing, false},
+		{NotifyFixed, false, passing, nil, false},
+		{NotifyFixed, false, failing, failing, false},
+	}
+	for i, test := range tests {
+		subscription := &Subscription{Trigger: test.trigger, Disabled: test.disabled}
+		if got, want := subscription.Match(test.build, test.prev), test.match; got != want {
+			t.Errorf("Want match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	errTemplateNameInvalid    = errors.New("Invalid Template Name")
+	errTemplateVersionInvalid = errors.New("Invalid Template Version")
+	errTemplateDataInvalid    = errors.New("Invalid Template Data")
+)
+
+type (
+	// Template represents a named, versioned pipeline template
+	// that is shared by all repositories in a namespace. A
+	// template version is immutable once created.
+	Template struct {
+		ID        int64  `json:"id"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		Version   string `json:"version"`
+		Data      string `json:"data"`
+		Created   int64  `json:"created"`
+		Updated   int64  `json:"updated"`
+	}
+
+	// TemplateStore manages pipeline templates.
+	TemplateStore interface {
+		// List returns a template list from the datastore.
+		List(ctx context.Context, namespace string) ([]*Template, error)
+
+		// ListAll returns a template list from the datastore
+		// for all namespaces.
+		ListAll(ctx context.Context) ([]*Template, error)
+
+		// Find returns a template from the datastore.
+		Find(ctx context.Context, id int64) (*Template, error)
+
+		// FindName returns a template from the datastore. If
+		// the version is empty, the most recently created
+		// version is returned.
+		FindName(ctx context.Context, namespace, name, version string) (*Template, error)
+
+		// Create persists a new template to the datastore.
+		Create(ctx context.Context, template *Template) error
+
+		// Delete deletes a template from the datastore.
+		Delete(ctx context.Context, template *Template) error
+	}
+)
+
+// Validate validates the required fields and formats.
+func (t *Template) Validate() error {
+	switch {
+	case len(t.Name) == 0:
+		return errTemplateNameInvalid
+	case slugRE.MatchString(t.Name):
+		return errTemplateNameInvalid
+	case len(t.Version) == 0:
+		return errTemplateVersionInvalid
+	case slugRE.MatchString(t.Version):
+		return errTemplateVersionInvalid
+	case len(t.Data) == 0:
+		return errTemplateDataInvalid
+	default:
+		return nil
+	}
+}
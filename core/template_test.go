@@ -0,0 +1,47 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestTemplateValidate(t *testing.T) {
+	tests := []struct {
+		template *Template
+		error    error
+	}{
+		{
+			template: &Template{Name: "golang", Version: "1.0.0", Data: "kind: pipeline"},
+			error:    nil,
+		},
+		{
+			template: &Template{Name: "", Version: "1.0.0", Data: "kind: pipeline"},
+			error:    errTemplateNameInvalid,
+		},
+		{
+			template: &Template{Name: "go/lang", Version: "1.0.0", Data: "kind: pipeline"},
+			error:    errTemplateNameInvalid,
+		},
+		{
+			template: &Template{Name: "golang", Version: "", Data: "kind: pipeline"},
+			error:    errTemplateVersionInvalid,
+		},
+		{
+			template: &Template{Name: "golang", Version: "1.0@0", Data: "kind: pipeline"},
+			error:    errTemplateVersionInvalid,
+		},
+		{
+			template: &Template{Name: "golang", Version: "1.0.0", Data: ""},
+			error:    errTemplateDataInvalid,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.template.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "context"
+
+// Test case status values.
+const (
+	TestPassed  = "passed"
+	TestFailed  = "failed"
+	TestSkipped = "skipped"
+)
+
+type (
+	// TestCase represents a test case result parsed from a
+	// test report uploaded by a build step.
+	TestCase struct {
+		ID       int64  `json:"id"`
+		RepoID   int64  `json:"repo_id"`
+		BuildID  int64  `json:"build_id"`
+		StageID  int64  `json:"stage_id"`
+		StepID   int64  `json:"step_id"`
+		Number   int64  `json:"build_number"`
+		Suite    string `json:"suite"`
+		Name     string `json:"name"`
+		Status   string `json:"status"`
+		Duration int64  `json:"duration"`
+		Message  string `json:"message,omitempty"`
+		Created  int64  `json:"created"`
+	}
+
+	// TestSummary provides a summary of the test case
+	// results for a build.
+	TestSummary struct {
+		Total   int64 `json:"total"`
+		Passed  int64 `json:"passed"`
+		Failed  int64 `json:"failed"`
+		Skipped int64 `json:"skipped"`
+	}
+
+	// TestStore persists test case results to storage.
+	TestStore interface {
+		// List returns a list of test cases for the build.
+		List(ctx context.Context, build int64) ([]*TestCase, error)
+
+		// ListStage returns a list of test cases for the stage.
+		ListStage(ctx context.Context, stage int64) ([]*TestCase, error)
+
+		// ListFailed returns a list of failed test cases for
+		// the build.
+		ListFailed(ctx context.Context, build int64) ([]*TestCase, error)
+
+		// History returns the results of the named test case
+		// across builds for the repository, most recent first.
+		History(ctx context.Context, repo int64, suite, name string, limit int) ([]*TestCase, error)
+
+		// Summary returns the test case summary for the build.
+		Summary(ctx context.Context, build int64) (*TestSummary, error)
+
+		// Create persists a list of test cases to the datastore.
+		Create(ctx context.Context, tests []*TestCase) error
+
+		// Purge deletes the test cases for builds where the
+		// build number is less than n.
+		Purge(ctx context.Context, repo, number int64) error
+	}
+)
@@ -22,9 +22,31 @@ const (
 	TriggerCron = "@cron"
 )
 
-// Triggerer is responsible for triggering a Build from an
-// incoming drone. If a build is skipped a nil value is
-// returned.
-type Triggerer interface {
-	Trigger(context.Context, *Repository, *Hook) (*Build, error)
-}
+type (
+	// Triggerer is responsible for triggering a Build from an
+	// incoming drone. If a build is skipped a nil value is
+	// returned.
+	Triggerer interface {
+		Trigger(context.Context, *Repository, *Hook) (*Build, error)
+
+		// Evaluate evaluates the pipeline configuration for the
+		// hook without creating a build.
+		Evaluate(context.Context, *Repository, *Hook) (*Evaluation, error)
+	}
+
+	// Evaluation represents the result of a dry-run evaluation
+	// of the pipeline configuration.
+	Evaluation struct {
+		Skip    string          `json:"skip,omitempty"`
+		Error   string          `json:"error,omitempty"`
+		Stages  []*Stage        `json:"stages"`
+		Skipped []*PipelineSkip `json:"skipped"`
+	}
+
+	// PipelineSkip provides the reason a pipeline is excluded
+	// from the build.
+	PipelineSkip struct {
+		Name   string `json:"name"`
+		Reason string `json:"reason"`
+	}
+)
@@ -16,12 +16,22 @@ package core
 
 import (
 	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	errWebhookEndpointInvalid = errors.New("Invalid Webhook Endpoint")
+	errWebhookEventInvalid    = errors.New("Invalid Webhook Event")
 )
 
 // Webhook event types.
 const (
 	WebhookEventBuild = "build"
 	WebhookEventRepo  = "repo"
+	WebhookEventStage = "stage"
+	WebhookEventStep  = "step"
 	WebhookEventUser  = "user"
 )
 
@@ -32,28 +42,216 @@ const (
 	WebhookActionDeleted  = "deleted"
 	WebhookActionEnabled  = "enabled"
 	WebhookActionDisabled = "disabled"
+	WebhookActionStarted  = "started"
+	WebhookActionFinished = "finished"
+	WebhookActionApproved = "approved"
+	WebhookActionDeclined = "declined"
+	WebhookActionBlocked  = "blocked"
+)
+
+// Webhook delivery status.
+const (
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailure = "failure"
 )
 
 type (
-	// Webhook defines an integration endpoint.
+	// Webhook defines an integration endpoint subscribed to
+	// repository or namespace events.
 	Webhook struct {
-		Endpoint   string `json:"endpoint,omitempty"`
-		Signer     string `json:"-"`
-		SkipVerify bool   `json:"skip_verify,omitempty"`
+		ID         int64    `json:"id"`
+		RepoID     int64    `json:"repo_id,omitempty"`
+		Namespace  string   `json:"namespace,omitempty"`
+		Endpoint   string   `json:"endpoint"`
+		Secret     string   `json:"secret,omitempty"`
+		SkipVerify bool     `json:"skip_verify"`
+		Events     []string `json:"events"`
+		Disabled   bool     `json:"disabled"`
+		Created    int64    `json:"created"`
+		Updated    int64    `json:"updated"`
 	}
 
 	// WebhookData provides the webhook data.
 	WebhookData struct {
-		Event  string      `json:"-"`
-		Action string      `json:"action"`
-		User   *User       `json:"user,omitempty"`
-		Repo   *Repository `json:"repo,omitempty"`
-		Build  *Build      `json:"build,omitempty"`
+		Event       string        `json:"-"`
+		Action      string        `json:"action"`
+		User        *User         `json:"user,omitempty"`
+		Repo        *Repository   `json:"repo,omitempty"`
+		Build       *Build        `json:"build,omitempty"`
+		Stage       *Stage        `json:"stage,omitempty"`
+		Step        *Step         `json:"step,omitempty"`
+		Annotations []*Annotation `json:"annotations,omitempty"`
+		Cards       []*Card       `json:"cards,omitempty"`
+	}
+
+	// WebhookDelivery represents a queued webhook delivery
+	// and the result of the most recent delivery attempt.
+	WebhookDelivery struct {
+		ID       int64  `json:"id"`
+		Endpoint string `json:"endpoint"`
+		Event    string `json:"event"`
+		Action   string `json:"action"`
+		Payload  string `json:"payload"`
+		Status   string `json:"status"`
+		Attempts int    `json:"attempts"`
+		Code     int    `json:"response_code,omitempty"`
+		Latency  int64  `json:"latency,omitempty"`
+		Error    string `json:"error,omitempty"`
+		Next     int64  `json:"next,omitempty"`
+		Webhook  int64  `json:"webhook_id,omitempty"`
+		Created  int64  `json:"created"`
+		Updated  int64  `json:"updated"`
 	}
 
 	// WebhookSender sends the webhook payload.
 	WebhookSender interface {
 		// Send sends the webhook to the global endpoint.
 		Send(context.Context, *WebhookData) error
+
+		// Subscribed returns true if at least one endpoint is
+		// subscribed to the webhook event.
+		Subscribed(context.Context, *WebhookData) (bool, error)
+	}
+
+	// WebhookStore manages repository and namespace webhooks.
+	WebhookStore interface {
+		// List returns a list of repository webhooks from
+		// the datastore.
+		List(ctx context.Context, repo int64) ([]*Webhook, error)
+
+		// ListNamespace returns a list of namespace webhooks
+		// from the datastore.
+		ListNamespace(ctx context.Context, namespace string) ([]*Webhook, error)
+
+		// Find returns a webhook from the datastore.
+		Find(ctx context.Context, id int64) (*Webhook, error)
+
+		// Create persists a new webhook to the datastore.
+		Create(ctx context.Context, webhook *Webhook) error
+
+		// Update persists an updated webhook to the datastore.
+		Update(ctx context.Context, webhook *Webhook) error
+
+		// Delete deletes a webhook from the datastore.
+		Delete(ctx context.Context, webhook *Webhook) error
+	}
+
+	// WebhookDeliveryStore persists webhook deliveries.
+	WebhookDeliveryStore interface {
+		// List returns a list of deliveries from the datastore,
+		// ordered by most recent.
+		List(ctx context.Context, limit, offset int) ([]*WebhookDelivery, error)
+
+		// Pending returns a list of pending deliveries from the
+		// datastore scheduled before the given time. The number
+		// of deliveries returned per endpoint is limited, so
+		// that an endpoint with a large backlog does not delay
+		// delivery to other endpoints.
+		Pending(ctx context.Context, before int64) ([]*WebhookDelivery, error)
+
+		// Claim reserves the pending delivery until the given
+		// time, returning false if the delivery was already
+		// claimed by another server.
+		Claim(ctx context.Context, delivery *WebhookDelivery, until int64) (bool, error)
+
+		// Find returns a delivery from the datastore.
+		Find(ctx context.Context, id int64) (*WebhookDelivery, error)
+
+		// Create persists a new delivery to the datastore.
+		Create(ctx context.Context, delivery *WebhookDelivery) error
+
+		// Update persists an updated delivery to the datastore.
+		Update(ctx context.Context, delivery *WebhookDelivery) error
+
+		// Purge deletes completed deliveries from the datastore
+		// last updated before the given time.
+		Purge(ctx context.Context, before int64) error
 	}
 )
+
+// Validate validates the required fields and formats.
+func (w *Webhook) Validate() error {
+	uri, err := url.Parse(w.Endpoint)
+	if err != nil || uri.Host == "" {
+		return errWebhookEndpointInvalid
+	}
+	if uri.Scheme != "http" && uri.Scheme != "https" {
+		return errWebhookEndpointInvalid
+	}
+	for _, event := range w.Events {
+		if !validWebhookEvent(event) {
+			return errWebhookEventInvalid
+		}
+	}
+	return nil
+}
+
+// Match returns true if the webhook is subscribed to the
+// event and action. The webhook subscribes to all events,
+// with the exception of high-volume step events, if the
+// event filter is empty. Filter entries are either an event
+// name (e.g. build), matching all actions, or an event and
+// action pair (e.g. build:created).
+func (w *Webhook) Match(event, action string) bool {
+	if w.Disabled {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return event != WebhookEventStep
+	}
+	for _, filter := range w.Events {
+		if filter == event || filter == event+":"+action {
+			return true
+		}
+	}
+	return false
+}
+
+// Copy makes a copy of the webhook without the secret.
+func (w *Webhook) Copy() *Webhook {
+	return &Webhook{
+		ID:         w.ID,
+		RepoID:     w.RepoID,
+		Namespace:  w.Namespace,
+		Endpoint:   w.Endpoint,
+		SkipVerify: w.SkipVerify,
+		Events:     w.Events,
+		Disabled:   w.Disabled,
+		Created:    w.Created,
+		Updated:    w.Updated,
+	}
+}
+
+// helper function returns true if the event filter is a
+// known event, or event and action pair.
+func validWebhookEvent(filter string) bool {
+	parts := strings.SplitN(filter, ":", 2)
+	switch parts[0] {
+	case WebhookEventBuild,
+		WebhookEventRepo,
+		WebhookEventStage,
+		WebhookEventStep,
+		WebhookEventUser:
+	default:
+		return false
+	}
+	if len(parts) == 1 {
+		return true
+	}
+	switch parts[1] {
+	case WebhookActionCreated,
+		WebhookActionUpdated,
+		WebhookActionDeleted,
+		WebhookActionEnabled,
+		WebhookActionDisabled,
+		WebhookActionStarted,
+		WebhookActionFinished,
+		WebhookActionApproved,
+		WebhookActionDeclined,
+		WebhookActionBlocked:
+		return true
+	default:
+		return false
+	}
+}
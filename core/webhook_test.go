@@ -0,0 +1,101 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package core
+
+import "testing"
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		webhook *Webhook
+		error   error
+	}{
+		{
+			webhook: &Webhook{Endpoint: "https://company.com/hooks"},
+			error:   nil,
+		},
+		{
+			webhook: &Webhook{Endpoint: "http://company.com/hooks", Events: []string{"build", "repo:enabled"}},
+			error:   nil,
+		},
+		{
+			webhook: &Webhook{Endpoint: ""},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &Webhook{Endpoint: "/hooks"},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &Webhook{Endpoint: "ftp://company.com/hooks"},
+			error:   errWebhookEndpointInvalid,
+		},
+		{
+			webhook: &Webhook{Endpoint: "https://company.com/hooks", Events: []string{"push"}},
+			error:   errWebhookEventInvalid,
+		},
+		{
+			webhook: &Webhook{Endpoint: "https://company.com/hooks", Events: []string{"build:merged"}},
+			error:   errWebhookEventInvalid,
+		},
+		{
+			webhook: &Webhook{Endpoint: "https://company.com/hooks", Events: []string{"stage", "step:finished"}},
+			error:   nil,
+		},
+	}
+	for i, test := range tests {
+		got, want := test.webhook.Validate(), test.error
+		if got != want {
+			t.Errorf("Want error %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestWebhookMatch(t *testing.T) {
+	tests := []struct {
+		events   []string
+		disabled bool
+		event    string
+		action   string
+		match    bool
+	}{
+		{nil, false, WebhookEventBuild, WebhookActionCreated, true},
+		{nil, true, WebhookEventBuild, WebhookActionCreated, false},
+		{[]string{"build"}, false, WebhookEventBuild, WebhookActionUpdated, true},
+		{[]string{"build"}, false, WebhookEventRepo, WebhookActionEnabled, false},
+		{[]string{"build:created"}, false, WebhookEventBuild, WebhookActionCreated, true},
+		{[]string{"build:created"}, false, WebhookEventBuild, WebhookActionUpdated, false},
+		{[]string{"build:created", "repo:enabled"}, false, WebhookEventRepo, WebhookActionEnabled, true},
+		{nil, false, WebhookEventStage, WebhookActionStarted, true},
+		{nil, false, WebhookEventStep, WebhookActionStarted, false},
+		{[]string{"step"}, false, WebhookEventStep, WebhookActionFinished, true},
+		{[]string{"step:finished"}, false, WebhookEventStep, WebhookActionStarted, false},
+	}
+	for i, test := range tests {
+		webhook := &Webhook{Events: test.events, Disabled: test.disabled}
+		if got, want := webhook.Match(test.event, test.action), test.match; got != want {
+			t.Errorf("Want match %v, got %v at index %d", want, got, i)
+		}
+	}
+}
+
+func TestWebhookSafeCopy(t *testing.T) {
+	before := Webhook{
+		ID:         1,
+		RepoID:     2,
+		Endpoint:   "https://company.com/hooks",
+		Secret:     "correct-horse-battery-staple",
+		SkipVerify: true,
+		Events:     []string{"build"},
+	}
+	after := before.Copy()
+	if after.Secret != "" {
+		t.Errorf("Expect secret is empty after copy")
+	}
+	if after.Endpoint != before.Endpoint || after.ID != before.ID || !after.SkipVerify {
+		t.Errorf("Expect fields copied")
+	}
+}
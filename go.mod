@@ -33,6 +33,7 @@ require (
 	github.com/gogo/protobuf v0.0.0-20170307180453-100ba4e88506
 	github.com/golang/mock v1.1.1
 	github.com/golang/protobuf v1.2.0
+	github.com/golang/snappy v0.0.1
 	github.com/google/btree v0.0.0-20180813153112-4030bb1f1f0c
 	github.com/google/go-cmp v0.2.0
 	github.com/google/go-jsonnet v0.12.1
@@ -0,0 +1,118 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package acl
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// CheckNamespaceAccess returns an http.Handler middleware that
+// authorizes only system administrators, and users with access to
+// at least one repository in the requested namespace, to proceed
+// to the next handler in the chain.
+func CheckNamespaceAccess(repos core.RepositoryStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				ctx       = r.Context()
+				namespace = chi.URLParam(r, "namespace")
+			)
+			log := logger.FromRequest(r).
+				WithField("namespace", namespace)
+
+			user, ok := request.UserFrom(ctx)
+			switch {
+			case ok == false:
+				render.Unauthorized(w, errors.ErrUnauthorized)
+				log.Debugln("api: authentication required")
+				return
+			case user.Admin == true:
+				log.Debugln("api: root access granted")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			list, err := repos.List(ctx, user.ID)
+			if err != nil {
+				render.InternalError(w, err)
+				log.WithError(err).Warnln("api: cannot list repositories")
+				return
+			}
+
+			for _, repo := range list {
+				if repo.Namespace == namespace {
+					log.Debugln("api: namespace access granted")
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			render.Forbidden(w, errors.ErrForbidden)
+			log.Debugln("api: namespace access required")
+		})
+	}
+}
+
+// CheckNamespaceAdmin returns an http.Handler middleware that
+// authorizes only system administrators, and administrators of
+// the requested organization in the source code management
+// system, to proceed to the next handler in the chain.
+func CheckNamespaceAdmin(orgs core.OrganizationService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				ctx       = r.Context()
+				namespace = chi.URLParam(r, "namespace")
+			)
+			log := logger.FromRequest(r).
+				WithField("namespace", namespace)
+
+			user, ok := request.UserFrom(ctx)
+			switch {
+			case ok == false:
+				render.Unauthorized(w, errors.ErrUnauthorized)
+				log.Debugln("api: authentication required")
+				return
+			case user.Admin == true:
+				log.Debugln("api: root access granted")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			_, admin, err := orgs.Membership(ctx, user, namespace)
+			if err != nil {
+				render.InternalError(w, err)
+				log.WithError(err).Warnln("api: cannot get organization membership")
+				return
+			}
+			if admin == false {
+				render.Forbidden(w, errors.ErrForbidden)
+				log.Debugln("api: organization admin access required")
+				return
+			}
+
+			log.Debugln("api: organization admin access granted")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
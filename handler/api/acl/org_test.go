@@ -0,0 +1,185 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package acl
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestCheckNamespaceAccess(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().List(gomock.Any(), mockUser.ID).Return([]*core.Repository{mockRepo}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	CheckNamespaceAccess(repos)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusTeapot; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheckNamespaceAccess_OtherNamespace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().List(gomock.Any(), mockUser.ID).Return([]*core.Repository{mockRepo}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "spaceghost")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fail()
+	})
+
+	CheckNamespaceAccess(repos)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusForbidden; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheckNamespaceAccess_Guest(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fail()
+	})
+
+	CheckNamespaceAccess(nil)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusUnauthorized; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheckNamespaceAdmin(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	orgs := mock.NewMockOrganizationService(controller)
+	orgs.EXPECT().Membership(gomock.Any(), mockUser, "octocat").Return(true, true, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	CheckNamespaceAdmin(orgs)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusTeapot; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheckNamespaceAdmin_SystemAdmin(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), &core.User{ID: 1, Login: "octocat", Admin: true}), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	CheckNamespaceAdmin(nil)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusTeapot; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// this test verifies that repository admin access does not
+// grant organization admin access.
+func TestCheckNamespaceAdmin_Member(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	orgs := mock.NewMockOrganizationService(controller)
+	orgs.EXPECT().Membership(gomock.Any(), mockUser, "octocat").Return(true, false, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fail()
+	})
+
+	CheckNamespaceAdmin(orgs)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusForbidden; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestCheckNamespaceAdmin_Guest(t *testing.T) {
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(r.Context(), chi.RouteCtxKey, c),
+	)
+
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fail()
+	})
+
+	CheckNamespaceAdmin(nil)(next).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusUnauthorized; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -23,21 +23,33 @@ import (
 	"github.com/drone/drone/handler/api/badge"
 	globalbuilds "github.com/drone/drone/handler/api/builds"
 	"github.com/drone/drone/handler/api/ccmenu"
+	"github.com/drone/drone/handler/api/central"
 	"github.com/drone/drone/handler/api/events"
 	"github.com/drone/drone/handler/api/queue"
 	"github.com/drone/drone/handler/api/repos"
+	"github.com/drone/drone/handler/api/repos/analytics"
 	"github.com/drone/drone/handler/api/repos/builds"
+	"github.com/drone/drone/handler/api/repos/builds/annotations"
+	"github.com/drone/drone/handler/api/repos/builds/artifacts"
 	"github.com/drone/drone/handler/api/repos/builds/logs"
 	"github.com/drone/drone/handler/api/repos/builds/stages"
+	"github.com/drone/drone/handler/api/repos/builds/tests"
+	"github.com/drone/drone/handler/api/repos/caches"
 	"github.com/drone/drone/handler/api/repos/collabs"
 	"github.com/drone/drone/handler/api/repos/crons"
 	"github.com/drone/drone/handler/api/repos/encrypt"
 	"github.com/drone/drone/handler/api/repos/secrets"
 	"github.com/drone/drone/handler/api/repos/sign"
+	reposubscriptions "github.com/drone/drone/handler/api/repos/subscriptions"
+	repowebhooks "github.com/drone/drone/handler/api/repos/webhooks"
 	globalsecrets "github.com/drone/drone/handler/api/secrets"
 	"github.com/drone/drone/handler/api/system"
+	"github.com/drone/drone/handler/api/templates"
 	"github.com/drone/drone/handler/api/user"
+	usersubscriptions "github.com/drone/drone/handler/api/user/subscriptions"
 	"github.com/drone/drone/handler/api/users"
+	"github.com/drone/drone/handler/api/webhooks"
+	"github.com/drone/drone/handler/api/webhooks/deliveries"
 	"github.com/drone/drone/logger"
 
 	"github.com/go-chi/chi"
@@ -55,15 +67,21 @@ var corsOpts = cors.Options{
 }
 
 func New(
+	annotations core.AnnotationStore,
+	artifacts core.ArtifactStore,
 	builds core.BuildStore,
+	caches core.CacheStore,
+	central core.CentralConfigStore,
 	commits core.CommitService,
 	cron core.CronStore,
+	deliveries core.WebhookDeliveryStore,
 	events core.Pubsub,
 	globals core.GlobalSecretStore,
 	hooks core.HookService,
 	logs core.LogStore,
 	license *core.License,
 	licenses core.LicenseService,
+	orgs core.OrganizationService,
 	perms core.PermStore,
 	repos core.RepositoryStore,
 	repoz core.RepositoryService,
@@ -74,66 +92,90 @@ func New(
 	status core.StatusService,
 	session core.Session,
 	stream core.LogStream,
+	subscriptions core.SubscriptionStore,
 	syncer core.Syncer,
 	system *core.System,
+	templates core.TemplateStore,
+	tests core.TestStore,
 	triggerer core.Triggerer,
 	users core.UserStore,
 	webhook core.WebhookSender,
+	webhooks core.WebhookStore,
 ) Server {
 	return Server{
-		Builds:    builds,
-		Cron:      cron,
-		Commits:   commits,
-		Events:    events,
-		Globals:   globals,
-		Hooks:     hooks,
-		Logs:      logs,
-		License:   license,
-		Licenses:  licenses,
-		Perms:     perms,
-		Repos:     repos,
-		Repoz:     repoz,
-		Scheduler: scheduler,
-		Secrets:   secrets,
-		Stages:    stages,
-		Steps:     steps,
-		Status:    status,
-		Session:   session,
-		Stream:    stream,
-		Syncer:    syncer,
-		System:    system,
-		Triggerer: triggerer,
-		Users:     users,
-		Webhook:   webhook,
+		Annotations:   annotations,
+		Artifacts:     artifacts,
+		Builds:        builds,
+		Caches:        caches,
+		Central:       central,
+		Cron:          cron,
+		Commits:       commits,
+		Deliveries:    deliveries,
+		Events:        events,
+		Globals:       globals,
+		Hooks:         hooks,
+		Logs:          logs,
+		License:       license,
+		Licenses:      licenses,
+		Orgs:          orgs,
+		Perms:         perms,
+		Repos:         repos,
+		Repoz:         repoz,
+		Scheduler:     scheduler,
+		Secrets:       secrets,
+		Stages:        stages,
+		Steps:         steps,
+		Status:        status,
+		Session:       session,
+		Stream:        stream,
+		Subscriptions: subscriptions,
+		Syncer:        syncer,
+		System:        system,
+		Templates:     templates,
+		Tests:         tests,
+		Triggerer:     triggerer,
+		Users:         users,
+		Webhook:       webhook,
+		Webhooks:      webhooks,
 	}
 }
 
 // Server is a http.Handler which exposes drone functionality over HTTP.
 type Server struct {
-	Builds    core.BuildStore
-	Cron      core.CronStore
-	Commits   core.CommitService
-	Events    core.Pubsub
-	Globals   core.GlobalSecretStore
-	Hooks     core.HookService
-	Logs      core.LogStore
-	License   *core.License
-	Licenses  core.LicenseService
-	Perms     core.PermStore
-	Repos     core.RepositoryStore
-	Repoz     core.RepositoryService
-	Scheduler core.Scheduler
-	Secrets   core.SecretStore
-	Stages    core.StageStore
-	Steps     core.StepStore
-	Status    core.StatusService
-	Session   core.Session
-	Stream    core.LogStream
-	Syncer    core.Syncer
-	System    *core.System
-	Triggerer core.Triggerer
-	Users     core.UserStore
-	Webhook   core.WebhookSender
+	Annotations   core.AnnotationStore
+	Artifacts     core.ArtifactStore
+	Builds        core.BuildStore
+	Caches        core.CacheStore
+	Central       core.CentralConfigStore
+	Cron          core.CronStore
+	Commits       core.CommitService
+	Deliveries    core.WebhookDeliveryStore
+	Events        core.Pubsub
+	Globals       core.GlobalSecretStore
+	Hooks         core.HookService
+	Logs          core.LogStore
+	License       *core.License
+	Licenses      core.LicenseService
+	Orgs          core.OrganizationService
+	Perms         core.PermStore
+	Repos         core.RepositoryStore
+	Repoz         core.RepositoryService
+	Scheduler     core.Scheduler
+	Secrets       core.SecretStore
+	Stages        core.StageStore
+	Steps         core.StepStore
+	Status        core.StatusService
+	Session       core.Session
+	Stream        core.LogStream
+	Subscriptions core.SubscriptionStore
+	Syncer        core.Syncer
+	System        *core.System
+	Templates     core.TemplateStore
+	Tests         core.TestStore
+	Triggerer     core.Triggerer
+	Users         core.UserStore
+	Webhook       core.WebhookSender
+	Webhooks      core.WebhookStore
 }
 
 // Handler returns an http.Handler
@@ -168,13 +210,32 @@ func (s Server) Handler() http.Handler {
 			acl.CheckAdminAccess(),
 		).Post("/repair", repos.HandleRepair(s.Hooks, s.Repoz, s.Repos, s.Users, s.System.Link))
 
+		r.Get("/tests/history", tests.HandleHistory(s.Repos, s.Tests))
+		r.Get("/analytics", analytics.HandleFind(s.Repos, s.Builds, s.Stages))
+
+		r.Route("/caches", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Get("/", caches.HandleList(s.Repos, s.Caches))
+			r.Delete("/", caches.HandleClear(s.Repos, s.Caches))
+			r.Delete("/*", caches.HandleDelete(s.Repos, s.Caches))
+		})
+
 		r.Route("/builds", func(r chi.Router) {
 			r.With(acl.CheckWriteAccess()).Get("/", builds.HandleList(s.Repos, s.Builds))
 			r.With(acl.CheckWriteAccess()).Post("/", builds.HandleCreate(s.Repos, s.Commits, s.Triggerer))
+			r.With(acl.CheckWriteAccess()).Post("/evaluate", builds.HandleEvaluate(s.Repos, s.Commits, s.Triggerer))
 
 			r.Get("/latest", builds.HandleLast(s.Repos, s.Builds, s.Stages))
 			r.Get("/{number}", builds.HandleFind(s.Repos, s.Builds, s.Stages))
 			r.Get("/{number}/logs/{stage}/{step}", logs.HandleFind(s.Repos, s.Builds, s.Stages, s.Steps, s.Logs))
+			r.Get("/{number}/artifacts", artifacts.HandleList(s.Repos, s.Builds, s.Artifacts))
+			r.Get("/{number}/artifacts/{stage}", artifacts.HandleListStage(s.Repos, s.Builds, s.Stages, s.Artifacts))
+			r.Get("/{number}/artifacts/{stage}/*", artifacts.HandleFind(s.Repos, s.Builds, s.Stages, s.Artifacts))
+			r.Get("/{number}/annotations", annotations.HandleList(s.Repos, s.Builds, s.Annotations))
+			r.Get("/{number}/cards", annotations.HandleListCards(s.Repos, s.Builds, s.Annotations))
+			r.Get("/{number}/tests", tests.HandleSummary(s.Repos, s.Builds, s.Tests))
+			r.Get("/{number}/tests/failed", tests.HandleListFailed(s.Repos, s.Builds, s.Tests))
+			r.Get("/{number}/tests/{stage}", tests.HandleListStage(s.Repos, s.Builds, s.Stages, s.Tests))
 
 			r.With(
 				acl.CheckWriteAccess(),
@@ -194,11 +255,11 @@ func (s Server) Handler() http.Handler {
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Post("/{number}/decline/{stage}", stages.HandleDecline(s.Repos, s.Builds, s.Stages))
+			).Post("/{number}/decline/{stage}", stages.HandleDecline(s.Repos, s.Builds, s.Stages, s.Webhook))
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Post("/{number}/approve/{stage}", stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Scheduler))
+			).Post("/{number}/approve/{stage}", stages.HandleApprove(s.Repos, s.Builds, s.Stages, s.Scheduler, s.Webhook))
 
 			r.With(
 				acl.CheckAdminAccess(),
@@ -206,7 +267,7 @@ func (s Server) Handler() http.Handler {
 
 			r.With(
 				acl.CheckAdminAccess(),
-			).Delete("/", builds.HandlePurge(s.Repos, s.Builds))
+			).Delete("/", builds.HandlePurge(s.Repos, s.Builds, s.Artifacts, s.Tests))
 
 		})
 
@@ -219,6 +280,24 @@ func (s Server) Handler() http.Handler {
 			r.Delete("/{secret}", secrets.HandleDelete(s.Repos, s.Secrets))
 		})
 
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Get("/", repowebhooks.HandleList(s.Repos, s.Webhooks))
+			r.Post("/", repowebhooks.HandleCreate(s.Repos, s.Webhooks))
+			r.Get("/{webhook}", repowebhooks.HandleFind(s.Repos, s.Webhooks))
+			r.Patch("/{webhook}", repowebhooks.HandleUpdate(s.Repos, s.Webhooks))
+			r.Delete("/{webhook}", repowebhooks.HandleDelete(s.Repos, s.Webhooks))
+		})
+
+		r.Route("/subscriptions", func(r chi.Router) {
+			r.Use(acl.CheckAdminAccess())
+			r.Get("/", reposubscriptions.HandleList(s.Repos, s.Subscriptions))
+			r.Post("/", reposubscriptions.HandleCreate(s.Repos, s.Subscriptions))
+			r.Get("/{subscription}", reposubscriptions.HandleFind(s.Repos, s.Subscriptions))
+			r.Patch("/{subscription}", reposubscriptions.HandleUpdate(s.Repos, s.Subscriptions))
+			r.Delete("/{subscription}", reposubscriptions.HandleDelete(s.Repos, s.Subscriptions))
+		})
+
 		r.Route("/sign", func(r chi.Router) {
 			r.Use(acl.CheckWriteAccess())
 			r.Post("/", sign.HandleSign(s.Repos))
@@ -235,6 +314,7 @@ func (s Server) Handler() http.Handler {
 			r.Post("/", crons.HandleCreate(s.Repos, s.Cron))
 			r.Get("/", crons.HandleList(s.Repos, s.Cron))
 			r.Get("/{cron}", crons.HandleFind(s.Repos, s.Cron))
+			r.Post("/{cron}", crons.HandleExec(s.Users, s.Repos, s.Cron, s.Commits, s.Triggerer))
 			r.Patch("/{cron}", crons.HandleUpdate(s.Repos, s.Cron))
 			r.Delete("/{cron}", crons.HandleDelete(s.Repos, s.Cron))
 		})
@@ -261,6 +341,7 @@ func (s Server) Handler() http.Handler {
 		r.Get("/", queue.HandleItems(s.Stages))
 		r.Post("/", queue.HandleResume(s.Scheduler))
 		r.Delete("/", queue.HandlePause(s.Scheduler))
+		r.Post("/drain/{machine}", queue.HandleDrain(s.Scheduler))
 	})
 
 	r.Route("/user", func(r chi.Router) {
@@ -271,6 +352,12 @@ func (s Server) Handler() http.Handler {
 		r.Get("/repos", user.HandleRepos(s.Repos))
 		r.Post("/repos", user.HandleSync(s.Syncer, s.Repos))
 
+		r.Get("/subscriptions", usersubscriptions.HandleList(s.Subscriptions))
+		r.Post("/subscriptions", usersubscriptions.HandleCreate(s.Subscriptions))
+		r.Get("/subscriptions/{subscription}", usersubscriptions.HandleFind(s.Subscriptions))
+		r.Patch("/subscriptions/{subscription}", usersubscriptions.HandleUpdate(s.Subscriptions))
+		r.Delete("/subscriptions/{subscription}", usersubscriptions.HandleDelete(s.Subscriptions))
+
 		// TODO(bradrydzewski) finalize the name for this endpoint.
 		r.Get("/builds", user.HandleRecent(s.Repos))
 		r.Get("/builds/recent", user.HandleRecent(s.Repos))
@@ -313,6 +400,43 @@ func (s Server) Handler() http.Handler {
 		r.Delete("/{namespace}/{name}", globalsecrets.HandleDelete(s.Globals))
 	})
 
+	r.Route("/templates", func(r chi.Router) {
+		r.With(acl.AuthorizeAdmin).Get("/", templates.HandleAll(s.Templates))
+
+		r.Route("/{namespace}", func(r chi.Router) {
+			member := acl.CheckNamespaceAccess(s.Repos)
+			owner := acl.CheckNamespaceAdmin(s.Orgs)
+
+			r.With(member).Get("/", templates.HandleList(s.Templates))
+			r.With(owner).Post("/", templates.HandleCreate(s.Templates))
+			r.With(member).Get("/{name}", templates.HandleFind(s.Templates))
+			r.With(member).Get("/{name}/{version}", templates.HandleFind(s.Templates))
+			r.With(owner).Delete("/{name}/{version}", templates.HandleDelete(s.Templates))
+		})
+	})
+
+	r.Route("/namespaces/{namespace}/config", func(r chi.Router) {
+		r.With(acl.CheckNamespaceAccess(s.Repos)).Get("/", central.HandleFind(s.Central))
+		r.With(acl.CheckNamespaceAdmin(s.Orgs)).Post("/", central.HandleUpdate(s.Central))
+		r.With(acl.CheckNamespaceAdmin(s.Orgs)).Delete("/", central.HandleDelete(s.Central))
+	})
+
+	r.Route("/namespaces/{namespace}/webhooks", func(r chi.Router) {
+		r.Use(acl.CheckNamespaceAdmin(s.Orgs))
+		r.Get("/", webhooks.HandleList(s.Webhooks))
+		r.Post("/", webhooks.HandleCreate(s.Webhooks))
+		r.Get("/{webhook}", webhooks.HandleFind(s.Webhooks))
+		r.Patch("/{webhook}", webhooks.HandleUpdate(s.Webhooks))
+		r.Delete("/{webhook}", webhooks.HandleDelete(s.Webhooks))
+	})
+
+	r.Route("/webhooks/deliveries", func(r chi.Router) {
+		r.Use(acl.AuthorizeAdmin)
+		r.Get("/", deliveries.HandleList(s.Deliveries))
+		r.Get("/{delivery}", deliveries.HandleFind(s.Deliveries))
+		r.Post("/{delivery}", deliveries.HandleRedeliver(s.Deliveries))
+	})
+
 	r.Route("/system", func(r chi.Router) {
 		r.Use(acl.AuthorizeAdmin)
 		// r.Get("/license", system.HandleLicense())
@@ -0,0 +1,31 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes the
+// json-encoded central configuration of the namespace to
+// the response body.
+func HandleFind(configs core.CentralConfigStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := chi.URLParam(r, "namespace")
+		config, err := configs.Find(r.Context(), namespace)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, config, 200)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type centralInput struct {
+	Path string `json:"path"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to create or update the central configuration of
+// the namespace.
+func HandleUpdate(configs core.CentralConfigStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := chi.URLParam(r, "namespace")
+
+		in := new(centralInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		config, err := configs.Find(r.Context(), namespace)
+		if err == sql.ErrNoRows {
+			config = &core.CentralConfig{
+				Namespace: namespace,
+				Created:   time.Now().Unix(),
+			}
+		} else if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		config.Path = in.Path
+		config.Updated = time.Now().Unix()
+
+		err = config.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		if config.ID == 0 {
+			err = configs.Create(r.Context(), config)
+		} else {
+			err = configs.Update(r.Context(), config)
+		}
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, config, 200)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate_Create(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	configs := mock.NewMockCentralConfigStore(controller)
+	configs.EXPECT().Find(gomock.Any(), "octocat").Return(nil, sql.ErrNoRows)
+	configs.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&centralInput{Path: dummyConfig.Path})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(configs).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := new(core.CentralConfig)
+	json.NewDecoder(w.Body).Decode(got)
+	if got.Namespace != "octocat" || got.Path != dummyConfig.Path {
+		t.Errorf("Want central config created for the namespace")
+	}
+}
+
+func TestHandleUpdate_Update(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	config := new(core.CentralConfig)
+	*config = *dummyConfig
+
+	configs := mock.NewMockCentralConfigStore(controller)
+	configs.EXPECT().Find(gomock.Any(), "octocat").Return(config, nil)
+	configs.EXPECT().Update(gomock.Any(), config).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&centralInput{Path: "octocat/drone-config@develop/.drone.yml"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(configs).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := config.Path, "octocat/drone-config@develop/.drone.yml"; got != want {
+		t.Errorf("Want path %q, got %q", want, got)
+	}
+}
+
+func TestHandleUpdate_Invalid(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	configs := mock.NewMockCentralConfigStore(controller)
+	configs.EXPECT().Find(gomock.Any(), "octocat").Return(nil, sql.ErrNoRows)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(&centralInput{Path: "drone-config"})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(configs).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
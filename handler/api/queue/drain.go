@@ -0,0 +1,37 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package queue
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleDrain returns an http.HandlerFunc that processes
+// an http.Request to drain the named runner. The runner
+// stops requesting new stages, and exits once its running
+// stages complete.
+func HandleDrain(scheduler core.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		machine := chi.URLParam(r, "machine")
+		err := scheduler.Drain(ctx, machine)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				WithField("machine", machine).
+				Errorln("api: cannot drain runner")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
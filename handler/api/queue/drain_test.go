@@ -0,0 +1,63 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestDrain(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Drain(gomock.Any(), "agent-1").Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("machine", "agent-1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleDrain(scheduler)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestDrain_Error(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	scheduler := mock.NewMockScheduler(controller)
+	scheduler.EXPECT().Drain(gomock.Any(), "agent-1").Return(errors.New("not implemented"))
+
+	c := new(chi.Context)
+	c.URLParams.Add("machine", "agent-1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleDrain(scheduler)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -31,6 +31,10 @@ func HandleItems(store core.StageStore) http.HandlerFunc {
 	return notImplemented
 }
 
+func HandleDrain(core.Scheduler) http.HandlerFunc {
+	return notImplemented
+}
+
 func HandlePause(core.Scheduler) http.HandlerFunc {
 	return notImplemented
 }
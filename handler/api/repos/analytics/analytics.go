@@ -0,0 +1,103 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// default time window of the analytics endpoint.
+const defaultWindow = 30 * 24 * time.Hour
+
+var errInvalidWindow = errors.New("Invalid time window")
+
+// HandleFind returns an http.HandlerFunc that writes a
+// json-encoded summary of build analytics for the repository
+// to the response body. The results can be narrowed by branch
+// and time window; the pipeline filter applies to the stage
+// durations, queue times and failing steps.
+func HandleFind(
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		until, err := parseTime(r.FormValue("until"), time.Now().Unix())
+		if err != nil {
+			render.BadRequest(w, errInvalidWindow)
+			return
+		}
+		since, err := parseTime(r.FormValue("since"), until-int64(defaultWindow/time.Second))
+		if err != nil || since >= until {
+			render.BadRequest(w, errInvalidWindow)
+			return
+		}
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		filter := &core.AnalyticsFilter{
+			Repo:     repo.ID,
+			Branch:   r.FormValue("branch"),
+			Pipeline: r.FormValue("pipeline"),
+			Since:    since,
+			Until:    until,
+		}
+		buildStats, err := builds.Stats(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		stageStats, err := stages.Stats(r.Context(), filter)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		out := &core.Analytics{
+			Since:    filter.Since,
+			Until:    filter.Until,
+			Branch:   filter.Branch,
+			Pipeline: filter.Pipeline,
+			Builds:   buildStats,
+			Stages:   stageStats,
+		}
+		if buildStats.Total != 0 {
+			out.SuccessRate = float64(buildStats.Passed) / float64(buildStats.Total)
+		}
+		render.JSON(w, out, 200)
+	}
+}
+
+// helper function parses a unix timestamp, returning the
+// default value if the timestamp is empty.
+func parseTime(s string, def int64) (int64, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
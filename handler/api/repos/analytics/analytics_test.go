@@ -0,0 +1,171 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+
+	mockBuildStats = &core.BuildStats{
+		Total:      4,
+		Passed:     3,
+		Failed:     1,
+		Duration:   core.Percentiles{P50: 60, P90: 120, P99: 180},
+		Recoveries: 1,
+		MTTR:       290,
+	}
+
+	mockStageStats = &core.StageStats{
+		Duration:  core.Percentiles{P50: 30, P90: 60, P99: 90},
+		QueueTime: core.Percentiles{P50: 5, P90: 10, P99: 15},
+		FailingSteps: []*core.StepFailure{
+			{Pipeline: "default", Step: "test", Count: 1},
+		},
+	}
+)
+
+func TestFind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	filter := &core.AnalyticsFilter{
+		Repo:     mockRepo.ID,
+		Branch:   "master",
+		Pipeline: "default",
+		Since:    100,
+		Until:    200,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Stats(gomock.Any(), filter).Return(mockBuildStats, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Stats(gomock.Any(), filter).Return(mockStageStats, nil)
+
+	w := httptest.NewRecorder()
+	r := newRequest("/?branch=master&pipeline=default&since=100&until=200")
+
+	HandleFind(repos, builds, stages)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.Analytics), &core.Analytics{
+		Since:       100,
+		Until:       200,
+		Branch:      "master",
+		Pipeline:    "default",
+		SuccessRate: 0.75,
+		Builds:      mockBuildStats,
+		Stages:      mockStageStats,
+	}
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestFind_DefaultWindow(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	var filter *core.AnalyticsFilter
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Stats(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, f *core.AnalyticsFilter) (*core.BuildStats, error) {
+			filter = f
+			return new(core.BuildStats), nil
+		})
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Stats(gomock.Any(), gomock.Any()).Return(new(core.StageStats), nil)
+
+	w := httptest.NewRecorder()
+	r := newRequest("/")
+
+	HandleFind(repos, builds, stages)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := filter.Until-filter.Since, int64(30*24*60*60); got != want {
+		t.Errorf("Want default window of %d seconds, got %d", want, got)
+	}
+}
+
+func TestFind_InvalidWindow(t *testing.T) {
+	for _, target := range []string{
+		"/?since=200&until=100",
+		"/?since=foo",
+		"/?until=bar",
+	} {
+		w := httptest.NewRecorder()
+		r := newRequest(target)
+
+		HandleFind(nil, nil, nil)(w, r)
+		if got, want := w.Code, 400; want != got {
+			t.Errorf("Want response code %d, got %d", want, got)
+		}
+
+		got, want := new(errors.Error), &errors.Error{Message: "Invalid time window"}
+		json.NewDecoder(w.Body).Decode(got)
+		if diff := cmp.Diff(got, want); len(diff) != 0 {
+			t.Errorf(diff)
+		}
+	}
+}
+
+func TestFind_RepoNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(nil, errors.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	r := newRequest("/")
+
+	HandleFind(repos, nil, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+// helper function returns a request with the repository
+// route parameters added to the context.
+func newRequest(target string) *http.Request {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	r := httptest.NewRequest("GET", target, nil)
+	return r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+}
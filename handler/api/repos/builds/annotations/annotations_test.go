@@ -0,0 +1,65 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package annotations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-chi/chi"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+
+	mockBuild = &core.Build{
+		ID:     1,
+		RepoID: 1,
+		Number: 1,
+	}
+
+	mockAnnotations = []*core.Annotation{
+		{
+			ID:       3,
+			BuildID:  1,
+			StageID:  2,
+			StepID:   4,
+			File:     "main.go",
+			Line:     12,
+			Severity: core.SeverityWarning,
+			Message:  "unused variable",
+		},
+	}
+
+	mockCards = []*core.Card{
+		{
+			ID:      5,
+			BuildID: 1,
+			StageID: 2,
+			StepID:  4,
+			Data:    "# Coverage\n\n80%",
+		},
+	}
+)
+
+// helper function returns a request with the build route
+// parameters added to the context.
+func newRequest(number string) *http.Request {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("number", number)
+	r := httptest.NewRequest("GET", "/", nil)
+	return r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifacts
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/drone/drone/core"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+
+	mockBuild = &core.Build{
+		ID:     1,
+		RepoID: 1,
+		Number: 1,
+	}
+
+	mockStage = &core.Stage{
+		ID:      2,
+		BuildID: 1,
+		Number:  1,
+	}
+
+	mockArtifact = &core.Artifact{
+		ID:      3,
+		RepoID:  1,
+		BuildID: 1,
+		StageID: 2,
+		StepID:  4,
+		Name:    "dist/hello-world.tar.gz",
+		Size:    11,
+	}
+
+	mockArtifacts = []*core.Artifact{
+		mockArtifact,
+	}
+)
+
+func mockContents() io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader("hello world"))
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builds
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleEvaluate returns an http.HandlerFunc that processes http
+// requests to evaluate the pipeline configuration for the specified
+// commit, without creating a build. The response includes the
+// stages that would be created, and the reason each remaining
+// pipeline would be skipped.
+func HandleEvaluate(
+	repos core.RepositoryStore,
+	commits core.CommitService,
+	triggerer core.Triggerer,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			ctx       = r.Context()
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+			sha       = r.FormValue("commit")
+			branch    = r.FormValue("branch")
+			ref       = r.FormValue("ref")
+			event     = r.FormValue("event")
+			deploy    = r.FormValue("target")
+			cron      = r.FormValue("cron")
+			user, _   = request.UserFrom(ctx)
+		)
+
+		repo, err := repos.FindName(ctx, namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		// if the user does not provide a branch, assume the
+		// default repository branch.
+		if branch == "" {
+			branch = repo.Branch
+		}
+		// if the user does not provide a reference, expand
+		// the branch to a git reference.
+		if ref == "" {
+			ref = scm.ExpandRef(branch, "refs/heads")
+		}
+		if event == "" {
+			event = core.EventPush
+		}
+
+		var commit *core.Commit
+		if sha != "" {
+			commit, err = commits.Find(ctx, user, repo.Slug, sha)
+		} else {
+			commit, err = commits.FindRef(ctx, user, repo.Slug, ref)
+		}
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		trigger := user.Login
+		if cron != "" {
+			trigger = core.TriggerCron
+		}
+
+		hook := &core.Hook{
+			Trigger:      trigger,
+			Event:        event,
+			Link:         commit.Link,
+			Timestamp:    commit.Author.Date,
+			Message:      commit.Message,
+			Before:       commit.Sha,
+			After:        commit.Sha,
+			Ref:          ref,
+			Source:       branch,
+			Target:       branch,
+			Author:       commit.Author.Login,
+			AuthorName:   commit.Author.Name,
+			AuthorEmail:  commit.Author.Email,
+			AuthorAvatar: commit.Author.Avatar,
+			Deployment:   deploy,
+			Cron:         cron,
+			Sender:       user.Login,
+			Params:       map[string]string{},
+		}
+
+		result, err := triggerer.Evaluate(ctx, repo, hook)
+		if err != nil {
+			render.InternalError(w, err)
+		} else {
+			render.JSON(w, result, 200)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package builds
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEvaluate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockCommit := &core.Commit{
+		Sha:     "cce10d5c4760d1d6ede99db850ab7e77efe15579",
+		Ref:     "refs/heads/develop",
+		Message: "updated README.md",
+		Author: &core.Committer{
+			Login: "octocat",
+		},
+	}
+
+	mockResult := &core.Evaluation{
+		Stages: []*core.Stage{
+			{Name: "test", Status: core.StatusPending},
+		},
+		Skipped: []*core.PipelineSkip{
+			{Name: "deploy", Reason: "does not match event"},
+		},
+	}
+
+	checkHook := func(_ context.Context, _ *core.Repository, hook *core.Hook) {
+		if got, want := hook.Event, core.EventPush; got != want {
+			t.Errorf("Want hook Event %s, got %s", want, got)
+		}
+		if got, want := hook.Ref, mockCommit.Ref; got != want {
+			t.Errorf("Want hook Ref %s, got %s", want, got)
+		}
+		if got, want := hook.Target, "develop"; got != want {
+			t.Errorf("Want hook Target %s, got %s", want, got)
+		}
+		if got, want := hook.After, mockCommit.Sha; got != want {
+			t.Errorf("Want hook After %s, got %s", want, got)
+		}
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	commits := mock.NewMockCommitService(controller)
+	commits.EXPECT().FindRef(gomock.Any(), mockUser, mockRepo.Slug, mockCommit.Ref).Return(mockCommit, nil)
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Evaluate(gomock.Any(), mockRepo, gomock.Any()).Return(mockResult, nil).Do(checkHook)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	params := &url.Values{}
+	params.Set("branch", "develop")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/?"+params.Encode(), nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleEvaluate(repos, commits, triggerer)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(core.Evaluation), mockResult
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestEvaluate_CommitNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
+
+	commits := mock.NewMockCommitService(controller)
+	commits.EXPECT().FindRef(gomock.Any(), mockUser, mockRepo.Slug, "refs/heads/master").Return(nil, errors.ErrNotFound)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
+	)
+
+	HandleEvaluate(repos, commits, nil)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
@@ -17,8 +17,9 @@ import (
 )
 
 // HandlePurge returns an http.HandlerFunc that purges the
-// build history. If successful a 204 status code is returned.
-func HandlePurge(repos core.RepositoryStore, builds core.BuildStore) http.HandlerFunc {
+// build history, build artifacts and test results. If successful
+// a 204 status code is returned.
+func HandlePurge(repos core.RepositoryStore, builds core.BuildStore, artifacts core.ArtifactStore, tests core.TestStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
 			namespace = chi.URLParam(r, "owner")
@@ -35,6 +36,16 @@ func HandlePurge(repos core.RepositoryStore, builds core.BuildStore) http.Handle
 			render.NotFound(w, err)
 			return
 		}
+		err = artifacts.Purge(r.Context(), repo.ID, number)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		err = tests.Purge(r.Context(), repo.ID, number)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
 		err = builds.Purge(r.Context(), repo.ID, number)
 		if err != nil {
 			render.InternalError(w, err)
@@ -28,6 +28,12 @@ func TestPurge(t *testing.T) {
 	repos := mock.NewMockRepositoryStore(controller)
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
 
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
+
+	tests := mock.NewMockTestStore(controller)
+	tests.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
+
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
 
@@ -41,7 +47,7 @@ func TestPurge(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, builds)(w, r)
+	HandlePurge(repos, builds, artifacts, tests)(w, r)
 	if got, want := w.Code, http.StatusNoContent; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -66,7 +72,7 @@ func TestPurge_NotFound(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, nil)(w, r)
+	HandlePurge(repos, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -95,7 +101,7 @@ func TestPurge_BadRequest(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(nil, nil)(w, r)
+	HandlePurge(nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -118,6 +124,12 @@ func TestPurge_InternalError(t *testing.T) {
 	repos := mock.NewMockRepositoryStore(controller)
 	repos.EXPECT().FindName(gomock.Any(), gomock.Any(), mockRepo.Name).Return(mockRepo, nil)
 
+	artifacts := mock.NewMockArtifactStore(controller)
+	artifacts.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
+
+	tests := mock.NewMockTestStore(controller)
+	tests.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(nil)
+
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().Purge(gomock.Any(), mockRepo.ID, int64(50)).Return(errors.ErrNotFound)
 
@@ -131,7 +143,7 @@ func TestPurge_InternalError(t *testing.T) {
 		context.WithValue(request.WithUser(r.Context(), mockUser), chi.RouteCtxKey, c),
 	)
 
-	HandlePurge(repos, builds)(w, r)
+	HandlePurge(repos, builds, artifacts, tests)(w, r)
 	if got, want := w.Code, http.StatusInternalServerError; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
 
 	"github.com/go-chi/chi"
 )
@@ -34,6 +35,7 @@ func HandleApprove(
 	builds core.BuildStore,
 	stages core.StageStore,
 	sched core.Scheduler,
+	sender core.WebhookSender,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -80,6 +82,20 @@ func HandleApprove(
 			render.InternalErrorf(w, "There was a problem scheduling the Pipeline")
 			return
 		}
+		err = sender.Send(r.Context(), &core.WebhookData{
+			Event:  core.WebhookEventStage,
+			Action: core.WebhookActionApproved,
+			Repo:   repo,
+			Build:  build,
+			Stage:  stage,
+		})
+		if err != nil {
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Warnln("api: cannot send webhook")
+		}
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -62,6 +62,19 @@ func TestApprove(t *testing.T) {
 	sched := mock.NewMockScheduler(controller)
 	sched.EXPECT().Schedule(gomock.Any(), mockStage).Return(nil)
 
+	checkWebhook := func(_ context.Context, data *core.WebhookData) error {
+		if got, want := data.Event, core.WebhookEventStage; got != want {
+			t.Errorf("Want webhook event %s, got %s", want, got)
+		}
+		if got, want := data.Action, core.WebhookActionApproved; got != want {
+			t.Errorf("Want webhook action %s, got %s", want, got)
+		}
+		return nil
+	}
+
+	webhook := mock.NewMockWebhookSender(controller)
+	webhook.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(checkWebhook)
+
 	c := new(chi.Context)
 	c.URLParams.Add("owner", "octocat")
 	c.URLParams.Add("name", "hello-world")
@@ -74,7 +87,7 @@ func TestApprove(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, sched)(w, r)
+	HandleApprove(repos, builds, stages, sched, webhook)(w, r)
 	if got, want := w.Code, 204; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -125,7 +138,7 @@ func TestApprove_InvalidStatus(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -153,7 +166,7 @@ func TestApprove_InvalidBuildNumber(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(nil, nil, nil, nil)(w, r)
+	HandleApprove(nil, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -181,7 +194,7 @@ func TestApprove_InvalidStageNumber(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(nil, nil, nil, nil)(w, r)
+	HandleApprove(nil, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 400; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -231,7 +244,7 @@ func TestApprove_StageNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -273,7 +286,7 @@ func TestApprove_BuildNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, nil, nil)(w, r)
+	HandleApprove(repos, builds, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -312,7 +325,7 @@ func TestApprove_RepoNotFound(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, nil, nil, nil)(w, r)
+	HandleApprove(repos, nil, nil, nil, nil)(w, r)
 	if got, want := w.Code, 404; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -370,7 +383,7 @@ func TestApprove_CannotSaveStage(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, nil)(w, r)
+	HandleApprove(repos, builds, stages, nil, nil)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -431,7 +444,7 @@ func TestApprove_CannotEnqueue(t *testing.T) {
 		context.WithValue(context.Background(), chi.RouteCtxKey, c),
 	)
 
-	HandleApprove(repos, builds, stages, sched)(w, r)
+	HandleApprove(repos, builds, stages, sched, nil)(w, r)
 	if got, want := w.Code, 500; want != got {
 		t.Errorf("Want response code %d, got %d", want, got)
 	}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
 
 	"github.com/go-chi/chi"
 )
@@ -31,6 +32,7 @@ func HandleDecline(
 	repos core.RepositoryStore,
 	builds core.BuildStore,
 	stages core.StageStore,
+	sender core.WebhookSender,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var (
@@ -79,6 +81,20 @@ func HandleDecline(
 			render.InternalError(w, err)
 			return
 		}
+		err = sender.Send(r.Context(), &core.WebhookData{
+			Event:  core.WebhookEventStage,
+			Action: core.WebhookActionDeclined,
+			Repo:   repo,
+			Build:  build,
+			Stage:  stage,
+		})
+		if err != nil {
+			logger.FromRequest(r).
+				WithError(err).
+				WithField("namespace", namespace).
+				WithField("name", name).
+				Warnln("api: cannot send webhook")
+		}
 
 		// TODO delete any pending stages from the build queue
 		// TODO update any pending stages to skipped in the database
@@ -0,0 +1,64 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-chi/chi"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+
+	mockBuild = &core.Build{
+		ID:     1,
+		RepoID: 1,
+		Number: 1,
+	}
+
+	mockStage = &core.Stage{
+		ID:      2,
+		BuildID: 1,
+		Number:  1,
+	}
+
+	mockTests = []*core.TestCase{
+		{
+			ID:      3,
+			RepoID:  1,
+			BuildID: 1,
+			StageID: 2,
+			Number:  1,
+			Suite:   "math",
+			Name:    "TestAdd",
+			Status:  core.TestFailed,
+			Message: "expected 2, got 3",
+		},
+	}
+)
+
+// helper function returns a request with the build route
+// parameters added to the context.
+func newRequest(target string, params ...string) *http.Request {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	for i := 0; i+1 < len(params); i += 2 {
+		c.URLParams.Add(params[i], params[i+1])
+	}
+	r := httptest.NewRequest("GET", target, nil)
+	return r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+}
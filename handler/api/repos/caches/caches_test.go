@@ -0,0 +1,51 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package caches
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/drone/drone/core"
+
+	"github.com/go-chi/chi"
+)
+
+var (
+	mockRepo = &core.Repository{
+		ID:        1,
+		Namespace: "octocat",
+		Name:      "hello-world",
+	}
+
+	mockCache = &core.Cache{
+		ID:       2,
+		RepoID:   1,
+		Key:      "node_modules/master",
+		Size:     11,
+		Created:  1556654400,
+		Accessed: 1556654400,
+	}
+
+	mockCaches = []*core.Cache{
+		mockCache,
+	}
+)
+
+// helper function returns a test request with the route
+// context parameters populated.
+func newRequest(method, key string) *http.Request {
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	if key != "" {
+		c.URLParams.Add("*", key)
+	}
+	r := httptest.NewRequest(method, "/", nil)
+	return r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+}
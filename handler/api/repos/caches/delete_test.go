@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package caches
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDelete(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Find(gomock.Any(), mockRepo.ID, mockCache.Key, nil).Return(mockCache, nil)
+	caches.EXPECT().Delete(gomock.Any(), mockCache).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := newRequest("DELETE", mockCache.Key)
+
+	HandleDelete(repos, caches)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestDelete_CacheNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Find(gomock.Any(), mockRepo.ID, mockCache.Key, nil).Return(nil, errors.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	r := newRequest("DELETE", mockCache.Key)
+
+	HandleDelete(repos, caches)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := new(errors.Error), errors.ErrNotFound
+	json.NewDecoder(w.Body).Decode(got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestClear(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), mockRepo.Namespace, mockRepo.Name).Return(mockRepo, nil)
+
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Clear(gomock.Any(), mockRepo.ID).Return(nil)
+
+	w := httptest.NewRecorder()
+	r := newRequest("DELETE", "")
+
+	HandleClear(repos, caches)(w, r)
+	if got, want := w.Code, 204; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
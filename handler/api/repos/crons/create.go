@@ -41,6 +41,8 @@ func HandleCreate(
 		cronjob := new(core.Cron)
 		cronjob.Event = core.EventPush
 		cronjob.Branch = in.Branch
+		cronjob.Target = in.Target
+		cronjob.Params = in.Params
 		cronjob.RepoID = repo.ID
 		cronjob.SetName(in.Name)
 		err = cronjob.SetExpr(in.Expr)
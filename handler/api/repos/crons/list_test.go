@@ -36,6 +36,8 @@ var (
 		Expr:   "* * * * * *",
 		Next:   0,
 		Branch: "master",
+		Target: "production",
+		Params: map[string]string{"nightly": "true"},
 	}
 
 	dummyCronList = []*core.Cron{
@@ -17,9 +17,10 @@ import (
 )
 
 type cronUpdate struct {
-	Branch   *string `json:"branch"`
-	Target   *string `json:"target"`
-	Disabled *bool   `json:"disabled"`
+	Branch   *string            `json:"branch"`
+	Target   *string            `json:"target"`
+	Params   *map[string]string `json:"params"`
+	Disabled *bool              `json:"disabled"`
 }
 
 // HandleUpdate returns an http.HandlerFunc that processes http
@@ -53,6 +54,9 @@ func HandleUpdate(
 		if in.Target != nil {
 			cronjob.Target = *in.Target
 		}
+		if in.Params != nil {
+			cronjob.Params = *in.Params
+		}
 		if in.Disabled != nil {
 			cronjob.Disabled = *in.Disabled
 		}
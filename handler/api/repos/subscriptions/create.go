@@ -0,0 +1,77 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type subscriptionInput struct {
+	Kind     string `json:"kind"`
+	Target   string `json:"target"`
+	Trigger  string `json:"trigger"`
+	Template string `json:"template"`
+	Disabled bool   `json:"disabled"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new repository notification subscription.
+func HandleCreate(
+	repos core.RepositoryStore,
+	subscriptions core.SubscriptionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(subscriptionInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		now := time.Now().Unix()
+		subscription := &core.Subscription{
+			RepoID:   repo.ID,
+			Kind:     in.Kind,
+			Target:   in.Target,
+			Trigger:  in.Trigger,
+			Template: in.Template,
+			Disabled: in.Disabled,
+			Created:  now,
+			Updated:  now,
+		}
+
+		err = subscription.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = subscriptions.Create(r.Context(), subscription)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, subscription, 200)
+	}
+}
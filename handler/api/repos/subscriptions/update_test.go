@@ -0,0 +1,113 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	subscription := &core.Subscription{
+		ID:      1,
+		RepoID:  1,
+		Kind:    core.SubscriptionEmail,
+		Target:  "octocat@github.com",
+		Trigger: core.NotifyAlways,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummySubscriptionRepo.Namespace, dummySubscriptionRepo.Name).Return(dummySubscriptionRepo, nil)
+
+	subscriptions := mock.NewMockSubscriptionStore(controller)
+	subscriptions.EXPECT().Find(gomock.Any(), subscription.ID).Return(subscription, nil)
+	subscriptions.EXPECT().Update(gomock.Any(), subscription).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("subscription", "1")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"disabled": true,
+		"trigger":  "fixed",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(repos, subscriptions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if !subscription.Disabled {
+		t.Errorf("Want subscription disabled")
+	}
+	if got, want := subscription.Target, "octocat@github.com"; got != want {
+		t.Errorf("Want target unchanged")
+	}
+	if got, want := subscription.Trigger, core.NotifyFixed; got != want {
+		t.Errorf("Want trigger %s, got %s", want, got)
+	}
+}
+
+func TestHandleUpdate_ValidationError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	subscription := &core.Subscription{
+		ID:      1,
+		RepoID:  1,
+		Kind:    core.SubscriptionEmail,
+		Target:  "octocat@github.com",
+		Trigger: core.NotifyAlways,
+	}
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().FindName(gomock.Any(), dummySubscriptionRepo.Namespace, dummySubscriptionRepo.Name).Return(dummySubscriptionRepo, nil)
+
+	subscriptions := mock.NewMockSubscriptionStore(controller)
+	subscriptions.EXPECT().Find(gomock.Any(), subscription.ID).Return(subscription, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("owner", "octocat")
+	c.URLParams.Add("name", "hello-world")
+	c.URLParams.Add("subscription", "1")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"target": "octocat",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(repos, subscriptions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusBadRequest; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
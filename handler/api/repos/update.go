@@ -28,14 +28,15 @@ import (
 
 type (
 	repositoryInput struct {
-		Visibility  *string `json:"visibility"`
-		Config      *string `json:"config_path"`
-		Trusted     *bool   `json:"trusted"`
-		Protected   *bool   `json:"protected"`
-		IgnoreForks *bool   `json:"ignore_forks"`
-		IgnorePulls *bool   `json:"ignore_pull_requests"`
-		Timeout     *int64  `json:"timeout"`
-		Counter     *int64  `json:"counter"`
+		Visibility  *string   `json:"visibility"`
+		Config      *string   `json:"config_path"`
+		Fallback    *[]string `json:"config_fallback"`
+		Trusted     *bool     `json:"trusted"`
+		Protected   *bool     `json:"protected"`
+		IgnoreForks *bool     `json:"ignore_forks"`
+		IgnorePulls *bool     `json:"ignore_pull_requests"`
+		Timeout     *int64    `json:"timeout"`
+		Counter     *int64    `json:"counter"`
 	}
 )
 
@@ -77,6 +78,9 @@ func HandleUpdate(repos core.RepositoryStore) http.HandlerFunc {
 		if in.Config != nil {
 			repo.Config = *in.Config
 		}
+		if in.Fallback != nil {
+			repo.Fallback = *in.Fallback
+		}
 		if in.Protected != nil {
 			repo.Protected = *in.Protected
 		}
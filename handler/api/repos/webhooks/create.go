@@ -0,0 +1,77 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type webhookInput struct {
+	Endpoint   string   `json:"endpoint"`
+	Secret     string   `json:"secret"`
+	SkipVerify bool     `json:"skip_verify"`
+	Events     []string `json:"events"`
+	Disabled   bool     `json:"disabled"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new repository webhook.
+func HandleCreate(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		in := new(webhookInput)
+		err = json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		now := time.Now().Unix()
+		webhook := &core.Webhook{
+			RepoID:     repo.ID,
+			Endpoint:   in.Endpoint,
+			Secret:     in.Secret,
+			SkipVerify: in.SkipVerify,
+			Events:     in.Events,
+			Disabled:   in.Disabled,
+			Created:    now,
+			Updated:    now,
+		}
+
+		err = webhook.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = webhooks.Create(r.Context(), webhook)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, webhook.Copy(), 200)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes json-encoded
+// webhook details to the the response body.
+func HandleFind(
+	repos core.RepositoryStore,
+	webhooks core.WebhookStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "owner")
+			name      = chi.URLParam(r, "name")
+		)
+		repo, err := repos.FindName(r.Context(), namespace, name)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		webhook, err := findWebhook(r.Context(), webhooks, repo, chi.URLParam(r, "webhook"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, webhook.Copy(), 200)
+	}
+}
+
+// helper function returns the repository webhook with the
+// given identifier. An error is returned if the webhook does
+// not belong to the repository.
+func findWebhook(ctx context.Context, webhooks core.WebhookStore, repo *core.Repository, param string) (*core.Webhook, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	webhook, err := webhooks.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.RepoID != repo.ID {
+		return nil, errors.ErrNotFound
+	}
+	return webhook, nil
+}
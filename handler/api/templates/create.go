@@ -0,0 +1,60 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type templateInput struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Data    string `json:"data"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new template.
+func HandleCreate(templates core.TemplateStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		in := new(templateInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		t := &core.Template{
+			Namespace: chi.URLParam(r, "namespace"),
+			Name:      in.Name,
+			Version:   in.Version,
+			Data:      in.Data,
+			Created:   time.Now().Unix(),
+			Updated:   time.Now().Unix(),
+		}
+
+		err = t.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = templates.Create(r.Context(), t)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, t, 200)
+	}
+}
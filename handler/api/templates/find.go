@@ -0,0 +1,35 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package templates
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes a json-encoded
+// template to the response body. If the version is omitted from
+// the request path, the latest version is returned.
+func HandleFind(templates core.TemplateStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			name      = chi.URLParam(r, "name")
+			version   = chi.URLParam(r, "version")
+		)
+		template, err := templates.FindName(r.Context(), namespace, name, version)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, template, 200)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+)
+
+type subscriptionInput struct {
+	Kind     string `json:"kind"`
+	Target   string `json:"target"`
+	Trigger  string `json:"trigger"`
+	Template string `json:"template"`
+	Disabled bool   `json:"disabled"`
+}
+
+// HandleCreate returns an http.HandlerFunc that processes http
+// requests to create a new user notification subscription.
+func HandleCreate(
+	subscriptions core.SubscriptionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		in := new(subscriptionInput)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		now := time.Now().Unix()
+		subscription := &core.Subscription{
+			UserID:   viewer.ID,
+			Kind:     in.Kind,
+			Target:   in.Target,
+			Trigger:  in.Trigger,
+			Template: in.Template,
+			Disabled: in.Disabled,
+			Created:  now,
+			Updated:  now,
+		}
+
+		err = subscription.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = subscription.ValidateUser(viewer)
+		if err != nil {
+			render.Forbidden(w, err)
+			return
+		}
+
+		err = subscriptions.Create(r.Context(), subscription)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, subscription, 200)
+	}
+}
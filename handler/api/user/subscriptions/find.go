@@ -0,0 +1,54 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/errors"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleFind returns an http.HandlerFunc that writes json-encoded
+// subscription details to the the response body.
+func HandleFind(
+	subscriptions core.SubscriptionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+		subscription, err := findSubscription(r.Context(), subscriptions, viewer, chi.URLParam(r, "subscription"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+		render.JSON(w, subscription, 200)
+	}
+}
+
+// helper function returns the user subscription with the given
+// identifier. An error is returned if the subscription does not
+// belong to the user.
+func findSubscription(ctx context.Context, subscriptions core.SubscriptionStore, user *core.User, param string) (*core.Subscription, error) {
+	id, err := strconv.ParseInt(param, 10, 64)
+	if err != nil {
+		return nil, errors.ErrNotFound
+	}
+	subscription, err := subscriptions.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.UserID != user.ID || subscription.RepoID != 0 {
+		return nil, errors.ErrNotFound
+	}
+	return subscription, nil
+}
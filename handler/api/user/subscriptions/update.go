@@ -0,0 +1,87 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/handler/api/request"
+
+	"github.com/go-chi/chi"
+)
+
+type subscriptionUpdate struct {
+	Kind     *string `json:"kind"`
+	Target   *string `json:"target"`
+	Trigger  *string `json:"trigger"`
+	Template *string `json:"template"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a user notification subscription.
+func HandleUpdate(
+	subscriptions core.SubscriptionStore,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		viewer, _ := request.UserFrom(r.Context())
+
+		in := new(subscriptionUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		subscription, err := findSubscription(r.Context(), subscriptions, viewer, chi.URLParam(r, "subscription"))
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Kind != nil {
+			subscription.Kind = *in.Kind
+		}
+		if in.Target != nil {
+			subscription.Target = *in.Target
+		}
+		if in.Trigger != nil {
+			subscription.Trigger = *in.Trigger
+		}
+		if in.Template != nil {
+			subscription.Template = *in.Template
+		}
+		if in.Disabled != nil {
+			subscription.Disabled = *in.Disabled
+		}
+		subscription.Updated = time.Now().Unix()
+
+		err = subscription.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = subscription.ValidateUser(viewer)
+		if err != nil {
+			render.Forbidden(w, err)
+			return
+		}
+
+		err = subscriptions.Update(r.Context(), subscription)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, subscription, 200)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package subscriptions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/request"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleUpdate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	subscription := &core.Subscription{
+		ID:      1,
+		UserID:  1,
+		Kind:    core.SubscriptionEmail,
+		Target:  "octocat@github.com",
+		Trigger: core.NotifyAlways,
+	}
+
+	subscriptions := mock.NewMockSubscriptionStore(controller)
+	subscriptions.EXPECT().Find(gomock.Any(), subscription.ID).Return(subscription, nil)
+	subscriptions.EXPECT().Update(gomock.Any(), subscription).Return(nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("subscription", "1")
+
+	in := new(bytes.Buffer)
+	json.NewEncoder(in).Encode(map[string]interface{}{
+		"trigger":  "failure",
+		"template": "{{ .Repo.Slug }} failed",
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("PATCH", "/", in)
+	r = r.WithContext(
+		context.WithValue(request.WithUser(r.Context(), dummyUser), chi.RouteCtxKey, c),
+	)
+
+	HandleUpdate(subscriptions).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := subscription.Trigger, core.NotifyFailure; got != want {
+		t.Errorf("Want trigger %s, got %s", want, got)
+	}
+	if got, want := subscription.Template, "{{ .Repo.Slug }} failed"; got != want {
+		t.Errorf("Want template %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deliveries
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var (
+	mockDelivery = &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Action:   core.WebhookActionCreated,
+		Payload:  `{"action":"created"}`,
+		Status:   core.WebhookDeliveryFailure,
+		Attempts: 5,
+		Code:     502,
+		Latency:  120,
+		Error:    "unexpected status code 502",
+	}
+
+	mockDeliveries = []*core.WebhookDelivery{
+		mockDelivery,
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), 10, 20).Return(mockDeliveries, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/?page=3&per_page=10", nil)
+
+	HandleList(deliveries)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.WebhookDelivery{}, mockDeliveries
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
+
+func TestHandleList_Err(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().List(gomock.Any(), 25, 0).Return(nil, sql.ErrConnDone)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	HandleList(deliveries)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
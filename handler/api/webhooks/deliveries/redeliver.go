@@ -0,0 +1,71 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deliveries
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+	"github.com/drone/drone/logger"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleRedeliver returns an http.HandlerFunc that queues a
+// new delivery of the webhook payload, and writes the json-encoded
+// delivery to the response body.
+func HandleRedeliver(deliveries core.WebhookDeliveryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "delivery"), 10, 64)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+		prev, err := deliveries.Find(r.Context(), id)
+		if err != nil {
+			render.NotFound(w, err)
+			logger.FromRequest(r).WithError(err).
+				WithField("delivery", id).
+				Debugln("api: cannot find webhook delivery")
+			return
+		}
+
+		// the original delivery is left unchanged to preserve
+		// the delivery history.
+		now := time.Now().Unix()
+		delivery := &core.WebhookDelivery{
+			Endpoint: prev.Endpoint,
+			Event:    prev.Event,
+			Action:   prev.Action,
+			Payload:  prev.Payload,
+			Status:   core.WebhookDeliveryPending,
+			Next:     now,
+			Created:  now,
+			Updated:  now,
+		}
+		err = deliveries.Create(r.Context(), delivery)
+		if err != nil {
+			render.InternalError(w, err)
+			logger.FromRequest(r).WithError(err).
+				WithField("delivery", id).
+				Debugln("api: cannot queue webhook delivery")
+		} else {
+			render.JSON(w, delivery, 200)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package deliveries
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+)
+
+func TestHandleRedeliver(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	checkDelivery := func(_ context.Context, delivery *core.WebhookDelivery) error {
+		if got, want := delivery.Endpoint, mockDelivery.Endpoint; got != want {
+			t.Errorf("Want endpoint %s, got %s", want, got)
+		}
+		if got, want := delivery.Payload, mockDelivery.Payload; got != want {
+			t.Errorf("Want payload %s, got %s", want, got)
+		}
+		if got, want := delivery.Status, core.WebhookDeliveryPending; got != want {
+			t.Errorf("Want status %s, got %s", want, got)
+		}
+		if got, want := delivery.Attempts, 0; got != want {
+			t.Errorf("Want attempts %d, got %d", want, got)
+		}
+		delivery.ID = 2
+		return nil
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Find(gomock.Any(), mockDelivery.ID).Return(mockDelivery, nil)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(checkDelivery)
+
+	c := new(chi.Context)
+	c.URLParams.Add("delivery", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRedeliver(deliveries)(w, r)
+	if got, want := w.Code, 200; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got := &core.WebhookDelivery{}
+	json.NewDecoder(w.Body).Decode(got)
+	if got, want := got.ID, int64(2); got != want {
+		t.Errorf("Want delivery id %d, got %d", want, got)
+	}
+	if got, want := mockDelivery.Status, core.WebhookDeliveryFailure; got != want {
+		t.Errorf("Want original delivery unchanged")
+	}
+}
+
+func TestHandleRedeliver_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Find(gomock.Any(), mockDelivery.ID).Return(nil, sql.ErrNoRows)
+
+	c := new(chi.Context)
+	c.URLParams.Add("delivery", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRedeliver(deliveries)(w, r)
+	if got, want := w.Code, 404; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
+
+func TestHandleRedeliver_CreateErr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Find(gomock.Any(), mockDelivery.ID).Return(mockDelivery, nil)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Return(sql.ErrConnDone)
+
+	c := new(chi.Context)
+	c.URLParams.Add("delivery", "1")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleRedeliver(deliveries)(w, r)
+	if got, want := w.Code, 500; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+}
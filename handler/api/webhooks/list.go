@@ -0,0 +1,36 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+// HandleList returns an http.HandlerFunc that writes a json-encoded
+// list of namespace webhooks to the response body.
+func HandleList(webhooks core.WebhookStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		namespace := chi.URLParam(r, "namespace")
+		list, err := webhooks.ListNamespace(r.Context(), namespace)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+		// the webhook list is copied and the webhook secret
+		// is removed from the response.
+		webhooks := []*core.Webhook{}
+		for _, webhook := range list {
+			webhooks = append(webhooks, webhook.Copy())
+		}
+		render.JSON(w, webhooks, 200)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	dummyWebhook = &core.Webhook{
+		ID:        1,
+		Namespace: "octocat",
+		Endpoint:  "https://company.com/hooks",
+		Secret:    "correct-horse-battery-staple",
+		Events:    []string{"build"},
+	}
+
+	dummyWebhookScrubbed = &core.Webhook{
+		ID:        1,
+		Namespace: "octocat",
+		Endpoint:  "https://company.com/hooks",
+		Events:    []string{"build"},
+	}
+)
+
+func TestHandleList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().ListNamespace(gomock.Any(), "octocat").Return([]*core.Webhook{dummyWebhook}, nil)
+
+	c := new(chi.Context)
+	c.URLParams.Add("namespace", "octocat")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(
+		context.WithValue(context.Background(), chi.RouteCtxKey, c),
+	)
+
+	HandleList(webhooks).ServeHTTP(w, r)
+	if got, want := w.Code, http.StatusOK; want != got {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+
+	got, want := []*core.Webhook{}, []*core.Webhook{dummyWebhookScrubbed}
+	json.NewDecoder(w.Body).Decode(&got)
+	if diff := cmp.Diff(got, want); len(diff) != 0 {
+		t.Errorf(diff)
+	}
+}
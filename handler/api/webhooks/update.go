@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/handler/api/render"
+
+	"github.com/go-chi/chi"
+)
+
+type webhookUpdate struct {
+	Endpoint   *string   `json:"endpoint"`
+	Secret     *string   `json:"secret"`
+	SkipVerify *bool     `json:"skip_verify"`
+	Events     *[]string `json:"events"`
+	Disabled   *bool     `json:"disabled"`
+}
+
+// HandleUpdate returns an http.HandlerFunc that processes http
+// requests to update a namespace webhook.
+func HandleUpdate(webhooks core.WebhookStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			namespace = chi.URLParam(r, "namespace")
+			id        = chi.URLParam(r, "webhook")
+		)
+
+		in := new(webhookUpdate)
+		err := json.NewDecoder(r.Body).Decode(in)
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		webhook, err := findWebhook(r.Context(), webhooks, namespace, id)
+		if err != nil {
+			render.NotFound(w, err)
+			return
+		}
+
+		if in.Endpoint != nil {
+			webhook.Endpoint = *in.Endpoint
+		}
+		if in.Secret != nil {
+			webhook.Secret = *in.Secret
+		}
+		if in.SkipVerify != nil {
+			webhook.SkipVerify = *in.SkipVerify
+		}
+		if in.Events != nil {
+			webhook.Events = *in.Events
+		}
+		if in.Disabled != nil {
+			webhook.Disabled = *in.Disabled
+		}
+		webhook.Updated = time.Now().Unix()
+
+		err = webhook.Validate()
+		if err != nil {
+			render.BadRequest(w, err)
+			return
+		}
+
+		err = webhooks.Update(r.Context(), webhook)
+		if err != nil {
+			render.InternalError(w, err)
+			return
+		}
+
+		render.JSON(w, webhook.Copy(), 200)
+	}
+}
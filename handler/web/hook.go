@@ -26,6 +26,7 @@ import (
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/logger"
+	"github.com/drone/drone/trace"
 	"github.com/drone/go-scm/scm"
 )
 
@@ -56,6 +57,9 @@ func HandleHook(
 			os.Stderr.Write(out)
 		}
 
+		traced, span := trace.Start(r.Context(), "hook")
+		defer span.End()
+
 		hook, remote, err := parser.Parse(r, func(slug string) string {
 			namespace, name := scm.Split(slug)
 			repo, err := repos.FindName(r.Context(), namespace, name)
@@ -247,12 +251,19 @@ func HandleHook(
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+		span.SetAttribute("repo", repo.Slug)
+		span.SetAttribute("event", hook.Event)
+
+		// the trigger is detached from the request context to
+		// prevent cancellation if the client disconnects, but
+		// remains part of the request trace.
+		ctx, cancel := context.WithTimeout(trace.Detach(traced), time.Minute*5)
 		ctx = logger.WithContext(ctx, log)
 		defer cancel()
 
 		builds, err := triggerer.Trigger(ctx, repo, hook)
 		if err != nil {
+			span.SetError(err)
 			writeError(w, err)
 			return
 		}
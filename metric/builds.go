@@ -7,6 +7,8 @@
 package metric
 
 import (
+	"context"
+
 	"github.com/drone/drone/core"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -50,3 +52,44 @@ func RunningBuildCount(builds core.BuildStore) {
 		}),
 	)
 }
+
+// BuildDuration provides metrics for build duration. It
+// returns a BuildStore that observes the duration of each
+// build as it is updated to a completed state.
+func BuildDuration(builds core.BuildStore) core.BuildStore {
+	s := &buildDuration{
+		BuildStore: builds,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "drone_build_duration_seconds",
+			Help:    "Build duration in seconds.",
+			Buckets: durationBuckets,
+		}, []string{"status"}),
+	}
+	prometheus.MustRegister(s.duration)
+	return s
+}
+
+type buildDuration struct {
+	core.BuildStore
+	duration *prometheus.HistogramVec
+}
+
+func (s *buildDuration) Update(ctx context.Context, build *core.Build) error {
+	// the stored build is compared to prevent observing
+	// the same build twice when it is updated again.
+	observe := false
+	if build.Started != 0 && build.Finished != 0 {
+		prev, err := s.BuildStore.Find(ctx, build.ID)
+		observe = err == nil && prev.Finished == 0
+	}
+	err := s.BuildStore.Update(ctx, build)
+	if err != nil {
+		return err
+	}
+	if observe {
+		s.duration.WithLabelValues(build.Status).Observe(
+			float64(build.Finished - build.Started),
+		)
+	}
+	return nil
+}
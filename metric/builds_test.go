@@ -135,3 +135,55 @@ func TestBuildRunningCount(t *testing.T) {
 		t.Errorf("Expect metric value %f, got %f", want, got)
 	}
 }
+
+func TestBuildDuration(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	build := &core.Build{
+		ID:       1,
+		Status:   core.StatusFailing,
+		Started:  1556000000,
+		Finished: 1556000090,
+	}
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), build.ID).Return(&core.Build{ID: 1, Started: build.Started}, nil)
+	builds.EXPECT().Find(gomock.Any(), build.ID).Return(build, nil)
+	builds.EXPECT().Update(gomock.Any(), build).Return(nil).Times(2)
+
+	store := BuildDuration(builds)
+	store.Update(noContext, build)
+	store.Update(noContext, build) // duration is not observed twice
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_build_duration_seconds"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Label[0].GetValue(), core.StatusFailing; want != got {
+		t.Errorf("Expect status label %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Histogram.GetSampleCount(), uint64(1); want != got {
+		t.Errorf("Expect sample count %d, got %d", want, got)
+	}
+	if want, got := metric.Metric[0].Histogram.GetSampleSum(), float64(90); want != got {
+		t.Errorf("Expect sample sum %f, got %f", want, got)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+// Config configures the cardinality of the repository and
+// pipeline labels attached to the stage duration metrics.
+type Config struct {
+	// Repos is an optional list of repositories that are
+	// labeled individually. If empty, all repositories are
+	// labeled individually, subject to the limit.
+	Repos []string
+
+	// Limit is the maximum number of distinct repository
+	// and pipeline label pairs. Additional pairs are
+	// labeled as other. A zero value disables the limit.
+	Limit int
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import "sync"
+
+// labelOther is the label value used for repositories and
+// pipelines that exceed the configured cardinality.
+const labelOther = "other"
+
+// labeler bounds the number of distinct repository and
+// pipeline label pairs.
+type labeler struct {
+	sync.Mutex
+
+	repos map[string]struct{}
+	seen  map[[2]string]struct{}
+	limit int
+}
+
+func newLabeler(config Config) *labeler {
+	l := &labeler{
+		seen:  map[[2]string]struct{}{},
+		limit: config.Limit,
+	}
+	if len(config.Repos) != 0 {
+		l.repos = map[string]struct{}{}
+		for _, repo := range config.Repos {
+			l.repos[repo] = struct{}{}
+		}
+	}
+	return l
+}
+
+// labels returns the repository and pipeline label values,
+// or other if the pair exceeds the configured cardinality.
+func (l *labeler) labels(repo, pipeline string) (string, string) {
+	if l.repos != nil {
+		if _, ok := l.repos[repo]; !ok {
+			return labelOther, labelOther
+		}
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	key := [2]string{repo, pipeline}
+	if _, ok := l.seen[key]; ok {
+		return repo, pipeline
+	}
+	if l.limit > 0 && len(l.seen) >= l.limit {
+		return labelOther, labelOther
+	}
+	l.seen[key] = struct{}{}
+	return repo, pipeline
+}
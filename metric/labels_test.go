@@ -0,0 +1,38 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import "testing"
+
+func TestLabeler(t *testing.T) {
+	l := newLabeler(Config{Limit: 2})
+	tests := []struct {
+		repo, pipeline string
+		want           string
+	}{
+		{"octocat/hello-world", "test", "octocat/hello-world"},
+		{"octocat/hello-world", "deploy", "octocat/hello-world"},
+		{"octocat/hello-world", "test", "octocat/hello-world"},
+		{"octocat/spoon-knife", "test", labelOther},
+	}
+	for _, test := range tests {
+		repo, _ := l.labels(test.repo, test.pipeline)
+		if repo != test.want {
+			t.Errorf("Want repo label %s for %s/%s, got %s", test.want, test.repo, test.pipeline, repo)
+		}
+	}
+}
+
+func TestLabeler_Repos(t *testing.T) {
+	l := newLabeler(Config{Repos: []string{"octocat/hello-world"}})
+	if repo, pipeline := l.labels("octocat/hello-world", "test"); repo != "octocat/hello-world" || pipeline != "test" {
+		t.Errorf("Want repository labeled individually, got %s %s", repo, pipeline)
+	}
+	if repo, pipeline := l.labels("octocat/spoon-knife", "test"); repo != labelOther || pipeline != labelOther {
+		t.Errorf("Want repository labeled as other, got %s %s", repo, pipeline)
+	}
+}
@@ -5,3 +5,7 @@
 // +build !oss
 
 package metric
+
+// durationBuckets defines the histogram buckets, in
+// seconds, used to observe build and stage duration.
+var durationBuckets = []float64{30, 60, 120, 300, 600, 900, 1800, 3600, 7200}
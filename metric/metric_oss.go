@@ -25,3 +25,17 @@ func RunningJobCount(core.StageStore)   {}
 func PendingJobCount(core.StageStore)   {}
 func RepoCount(core.RepositoryStore)    {}
 func UserCount(core.UserStore)          {}
+
+func StepUsage(steps core.StepStore) core.StepStore { return steps }
+
+func BuildDuration(builds core.BuildStore) core.BuildStore  { return builds }
+func RequestWait(sched core.Scheduler) core.Scheduler       { return sched }
+func TriggerErrors(triggerer core.Triggerer) core.Triggerer { return triggerer }
+
+func StageDuration(stages core.StageStore, _ core.RepositoryStore, _ Config) core.StageStore {
+	return stages
+}
+
+func WebhookLatency(deliveries core.WebhookDeliveryStore) core.WebhookDeliveryStore {
+	return deliveries
+}
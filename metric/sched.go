@@ -0,0 +1,46 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RequestWait provides metrics for agent request wait time.
+// It returns a Scheduler that observes the time an agent
+// waits for a stage to be assigned.
+func RequestWait(sched core.Scheduler) core.Scheduler {
+	s := &requestWait{
+		Scheduler: sched,
+		wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "drone_agent_request_wait_seconds",
+			Help:    "Time in seconds an agent waits for a stage to be assigned.",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+		}),
+	}
+	prometheus.MustRegister(s.wait)
+	return s
+}
+
+type requestWait struct {
+	core.Scheduler
+	wait prometheus.Histogram
+}
+
+func (s *requestWait) Request(ctx context.Context, filter core.Filter) (*core.Stage, error) {
+	start := time.Now()
+	stage, err := s.Scheduler.Request(ctx, filter)
+	if err == nil && stage != nil {
+		s.wait.Observe(time.Since(start).Seconds())
+	}
+	return stage, err
+}
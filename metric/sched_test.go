@@ -0,0 +1,56 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRequestWait(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	sched := mock.NewMockScheduler(controller)
+	sched.EXPECT().Request(gomock.Any(), gomock.Any()).Return(&core.Stage{ID: 1}, nil)
+	sched.EXPECT().Request(gomock.Any(), gomock.Any()).Return(nil, context.Canceled)
+
+	wrapped := RequestWait(sched)
+	wrapped.Request(noContext, core.Filter{})
+	wrapped.Request(noContext, core.Filter{}) // canceled requests are not observed
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_agent_request_wait_seconds"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Histogram.GetSampleCount(), uint64(1); want != got {
+		t.Errorf("Expect sample count %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// activeWindow is the window in which a repository must
+// have created a build to be counted as active.
+const activeWindow = 24 * time.Hour
+
+// statusWindow is the window in which builds are counted
+// by status and event.
+const statusWindow = 24 * time.Hour
+
+// Collector collects usage metrics from the datastore and
+// writes them to the sinks at a regular interval.
+type Collector struct {
+	users    core.UserStore
+	repos    core.RepositoryStore
+	builds   core.BuildStore
+	stages   core.StageStore
+	interval time.Duration
+	sinks    []Sink
+}
+
+// NewCollector returns a new Collector.
+func NewCollector(
+	users core.UserStore,
+	repos core.RepositoryStore,
+	builds core.BuildStore,
+	stages core.StageStore,
+	interval time.Duration,
+	sinks ...Sink,
+) *Collector {
+	return &Collector{
+		users:    users,
+		repos:    repos,
+		builds:   builds,
+		stages:   stages,
+		interval: interval,
+		sinks:    sinks,
+	}
+}
+
+// Start starts the collector. Metrics are collected and
+// written to the sinks at each interval until the context
+// is canceled.
+func (c *Collector) Start(ctx context.Context) error {
+	if len(c.sinks) == 0 || c.interval <= 0 {
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.do(ctx, time.Now())
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (c *Collector) do(ctx context.Context, now time.Time) {
+	points, err := c.Collect(ctx, now)
+	if err != nil {
+		logrus.WithError(err).Warnln("sink: cannot collect metrics")
+		return
+	}
+	for _, sink := range c.sinks {
+		err := sink.Write(ctx, points)
+		if err != nil {
+			logrus.WithError(err).Warnln("sink: cannot write metrics")
+		}
+	}
+}
+
+// Collect returns the usage metrics at the given time.
+func (c *Collector) Collect(ctx context.Context, now time.Time) ([]*Point, error) {
+	users, err := c.users.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := c.repos.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active, err := c.builds.CountRepos(ctx, now.Add(-activeWindow).Unix())
+	if err != nil {
+		return nil, err
+	}
+	builds, err := c.builds.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := c.builds.CountStatus(ctx, now.Add(-statusWindow).Unix())
+	if err != nil {
+		return nil, err
+	}
+	pending, err := c.stages.ListState(ctx, core.StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	running, err := c.stages.ListState(ctx, core.StatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	// agents are counted by the distinct machines that are
+	// currently executing a stage.
+	machines := map[string]struct{}{}
+	for _, stage := range running {
+		if stage.Machine != "" {
+			machines[stage.Machine] = struct{}{}
+		}
+	}
+
+	points := []*Point{
+		{Name: "users", Value: float64(users), Time: now},
+		{Name: "repos", Value: float64(repos), Time: now},
+		{Name: "repos.active", Value: float64(active), Time: now},
+		{Name: "builds", Value: float64(builds), Time: now},
+		{Name: "stages.pending", Value: float64(len(pending)), Time: now},
+		{Name: "stages.running", Value: float64(len(running)), Time: now},
+		{Name: "agents.busy", Value: float64(len(machines)), Time: now},
+	}
+	for _, count := range counts {
+		points = append(points, &Point{
+			Name:  "builds.status",
+			Value: float64(count.Count),
+			Time:  now,
+			Tags: map[string]string{
+				"status": count.Status,
+				"event":  count.Event,
+			},
+		})
+	}
+	return points, nil
+}
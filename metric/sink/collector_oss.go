@@ -0,0 +1,44 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build oss
+
+package sink
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// Collector defines a no-op metric collector.
+type Collector struct{}
+
+// NewCollector returns a no-op collector.
+func NewCollector(
+	core.UserStore,
+	core.RepositoryStore,
+	core.BuildStore,
+	core.StageStore,
+	time.Duration,
+	...Sink,
+) *Collector {
+	return new(Collector)
+}
+
+// Start starts the collector.
+func (c *Collector) Start(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package sink
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCollect(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	now := time.Unix(915148800, 0)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Count(gomock.Any()).Return(int64(10), nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Count(gomock.Any()).Return(int64(20), nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Count(gomock.Any()).Return(int64(30), nil)
+	builds.EXPECT().CountRepos(gomock.Any(), now.Add(-activeWindow).Unix()).Return(int64(5), nil)
+	builds.EXPECT().CountStatus(gomock.Any(), now.Add(-statusWindow).Unix()).Return([]*core.BuildCount{
+		{Status: core.StatusPassing, Event: core.EventPush, Count: 25},
+		{Status: core.StatusFailing, Event: core.EventPullRequest, Count: 5},
+	}, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusPending).Return([]*core.Stage{{}}, nil)
+	stages.EXPECT().ListState(gomock.Any(), core.StatusRunning).Return([]*core.Stage{
+		{Machine: "agent-1"},
+		{Machine: "agent-1"},
+		{Machine: "agent-2"},
+	}, nil)
+
+	c := NewCollector(users, repos, builds, stages, time.Minute)
+	got, err := c.Collect(noContext, now)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := []*Point{
+		{Name: "users", Value: 10, Time: now},
+		{Name: "repos", Value: 20, Time: now},
+		{Name: "repos.active", Value: 5, Time: now},
+		{Name: "builds", Value: 30, Time: now},
+		{Name: "stages.pending", Value: 1, Time: now},
+		{Name: "stages.running", Value: 3, Time: now},
+		{Name: "agents.busy", Value: 2, Time: now},
+		{Name: "builds.status", Value: 25, Time: now, Tags: map[string]string{"status": "success", "event": "push"}},
+		{Name: "builds.status", Value: 5, Time: now, Tags: map[string]string{"status": "failure", "event": "pull_request"}},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestCollect_Write(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().Count(gomock.Any()).Return(int64(0), nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Count(gomock.Any()).Return(int64(0), nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Count(gomock.Any()).Return(int64(0), nil)
+	builds.EXPECT().CountRepos(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	builds.EXPECT().CountStatus(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	a, b := new(mockSink), new(mockSink)
+	c := NewCollector(users, repos, builds, stages, time.Minute, a, b)
+	c.do(noContext, time.Now())
+
+	if got, want := len(a.points), 7; got != want {
+		t.Errorf("Want %d points written to sink, got %d", want, got)
+	}
+	if got, want := len(b.points), 7; got != want {
+		t.Errorf("Want %d points written to sink, got %d", want, got)
+	}
+}
+
+type mockSink struct {
+	points []*Point
+}
+
+func (s *mockSink) Write(ctx context.Context, points []*Point) error {
+	s.points = append(s.points, points...)
+	return nil
+}
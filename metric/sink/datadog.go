@@ -22,21 +22,24 @@ type payload struct {
 }
 
 type series struct {
-	Metric string    `json:"metric"`
-	Points [][]int64 `json:"points"`
-	Host   string    `json:"host"`
-	Type   string    `json:"type"`
-	Tags   []string  `json:"tags,omitempty"`
+	Metric string      `json:"metric"`
+	Points [][]float64 `json:"points"`
+	Host   string      `json:"host"`
+	Type   string      `json:"type"`
+	Tags   []string    `json:"tags,omitempty"`
 }
 
-// Datadog defines a no-op sink to datadog.
+// datadogInterval is the interval at which usage metrics are
+// collected and written to datadog.
+const datadogInterval = 24 * time.Hour
+
+// Datadog defines a sink to datadog. Usage metrics are
+// collected with the Collector and written to datadog daily.
 type Datadog struct {
-	users  core.UserStore
-	repos  core.RepositoryStore
-	builds core.BuildStore
-	system core.System
-	config Config
-	client *http.Client
+	system    core.System
+	config    Config
+	client    *http.Client
+	collector *Collector
 }
 
 // New returns a Datadog sink.
@@ -44,72 +47,52 @@ func New(
 	users core.UserStore,
 	repos core.RepositoryStore,
 	builds core.BuildStore,
+	stages core.StageStore,
 	system core.System,
 	config Config,
 ) *Datadog {
-	return &Datadog{
-		users:  users,
-		repos:  repos,
-		builds: builds,
+	d := &Datadog{
 		system: system,
 		config: config,
 	}
+	d.collector = NewCollector(users, repos, builds, stages, datadogInterval, d)
+	return d
 }
 
-// Start starts the sink.
+// Start starts the sink. Metrics are collected and written
+// to datadog daily at midnight until the context is canceled.
 func (d *Datadog) Start(ctx context.Context) error {
 	for {
 		diff := midnightDiff()
 		select {
 		case <-time.After(diff):
-			d.do(ctx, time.Now().Unix())
+			d.collector.do(ctx, time.Now())
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
 
-func (d *Datadog) do(ctx context.Context, unix int64) error {
-	users, err := d.users.Count(ctx)
-	if err != nil {
-		return err
-	}
-	repos, err := d.repos.Count(ctx)
-	if err != nil {
-		return err
-	}
-	builds, err := d.builds.Count(ctx)
-	if err != nil {
-		return err
-	}
+// Write writes the points to datadog.
+func (d *Datadog) Write(ctx context.Context, points []*Point) error {
 	tags := createTags(d.config)
 	data := new(payload)
-	data.Series = []series{
-		{
-			Metric: "drone.users",
-			Points: [][]int64{[]int64{unix, users}},
-			Type:   "gauge",
-			Host:   d.system.Host,
-			Tags:   tags,
-		},
-		{
-			Metric: "drone.repos",
-			Points: [][]int64{[]int64{unix, repos}},
-			Type:   "gauge",
-			Host:   d.system.Host,
-			Tags:   tags,
-		},
-		{
-			Metric: "drone.builds",
-			Points: [][]int64{[]int64{unix, builds}},
+	for _, point := range points {
+		item := series{
+			Metric: join("drone", point.Name, "."),
+			Points: [][]float64{{float64(point.Time.Unix()), point.Value}},
 			Type:   "gauge",
 			Host:   d.system.Host,
-			Tags:   tags,
-		},
+			Tags:   append([]string(nil), tags...),
+		}
+		for _, k := range tagKeys(point) {
+			item.Tags = append(item.Tags, k+":"+point.Tags[k])
+		}
+		data.Series = append(data.Series, item)
 	}
 
 	buf := new(bytes.Buffer)
-	err = json.NewEncoder(buf).Encode(data)
+	err := json.NewEncoder(buf).Encode(data)
 	if err != nil {
 		return err
 	}
@@ -119,10 +102,11 @@ func (d *Datadog) do(ctx context.Context, unix int64) error {
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
 
-	res, err := httpClient.Do(req)
+	res, err := d.Client().Do(req)
 	if err != nil {
 		return err
 	}
@@ -146,14 +130,3 @@ func midnightDiff() time.Duration {
 	b := time.Date(a.Year(), a.Month(), a.Day()+1, 0, 0, 0, 0, a.Location())
 	return b.Sub(a)
 }
-
-// httpClient should be used for HTTP requests. It
-// is configured with a timeout for reliability.
-var httpClient = &http.Client{
-	Transport: &http.Transport{
-		Proxy:               http.ProxyFromEnvironment,
-		TLSHandshakeTimeout: 30 * time.Second,
-		DisableKeepAlives:   true,
-	},
-	Timeout: 1 * time.Minute,
-}
@@ -30,6 +30,7 @@ func New(
 	core.UserStore,
 	core.RepositoryStore,
 	core.BuildStore,
+	core.StageStore,
 	core.System,
 	Config,
 ) *Datadog {
@@ -40,3 +41,8 @@ func New(
 func (d *Datadog) Start(ctx context.Context) error {
 	return nil
 }
+
+// Write writes the points to the sink.
+func (d *Datadog) Write(context.Context, []*Point) error {
+	return nil
+}
@@ -8,17 +8,22 @@ package sink
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/drone/drone/core"
 	"github.com/drone/drone/mock"
 	"github.com/drone/drone/version"
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"github.com/h2non/gock"
 )
 
 var noContext = context.Background()
 
-func TestDo(t *testing.T) {
+func TestDatadog(t *testing.T) {
 	controller := gomock.NewController(t)
 
 	gock.InterceptClient(httpClient)
@@ -28,6 +33,8 @@ func TestDo(t *testing.T) {
 		controller.Finish()
 	}()
 
+	now := time.Unix(915148800, 0)
+
 	users := mock.NewMockUserStore(controller)
 	users.EXPECT().Count(gomock.Any()).Return(int64(10), nil)
 
@@ -36,50 +43,58 @@ func TestDo(t *testing.T) {
 
 	builds := mock.NewMockBuildStore(controller)
 	builds.EXPECT().Count(gomock.Any()).Return(int64(30), nil)
+	builds.EXPECT().CountRepos(gomock.Any(), gomock.Any()).Return(int64(5), nil)
+	builds.EXPECT().CountStatus(gomock.Any(), gomock.Any()).Return([]*core.BuildCount{
+		{Status: core.StatusPassing, Event: core.EventPush, Count: 25},
+	}, nil)
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().ListState(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	tags := []string{
+		"version:" + version.Version.String(),
+		"remote:github:cloud",
+		"scheduler:internal:agents",
+		"license:trial",
+	}
+	want := []series{
+		{Metric: "drone.users", Points: [][]float64{{915148800, 10}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.repos", Points: [][]float64{{915148800, 20}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.repos.active", Points: [][]float64{{915148800, 5}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.builds", Points: [][]float64{{915148800, 30}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.stages.pending", Points: [][]float64{{915148800, 0}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.stages.running", Points: [][]float64{{915148800, 0}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.agents.busy", Points: [][]float64{{915148800, 0}}, Type: "gauge", Host: "test.example.com", Tags: tags},
+		{Metric: "drone.builds.status", Points: [][]float64{{915148800, 25}}, Type: "gauge", Host: "test.example.com", Tags: append(tags[:len(tags):len(tags)], "event:push", "status:success")},
+	}
+
+	matchSeries := func(r *http.Request, _ *gock.Request) (bool, error) {
+		got := new(payload)
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			return false, err
+		}
+		if diff := cmp.Diff(got.Series, want); diff != "" {
+			t.Errorf(diff)
+			return false, nil
+		}
+		return true, nil
+	}
 
 	gock.New("https://api.datadoghq.com").
 		Post("/api/v1/series").
-		JSON(sample).
+		SetMatcher(gock.NewMatcher()).
+		AddMatcher(matchSeries).
 		Reply(200)
 
-	d := new(Datadog)
-	d.users = users
-	d.repos = repos
-	d.builds = builds
-	d.system.Host = "test.example.com"
-	d.config.License = "trial"
-	d.config.EnableGithub = true
-	d.config.EnableAgents = true
-	d.config.Endpoint = "https://api.datadoghq.com/api/v1/series"
-	d.do(noContext, 915148800)
+	d := New(users, repos, builds, stages, core.System{Host: "test.example.com"}, Config{
+		Endpoint:     "https://api.datadoghq.com/api/v1/series",
+		License:      "trial",
+		EnableGithub: true,
+		EnableAgents: true,
+	})
+	d.collector.do(noContext, now)
 
 	if gock.IsPending() {
 		t.Errorf("Unfinished requests")
 	}
 }
-
-var sample = `{
-	"series" : [
-		{
-			"metric": "drone.users",
-			"points": [[915148800, 10]],
-			"type": "gauge",
-			"host": "test.example.com",
-			"tags": ["version:` + version.Version.String() + `","remote:github:cloud","scheduler:internal:agents","license:trial"]
-		},
-		{
-			"metric": "drone.repos",
-			"points": [[915148800, 20]],
-			"type": "gauge",
-			"host": "test.example.com",
-			"tags": ["version:` + version.Version.String() + `","remote:github:cloud","scheduler:internal:agents","license:trial"]
-		},
-		{
-			"metric": "drone.builds",
-			"points": [[915148800, 30]],
-			"type": "gauge",
-			"host": "test.example.com",
-			"tags": ["version:` + version.Version.String() + `","remote:github:cloud","scheduler:internal:agents","license:trial"]
-		}
-    ]
-}`
@@ -0,0 +1,181 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OTLP returns a Sink that writes gauges to an OpenTelemetry
+// collector using the OTLP/HTTP protocol with json encoding.
+func OTLP(endpoint, prefix string, headers map[string]string) Sink {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/metrics") {
+		endpoint = endpoint + "/v1/metrics"
+	}
+	return &otlp{
+		endpoint: endpoint,
+		prefix:   prefix,
+		headers:  headers,
+	}
+}
+
+type otlp struct {
+	client   *http.Client
+	endpoint string
+	prefix   string
+	headers  map[string]string
+}
+
+// Write writes the points to the collector.
+func (s *otlp) Write(ctx context.Context, points []*Point) error {
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(s.convert(points))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.endpoint, buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := s.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("sink: collector responded with status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Client returns the http client. If no custom
+// client is provided, the default client is used.
+func (s *otlp) Client() *http.Client {
+	if s.client == nil {
+		return httpClient
+	}
+	return s.client
+}
+
+//
+// otlp json encoding
+//
+
+type (
+	otlpRequest struct {
+		ResourceMetrics []*otlpResourceMetrics `json:"resourceMetrics"`
+	}
+
+	otlpResourceMetrics struct {
+		Resource     *otlpResource       `json:"resource"`
+		ScopeMetrics []*otlpScopeMetrics `json:"scopeMetrics"`
+	}
+
+	otlpResource struct {
+		Attributes []*otlpKeyValue `json:"attributes"`
+	}
+
+	otlpScopeMetrics struct {
+		Scope   *otlpScope    `json:"scope"`
+		Metrics []*otlpMetric `json:"metrics"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpMetric struct {
+		Name  string     `json:"name"`
+		Gauge *otlpGauge `json:"gauge"`
+	}
+
+	otlpGauge struct {
+		DataPoints []*otlpDataPoint `json:"dataPoints"`
+	}
+
+	otlpDataPoint struct {
+		Attributes   []*otlpKeyValue `json:"attributes,omitempty"`
+		TimeUnixNano string          `json:"timeUnixNano"`
+		AsDouble     float64         `json:"asDouble"`
+	}
+
+	otlpKeyValue struct {
+		Key   string        `json:"key"`
+		Value *otlpAnyValue `json:"value"`
+	}
+
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+func (s *otlp) convert(points []*Point) *otlpRequest {
+	// points are grouped by metric name, in the order in
+	// which each metric name first appears.
+	var metrics []*otlpMetric
+	index := map[string]*otlpMetric{}
+	for _, point := range points {
+		name := join(s.prefix, point.Name, ".")
+		metric, ok := index[name]
+		if !ok {
+			metric = &otlpMetric{
+				Name:  name,
+				Gauge: new(otlpGauge),
+			}
+			index[name] = metric
+			metrics = append(metrics, metric)
+		}
+		data := &otlpDataPoint{
+			TimeUnixNano: strconv.FormatInt(point.Time.UnixNano(), 10),
+			AsDouble:     point.Value,
+		}
+		for _, k := range tagKeys(point) {
+			data.Attributes = append(data.Attributes, &otlpKeyValue{
+				Key:   k,
+				Value: &otlpAnyValue{StringValue: point.Tags[k]},
+			})
+		}
+		metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, data)
+	}
+	return &otlpRequest{
+		ResourceMetrics: []*otlpResourceMetrics{
+			{
+				Resource: &otlpResource{
+					Attributes: []*otlpKeyValue{
+						{Key: "service.name", Value: &otlpAnyValue{StringValue: "drone-server"}},
+					},
+				},
+				ScopeMetrics: []*otlpScopeMetrics{
+					{
+						Scope:   &otlpScope{Name: "github.com/drone/drone/metric/sink"},
+						Metrics: metrics,
+					},
+				},
+			},
+		},
+	}
+}
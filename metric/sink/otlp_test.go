@@ -0,0 +1,96 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sink
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/h2non/gock"
+)
+
+func TestOTLP(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://collector:4318").
+		Post("/v1/metrics").
+		MatchHeader("Authorization", "Bearer secret").
+		JSON(otlpSample).
+		Reply(200)
+
+	s := OTLP("http://collector:4318/", "drone", map[string]string{"Authorization": "Bearer secret"}).(*otlp)
+	s.client = new(http.Client)
+	gock.InterceptClient(s.client)
+
+	now := time.Unix(915148800, 0)
+	points := []*Point{
+		{Name: "users", Value: 10, Time: now},
+		{Name: "builds.status", Value: 2, Time: now, Tags: map[string]string{"status": "success", "event": "push"}},
+		{Name: "builds.status", Value: 1, Time: now, Tags: map[string]string{"status": "failure", "event": "push"}},
+	}
+	if err := s.Write(context.Background(), points); err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestOTLP_Error(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://collector:4318").
+		Post("/v1/metrics").
+		Reply(500)
+
+	s := OTLP("http://collector:4318/v1/metrics", "drone", nil).(*otlp)
+	s.client = new(http.Client)
+	gock.InterceptClient(s.client)
+
+	err := s.Write(context.Background(), []*Point{{Name: "users", Value: 10}})
+	if err == nil {
+		t.Errorf("Expect error when the collector returns a non-2xx status")
+	}
+}
+
+var otlpSample = `{
+	"resourceMetrics": [{
+		"resource": {
+			"attributes": [{"key": "service.name", "value": {"stringValue": "drone-server"}}]
+		},
+		"scopeMetrics": [{
+			"scope": {"name": "github.com/drone/drone/metric/sink"},
+			"metrics": [
+				{
+					"name": "drone.users",
+					"gauge": {"dataPoints": [{"timeUnixNano": "915148800000000000", "asDouble": 10}]}
+				},
+				{
+					"name": "drone.builds.status",
+					"gauge": {"dataPoints": [
+						{
+							"attributes": [
+								{"key": "event", "value": {"stringValue": "push"}},
+								{"key": "status", "value": {"stringValue": "success"}}
+							],
+							"timeUnixNano": "915148800000000000",
+							"asDouble": 2
+						},
+						{
+							"attributes": [
+								{"key": "event", "value": {"stringValue": "push"}},
+								{"key": "status", "value": {"stringValue": "failure"}}
+							],
+							"timeUnixNano": "915148800000000000",
+							"asDouble": 1
+						}
+					]}
+				}
+			]
+		}]
+	}]
+}`
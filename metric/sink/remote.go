@@ -0,0 +1,147 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/golang/snappy"
+)
+
+// RemoteWrite returns a Sink that writes gauges to a
+// Prometheus remote write endpoint.
+func RemoteWrite(endpoint, prefix string, headers map[string]string) Sink {
+	return &remote{
+		endpoint: endpoint,
+		prefix:   prefix,
+		headers:  headers,
+	}
+}
+
+type remote struct {
+	client   *http.Client
+	endpoint string
+	prefix   string
+	headers  map[string]string
+}
+
+// Write writes the points to the remote write endpoint.
+func (s *remote) Write(ctx context.Context, points []*Point) error {
+	body := snappy.Encode(nil, s.encode(points))
+	req, err := http.NewRequest("POST", s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := s.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("sink: remote write responded with status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Client returns the http client. If no custom
+// client is provided, the default client is used.
+func (s *remote) Client() *http.Client {
+	if s.client == nil {
+		return httpClient
+	}
+	return s.client
+}
+
+//
+// remote write protobuf encoding
+//
+
+// encode returns the points encoded as a protobuf
+// prometheus.WriteRequest message.
+func (s *remote) encode(points []*Point) []byte {
+	req := new(protoBuffer)
+	for _, point := range points {
+		labels := []label{
+			{"__name__", join(s.prefix, point.Name, "_")},
+		}
+		for k, v := range point.Tags {
+			labels = append(labels, label{k, v})
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			return labels[i].name < labels[j].name
+		})
+
+		series := new(protoBuffer)
+		for _, l := range labels {
+			msg := new(protoBuffer)
+			msg.bytes(1, []byte(l.name))
+			msg.bytes(2, []byte(l.value))
+			series.bytes(1, msg.buf)
+		}
+		sample := new(protoBuffer)
+		sample.double(1, point.Value)
+		sample.varint(2, uint64(point.Time.UnixNano()/1e6))
+		series.bytes(2, sample.buf)
+
+		req.bytes(1, series.buf)
+	}
+	return req.buf
+}
+
+type label struct {
+	name, value string
+}
+
+// protoBuffer provides minimal protobuf encoding.
+type protoBuffer struct {
+	buf []byte
+}
+
+func (b *protoBuffer) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	b.buf = append(b.buf, tmp[:n]...)
+}
+
+func (b *protoBuffer) varint(field int, v uint64) {
+	b.uvarint(uint64(field)<<3 | 0)
+	b.uvarint(v)
+}
+
+func (b *protoBuffer) double(field int, v float64) {
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(v))
+	b.uvarint(uint64(field)<<3 | 1)
+	b.buf = append(b.buf, tmp[:]...)
+}
+
+func (b *protoBuffer) bytes(field int, v []byte) {
+	b.uvarint(uint64(field)<<3 | 2)
+	b.uvarint(uint64(len(v)))
+	b.buf = append(b.buf, v...)
+}
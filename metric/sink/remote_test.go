@@ -0,0 +1,134 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/h2non/gock"
+)
+
+func TestRemoteWrite(t *testing.T) {
+	defer gock.Off()
+
+	s := RemoteWrite("http://prometheus:9090/api/v1/write", "drone", nil).(*remote)
+	s.client = new(http.Client)
+	gock.InterceptClient(s.client)
+
+	points := []*Point{{Name: "users", Value: 10}}
+
+	// the request body is the snappy compressed write request.
+	matchBody := func(r *http.Request, _ *gock.Request) (bool, error) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return false, err
+		}
+		data, err := snappy.Decode(nil, body)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(data, s.encode(points)), nil
+	}
+
+	gock.New("http://prometheus:9090").
+		Post("/api/v1/write").
+		MatchHeader("Content-Encoding", "snappy").
+		MatchHeader("Content-Type", "application/x-protobuf").
+		MatchHeader("X-Prometheus-Remote-Write-Version", "0.1.0").
+		SetMatcher(gock.NewMatcher()).
+		AddMatcher(matchBody).
+		Reply(204)
+
+	err := s.Write(context.Background(), points)
+	if err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRemoteWrite_Encode(t *testing.T) {
+	s := &remote{prefix: "drone"}
+	points := []*Point{
+		{
+			Name:  "builds.status",
+			Value: 2,
+			Time:  time.Unix(915148800, 0),
+			Tags:  map[string]string{"status": "success", "event": "push"},
+		},
+	}
+
+	// decode the write request to verify each field is
+	// encoded using the expected field number and type.
+	req := decodeMessage(t, s.encode(points))
+	if got, want := len(req[1]), 1; got != want {
+		t.Errorf("Want %d timeseries, got %d", want, got)
+		return
+	}
+	series := decodeMessage(t, req[1][0].([]byte))
+
+	var labels [][]string
+	for _, raw := range series[1] {
+		label := decodeMessage(t, raw.([]byte))
+		labels = append(labels, []string{
+			string(label[1][0].([]byte)),
+			string(label[2][0].([]byte)),
+		})
+	}
+	want := [][]string{
+		{"__name__", "drone_builds_status"},
+		{"event", "push"},
+		{"status", "success"},
+	}
+	if diff := cmp.Diff(labels, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	sample := decodeMessage(t, series[2][0].([]byte))
+	if got, want := math.Float64frombits(sample[1][0].(uint64)), float64(2); got != want {
+		t.Errorf("Want sample value %v, got %v", want, got)
+	}
+	if got, want := sample[2][0].(uint64), uint64(915148800000); got != want {
+		t.Errorf("Want sample timestamp %d, got %d", want, got)
+	}
+}
+
+// decodeMessage decodes a protobuf message into a map of
+// field values, keyed by field number.
+func decodeMessage(t *testing.T, b []byte) map[uint64][]interface{} {
+	out := map[uint64][]interface{}{}
+	buf := proto.NewBuffer(b)
+	for {
+		key, err := buf.DecodeVarint()
+		if err != nil {
+			return out
+		}
+		var v interface{}
+		switch key & 7 {
+		case 0:
+			v, err = buf.DecodeVarint()
+		case 1:
+			v, err = buf.DecodeFixed64()
+		case 2:
+			v, err = buf.DecodeRawBytes(true)
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[key>>3] = append(out[key>>3], v)
+	}
+}
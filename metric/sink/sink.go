@@ -0,0 +1,68 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Point represents a single gauge sample.
+type Point struct {
+	Name  string
+	Value float64
+	Tags  map[string]string
+	Time  time.Time
+}
+
+// Sink writes metric points to an external monitoring
+// system.
+type Sink interface {
+	// Write writes the points to the sink.
+	Write(context.Context, []*Point) error
+}
+
+// tagKeys returns the point tag keys in sorted order.
+func tagKeys(point *Point) []string {
+	var keys []string
+	for k := range point.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// join joins the metric prefix and name using the
+// separator.
+func join(prefix, name, sep string) string {
+	if prefix == "" {
+		return strings.Replace(name, ".", sep, -1)
+	}
+	return strings.Replace(prefix+"."+name, ".", sep, -1)
+}
+
+// httpClient should be used for HTTP requests. It
+// is configured with a timeout for reliability.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		TLSHandshakeTimeout: 30 * time.Second,
+		DisableKeepAlives:   true,
+	},
+	Timeout: 1 * time.Minute,
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// maxPacketSize is the maximum statsd packet size. It is
+// chosen to avoid fragmentation on common networks.
+const maxPacketSize = 1432
+
+// StatsD returns a Sink that writes gauges to a StatsD
+// server over udp. Tags are written using the DogStatsD
+// format, which is supported by most StatsD servers.
+func StatsD(address, prefix string) Sink {
+	return &statsd{
+		address: address,
+		prefix:  prefix,
+	}
+}
+
+type statsd struct {
+	address string
+	prefix  string
+}
+
+// Write writes the points to the StatsD server.
+func (s *statsd) Write(ctx context.Context, points []*Point) error {
+	conn, err := net.Dial("udp", s.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	buf := new(bytes.Buffer)
+	for _, point := range points {
+		line := s.format(point)
+		if buf.Len() != 0 && buf.Len()+len(line)+1 > maxPacketSize {
+			if _, err := conn.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+		if buf.Len() != 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(line)
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err = conn.Write(buf.Bytes())
+	return err
+}
+
+// format returns the point in the statsd line format.
+func (s *statsd) format(point *Point) string {
+	line := join(s.prefix, point.Name, ".") + ":" +
+		strconv.FormatFloat(point.Value, 'f', -1, 64) + "|g"
+	if len(point.Tags) == 0 {
+		return line
+	}
+	var tags []string
+	for _, k := range tagKeys(point) {
+		tags = append(tags, k+":"+point.Tags[k])
+	}
+	return line + "|#" + strings.Join(tags, ",")
+}
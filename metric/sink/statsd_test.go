@@ -0,0 +1,50 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package sink
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestStatsD(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	points := []*Point{
+		{Name: "users", Value: 10},
+		{Name: "builds.status", Value: 2, Tags: map[string]string{"status": "success", "event": "push"}},
+	}
+	sink := StatsD(conn.LocalAddr().String(), "drone")
+	if err := sink.Write(context.Background(), points); err != nil {
+		t.Error(err)
+		return
+	}
+
+	buf := make([]byte, maxPacketSize)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := "drone.users:10|g\ndrone.builds.status:2|g|#event:push,status:success"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("Want packet %q, got %q", want, got)
+	}
+}
+
+func TestStatsD_Format(t *testing.T) {
+	s := &statsd{prefix: "drone"}
+	point := &Point{Name: "stages.pending", Value: 1.5}
+	if got, want := s.format(point), "drone.stages.pending:1.5|g"; got != want {
+		t.Errorf("Want line %q, got %q", want, got)
+	}
+}
@@ -7,6 +7,8 @@
 package metric
 
 import (
+	"context"
+
 	"github.com/drone/drone/core"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,3 +39,72 @@ func PendingJobCount(stages core.StageStore) {
 		}),
 	)
 }
+
+// StageDuration provides metrics for stage duration and
+// queue wait time. It returns a StageStore that observes
+// the time each stage waits in the queue before it starts,
+// and the duration of each stage once it completes, labeled
+// by repository and pipeline name.
+func StageDuration(stages core.StageStore, repos core.RepositoryStore, config Config) core.StageStore {
+	s := &stageDuration{
+		StageStore: stages,
+		repos:      repos,
+		labeler:    newLabeler(config),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "drone_stage_duration_seconds",
+			Help:    "Stage duration in seconds.",
+			Buckets: durationBuckets,
+		}, []string{"repo", "pipeline"}),
+		wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "drone_stage_queue_wait_seconds",
+			Help:    "Time in seconds a stage waits in the queue before it starts.",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600},
+		}),
+	}
+	prometheus.MustRegister(s.duration, s.wait)
+	return s
+}
+
+type stageDuration struct {
+	core.StageStore
+	repos    core.RepositoryStore
+	labeler  *labeler
+	duration *prometheus.HistogramVec
+	wait     prometheus.Histogram
+}
+
+func (s *stageDuration) Update(ctx context.Context, stage *core.Stage) error {
+	// the stored stage is compared to prevent observing
+	// the same transition twice when the stage is updated
+	// again.
+	started, stopped := false, false
+	if stage.Started != 0 {
+		prev, err := s.StageStore.Find(ctx, stage.ID)
+		if err == nil {
+			started = prev.Started == 0
+			stopped = stage.Stopped != 0 && prev.Stopped == 0
+		}
+	}
+	err := s.StageStore.Update(ctx, stage)
+	if err != nil {
+		return err
+	}
+	if started && stage.Created != 0 {
+		s.wait.Observe(float64(stage.Started - stage.Created))
+	}
+	if stopped {
+		s.observe(ctx, stage)
+	}
+	return nil
+}
+
+func (s *stageDuration) observe(ctx context.Context, stage *core.Stage) {
+	repo, err := s.repos.Find(ctx, stage.RepoID)
+	if err != nil {
+		return
+	}
+	repoLabel, pipelineLabel := s.labeler.labels(repo.Slug, stage.Name)
+	s.duration.WithLabelValues(repoLabel, pipelineLabel).Observe(
+		float64(stage.Stopped - stage.Started),
+	)
+}
@@ -95,3 +95,81 @@ func TestStageRunningCount(t *testing.T) {
 		t.Errorf("Expect metric value %f, got %f", want, got)
 	}
 }
+
+func TestStageDuration(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	running := &core.Stage{
+		ID:      1,
+		RepoID:  2,
+		Name:    "test",
+		Status:  core.StatusRunning,
+		Created: 1556000000,
+		Started: 1556000030,
+	}
+	finished := &core.Stage{
+		ID:      1,
+		RepoID:  2,
+		Name:    "test",
+		Status:  core.StatusPassing,
+		Created: 1556000000,
+		Started: 1556000030,
+		Stopped: 1556000150,
+	}
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), int64(1)).Return(&core.Stage{ID: 1}, nil)
+	stages.EXPECT().Find(gomock.Any(), int64(1)).Return(running, nil)
+	stages.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), int64(2)).Return(&core.Repository{Slug: "octocat/hello-world"}, nil)
+
+	store := StageDuration(stages, repos, Config{})
+	store.Update(noContext, running)
+	store.Update(noContext, finished)
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 2; want != got {
+		t.Errorf("Expect registered metrics")
+		return
+	}
+	for _, metric := range metrics {
+		switch metric.GetName() {
+		case "drone_stage_duration_seconds":
+			if want, got := len(metric.Metric[0].Label), 2; want != got {
+				t.Errorf("Expect %d labels, got %d", want, got)
+				return
+			}
+			if want, got := metric.Metric[0].Label[0].GetValue(), "test"; want != got {
+				t.Errorf("Expect pipeline label %s, got %s", want, got)
+			}
+			if want, got := metric.Metric[0].Label[1].GetValue(), "octocat/hello-world"; want != got {
+				t.Errorf("Expect repo label %s, got %s", want, got)
+			}
+			if want, got := metric.Metric[0].Histogram.GetSampleSum(), float64(120); want != got {
+				t.Errorf("Expect sample sum %f, got %f", want, got)
+			}
+		case "drone_stage_queue_wait_seconds":
+			if want, got := metric.Metric[0].Histogram.GetSampleCount(), uint64(1); want != got {
+				t.Errorf("Expect sample count %d, got %d", want, got)
+			}
+			if want, got := metric.Metric[0].Histogram.GetSampleSum(), float64(30); want != got {
+				t.Errorf("Expect sample sum %f, got %f", want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StepUsage provides metrics for step resource usage. It
+// returns a StepStore that observes the resource usage of
+// each step as it is updated to a completed state.
+func StepUsage(steps core.StepStore) core.StepStore {
+	s := &stepUsage{
+		StepStore: steps,
+		cpu: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "drone_step_cpu_peak_millicores",
+			Help:    "Peak step cpu usage in millicores.",
+			Buckets: []float64{100, 250, 500, 1000, 2000, 4000, 8000, 16000},
+		}),
+		mem: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "drone_step_memory_peak_bytes",
+			Help:    "Peak step memory usage in bytes.",
+			Buckets: prometheus.ExponentialBuckets(64<<20, 2, 8),
+		}),
+		read: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "drone_step_io_read_bytes_total",
+			Help: "Total bytes read by steps.",
+		}),
+		write: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "drone_step_io_write_bytes_total",
+			Help: "Total bytes written by steps.",
+		}),
+	}
+	prometheus.MustRegister(s.cpu, s.mem, s.read, s.write)
+	return s
+}
+
+type stepUsage struct {
+	core.StepStore
+	cpu   prometheus.Histogram
+	mem   prometheus.Histogram
+	read  prometheus.Counter
+	write prometheus.Counter
+}
+
+func (s *stepUsage) Update(ctx context.Context, step *core.Step) error {
+	// resource usage is only reported when the step is
+	// complete, and is not reported by all runners. The
+	// stored step is compared to prevent observing the same
+	// usage twice when the step is updated again.
+	observe := false
+	if step.IsDone() && hasUsage(step) {
+		prev, err := s.StepStore.Find(ctx, step.ID)
+		observe = err == nil && !hasUsage(prev)
+	}
+	err := s.StepStore.Update(ctx, step)
+	if err != nil {
+		return err
+	}
+	if observe {
+		s.cpu.Observe(float64(step.CPUPeak))
+		s.mem.Observe(float64(step.MemPeak))
+		s.read.Add(float64(step.IORead))
+		s.write.Add(float64(step.IOWrite))
+	}
+	return nil
+}
+
+// helper function returns true if resource usage was
+// reported for the step.
+func hasUsage(step *core.Step) bool {
+	return step.CPUPeak != 0 || step.MemPeak != 0
+}
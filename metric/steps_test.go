@@ -0,0 +1,109 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestStepUsage(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	// restore the default prometheus registerer
+	// when the unit test is complete.
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	// creates a blank registry
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	step := &core.Step{
+		ID:      1,
+		Status:  core.StatusPassing,
+		CPUPeak: 1500,
+		MemPeak: 268435456,
+		IORead:  4096,
+		IOWrite: 8192,
+	}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), step.ID).Return(&core.Step{ID: 1}, nil)
+	steps.EXPECT().Find(gomock.Any(), step.ID).Return(step, nil)
+	steps.EXPECT().Update(gomock.Any(), step).Return(nil).Times(2)
+
+	store := StepUsage(steps)
+	store.Update(noContext, step)
+	store.Update(noContext, step) // usage is not observed twice
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 4; want != got {
+		t.Errorf("Expect registered metrics")
+		return
+	}
+	for _, metric := range metrics {
+		switch metric.GetName() {
+		case "drone_step_cpu_peak_millicores":
+			if want, got := metric.Metric[0].Histogram.GetSampleCount(), uint64(1); want != got {
+				t.Errorf("Expect sample count %d, got %d", want, got)
+			}
+			if want, got := metric.Metric[0].Histogram.GetSampleSum(), float64(1500); want != got {
+				t.Errorf("Expect sample sum %f, got %f", want, got)
+			}
+		case "drone_step_io_write_bytes_total":
+			if want, got := metric.Metric[0].Counter.GetValue(), float64(8192); want != got {
+				t.Errorf("Expect metric value %f, got %f", want, got)
+			}
+		}
+	}
+}
+
+func TestStepUsage_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	step := &core.Step{ID: 1, Status: core.StatusRunning}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Update(gomock.Any(), step).Return(nil)
+
+	StepUsage(steps).Update(noContext, step)
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, metric := range metrics {
+		if metric.GetName() == "drone_step_cpu_peak_millicores" {
+			if got := metric.Metric[0].Histogram.GetSampleCount(); got != 0 {
+				t.Errorf("Expect no samples for running step, got %d", got)
+			}
+		}
+	}
+}
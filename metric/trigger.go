@@ -0,0 +1,68 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TriggerErrors provides metrics for build trigger errors.
+// It returns a Triggerer that counts trigger errors by
+// reason.
+func TriggerErrors(triggerer core.Triggerer) core.Triggerer {
+	t := &triggerErrors{
+		Triggerer: triggerer,
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "drone_trigger_errors_total",
+			Help: "Total number of build trigger errors.",
+		}, []string{"reason"}),
+	}
+	prometheus.MustRegister(t.errors)
+	return t
+}
+
+type triggerErrors struct {
+	core.Triggerer
+	errors *prometheus.CounterVec
+}
+
+func (t *triggerErrors) Trigger(ctx context.Context, repo *core.Repository, hook *core.Hook) (*core.Build, error) {
+	build, err := t.Triggerer.Trigger(ctx, repo, hook)
+	if reason := triggerReason(build, err); reason != "" {
+		t.errors.WithLabelValues(reason).Inc()
+	}
+	return build, err
+}
+
+// helper function returns the reason the build could not
+// be triggered, or an empty string if the build was
+// triggered or skipped.
+func triggerReason(build *core.Build, err error) string {
+	switch {
+	case err == nil && build != nil && build.Status == core.StatusError:
+		// the trigger creates a build in an error state
+		// when the yaml cannot be parsed or fails linting.
+		return "invalid_config"
+	case err == nil:
+		return ""
+	case err == scm.ErrNotFound:
+		return "not_found"
+	case err == sql.ErrNoRows:
+		return "not_found"
+	case err == context.Canceled,
+		err == context.DeadlineExceeded:
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
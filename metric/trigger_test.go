@@ -0,0 +1,83 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestTriggerErrors(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	triggerer := mock.NewMockTriggerer(controller)
+	triggerer.EXPECT().Trigger(gomock.Any(), gomock.Any(), gomock.Any()).Return(&core.Build{Status: core.StatusPending}, nil)
+	triggerer.EXPECT().Trigger(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, scm.ErrNotFound)
+
+	wrapped := TriggerErrors(triggerer)
+	wrapped.Trigger(noContext, nil, nil)
+	wrapped.Trigger(noContext, nil, nil)
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_trigger_errors_total"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Label[0].GetValue(), "not_found"; want != got {
+		t.Errorf("Expect reason label %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Counter.GetValue(), float64(1); want != got {
+		t.Errorf("Expect metric value %f, got %f", want, got)
+	}
+}
+
+func TestTriggerReason(t *testing.T) {
+	tests := []struct {
+		build  *core.Build
+		err    error
+		reason string
+	}{
+		{nil, nil, ""},
+		{&core.Build{Status: core.StatusPending}, nil, ""},
+		{&core.Build{Status: core.StatusError}, nil, "invalid_config"},
+		{nil, scm.ErrNotFound, "not_found"},
+		{nil, sql.ErrNoRows, "not_found"},
+		{nil, context.DeadlineExceeded, "timeout"},
+		{nil, errors.New("connection refused"), "internal"},
+	}
+	for _, test := range tests {
+		if got, want := triggerReason(test.build, test.err), test.reason; got != want {
+			t.Errorf("Want reason %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WebhookLatency provides metrics for webhook delivery
+// latency. It returns a WebhookDeliveryStore that observes
+// the latency of each delivery attempt as it is recorded.
+func WebhookLatency(deliveries core.WebhookDeliveryStore) core.WebhookDeliveryStore {
+	s := &webhookLatency{
+		WebhookDeliveryStore: deliveries,
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "drone_webhook_delivery_latency_seconds",
+			Help:    "Webhook delivery attempt latency in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"status"}),
+	}
+	prometheus.MustRegister(s.latency)
+	return s
+}
+
+type webhookLatency struct {
+	core.WebhookDeliveryStore
+	latency *prometheus.HistogramVec
+}
+
+func (s *webhookLatency) Update(ctx context.Context, delivery *core.WebhookDelivery) error {
+	err := s.WebhookDeliveryStore.Update(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	// the delivery is updated once per delivery attempt,
+	// with the latency of the attempt in milliseconds.
+	if delivery.Attempts != 0 {
+		s.latency.WithLabelValues(delivery.Status).Observe(
+			float64(delivery.Latency) / 1000,
+		)
+	}
+	return nil
+}
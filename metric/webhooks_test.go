@@ -0,0 +1,62 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package metric
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWebhookLatency(t *testing.T) {
+	controller := gomock.NewController(t)
+
+	snapshot := prometheus.DefaultRegisterer
+	defer func() {
+		prometheus.DefaultRegisterer = snapshot
+		controller.Finish()
+	}()
+
+	registry := prometheus.NewRegistry()
+	prometheus.DefaultRegisterer = registry
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Status:   core.WebhookDeliverySuccess,
+		Attempts: 1,
+		Latency:  250,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+
+	WebhookLatency(deliveries).Update(noContext, delivery)
+
+	metrics, err := registry.Gather()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if want, got := len(metrics), 1; want != got {
+		t.Errorf("Expect registered metric")
+		return
+	}
+	metric := metrics[0]
+	if want, got := metric.GetName(), "drone_webhook_delivery_latency_seconds"; want != got {
+		t.Errorf("Expect metric name %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Label[0].GetValue(), core.WebhookDeliverySuccess; want != got {
+		t.Errorf("Expect status label %s, got %s", want, got)
+	}
+	if want, got := metric.Metric[0].Histogram.GetSampleSum(), 0.25; want != got {
+		t.Errorf("Expect sample sum %f, got %f", want, got)
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/drone/drone/core (interfaces: NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,GlobalSecretStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,LicenseService)
+// Source: github.com/drone/drone/core (interfaces: NetrcService,Renewer,HookParser,UserService,RepositoryService,CommitService,StatusService,CommentService,HookService,FileService,Batcher,BuildStore,CronStore,LogStore,PermStore,SecretStore,GlobalSecretStore,TemplateStore,CentralConfigStore,ArtifactStore,TestStore,OutputStore,CacheStore,AnnotationStore,StageStore,StepStore,RepositoryStore,UserStore,Scheduler,Session,OrganizationService,SecretService,RegistryService,ConfigService,Triggerer,Syncer,LogStream,WebhookSender,WebhookStore,WebhookDeliveryStore,SubscriptionStore,Notifier,LicenseService)
 
 // Package mock is a generated GoMock package.
 package mock
@@ -316,6 +316,41 @@ func (mr *MockStatusServiceMockRecorder) Send(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStatusService)(nil).Send), arg0, arg1, arg2)
 }
 
+// MockCommentService is a mock of CommentService interface
+type MockCommentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentServiceMockRecorder
+}
+
+// MockCommentServiceMockRecorder is the mock recorder for MockCommentService
+type MockCommentServiceMockRecorder struct {
+	mock *MockCommentService
+}
+
+// NewMockCommentService creates a new mock instance
+func NewMockCommentService(ctrl *gomock.Controller) *MockCommentService {
+	mock := &MockCommentService{ctrl: ctrl}
+	mock.recorder = &MockCommentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCommentService) EXPECT() *MockCommentServiceMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method
+func (m *MockCommentService) Send(arg0 context.Context, arg1 *core.User, arg2 *core.CommentInput) error {
+	ret := m.ctrl.Call(m, "Send", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockCommentServiceMockRecorder) Send(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockCommentService)(nil).Send), arg0, arg1, arg2)
+}
+
 // MockHookService is a mock of HookService interface
 type MockHookService struct {
 	ctrl     *gomock.Controller
@@ -399,6 +434,19 @@ func (mr *MockFileServiceMockRecorder) Find(arg0, arg1, arg2, arg3, arg4, arg5 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockFileService)(nil).Find), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// List mocks base method
+func (m *MockFileService) List(arg0 context.Context, arg1 *core.User, arg2, arg3, arg4, arg5 string) ([]*core.FileInfo, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]*core.FileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockFileServiceMockRecorder) List(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFileService)(nil).List), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // MockBatcher is a mock of Batcher interface
 type MockBatcher struct {
 	ctrl     *gomock.Controller
@@ -470,6 +518,32 @@ func (mr *MockBuildStoreMockRecorder) Count(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockBuildStore)(nil).Count), arg0)
 }
 
+// CountRepos mocks base method
+func (m *MockBuildStore) CountRepos(arg0 context.Context, arg1 int64) (int64, error) {
+	ret := m.ctrl.Call(m, "CountRepos", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRepos indicates an expected call of CountRepos
+func (mr *MockBuildStoreMockRecorder) CountRepos(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRepos", reflect.TypeOf((*MockBuildStore)(nil).CountRepos), arg0, arg1)
+}
+
+// CountStatus mocks base method
+func (m *MockBuildStore) CountStatus(arg0 context.Context, arg1 int64) ([]*core.BuildCount, error) {
+	ret := m.ctrl.Call(m, "CountStatus", arg0, arg1)
+	ret0, _ := ret[0].([]*core.BuildCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountStatus indicates an expected call of CountStatus
+func (mr *MockBuildStoreMockRecorder) CountStatus(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountStatus", reflect.TypeOf((*MockBuildStore)(nil).CountStatus), arg0, arg1)
+}
+
 // Create mocks base method
 func (m *MockBuildStore) Create(arg0 context.Context, arg1 *core.Build, arg2 []*core.Stage) error {
 	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
@@ -597,6 +671,19 @@ func (mr *MockBuildStoreMockRecorder) Running(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Running", reflect.TypeOf((*MockBuildStore)(nil).Running), arg0)
 }
 
+// Stats mocks base method
+func (m *MockBuildStore) Stats(arg0 context.Context, arg1 *core.AnalyticsFilter) (*core.BuildStats, error) {
+	ret := m.ctrl.Call(m, "Stats", arg0, arg1)
+	ret0, _ := ret[0].(*core.BuildStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats
+func (mr *MockBuildStoreMockRecorder) Stats(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockBuildStore)(nil).Stats), arg0, arg1)
+}
+
 // Update mocks base method
 func (m *MockBuildStore) Update(arg0 context.Context, arg1 *core.Build) error {
 	ret := m.ctrl.Call(m, "Update", arg0, arg1)
@@ -1074,6 +1161,632 @@ func (mr *MockGlobalSecretStoreMockRecorder) Update(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockGlobalSecretStore)(nil).Update), arg0, arg1)
 }
 
+// MockTemplateStore is a mock of TemplateStore interface
+type MockTemplateStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTemplateStoreMockRecorder
+}
+
+// MockTemplateStoreMockRecorder is the mock recorder for MockTemplateStore
+type MockTemplateStoreMockRecorder struct {
+	mock *MockTemplateStore
+}
+
+// NewMockTemplateStore creates a new mock instance
+func NewMockTemplateStore(ctrl *gomock.Controller) *MockTemplateStore {
+	mock := &MockTemplateStore{ctrl: ctrl}
+	mock.recorder = &MockTemplateStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTemplateStore) EXPECT() *MockTemplateStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTemplateStore) Create(arg0 context.Context, arg1 *core.Template) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTemplateStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTemplateStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockTemplateStore) Delete(arg0 context.Context, arg1 *core.Template) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockTemplateStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTemplateStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockTemplateStore) Find(arg0 context.Context, arg1 int64) (*core.Template, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockTemplateStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTemplateStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method
+func (m *MockTemplateStore) FindName(arg0 context.Context, arg1, arg2, arg3 string) (*core.Template, error) {
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName
+func (mr *MockTemplateStoreMockRecorder) FindName(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockTemplateStore)(nil).FindName), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method
+func (m *MockTemplateStore) List(arg0 context.Context, arg1 string) ([]*core.Template, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockTemplateStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTemplateStore)(nil).List), arg0, arg1)
+}
+
+// ListAll mocks base method
+func (m *MockTemplateStore) ListAll(arg0 context.Context) ([]*core.Template, error) {
+	ret := m.ctrl.Call(m, "ListAll", arg0)
+	ret0, _ := ret[0].([]*core.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll
+func (mr *MockTemplateStoreMockRecorder) ListAll(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockTemplateStore)(nil).ListAll), arg0)
+}
+
+// MockCentralConfigStore is a mock of CentralConfigStore interface
+type MockCentralConfigStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCentralConfigStoreMockRecorder
+}
+
+// MockCentralConfigStoreMockRecorder is the mock recorder for MockCentralConfigStore
+type MockCentralConfigStoreMockRecorder struct {
+	mock *MockCentralConfigStore
+}
+
+// NewMockCentralConfigStore creates a new mock instance
+func NewMockCentralConfigStore(ctrl *gomock.Controller) *MockCentralConfigStore {
+	mock := &MockCentralConfigStore{ctrl: ctrl}
+	mock.recorder = &MockCentralConfigStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCentralConfigStore) EXPECT() *MockCentralConfigStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockCentralConfigStore) Create(arg0 context.Context, arg1 *core.CentralConfig) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCentralConfigStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCentralConfigStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockCentralConfigStore) Delete(arg0 context.Context, arg1 *core.CentralConfig) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockCentralConfigStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCentralConfigStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockCentralConfigStore) Find(arg0 context.Context, arg1 string) (*core.CentralConfig, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.CentralConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockCentralConfigStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockCentralConfigStore)(nil).Find), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockCentralConfigStore) Update(arg0 context.Context, arg1 *core.CentralConfig) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCentralConfigStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCentralConfigStore)(nil).Update), arg0, arg1)
+}
+
+// MockArtifactStore is a mock of ArtifactStore interface
+type MockArtifactStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockArtifactStoreMockRecorder
+}
+
+// MockArtifactStoreMockRecorder is the mock recorder for MockArtifactStore
+type MockArtifactStoreMockRecorder struct {
+	mock *MockArtifactStore
+}
+
+// NewMockArtifactStore creates a new mock instance
+func NewMockArtifactStore(ctrl *gomock.Controller) *MockArtifactStore {
+	mock := &MockArtifactStore{ctrl: ctrl}
+	mock.recorder = &MockArtifactStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockArtifactStore) EXPECT() *MockArtifactStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockArtifactStore) Create(arg0 context.Context, arg1 *core.Artifact, arg2 io.Reader) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockArtifactStoreMockRecorder) Create(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockArtifactStore)(nil).Create), arg0, arg1, arg2)
+}
+
+// Delete mocks base method
+func (m *MockArtifactStore) Delete(arg0 context.Context, arg1 *core.Artifact) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockArtifactStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockArtifactStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockArtifactStore) Find(arg0 context.Context, arg1 int64) (*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockArtifactStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockArtifactStore)(nil).Find), arg0, arg1)
+}
+
+// FindName mocks base method
+func (m *MockArtifactStore) FindName(arg0 context.Context, arg1 int64, arg2 string) (*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "FindName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindName indicates an expected call of FindName
+func (mr *MockArtifactStoreMockRecorder) FindName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindName", reflect.TypeOf((*MockArtifactStore)(nil).FindName), arg0, arg1, arg2)
+}
+
+// List mocks base method
+func (m *MockArtifactStore) List(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockArtifactStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockArtifactStore)(nil).List), arg0, arg1)
+}
+
+// ListStage mocks base method
+func (m *MockArtifactStore) ListStage(arg0 context.Context, arg1 int64) ([]*core.Artifact, error) {
+	ret := m.ctrl.Call(m, "ListStage", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStage indicates an expected call of ListStage
+func (mr *MockArtifactStoreMockRecorder) ListStage(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStage", reflect.TypeOf((*MockArtifactStore)(nil).ListStage), arg0, arg1)
+}
+
+// Open mocks base method
+func (m *MockArtifactStore) Open(arg0 context.Context, arg1 *core.Artifact) (io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "Open", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open
+func (mr *MockArtifactStoreMockRecorder) Open(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockArtifactStore)(nil).Open), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockArtifactStore) Purge(arg0 context.Context, arg1, arg2 int64) error {
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockArtifactStoreMockRecorder) Purge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockArtifactStore)(nil).Purge), arg0, arg1, arg2)
+}
+
+// MockTestStore is a mock of TestStore interface
+type MockTestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTestStoreMockRecorder
+}
+
+// MockTestStoreMockRecorder is the mock recorder for MockTestStore
+type MockTestStoreMockRecorder struct {
+	mock *MockTestStore
+}
+
+// NewMockTestStore creates a new mock instance
+func NewMockTestStore(ctrl *gomock.Controller) *MockTestStore {
+	mock := &MockTestStore{ctrl: ctrl}
+	mock.recorder = &MockTestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTestStore) EXPECT() *MockTestStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTestStore) Create(arg0 context.Context, arg1 []*core.TestCase) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTestStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTestStore)(nil).Create), arg0, arg1)
+}
+
+// History mocks base method
+func (m *MockTestStore) History(arg0 context.Context, arg1 int64, arg2, arg3 string, arg4 int) ([]*core.TestCase, error) {
+	ret := m.ctrl.Call(m, "History", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History
+func (mr *MockTestStoreMockRecorder) History(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockTestStore)(nil).History), arg0, arg1, arg2, arg3, arg4)
+}
+
+// List mocks base method
+func (m *MockTestStore) List(arg0 context.Context, arg1 int64) ([]*core.TestCase, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockTestStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTestStore)(nil).List), arg0, arg1)
+}
+
+// ListFailed mocks base method
+func (m *MockTestStore) ListFailed(arg0 context.Context, arg1 int64) ([]*core.TestCase, error) {
+	ret := m.ctrl.Call(m, "ListFailed", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailed indicates an expected call of ListFailed
+func (mr *MockTestStoreMockRecorder) ListFailed(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailed", reflect.TypeOf((*MockTestStore)(nil).ListFailed), arg0, arg1)
+}
+
+// ListStage mocks base method
+func (m *MockTestStore) ListStage(arg0 context.Context, arg1 int64) ([]*core.TestCase, error) {
+	ret := m.ctrl.Call(m, "ListStage", arg0, arg1)
+	ret0, _ := ret[0].([]*core.TestCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStage indicates an expected call of ListStage
+func (mr *MockTestStoreMockRecorder) ListStage(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStage", reflect.TypeOf((*MockTestStore)(nil).ListStage), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockTestStore) Purge(arg0 context.Context, arg1, arg2 int64) error {
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockTestStoreMockRecorder) Purge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTestStore)(nil).Purge), arg0, arg1, arg2)
+}
+
+// Summary mocks base method
+func (m *MockTestStore) Summary(arg0 context.Context, arg1 int64) (*core.TestSummary, error) {
+	ret := m.ctrl.Call(m, "Summary", arg0, arg1)
+	ret0, _ := ret[0].(*core.TestSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary
+func (mr *MockTestStoreMockRecorder) Summary(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockTestStore)(nil).Summary), arg0, arg1)
+}
+
+// MockOutputStore is a mock of OutputStore interface
+type MockOutputStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutputStoreMockRecorder
+}
+
+// MockOutputStoreMockRecorder is the mock recorder for MockOutputStore
+type MockOutputStoreMockRecorder struct {
+	mock *MockOutputStore
+}
+
+// NewMockOutputStore creates a new mock instance
+func NewMockOutputStore(ctrl *gomock.Controller) *MockOutputStore {
+	mock := &MockOutputStore{ctrl: ctrl}
+	mock.recorder = &MockOutputStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOutputStore) EXPECT() *MockOutputStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockOutputStore) Create(arg0 context.Context, arg1 []*core.Output) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockOutputStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOutputStore)(nil).Create), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockOutputStore) List(arg0 context.Context, arg1 int64) ([]*core.Output, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Output)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockOutputStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOutputStore)(nil).List), arg0, arg1)
+}
+
+// MockCacheStore is a mock of CacheStore interface
+type MockCacheStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheStoreMockRecorder
+}
+
+// MockCacheStoreMockRecorder is the mock recorder for MockCacheStore
+type MockCacheStoreMockRecorder struct {
+	mock *MockCacheStore
+}
+
+// NewMockCacheStore creates a new mock instance
+func NewMockCacheStore(ctrl *gomock.Controller) *MockCacheStore {
+	mock := &MockCacheStore{ctrl: ctrl}
+	mock.recorder = &MockCacheStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCacheStore) EXPECT() *MockCacheStoreMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method
+func (m *MockCacheStore) Clear(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Clear", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear
+func (mr *MockCacheStoreMockRecorder) Clear(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockCacheStore)(nil).Clear), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockCacheStore) Delete(arg0 context.Context, arg1 *core.Cache) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockCacheStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCacheStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockCacheStore) Find(arg0 context.Context, arg1 int64, arg2 string, arg3 []string) (*core.Cache, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*core.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockCacheStoreMockRecorder) Find(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockCacheStore)(nil).Find), arg0, arg1, arg2, arg3)
+}
+
+// List mocks base method
+func (m *MockCacheStore) List(arg0 context.Context, arg1 int64) ([]*core.Cache, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Cache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockCacheStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCacheStore)(nil).List), arg0, arg1)
+}
+
+// Open mocks base method
+func (m *MockCacheStore) Open(arg0 context.Context, arg1 *core.Cache) (io.ReadCloser, error) {
+	ret := m.ctrl.Call(m, "Open", arg0, arg1)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open
+func (mr *MockCacheStoreMockRecorder) Open(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCacheStore)(nil).Open), arg0, arg1)
+}
+
+// Save mocks base method
+func (m *MockCacheStore) Save(arg0 context.Context, arg1 *core.Cache, arg2 io.Reader) error {
+	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockCacheStoreMockRecorder) Save(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCacheStore)(nil).Save), arg0, arg1, arg2)
+}
+
+// MockAnnotationStore is a mock of AnnotationStore interface
+type MockAnnotationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnnotationStoreMockRecorder
+}
+
+// MockAnnotationStoreMockRecorder is the mock recorder for MockAnnotationStore
+type MockAnnotationStoreMockRecorder struct {
+	mock *MockAnnotationStore
+}
+
+// NewMockAnnotationStore creates a new mock instance
+func NewMockAnnotationStore(ctrl *gomock.Controller) *MockAnnotationStore {
+	mock := &MockAnnotationStore{ctrl: ctrl}
+	mock.recorder = &MockAnnotationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAnnotationStore) EXPECT() *MockAnnotationStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockAnnotationStore) Create(arg0 context.Context, arg1 []*core.Annotation) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockAnnotationStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAnnotationStore)(nil).Create), arg0, arg1)
+}
+
+// CreateCard mocks base method
+func (m *MockAnnotationStore) CreateCard(arg0 context.Context, arg1 *core.Card) error {
+	ret := m.ctrl.Call(m, "CreateCard", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCard indicates an expected call of CreateCard
+func (mr *MockAnnotationStoreMockRecorder) CreateCard(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockAnnotationStore)(nil).CreateCard), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockAnnotationStore) List(arg0 context.Context, arg1 int64) ([]*core.Annotation, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Annotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockAnnotationStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAnnotationStore)(nil).List), arg0, arg1)
+}
+
+// ListCards mocks base method
+func (m *MockAnnotationStore) ListCards(arg0 context.Context, arg1 int64) ([]*core.Card, error) {
+	ret := m.ctrl.Call(m, "ListCards", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCards indicates an expected call of ListCards
+func (mr *MockAnnotationStoreMockRecorder) ListCards(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCards", reflect.TypeOf((*MockAnnotationStore)(nil).ListCards), arg0, arg1)
+}
+
 // MockStageStore is a mock of StageStore interface
 type MockStageStore struct {
 	ctrl     *gomock.Controller
@@ -1187,6 +1900,19 @@ func (mr *MockStageStoreMockRecorder) ListSteps(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSteps", reflect.TypeOf((*MockStageStore)(nil).ListSteps), arg0, arg1)
 }
 
+// Stats mocks base method
+func (m *MockStageStore) Stats(arg0 context.Context, arg1 *core.AnalyticsFilter) (*core.StageStats, error) {
+	ret := m.ctrl.Call(m, "Stats", arg0, arg1)
+	ret0, _ := ret[0].(*core.StageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stats indicates an expected call of Stats
+func (mr *MockStageStoreMockRecorder) Stats(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockStageStore)(nil).Stats), arg0, arg1)
+}
+
 // Update mocks base method
 func (m *MockStageStore) Update(arg0 context.Context, arg1 *core.Stage) error {
 	ret := m.ctrl.Call(m, "Update", arg0, arg1)
@@ -1632,6 +2358,31 @@ func (mr *MockSchedulerMockRecorder) Cancelled(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancelled", reflect.TypeOf((*MockScheduler)(nil).Cancelled), arg0, arg1)
 }
 
+// Drain mocks base method
+func (m *MockScheduler) Drain(arg0 context.Context, arg1 string) error {
+	ret := m.ctrl.Call(m, "Drain", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain
+func (mr *MockSchedulerMockRecorder) Drain(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockScheduler)(nil).Drain), arg0, arg1)
+}
+
+// Drained mocks base method
+func (m *MockScheduler) Drained(arg0 context.Context, arg1 string) (bool, error) {
+	ret := m.ctrl.Call(m, "Drained", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drained indicates an expected call of Drained
+func (mr *MockSchedulerMockRecorder) Drained(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drained", reflect.TypeOf((*MockScheduler)(nil).Drained), arg0, arg1)
+}
+
 // Pause mocks base method
 func (m *MockScheduler) Pause(arg0 context.Context) error {
 	ret := m.ctrl.Call(m, "Pause", arg0)
@@ -1790,6 +2541,20 @@ func (mr *MockOrganizationServiceMockRecorder) List(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrganizationService)(nil).List), arg0, arg1)
 }
 
+// Membership mocks base method
+func (m *MockOrganizationService) Membership(arg0 context.Context, arg1 *core.User, arg2 string) (bool, bool, error) {
+	ret := m.ctrl.Call(m, "Membership", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Membership indicates an expected call of Membership
+func (mr *MockOrganizationServiceMockRecorder) Membership(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Membership", reflect.TypeOf((*MockOrganizationService)(nil).Membership), arg0, arg1, arg2)
+}
+
 // MockSecretService is a mock of SecretService interface
 type MockSecretService struct {
 	ctrl     *gomock.Controller
@@ -1921,6 +2686,19 @@ func (m *MockTriggerer) EXPECT() *MockTriggererMockRecorder {
 	return m.recorder
 }
 
+// Evaluate mocks base method
+func (m *MockTriggerer) Evaluate(arg0 context.Context, arg1 *core.Repository, arg2 *core.Hook) (*core.Evaluation, error) {
+	ret := m.ctrl.Call(m, "Evaluate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*core.Evaluation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate
+func (mr *MockTriggererMockRecorder) Evaluate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockTriggerer)(nil).Evaluate), arg0, arg1, arg2)
+}
+
 // Trigger mocks base method
 func (m *MockTriggerer) Trigger(arg0 context.Context, arg1 *core.Repository, arg2 *core.Hook) (*core.Build, error) {
 	ret := m.ctrl.Call(m, "Trigger", arg0, arg1, arg2)
@@ -2089,6 +2867,361 @@ func (mr *MockWebhookSenderMockRecorder) Send(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockWebhookSender)(nil).Send), arg0, arg1)
 }
 
+// Subscribed mocks base method
+func (m *MockWebhookSender) Subscribed(arg0 context.Context, arg1 *core.WebhookData) (bool, error) {
+	ret := m.ctrl.Call(m, "Subscribed", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribed indicates an expected call of Subscribed
+func (mr *MockWebhookSenderMockRecorder) Subscribed(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribed", reflect.TypeOf((*MockWebhookSender)(nil).Subscribed), arg0, arg1)
+}
+
+// MockWebhookStore is a mock of WebhookStore interface
+type MockWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStoreMockRecorder
+}
+
+// MockWebhookStoreMockRecorder is the mock recorder for MockWebhookStore
+type MockWebhookStoreMockRecorder struct {
+	mock *MockWebhookStore
+}
+
+// NewMockWebhookStore creates a new mock instance
+func NewMockWebhookStore(ctrl *gomock.Controller) *MockWebhookStore {
+	mock := &MockWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockWebhookStore) Create(arg0 context.Context, arg1 *core.Webhook) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockWebhookStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockWebhookStore) Delete(arg0 context.Context, arg1 *core.Webhook) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockWebhookStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWebhookStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockWebhookStore) Find(arg0 context.Context, arg1 int64) (*core.Webhook, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockWebhookStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWebhookStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockWebhookStore) List(arg0 context.Context, arg1 int64) ([]*core.Webhook, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockWebhookStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookStore)(nil).List), arg0, arg1)
+}
+
+// ListNamespace mocks base method
+func (m *MockWebhookStore) ListNamespace(arg0 context.Context, arg1 string) ([]*core.Webhook, error) {
+	ret := m.ctrl.Call(m, "ListNamespace", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNamespace indicates an expected call of ListNamespace
+func (mr *MockWebhookStoreMockRecorder) ListNamespace(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNamespace", reflect.TypeOf((*MockWebhookStore)(nil).ListNamespace), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockWebhookStore) Update(arg0 context.Context, arg1 *core.Webhook) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockWebhookStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookStore)(nil).Update), arg0, arg1)
+}
+
+// MockWebhookDeliveryStore is a mock of WebhookDeliveryStore interface
+type MockWebhookDeliveryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryStoreMockRecorder
+}
+
+// MockWebhookDeliveryStoreMockRecorder is the mock recorder for MockWebhookDeliveryStore
+type MockWebhookDeliveryStoreMockRecorder struct {
+	mock *MockWebhookDeliveryStore
+}
+
+// NewMockWebhookDeliveryStore creates a new mock instance
+func NewMockWebhookDeliveryStore(ctrl *gomock.Controller) *MockWebhookDeliveryStore {
+	mock := &MockWebhookDeliveryStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookDeliveryStore) EXPECT() *MockWebhookDeliveryStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method
+func (m *MockWebhookDeliveryStore) Claim(arg0 context.Context, arg1 *core.WebhookDelivery, arg2 int64) (bool, error) {
+	ret := m.ctrl.Call(m, "Claim", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim
+func (mr *MockWebhookDeliveryStoreMockRecorder) Claim(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Claim), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockWebhookDeliveryStore) Create(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockWebhookDeliveryStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Create), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockWebhookDeliveryStore) Find(arg0 context.Context, arg1 int64) (*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockWebhookDeliveryStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockWebhookDeliveryStore) List(arg0 context.Context, arg1, arg2 int) ([]*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockWebhookDeliveryStoreMockRecorder) List(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).List), arg0, arg1, arg2)
+}
+
+// Pending mocks base method
+func (m *MockWebhookDeliveryStore) Pending(arg0 context.Context, arg1 int64) ([]*core.WebhookDelivery, error) {
+	ret := m.ctrl.Call(m, "Pending", arg0, arg1)
+	ret0, _ := ret[0].([]*core.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending
+func (mr *MockWebhookDeliveryStoreMockRecorder) Pending(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Pending), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockWebhookDeliveryStore) Purge(arg0 context.Context, arg1 int64) error {
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockWebhookDeliveryStoreMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Purge), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockWebhookDeliveryStore) Update(arg0 context.Context, arg1 *core.WebhookDelivery) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockWebhookDeliveryStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Update), arg0, arg1)
+}
+
+// MockSubscriptionStore is a mock of SubscriptionStore interface
+type MockSubscriptionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionStoreMockRecorder
+}
+
+// MockSubscriptionStoreMockRecorder is the mock recorder for MockSubscriptionStore
+type MockSubscriptionStoreMockRecorder struct {
+	mock *MockSubscriptionStore
+}
+
+// NewMockSubscriptionStore creates a new mock instance
+func NewMockSubscriptionStore(ctrl *gomock.Controller) *MockSubscriptionStore {
+	mock := &MockSubscriptionStore{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSubscriptionStore) EXPECT() *MockSubscriptionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockSubscriptionStore) Create(arg0 context.Context, arg1 *core.Subscription) error {
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockSubscriptionStoreMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubscriptionStore)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockSubscriptionStore) Delete(arg0 context.Context, arg1 *core.Subscription) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockSubscriptionStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubscriptionStore)(nil).Delete), arg0, arg1)
+}
+
+// Find mocks base method
+func (m *MockSubscriptionStore) Find(arg0 context.Context, arg1 int64) (*core.Subscription, error) {
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*core.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockSubscriptionStoreMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockSubscriptionStore)(nil).Find), arg0, arg1)
+}
+
+// List mocks base method
+func (m *MockSubscriptionStore) List(arg0 context.Context, arg1 int64) ([]*core.Subscription, error) {
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *MockSubscriptionStoreMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSubscriptionStore)(nil).List), arg0, arg1)
+}
+
+// ListUser mocks base method
+func (m *MockSubscriptionStore) ListUser(arg0 context.Context, arg1 int64) ([]*core.Subscription, error) {
+	ret := m.ctrl.Call(m, "ListUser", arg0, arg1)
+	ret0, _ := ret[0].([]*core.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUser indicates an expected call of ListUser
+func (mr *MockSubscriptionStoreMockRecorder) ListUser(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUser", reflect.TypeOf((*MockSubscriptionStore)(nil).ListUser), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockSubscriptionStore) Update(arg0 context.Context, arg1 *core.Subscription) error {
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockSubscriptionStoreMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscriptionStore)(nil).Update), arg0, arg1)
+}
+
+// MockNotifier is a mock of Notifier interface
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method
+func (m *MockNotifier) Notify(arg0 context.Context, arg1 *core.NotifyInput) error {
+	ret := m.ctrl.Call(m, "Notify", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify
+func (mr *MockNotifierMockRecorder) Notify(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), arg0, arg1)
+}
+
 // MockLicenseService is a mock of LicenseService interface
 type MockLicenseService struct {
 	ctrl     *gomock.Controller
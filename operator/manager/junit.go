@@ -0,0 +1,135 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/drone/drone/core"
+)
+
+// maximum length of the test suite and test name, which
+// matches the size of the database columns.
+const maxTestName = 250
+
+// maximum length of the test failure message.
+const maxTestMessage = 4096
+
+type (
+	// junitSuite represents a junit testsuite element. The
+	// testsuites root element is decoded using the same
+	// structure, since it contains nested testsuite elements.
+	junitSuite struct {
+		Name   string       `xml:"name,attr"`
+		Suites []junitSuite `xml:"testsuite"`
+		Cases  []junitCase  `xml:"testcase"`
+	}
+
+	// junitCase represents a junit testcase element.
+	junitCase struct {
+		Name      string       `xml:"name,attr"`
+		Classname string       `xml:"classname,attr"`
+		Time      string       `xml:"time,attr"`
+		Failure   *junitResult `xml:"failure"`
+		Error     *junitResult `xml:"error"`
+		Skipped   *junitResult `xml:"skipped"`
+	}
+
+	// junitResult represents a junit failure, error or
+	// skipped element.
+	junitResult struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// parseJUnit parses a junit xml test report and returns the
+// list of test cases. The returned test cases are not yet
+// associated with a build or step.
+func parseJUnit(r io.Reader) ([]*core.TestCase, error) {
+	root := junitSuite{}
+	err := xml.NewDecoder(r).Decode(&root)
+	if err != nil {
+		return nil, err
+	}
+	return flattenJUnit(root, nil), nil
+}
+
+// helper function recursively converts the junit test
+// suite, and any nested test suites, to a list of test
+// cases.
+func flattenJUnit(suite junitSuite, out []*core.TestCase) []*core.TestCase {
+	for _, c := range suite.Cases {
+		test := &core.TestCase{
+			Suite:    truncate(c.Classname, maxTestName),
+			Name:     truncate(c.Name, maxTestName),
+			Status:   core.TestPassed,
+			Duration: parseDuration(c.Time),
+		}
+		if test.Suite == "" {
+			test.Suite = truncate(suite.Name, maxTestName)
+		}
+		switch {
+		case c.Failure != nil:
+			test.Status = core.TestFailed
+			test.Message = c.Failure.String()
+		case c.Error != nil:
+			test.Status = core.TestFailed
+			test.Message = c.Error.String()
+		case c.Skipped != nil:
+			test.Status = core.TestSkipped
+			test.Message = c.Skipped.String()
+		}
+		out = append(out, test)
+	}
+	for _, nested := range suite.Suites {
+		out = flattenJUnit(nested, out)
+	}
+	return out
+}
+
+// String returns the result message, falling back to the
+// element text if the message attribute is empty.
+func (r *junitResult) String() string {
+	s := r.Message
+	if s == "" {
+		s = strings.TrimSpace(r.Text)
+	}
+	return truncate(s, maxTestMessage)
+}
+
+// helper function parses the test duration, in seconds,
+// and returns the duration in milliseconds.
+func parseDuration(s string) int64 {
+	s = strings.Replace(s, ",", "", -1)
+	f, _ := strconv.ParseFloat(s, 64)
+	return int64(f * 1000)
+}
+
+// helper function truncates the string to at most n bytes,
+// without splitting a multi-byte character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseJUnit(t *testing.T) {
+	tests, err := parseJUnit(strings.NewReader(testJUnit))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []*core.TestCase{
+		{Suite: "math.Add", Name: "TestAdd", Status: core.TestPassed, Duration: 1500},
+		{Suite: "math.Add", Name: "TestAddNegative", Status: core.TestFailed, Message: "expected -2, got 2"},
+		{Suite: "math.Div", Name: "TestDivZero", Status: core.TestFailed, Message: "panic: division by zero"},
+		{Suite: "strings", Name: "TestSplit", Status: core.TestSkipped, Duration: 2},
+	}
+	if diff := cmp.Diff(tests, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseJUnit_Suite(t *testing.T) {
+	tests, err := parseJUnit(strings.NewReader(testJUnitSuite))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := []*core.TestCase{
+		{Suite: "math", Name: "TestAdd", Status: core.TestPassed, Duration: 10},
+	}
+	if diff := cmp.Diff(tests, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseJUnit_Invalid(t *testing.T) {
+	_, err := parseJUnit(strings.NewReader("<testsuites>"))
+	if err == nil {
+		t.Errorf("Expect error parsing invalid xml")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got, want := truncate("hello", 10), "hello"; got != want {
+		t.Errorf("Want %q, got %q", want, got)
+	}
+	if got, want := truncate("hello", 4), "hell"; got != want {
+		t.Errorf("Want %q, got %q", want, got)
+	}
+	if got, want := truncate("héllo", 2), "h"; got != want {
+		t.Errorf("Want %q, got %q", want, got)
+	}
+}
+
+var testJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="math" tests="3">
+    <testcase classname="math.Add" name="TestAdd" time="1.5"></testcase>
+    <testcase classname="math.Add" name="TestAddNegative" time="0">
+      <failure message="expected -2, got 2" type="assert"></failure>
+    </testcase>
+    <testcase classname="math.Div" name="TestDivZero">
+      <error>
+        panic: division by zero
+      </error>
+    </testcase>
+  </testsuite>
+  <testsuite name="strings">
+    <testcase name="TestSplit" time="0.002">
+      <skipped/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+var testJUnitSuite = `<testsuite name="math">
+  <testcase name="TestAdd" time="0.010"/>
+</testsuite>`
@@ -0,0 +1,247 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package local provides a BuildManager that executes a single
+// pipeline stage from a local configuration file, without a
+// central server. It is intended for local pipeline execution.
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+)
+
+var _ manager.BuildManager = (*Manager)(nil)
+
+var (
+	errPipelineNotFound = errors.New("cannot find named pipeline")
+	errPipelineEmpty    = errors.New("configuration file does not define a pipeline")
+)
+
+// Lookup returns the name of the pipeline to execute from the
+// configuration file. If the name is empty, the first pipeline
+// defined in the configuration file is returned.
+func Lookup(data []byte, name string) (string, error) {
+	manifest, err := yaml.ParseBytes(data)
+	if err != nil {
+		return "", err
+	}
+	for _, resource := range manifest.Resources {
+		pipeline, ok := resource.(*yaml.Pipeline)
+		if !ok {
+			continue
+		}
+		if name == "" || pipeline.Name == name {
+			return pipeline.Name, nil
+		}
+	}
+	if name != "" {
+		return "", errPipelineNotFound
+	}
+	return "", errPipelineEmpty
+}
+
+// Manager is a BuildManager that provides the runner with the
+// details of a single local stage, and streams the build logs
+// to the output writer.
+type Manager struct {
+	Repo    *core.Repository
+	Build   *core.Build
+	Stage   *core.Stage
+	Config  *core.File
+	Secrets []*core.Secret
+	System  *core.System
+	Output  io.Writer
+
+	// Credentials are the optional netrc credentials used
+	// to authenticate with the remote repository.
+	Credentials *core.Netrc
+
+	mu    sync.Mutex
+	steps map[int64]string
+	seq   int64
+}
+
+// Request returns the local stage.
+func (m *Manager) Request(ctx context.Context, args *manager.Request) (*core.Stage, error) {
+	return m.Stage, nil
+}
+
+// Accept accepts the local stage for execution.
+func (m *Manager) Accept(ctx context.Context, stage int64, machine string) error {
+	return nil
+}
+
+// Netrc returns the local netrc, if configured.
+func (m *Manager) Netrc(ctx context.Context, repo int64) (*core.Netrc, error) {
+	return m.Credentials, nil
+}
+
+// Details returns the details of the local stage.
+func (m *Manager) Details(ctx context.Context, stage int64) (*manager.Context, error) {
+	return &manager.Context{
+		Repo:    m.Repo,
+		Build:   m.Build,
+		Stage:   m.Stage,
+		Config:  m.Config,
+		Secrets: m.Secrets,
+		System:  m.System,
+	}, nil
+}
+
+// Before assigns the step a unique identifier, used to
+// prefix the step logs.
+func (m *Manager) Before(ctx context.Context, step *core.Step) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.steps == nil {
+		m.steps = map[int64]string{}
+	}
+	m.seq++
+	step.ID = m.seq
+	step.Version++
+	m.steps[step.ID] = step.Name
+	return nil
+}
+
+// After signals the build step is complete.
+func (m *Manager) After(ctx context.Context, step *core.Step) error {
+	step.Version++
+	return nil
+}
+
+// BeforeAll signals the build stage is about to start.
+func (m *Manager) BeforeAll(ctx context.Context, stage *core.Stage) error {
+	stage.Version++
+	return nil
+}
+
+// AfterAll records the final state of the stage.
+func (m *Manager) AfterAll(ctx context.Context, stage *core.Stage) error {
+	m.mu.Lock()
+	m.Stage = stage
+	m.mu.Unlock()
+	return nil
+}
+
+// Watch blocks until the context is cancelled. Local builds
+// are cancelled by interrupting the process.
+func (m *Manager) Watch(ctx context.Context, build int64) (bool, error) {
+	<-ctx.Done()
+	return false, ctx.Err()
+}
+
+// Drained blocks until the context is cancelled.
+func (m *Manager) Drained(ctx context.Context, machine string) (bool, error) {
+	<-ctx.Done()
+	return false, ctx.Err()
+}
+
+// Write writes the line to the output, prefixed with the
+// step name.
+func (m *Manager) Write(ctx context.Context, step int64, line *core.Line) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	message := line.Message
+	if !strings.HasSuffix(message, "\n") {
+		message = message + "\n"
+	}
+	_, err := fmt.Fprintf(m.Output, "[%s:%d] %s", m.steps[step], line.Number, message)
+	return err
+}
+
+// Upload discards the full logs, which were already written
+// to the output as they were streamed.
+func (m *Manager) Upload(ctx context.Context, step int64, r io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, r)
+	return err
+}
+
+// UploadBytes discards the full logs, which were already
+// written to the output as they were streamed.
+func (m *Manager) UploadBytes(ctx context.Context, step int64, b []byte) error {
+	return nil
+}
+
+// UploadArtifact discards the build artifact.
+func (m *Manager) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, r)
+	return err
+}
+
+// UploadReport discards the test report.
+func (m *Manager) UploadReport(ctx context.Context, step int64, r io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, r)
+	return err
+}
+
+// UploadOutputs discards the step outputs. The runner passes
+// outputs to subsequent steps in the stage directly.
+func (m *Manager) UploadOutputs(ctx context.Context, step int64, outputs []*core.Output) error {
+	return nil
+}
+
+// UploadAnnotations writes the step annotations and summary
+// card to the output.
+func (m *Manager) UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation, card *core.Card) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	name := m.steps[step]
+	for _, annotation := range annotations {
+		location := annotation.File
+		if location != "" && annotation.Line != 0 {
+			location = fmt.Sprintf("%s:%d", location, annotation.Line)
+		}
+		if location != "" {
+			location = location + ": "
+		}
+		_, err := fmt.Fprintf(m.Output, "[%s] %s: %s%s\n", name, annotation.Severity, location, annotation.Message)
+		if err != nil {
+			return err
+		}
+	}
+	if card != nil {
+		_, err := fmt.Fprintf(m.Output, "[%s] summary:\n%s\n", name, strings.TrimSuffix(card.Data, "\n"))
+		return err
+	}
+	return nil
+}
+
+// RestoreCache returns a nil reader, since local builds do
+// not have access to the server-managed build cache.
+func (m *Manager) RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+// SaveCache discards the build cache.
+func (m *Manager) SaveCache(ctx context.Context, step int64, key string, r io.Reader) error {
+	_, err := io.Copy(ioutil.Discard, r)
+	return err
+}
+
+// Status returns the final status of the stage.
+func (m *Manager) Status() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Stage.Status
+}
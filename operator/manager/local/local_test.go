@@ -0,0 +1,109 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+)
+
+var noContext = context.Background()
+
+var mockConfig = []byte(`
+kind: pipeline
+name: backend
+
+steps:
+- name: test
+  image: golang
+  commands:
+  - go test
+
+---
+kind: pipeline
+name: frontend
+
+steps:
+- name: test
+  image: node
+  commands:
+  - npm test
+`)
+
+func TestLookup(t *testing.T) {
+	name, err := Lookup(mockConfig, "")
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := name, "backend"; got != want {
+		t.Errorf("Want first pipeline %q, got %q", want, got)
+	}
+
+	name, err = Lookup(mockConfig, "frontend")
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := name, "frontend"; got != want {
+		t.Errorf("Want named pipeline %q, got %q", want, got)
+	}
+}
+
+func TestLookup_NotFound(t *testing.T) {
+	_, err := Lookup(mockConfig, "docs")
+	if err != errPipelineNotFound {
+		t.Errorf("Want pipeline not found error, got %v", err)
+	}
+	_, err = Lookup([]byte("kind: secret\nname: token\n"), "")
+	if err != errPipelineEmpty {
+		t.Errorf("Want empty pipeline error, got %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	m := &Manager{Output: &buf}
+
+	step := &core.Step{Name: "test"}
+	m.Before(noContext, step)
+	m.Write(noContext, step.ID, &core.Line{Number: 0, Message: "go test\n"})
+	m.Write(noContext, step.ID, &core.Line{Number: 1, Message: "PASS"})
+
+	if got, want := buf.String(), "[test:0] go test\n[test:1] PASS\n"; got != want {
+		t.Errorf("Want output %q, got %q", want, got)
+	}
+}
+
+func TestUploadAnnotations(t *testing.T) {
+	var buf bytes.Buffer
+	m := &Manager{Output: &buf}
+
+	step := &core.Step{Name: "lint"}
+	m.Before(noContext, step)
+	m.UploadAnnotations(noContext, step.ID,
+		[]*core.Annotation{
+			{File: "main.go", Line: 12, Severity: core.SeverityWarning, Message: "unused variable"},
+			{Severity: core.SeverityNotice, Message: "no issues in tests"},
+		},
+		&core.Card{Data: "# Lint\n"},
+	)
+
+	want := "[lint] warning: main.go:12: unused variable\n" +
+		"[lint] notice: no issues in tests\n" +
+		"[lint] summary:\n# Lint\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Want output %q, got %q", want, got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	m := &Manager{Stage: &core.Stage{Status: core.StatusPending}}
+	m.AfterAll(noContext, &core.Stage{Status: core.StatusFailing})
+	if got, want := m.Status(), core.StatusFailing; got != want {
+		t.Errorf("Want stage status %q, got %q", want, got)
+	}
+}
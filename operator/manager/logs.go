@@ -0,0 +1,202 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/drone/drone/core"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logCounter tracks the size of the logs written to the
+// live stream by a single step.
+type logCounter struct {
+	core.LogCounter
+
+	// stage is the stage identifier, used to count the
+	// stage logs. A zero value indicates the stage logs
+	// are not counted.
+	stage int64
+}
+
+// acceptLine returns true if the line is within the step
+// and stage log limits. The truncated return value is true
+// for the first rejected line only, so that the truncation
+// marker is written to the live stream once.
+func (m *Manager) acceptLine(ctx context.Context, step int64, line *core.Line) (ok, truncated bool) {
+	if !m.LogLimits.Limited() {
+		return true, false
+	}
+	c := m.counter(ctx, step)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c.Truncated {
+		return false, false
+	}
+	size := int64(len(line.Message))
+	s := m.stageLogs[c.stage]
+	if c.Exceeds(size, m.LogLimits.StepBytes, m.LogLimits.StepLines) ||
+		(s != nil && s.Exceeds(size, m.LogLimits.StageBytes, m.LogLimits.StageLines)) {
+		c.Truncated = true
+		return false, true
+	}
+	c.Add(size)
+	if s != nil {
+		s.Add(size)
+	}
+	return true, false
+}
+
+// truncateUpload returns the complete step logs truncated to
+// the step and stage log limits. The stage logs are counted
+// using the complete step logs in place of the lines written
+// to the live stream.
+func (m *Manager) truncateUpload(ctx context.Context, step int64, r io.Reader) ([]byte, error) {
+	c := m.counter(ctx, step)
+	m.mu.Lock()
+	var stage core.LogCounter
+	s := m.stageLogs[c.stage]
+	if s != nil {
+		stage.Bytes = s.Bytes - c.Bytes
+		stage.Lines = s.Lines - c.Lines
+	}
+	m.mu.Unlock()
+
+	data, counted, err := truncateLogs(r, m.LogLimits, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s != nil {
+		s.Bytes += counted.Bytes - c.Bytes
+		s.Lines += counted.Lines - c.Lines
+	}
+	c.Bytes = counted.Bytes
+	c.Lines = counted.Lines
+	c.Truncated = c.Truncated || counted.Truncated
+	return data, nil
+}
+
+// counter returns the log counter for the step, creating it
+// if it does not exist. If the stage logs are limited the
+// step is resolved to its stage, and the stage logs are
+// counted across all steps in the stage.
+func (m *Manager) counter(ctx context.Context, step int64) *logCounter {
+	m.mu.Lock()
+	c, exists := m.logs[step]
+	m.mu.Unlock()
+	if exists {
+		return c
+	}
+
+	var stage int64
+	if m.LogLimits.StageBytes != 0 || m.LogLimits.StageLines != 0 {
+		stepp, err := m.Steps.Find(ctx, step)
+		if err != nil {
+			logger := logrus.WithError(err)
+			logger = logger.WithField("step-id", step)
+			logger.Warnln("manager: cannot find step, stage log limits ignored")
+		} else {
+			stage = stepp.StageID
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, exists := m.logs[step]; exists {
+		return c
+	}
+	if m.logs == nil {
+		m.logs = map[int64]*logCounter{}
+	}
+	c = &logCounter{stage: stage}
+	m.logs[step] = c
+	if stage == 0 {
+		return c
+	}
+	if m.stageLogs == nil {
+		m.stageLogs = map[int64]*core.LogCounter{}
+	}
+	if _, exists := m.stageLogs[stage]; !exists {
+		m.stageLogs[stage] = new(core.LogCounter)
+	}
+	return c
+}
+
+// releaseLogs releases the log counter for the step, and
+// returns true if the step logs were truncated.
+func (m *Manager) releaseLogs(step int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, exists := m.logs[step]
+	delete(m.logs, step)
+	return exists && c.Truncated
+}
+
+// releaseStage releases the log counters for the stage, and
+// for any steps in the stage that did not complete, such as
+// steps that were skipped or cancelled.
+func (m *Manager) releaseStage(stage *core.Stage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, step := range stage.Steps {
+		delete(m.logs, step.ID)
+	}
+	for id, c := range m.logs {
+		if c.stage == stage.ID {
+			delete(m.logs, id)
+		}
+	}
+	delete(m.stageLogs, stage.ID)
+}
+
+// helper function decodes the json-encoded log lines from
+// the reader, and returns the re-encoded lines truncated to
+// the step and stage log limits, with the size of the step
+// logs. The stage counter holds the size of the logs written
+// by the other steps in the stage. The reader is decoded
+// incrementally and is not read past the limits.
+func truncateLogs(r io.Reader, limits core.LogLimits, stage core.LogCounter) ([]byte, core.LogCounter, error) {
+	var step core.LogCounter
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return nil, step, err
+	}
+	var lines []*core.Line
+	for dec.More() {
+		line := new(core.Line)
+		if err := dec.Decode(line); err != nil {
+			return nil, step, err
+		}
+		size := int64(len(line.Message))
+		if step.Exceeds(size, limits.StepBytes, limits.StepLines) ||
+			stage.Exceeds(size, limits.StageBytes, limits.StageLines) {
+			lines = append(lines, core.TruncatedLine(line.Number, line.Timestamp))
+			step.Truncated = true
+			break
+		}
+		step.Add(size)
+		stage.Add(size)
+		lines = append(lines, line)
+	}
+	data, err := json.Marshal(lines)
+	return data, step, err
+}
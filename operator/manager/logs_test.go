@@ -0,0 +1,166 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAcceptLine(t *testing.T) {
+	m := &Manager{LogLimits: core.LogLimits{StepLines: 1}}
+	if ok, _ := m.acceptLine(noContext, 1, &core.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want first line accepted")
+	}
+	if ok, truncated := m.acceptLine(noContext, 1, &core.Line{Message: "world\n"}); ok || !truncated {
+		t.Errorf("Want second line truncated")
+	}
+	if ok, truncated := m.acceptLine(noContext, 1, &core.Line{Message: "!\n"}); ok || truncated {
+		t.Errorf("Want subsequent lines rejected")
+	}
+	if ok, _ := m.acceptLine(noContext, 2, &core.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want line accepted for a different step")
+	}
+	if !m.releaseLogs(1) {
+		t.Errorf("Want step logs truncated")
+	}
+	if m.releaseLogs(2) {
+		t.Errorf("Want step logs not truncated")
+	}
+	if got, want := len(m.logs), 0; got != want {
+		t.Errorf("Want log counters released")
+	}
+}
+
+func TestAcceptLine_Unlimited(t *testing.T) {
+	m := new(Manager)
+	for i := 0; i < 100; i++ {
+		if ok, _ := m.acceptLine(noContext, 1, &core.Line{Message: "hello\n"}); !ok {
+			t.Errorf("Want line %d accepted", i)
+		}
+	}
+	if m.logs != nil {
+		t.Errorf("Want no log counters when unlimited")
+	}
+}
+
+func TestAcceptLine_Stage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), int64(1)).Return(&core.Step{ID: 1, StageID: 3}, nil)
+	steps.EXPECT().Find(gomock.Any(), int64(2)).Return(&core.Step{ID: 2, StageID: 3}, nil)
+
+	m := &Manager{Steps: steps, LogLimits: core.LogLimits{StageLines: 2}}
+	if ok, _ := m.acceptLine(noContext, 1, &core.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want first line accepted")
+	}
+	if ok, _ := m.acceptLine(noContext, 2, &core.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want second line accepted")
+	}
+	if ok, truncated := m.acceptLine(noContext, 2, &core.Line{Message: "world\n"}); ok || !truncated {
+		t.Errorf("Want stage limit enforced across steps")
+	}
+
+	// the counters are released when the stage completes,
+	// including the counters for steps that did not.
+	m.releaseStage(&core.Stage{ID: 3})
+	if got, want := len(m.logs), 0; got != want {
+		t.Errorf("Want step log counters released")
+	}
+	if got, want := len(m.stageLogs), 0; got != want {
+		t.Errorf("Want stage log counters released")
+	}
+}
+
+func TestTruncateLogs(t *testing.T) {
+	lines := []*core.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: "world\n", Timestamp: 2},
+		{Number: 2, Message: "!\n", Timestamp: 3},
+	}
+	data, _ := json.Marshal(lines)
+
+	out, step, err := truncateLogs(strings.NewReader(string(data)), core.LogLimits{StepBytes: 10}, core.LogCounter{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !step.Truncated {
+		t.Errorf("Want logs truncated")
+	}
+	got := []*core.Line{}
+	json.Unmarshal(out, &got)
+	want := []*core.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: core.LogTruncated + "\n", Timestamp: 2},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestTruncateLogs_WithinLimits(t *testing.T) {
+	lines := []*core.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+	}
+	data, _ := json.Marshal(lines)
+
+	out, step, err := truncateLogs(strings.NewReader(string(data)), core.LogLimits{StepLines: 1}, core.LogCounter{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if step.Truncated {
+		t.Errorf("Want logs not truncated")
+	}
+	if got, want := string(out), string(data); got != want {
+		t.Errorf("Want logs %s, got %s", want, got)
+	}
+}
+
+func TestTruncateLogs_Stage(t *testing.T) {
+	lines := []*core.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: "world\n", Timestamp: 2},
+	}
+	data, _ := json.Marshal(lines)
+
+	stage := core.LogCounter{Lines: 2}
+	out, step, err := truncateLogs(strings.NewReader(string(data)), core.LogLimits{StageLines: 3}, stage)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !step.Truncated {
+		t.Errorf("Want logs truncated")
+	}
+	if got, want := step.Lines, 1; got != want {
+		t.Errorf("Want %d lines counted, got %d", want, got)
+	}
+	got := []*core.Line{}
+	json.Unmarshal(out, &got)
+	want := []*core.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: core.LogTruncated + "\n", Timestamp: 2},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestTruncateLogs_Invalid(t *testing.T) {
+	_, _, err := truncateLogs(strings.NewReader(""), core.LogLimits{StepLines: 1}, core.LogCounter{})
+	if err == nil {
+		t.Errorf("Want error decoding empty logs")
+	}
+}
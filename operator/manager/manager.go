@@ -17,11 +17,14 @@ package manager
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/trace"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/sirupsen/logrus"
@@ -41,6 +44,7 @@ type (
 		Config  *core.File       `json:"config"`
 		Secrets []*core.Secret   `json:"secrets"`
 		System  *core.System     `json:"system"`
+		Outputs []*core.Output   `json:"outputs,omitempty"`
 	}
 
 	// BuildManager encapsulets complex build operations and provides
@@ -73,6 +77,9 @@ type (
 		// Watch watches for build cancellation requests.
 		Watch(ctx context.Context, stage int64) (bool, error)
 
+		// Drained watches for runner drain requests.
+		Drained(ctx context.Context, machine string) (bool, error)
+
 		// Write writes a line to the build logs
 		Write(ctx context.Context, step int64, line *core.Line) error
 
@@ -81,6 +88,27 @@ type (
 
 		// UploadBytes uploads the full logs
 		UploadBytes(ctx context.Context, step int64, b []byte) error
+
+		// UploadArtifact uploads a build artifact
+		UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error
+
+		// UploadReport uploads a junit test report
+		UploadReport(ctx context.Context, step int64, r io.Reader) error
+
+		// UploadOutputs uploads the step outputs
+		UploadOutputs(ctx context.Context, step int64, outputs []*core.Output) error
+
+		// UploadAnnotations uploads the step annotations and
+		// the optional summary card
+		UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation, card *core.Card) error
+
+		// RestoreCache returns the build cache matching the key,
+		// or the first matching fallback prefix. If no cache
+		// matches, a nil reader is returned.
+		RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error)
+
+		// SaveCache saves the build cache.
+		SaveCache(ctx context.Context, step int64, key string, r io.Reader) error
 	}
 
 	// Request provildes filters when requesting a pending
@@ -99,12 +127,19 @@ type (
 
 // New returns a new Manager.
 func New(
+	annotations core.AnnotationStore,
+	artifacts core.ArtifactStore,
 	builds core.BuildStore,
+	caches core.CacheStore,
+	comments core.CommentService,
 	config core.ConfigService,
 	events core.Pubsub,
+	limits core.LogLimits,
 	logs core.LogStore,
 	logz core.LogStream,
 	netrcs core.NetrcService,
+	notifier core.Notifier,
+	outputs core.OutputStore,
 	repos core.RepositoryStore,
 	scheduler core.Scheduler,
 	secrets core.SecretStore,
@@ -113,48 +148,71 @@ func New(
 	stages core.StageStore,
 	steps core.StepStore,
 	system *core.System,
+	tests core.TestStore,
 	users core.UserStore,
 	webhook core.WebhookSender,
 ) BuildManager {
 	return &Manager{
-		Builds:    builds,
-		Config:    config,
-		Events:    events,
-		Globals:   globals,
-		Logs:      logs,
-		Logz:      logz,
-		Netrcs:    netrcs,
-		Repos:     repos,
-		Scheduler: scheduler,
-		Secrets:   secrets,
-		Status:    status,
-		Stages:    stages,
-		Steps:     steps,
-		System:    system,
-		Users:     users,
-		Webhook:   webhook,
+		Annotations: annotations,
+		Artifacts:   artifacts,
+		Builds:      builds,
+		Caches:      caches,
+		Comments:    comments,
+		Config:      config,
+		Events:      events,
+		Globals:     globals,
+		LogLimits:   limits,
+		Logs:        logs,
+		Logz:        logz,
+		Netrcs:      netrcs,
+		Notifier:    notifier,
+		Outputs:     outputs,
+		Repos:       repos,
+		Scheduler:   scheduler,
+		Secrets:     secrets,
+		Status:      status,
+		Stages:      stages,
+		Steps:       steps,
+		System:      system,
+		Tests:       tests,
+		Users:       users,
+		Webhook:     webhook,
 	}
 }
 
 // Manager provides a simplified interface to the build runner so that it
 // can more easily interact with the server.
 type Manager struct {
-	Builds    core.BuildStore
-	Config    core.ConfigService
-	Events    core.Pubsub
-	Globals   core.GlobalSecretStore
-	Logs      core.LogStore
-	Logz      core.LogStream
-	Netrcs    core.NetrcService
-	Repos     core.RepositoryStore
-	Scheduler core.Scheduler
-	Secrets   core.SecretStore
-	Status    core.StatusService
-	Stages    core.StageStore
-	Steps     core.StepStore
-	System    *core.System
-	Users     core.UserStore
-	Webhook   core.WebhookSender
+	Annotations core.AnnotationStore
+	Artifacts   core.ArtifactStore
+	Builds      core.BuildStore
+	Caches      core.CacheStore
+	Comments    core.CommentService
+	Config      core.ConfigService
+	Events      core.Pubsub
+	Globals     core.GlobalSecretStore
+	LogLimits   core.LogLimits
+	Logs        core.LogStore
+	Logz        core.LogStream
+	Netrcs      core.NetrcService
+	Notifier    core.Notifier
+	Outputs     core.OutputStore
+	Repos       core.RepositoryStore
+	Scheduler   core.Scheduler
+	Secrets     core.SecretStore
+	Status      core.StatusService
+	Stages      core.StageStore
+	Steps       core.StepStore
+	System      *core.System
+	Tests       core.TestStore
+	Users       core.UserStore
+	Webhook     core.WebhookSender
+
+	// log counters for the live log streams, used to
+	// enforce the step and stage log limits.
+	mu        sync.Mutex
+	logs      map[int64]*logCounter
+	stageLogs map[int64]*core.LogCounter
 }
 
 // Request requests the next available build stage for execution.
@@ -227,10 +285,62 @@ func (m *Manager) Accept(ctx context.Context, id int64, machine string) error {
 		logger.Debugln("manager: cannot update stage")
 	} else {
 		logger.Debugln("manager: stage accepted")
+		m.sendStage(stage, core.WebhookActionStarted)
 	}
 	return err
 }
 
+// sendStage sends the stage webhook, including the enclosing
+// build and repository, and the stage commit status. The time
+// the stage waited in the queue is recorded in the build trace.
+// Errors are logged and ignored.
+func (m *Manager) sendStage(stage *core.Stage, action string) {
+	logger := logrus.WithField("stage.id", stage.ID)
+
+	build, err := m.Builds.Find(noContext, stage.BuildID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find build")
+		return
+	}
+	if build.Trace != "" {
+		trace.Record(
+			trace.Decode(noContext, build.Trace),
+			"queue",
+			time.Unix(stage.Created, 0),
+			time.Now(),
+		)
+	}
+	repo, err := m.Repos.Find(noContext, build.RepoID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find repo")
+		return
+	}
+	err = m.Webhook.Send(noContext, &core.WebhookData{
+		Event:  core.WebhookEventStage,
+		Action: action,
+		Repo:   repo,
+		Build:  build,
+		Stage:  stage,
+	})
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot send stage webhook")
+	}
+
+	user, err := m.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find repository owner")
+		return
+	}
+	err = m.Status.Send(noContext, user, &core.StatusInput{
+		Repo:  repo,
+		Build: build,
+		Stage: stage,
+	})
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot publish stage status")
+	}
+}
+
 // Details fetches build details.
 func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 	logger := logrus.WithField("step-id", id)
@@ -313,6 +423,12 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 		}
 		secrets = append(secrets, secret)
 	}
+	outputs, err := m.Outputs.List(noContext, build.ID)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("manager: cannot list outputs")
+		return nil, err
+	}
 	return &Context{
 		Repo:    repo,
 		Build:   build,
@@ -320,6 +436,7 @@ func (m *Manager) Details(ctx context.Context, id int64) (*Context, error) {
 		Secrets: secrets,
 		System:  m.System,
 		Config:  &core.File{Data: []byte(config.Data)},
+		Outputs: filterOutputs(outputs, stage, stages),
 	}, nil
 }
 
@@ -341,12 +458,13 @@ func (m *Manager) Before(ctx context.Context, step *core.Step) error {
 		return err
 	}
 	updater := &updater{
-		Builds:  m.Builds,
-		Events:  m.Events,
-		Repos:   m.Repos,
-		Steps:   m.Steps,
-		Stages:  m.Stages,
-		Webhook: m.Webhook,
+		Annotations: m.Annotations,
+		Builds:      m.Builds,
+		Events:      m.Events,
+		Repos:       m.Repos,
+		Steps:       m.Steps,
+		Stages:      m.Stages,
+		Webhook:     m.Webhook,
 	}
 	return updater.do(ctx, step)
 }
@@ -362,14 +480,19 @@ func (m *Manager) After(ctx context.Context, step *core.Step) error {
 	)
 	logger.Debugln("manager: updating step status")
 
+	if m.releaseLogs(step.ID) {
+		step.Truncated = true
+	}
+
 	var errs error
 	updater := &updater{
-		Builds:  m.Builds,
-		Events:  m.Events,
-		Repos:   m.Repos,
-		Steps:   m.Steps,
-		Stages:  m.Stages,
-		Webhook: m.Webhook,
+		Annotations: m.Annotations,
+		Builds:      m.Builds,
+		Events:      m.Events,
+		Repos:       m.Repos,
+		Steps:       m.Steps,
+		Stages:      m.Stages,
+		Webhook:     m.Webhook,
 	}
 
 	if err := updater.do(ctx, step); err != nil {
@@ -388,13 +511,14 @@ func (m *Manager) After(ctx context.Context, step *core.Step) error {
 // BeforeAll signals the build stage is about to start.
 func (m *Manager) BeforeAll(ctx context.Context, stage *core.Stage) error {
 	s := &setup{
-		Builds: m.Builds,
-		Events: m.Events,
-		Repos:  m.Repos,
-		Steps:  m.Steps,
-		Stages: m.Stages,
-		Status: m.Status,
-		Users:  m.Users,
+		Builds:   m.Builds,
+		Comments: m.Comments,
+		Events:   m.Events,
+		Repos:    m.Repos,
+		Steps:    m.Steps,
+		Stages:   m.Stages,
+		Status:   m.Status,
+		Users:    m.Users,
 	}
 	return s.do(ctx, stage)
 }
@@ -403,15 +527,19 @@ func (m *Manager) BeforeAll(ctx context.Context, stage *core.Stage) error {
 func (m *Manager) AfterAll(ctx context.Context, stage *core.Stage) error {
 	t := &teardown{
 		Builds:    m.Builds,
+		Comments:  m.Comments,
 		Events:    m.Events,
 		Logs:      m.Logz,
+		Notifier:  m.Notifier,
 		Repos:     m.Repos,
 		Scheduler: m.Scheduler,
 		Steps:     m.Steps,
 		Stages:    m.Stages,
 		Status:    m.Status,
 		Users:     m.Users,
+		Webhook:   m.Webhook,
 	}
+	m.releaseStage(stage)
 	return t.do(ctx, stage)
 }
 
@@ -463,8 +591,19 @@ func (m *Manager) Watch(ctx context.Context, id int64) (bool, error) {
 	return stage.IsDone(), nil
 }
 
+// Drained watches for runner drain requests.
+func (m *Manager) Drained(ctx context.Context, machine string) (bool, error) {
+	return m.Scheduler.Drained(ctx, machine)
+}
+
 // Write writes a line to the build logs.
 func (m *Manager) Write(ctx context.Context, step int64, line *core.Line) error {
+	if ok, truncated := m.acceptLine(ctx, step, line); !ok {
+		if !truncated {
+			return nil
+		}
+		line = core.TruncatedLine(line.Number, line.Timestamp)
+	}
 	err := m.Logz.Write(ctx, step, line)
 	if err != nil {
 		logger := logrus.WithError(err)
@@ -476,6 +615,16 @@ func (m *Manager) Write(ctx context.Context, step int64, line *core.Line) error
 
 // Upload uploads the full logs.
 func (m *Manager) Upload(ctx context.Context, step int64, r io.Reader) error {
+	if m.LogLimits.Limited() {
+		data, err := m.truncateUpload(ctx, step, r)
+		if err != nil {
+			logger := logrus.WithError(err)
+			logger = logger.WithField("step-id", step)
+			logger.Warnln("manager: cannot decode complete logs")
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
 	err := m.Logs.Create(ctx, step, r)
 	if err != nil {
 		logger := logrus.WithError(err)
@@ -487,12 +636,245 @@ func (m *Manager) Upload(ctx context.Context, step int64, r io.Reader) error {
 
 // UploadBytes uploads the full logs.
 func (m *Manager) UploadBytes(ctx context.Context, step int64, data []byte) error {
-	buf := bytes.NewBuffer(data)
-	err := m.Logs.Create(ctx, step, buf)
+	return m.Upload(ctx, step, bytes.NewBuffer(data))
+}
+
+// UploadArtifact uploads a build artifact.
+func (m *Manager) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"step-id":  step,
+			"artifact": name,
+		},
+	)
+
+	stepp, stage, build, err := m.lookup(step)
 	if err != nil {
-		logger := logrus.WithError(err)
-		logger = logger.WithField("step-id", step)
-		logger.Warnln("manager: cannot upload complete logs")
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+
+	artifact := &core.Artifact{
+		RepoID:  build.RepoID,
+		BuildID: build.ID,
+		StageID: stage.ID,
+		StepID:  stepp.ID,
+		Name:    name,
+		Created: time.Now().Unix(),
+	}
+	err = artifact.Validate()
+	if err != nil {
+		logger.WithError(err).Warnln("manager: invalid artifact")
+		return err
+	}
+	err = m.Artifacts.Create(ctx, artifact, r)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot upload artifact")
+	}
+	return err
+}
+
+// UploadReport uploads a junit test report.
+func (m *Manager) UploadReport(ctx context.Context, step int64, r io.Reader) error {
+	logger := logrus.WithField("step-id", step)
+
+	stepp, stage, build, err := m.lookup(step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+
+	tests, err := parseJUnit(r)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot parse test report")
+		return err
+	}
+	created := time.Now().Unix()
+	for _, test := range tests {
+		test.RepoID = build.RepoID
+		test.BuildID = build.ID
+		test.StageID = stage.ID
+		test.StepID = stepp.ID
+		test.Created = created
+	}
+	err = m.Tests.Create(ctx, tests)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot save test report")
 	}
 	return err
 }
+
+// UploadOutputs uploads the step outputs.
+func (m *Manager) UploadOutputs(ctx context.Context, step int64, outputs []*core.Output) error {
+	logger := logrus.WithField("step-id", step)
+
+	stepp, stage, build, err := m.lookup(step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+	for _, output := range outputs {
+		err := output.Validate()
+		if err != nil {
+			logger.WithError(err).
+				WithField("output", output.Name).
+				Warnln("manager: invalid output")
+			return err
+		}
+		output.BuildID = build.ID
+		output.StageID = stage.ID
+		output.StepID = stepp.ID
+	}
+	err = m.Outputs.Create(ctx, outputs)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot save outputs")
+	}
+	return err
+}
+
+// UploadAnnotations uploads the step annotations and the
+// optional summary card.
+func (m *Manager) UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation, card *core.Card) error {
+	logger := logrus.WithField("step-id", step)
+
+	stepp, stage, build, err := m.lookup(step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find step")
+		return err
+	}
+	for _, annotation := range annotations {
+		err := annotation.Validate()
+		if err != nil {
+			logger.WithError(err).Warnln("manager: invalid annotation")
+			return err
+		}
+		annotation.BuildID = build.ID
+		annotation.StageID = stage.ID
+		annotation.StepID = stepp.ID
+	}
+	if len(annotations) != 0 {
+		err = m.Annotations.Create(ctx, annotations)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot save annotations")
+			return err
+		}
+	}
+	if card == nil {
+		return nil
+	}
+	err = card.Validate()
+	if err != nil {
+		logger.WithError(err).Warnln("manager: invalid card")
+		return err
+	}
+	card.BuildID = build.ID
+	card.StageID = stage.ID
+	card.StepID = stepp.ID
+	err = m.Annotations.CreateCard(ctx, card)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot save card")
+	}
+	return err
+}
+
+// RestoreCache returns the build cache matching the key.
+// Caches are scoped to the git reference of the build. If
+// no cache matches in the build scope, the cache saved by
+// the default branch is restored, which ensures builds for
+// pull requests can read, but never write, the default
+// branch cache.
+func (m *Manager) RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error) {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"step-id": step,
+			"cache":   key,
+		},
+	)
+
+	_, _, build, err := m.lookup(step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find build")
+		return nil, err
+	}
+	repo, err := m.Repos.Find(noContext, build.RepoID)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find repository")
+		return nil, err
+	}
+
+	scopes := []string{cacheScope(build.Ref)}
+	if fallback := cacheScope("refs/heads/" + repo.Branch); fallback != scopes[0] {
+		scopes = append(scopes, fallback)
+	}
+	for _, scope := range scopes {
+		cache, err := m.Caches.Find(ctx, repo.ID,
+			scopeCacheKey(scope, key),
+			scopeCacheKeys(scope, prefixes),
+		)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot find cache")
+			return nil, err
+		}
+		rc, err := m.Caches.Open(ctx, cache)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot open cache")
+			return nil, err
+		}
+		return rc, nil
+	}
+	logger.Debugln("manager: cache not found")
+	return nil, nil
+}
+
+// SaveCache saves the build cache, scoped to the git
+// reference of the build.
+func (m *Manager) SaveCache(ctx context.Context, step int64, key string, r io.Reader) error {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"step-id": step,
+			"cache":   key,
+		},
+	)
+
+	_, _, build, err := m.lookup(step)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot find build")
+		return err
+	}
+
+	cache := &core.Cache{
+		RepoID: build.RepoID,
+		Key:    scopeCacheKey(cacheScope(build.Ref), key),
+	}
+	err = cache.Validate()
+	if err != nil {
+		logger.WithError(err).Warnln("manager: invalid cache key")
+		return err
+	}
+	err = m.Caches.Save(ctx, cache, r)
+	if err != nil {
+		logger.WithError(err).Warnln("manager: cannot save cache")
+	}
+	return err
+}
+
+// helper function returns the step, and its parent stage
+// and build, for the given step id.
+func (m *Manager) lookup(id int64) (*core.Step, *core.Stage, *core.Build, error) {
+	step, err := m.Steps.Find(noContext, id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stage, err := m.Stages.Find(noContext, step.StageID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	build, err := m.Builds.Find(noContext, stage.BuildID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return step, stage, build, nil
+}
@@ -5,11 +5,109 @@
 package manager
 
 import (
+	"bytes"
+	"database/sql"
 	"io/ioutil"
+	"testing"
 
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
 )
 
 func init() {
 	logrus.SetOutput(ioutil.Discard)
 }
+
+func TestRestoreCache_Fallback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{ID: 1, Branch: "master"}
+	mockBuild := &core.Build{ID: 3, RepoID: 1, Ref: "refs/pull/42/head"}
+	mockCache := &core.Cache{ID: 4, RepoID: 1, Key: "heads/master/deps"}
+
+	m := mockCacheManager(controller, mockBuild)
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+	m.Repos = repos
+
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Find(gomock.Any(), mockRepo.ID, "pull/42/head/deps", []string{"pull/42/head/deps-"}).Return(nil, sql.ErrNoRows)
+	caches.EXPECT().Find(gomock.Any(), mockRepo.ID, "heads/master/deps", []string{"heads/master/deps-"}).Return(mockCache, nil)
+	caches.EXPECT().Open(gomock.Any(), mockCache).Return(ioutil.NopCloser(bytes.NewBufferString("hello")), nil)
+	m.Caches = caches
+
+	rc, err := m.RestoreCache(noContext, 1, "deps", []string{"deps-"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if rc == nil {
+		t.Errorf("Want default branch cache restored")
+	}
+}
+
+func TestRestoreCache_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockRepo := &core.Repository{ID: 1, Branch: "master"}
+	mockBuild := &core.Build{ID: 3, RepoID: 1, Ref: "refs/heads/master"}
+
+	m := mockCacheManager(controller, mockBuild)
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), mockRepo.ID).Return(mockRepo, nil)
+	m.Repos = repos
+
+	// the default branch scope is searched once.
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Find(gomock.Any(), mockRepo.ID, "heads/master/deps", nil).Return(nil, sql.ErrNoRows).Times(1)
+	m.Caches = caches
+
+	rc, err := m.RestoreCache(noContext, 1, "deps", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if rc != nil {
+		t.Errorf("Want nil reader when cache not found")
+	}
+}
+
+func TestSaveCache_Scoped(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockBuild := &core.Build{ID: 3, RepoID: 1, Ref: "refs/pull/42/head"}
+
+	m := mockCacheManager(controller, mockBuild)
+	caches := mock.NewMockCacheStore(controller)
+	caches.EXPECT().Save(gomock.Any(), &core.Cache{RepoID: 1, Key: "pull/42/head/deps"}, gomock.Any()).Return(nil)
+	m.Caches = caches
+
+	err := m.SaveCache(noContext, 1, "deps", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// helper function returns a manager that resolves step 1
+// to the build.
+func mockCacheManager(controller *gomock.Controller, build *core.Build) *Manager {
+	mockStep := &core.Step{ID: 1, StageID: 2}
+	mockStage := &core.Stage{ID: 2, BuildID: build.ID}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Find(gomock.Any(), mockStep.ID).Return(mockStep, nil)
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), mockStage.ID).Return(mockStage, nil)
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), build.ID).Return(build, nil)
+
+	return &Manager{
+		Steps:  steps,
+		Stages: stages,
+		Builds: builds,
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/trace"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/oxtoacart/bpool"
@@ -88,14 +90,14 @@ func (s *Client) Request(ctx context.Context, args *manager.Request) (*core.Stag
 // Accept accepts the build stage for execution.
 func (s *Client) Accept(ctx context.Context, stage int64, machine string) error {
 	in := &acceptRequest{Stage: stage, Machine: machine}
-	return s.send(noContext, "/rpc/v1/accept", in, nil)
+	return s.send(trace.Detach(ctx), "/rpc/v1/accept", in, nil)
 }
 
 // Netrc returns a valid netrc for execution.
 func (s *Client) Netrc(ctx context.Context, repo int64) (*core.Netrc, error) {
 	in := &netrcRequest{repo}
 	out := &core.Netrc{}
-	err := s.send(noContext, "/rpc/v1/netrc", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/netrc", in, out)
 	return out, err
 }
 
@@ -103,7 +105,7 @@ func (s *Client) Netrc(ctx context.Context, repo int64) (*core.Netrc, error) {
 func (s *Client) Details(ctx context.Context, stage int64) (*manager.Context, error) {
 	in := &detailsRequest{Stage: stage}
 	out := &buildContextToken{}
-	err := s.send(noContext, "/rpc/v1/details", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/details", in, out)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +120,7 @@ func (s *Client) Details(ctx context.Context, stage int64) (*manager.Context, er
 func (s *Client) Before(ctx context.Context, step *core.Step) error {
 	in := &stepRequest{Step: step}
 	out := &core.Step{}
-	err := s.send(noContext, "/rpc/v1/before", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/before", in, out)
 	if err != nil {
 		return err
 	}
@@ -134,7 +136,7 @@ func (s *Client) Before(ctx context.Context, step *core.Step) error {
 func (s *Client) After(ctx context.Context, step *core.Step) error {
 	in := &stepRequest{Step: step}
 	out := &core.Step{}
-	err := s.send(noContext, "/rpc/v1/after", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/after", in, out)
 	if err != nil {
 		return err
 	}
@@ -149,7 +151,7 @@ func (s *Client) After(ctx context.Context, step *core.Step) error {
 func (s *Client) BeforeAll(ctx context.Context, stage *core.Stage) error {
 	in := &stageRequest{Stage: stage}
 	out := &core.Stage{}
-	err := s.send(noContext, "/rpc/v1/beforeAll", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/beforeAll", in, out)
 	if err != nil {
 		return err
 	}
@@ -169,7 +171,7 @@ func (s *Client) BeforeAll(ctx context.Context, stage *core.Stage) error {
 func (s *Client) AfterAll(ctx context.Context, stage *core.Stage) error {
 	in := &stageRequest{Stage: stage}
 	out := &core.Stage{}
-	err := s.send(noContext, "/rpc/v1/afterAll", in, out)
+	err := s.send(trace.Detach(ctx), "/rpc/v1/afterAll", in, out)
 	if err != nil {
 		return err
 	}
@@ -189,10 +191,19 @@ func (s *Client) Watch(ctx context.Context, build int64) (bool, error) {
 	return out.Done, err
 }
 
+func (s *Client) Drained(ctx context.Context, machine string) (bool, error) {
+	in := &drainRequest{machine}
+	out := &drainResponse{}
+	err := s.send(ctx, "/rpc/v1/drain", in, out)
+	return out.Drained, err
+}
+
 func (s *Client) Write(ctx context.Context, step int64, line *core.Line) error {
 	in := writePool.Get().(*writeRequest)
 	in.Step = step
 	in.Line = line
+	// log lines are sent without the trace context to
+	// limit the number of spans created per step.
 	err := s.send(noContext, "/rpc/v1/write", in, nil)
 	writePool.Put(in)
 	return err
@@ -200,12 +211,49 @@ func (s *Client) Write(ctx context.Context, step int64, line *core.Line) error {
 
 func (s *Client) Upload(ctx context.Context, step int64, r io.Reader) error {
 	endpoint := "/rpc/v1/upload?id=" + fmt.Sprint(step)
-	return s.upload(noContext, endpoint, r)
+	return s.upload(trace.Detach(ctx), endpoint, r)
 }
 
 func (s *Client) UploadBytes(ctx context.Context, step int64, data []byte) error {
 	endpoint := "/rpc/v1/upload?id=" + fmt.Sprint(step)
-	return s.upload(noContext, endpoint, data)
+	return s.upload(trace.Detach(ctx), endpoint, data)
+}
+
+func (s *Client) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	endpoint := "/rpc/v1/artifact?id=" + fmt.Sprint(step) + "&name=" + url.QueryEscape(name)
+	return s.stream(trace.Detach(ctx), endpoint, r)
+}
+
+func (s *Client) UploadReport(ctx context.Context, step int64, r io.Reader) error {
+	endpoint := "/rpc/v1/report?id=" + fmt.Sprint(step)
+	return s.stream(trace.Detach(ctx), endpoint, r)
+}
+
+func (s *Client) UploadOutputs(ctx context.Context, step int64, outputs []*core.Output) error {
+	in := &outputsRequest{Step: step, Outputs: outputs}
+	return s.send(trace.Detach(ctx), "/rpc/v1/outputs", in, nil)
+}
+
+func (s *Client) UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation, card *core.Card) error {
+	in := &annotationsRequest{Step: step, Annotations: annotations, Card: card}
+	return s.send(trace.Detach(ctx), "/rpc/v1/annotations", in, nil)
+}
+
+func (s *Client) RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error) {
+	params := url.Values{}
+	params.Set("step", fmt.Sprint(step))
+	params.Set("key", key)
+	for _, prefix := range prefixes {
+		params.Add("prefix", prefix)
+	}
+	return s.download(trace.Detach(ctx), "/rpc/v1/cache/restore?"+params.Encode())
+}
+
+func (s *Client) SaveCache(ctx context.Context, step int64, key string, r io.Reader) error {
+	params := url.Values{}
+	params.Set("step", fmt.Sprint(step))
+	params.Set("key", key)
+	return s.stream(trace.Detach(ctx), "/rpc/v1/cache/save?"+params.Encode(), r)
 }
 
 func (s *Client) send(ctx context.Context, path string, in, out interface{}) error {
@@ -227,6 +275,7 @@ func (s *Client) send(ctx context.Context, path string, in, out interface{}) err
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("X-Drone-Token", s.token)
+	trace.Inject(ctx, req.Header)
 
 	res, err := s.client.Do(req)
 	if res != nil {
@@ -276,6 +325,7 @@ func (s *Client) upload(ctx context.Context, path string, body interface{}) erro
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("X-Drone-Token", s.token)
+	trace.Inject(ctx, req.Header)
 
 	res, err := s.client.Do(req)
 	if err != nil {
@@ -293,6 +343,66 @@ func (s *Client) upload(ctx context.Context, path string, body interface{}) erro
 	return nil
 }
 
+// helper function streams the reader to the server as the
+// request body. The body is not buffered in memory, which
+// means the request cannot be retried.
+func (s *Client) stream(ctx context.Context, path string, r io.Reader) error {
+	req, err := http.NewRequest("POST", s.server+path, ioutil.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Drone-Token", s.token)
+	trace.Inject(ctx, req.Header)
+
+	res, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(res.Body)
+		return &serverError{
+			Status:  res.StatusCode,
+			Message: string(body),
+		}
+	}
+	return nil
+}
+
+// helper function downloads the response body. If the server
+// responds with 404 Not Found, a nil reader is returned. The
+// caller is responsible for closing the reader.
+func (s *Client) download(ctx context.Context, path string) (io.ReadCloser, error) {
+	url := s.server + path
+	req, err := retryablehttp.NewRequest("POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Drone-Token", s.token)
+	trace.Inject(ctx, req.Header)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, nil
+	}
+	if res.StatusCode > 299 {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, &serverError{
+			Status:  res.StatusCode,
+			Message: string(body),
+		}
+	}
+	return res.Body, nil
+}
+
 // helper function returns true if the http.Request should be
 // retried based on error and http status code. This function
 // is used by the retryablehttp.Client.
@@ -8,6 +8,7 @@ package rpc
 
 import (
 	"bytes"
+	"io/ioutil"
 	"testing"
 
 	"github.com/drone/drone/core"
@@ -441,6 +442,33 @@ func TestWatch(t *testing.T) {
 	}
 }
 
+func TestDrained(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/drain").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Machine":"agent-1"}`).
+		Reply(200).
+		Type("application/json").
+		BodyString(`{"Drained":true}`)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	drained, err := client.Drained(noContext, "agent-1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !drained {
+		t.Errorf("Want drained=true, got false")
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
 func TestWrite(t *testing.T) {
 	defer gock.Off()
 
@@ -486,6 +514,153 @@ func TestUpload(t *testing.T) {
 	}
 }
 
+func TestUploadArtifact(t *testing.T) {
+	defer gock.Off()
+
+	buf := bytes.NewBufferString("hello world")
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/artifact").
+		MatchParam("id", "1").
+		MatchParam("name", "dist/hello-world").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString("hello world").
+		Reply(200)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadArtifact(noContext, 1, "dist/hello-world", buf)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadReport(t *testing.T) {
+	defer gock.Off()
+
+	buf := bytes.NewBufferString("<testsuites></testsuites>")
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/report").
+		MatchParam("id", "1").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString("<testsuites></testsuites>").
+		Reply(200)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadReport(noContext, 1, buf)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestUploadOutputs(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/outputs").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Step":1,"Outputs":[{"id":0,"build_id":0,"stage_id":0,"step_id":0,"name":"VERSION","value":"1.0.0","secret":false}]}`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadOutputs(noContext, 1, []*core.Output{{Name: "VERSION", Value: "1.0.0"}})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRestoreCache(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/cache/restore").
+		MatchParam("step", "1").
+		MatchParam("key", "node_modules-master").
+		MatchParam("prefix", "node_modules-").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		Reply(200).
+		BodyString("hello world")
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	rc, err := client.RestoreCache(noContext, 1, "node_modules-master", []string{"node_modules-"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer rc.Close()
+	data, _ := ioutil.ReadAll(rc)
+	if got, want := string(data), "hello world"; got != want {
+		t.Errorf("Want cache contents %q, got %q", want, got)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestRestoreCache_NotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/cache/restore").
+		MatchParam("step", "1").
+		MatchParam("key", "node_modules-master").
+		Reply(404)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	rc, err := client.RestoreCache(noContext, 1, "node_modules-master", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if rc != nil {
+		t.Errorf("Want nil reader when cache not found")
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestSaveCache(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/cache/save").
+		MatchParam("step", "1").
+		MatchParam("key", "node_modules-master").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString("hello world").
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.SaveCache(noContext, 1, "node_modules-master", bytes.NewBufferString("hello world"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
 // func xTestRetrySend(t *testing.T) {
 // 	defer gock.Off()
 
@@ -504,3 +679,27 @@ func TestUpload(t *testing.T) {
 // 		t.Errorf("Unfinished requests")
 // 	}
 // }
+
+func TestUploadAnnotations(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("http://drone.company.com").
+		Post("/rpc/v1/annotations").
+		MatchHeader("X-Drone-Token", "correct-horse-battery-staple").
+		BodyString(`{"Step":1,"Annotations":[{"id":0,"build_id":0,"stage_id":0,"step_id":0,"file":"main.go","line":12,"severity":"warning","message":"unused variable"}],"Card":{"id":0,"build_id":0,"stage_id":0,"step_id":0,"data":"# Coverage"}}`).
+		Reply(204)
+
+	client := NewClient("http://drone.company.com", "correct-horse-battery-staple")
+	gock.InterceptClient(client.client.HTTPClient)
+	err := client.UploadAnnotations(noContext, 1,
+		[]*core.Annotation{{File: "main.go", Line: 12, Severity: core.SeverityWarning, Message: "unused variable"}},
+		&core.Card{Data: "# Coverage"},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
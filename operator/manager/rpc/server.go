@@ -11,11 +11,13 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path"
 	"strconv"
 	"time"
 
 	"github.com/drone/drone/operator/manager"
 	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/trace"
 )
 
 // default http request timeout
@@ -48,6 +50,13 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(401) // not authorized
 		return
 	}
+	// requests sent on behalf of a traced build are
+	// recorded as child spans of the runner span.
+	if ctx, ok := trace.Extract(r); ok {
+		ctx, span := trace.Start(ctx, "rpc "+path.Base(r.URL.Path))
+		defer span.End()
+		r = r.WithContext(ctx)
+	}
 	switch r.URL.Path {
 	case "/rpc/v1/write":
 		s.handleWrite(w, r)
@@ -69,8 +78,22 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.handleAfterAll(w, r)
 	case "/rpc/v1/watch":
 		s.handleWatch(w, r)
+	case "/rpc/v1/drain":
+		s.handleDrain(w, r)
 	case "/rpc/v1/upload":
 		s.handleUpload(w, r)
+	case "/rpc/v1/artifact":
+		s.handleArtifact(w, r)
+	case "/rpc/v1/report":
+		s.handleReport(w, r)
+	case "/rpc/v1/outputs":
+		s.handleOutputs(w, r)
+	case "/rpc/v1/annotations":
+		s.handleAnnotations(w, r)
+	case "/rpc/v1/cache/restore":
+		s.handleCacheRestore(w, r)
+	case "/rpc/v1/cache/save":
+		s.handleCacheSave(w, r)
 	default:
 		w.WriteHeader(404)
 	}
@@ -246,6 +269,117 @@ func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (s *Server) handleArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	name := r.FormValue("name")
+	err = s.manager.UploadArtifact(ctx, id, name, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("id")
+	id, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	err = s.manager.UploadReport(ctx, id, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleOutputs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	in := &outputsRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	err = s.manager.UploadOutputs(ctx, in.Step, in.Outputs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	in := &annotationsRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	err = s.manager.UploadAnnotations(ctx, in.Step, in.Annotations, in.Card)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleCacheRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("step")
+	step, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	key := r.FormValue("key")
+	rc, err := s.manager.RestoreCache(ctx, step, key, r.Form["prefix"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if rc == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	defer rc.Close()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	io.Copy(w, rc)
+}
+
+func (s *Server) handleCacheSave(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	in := r.FormValue("step")
+	step, err := strconv.ParseInt(in, 10, 64)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	key := r.FormValue("key")
+	err = s.manager.SaveCache(ctx, step, key, r.Body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
@@ -267,6 +401,27 @@ func (s *Server) handleWatch(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *Server) handleDrain(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	in := &drainRequest{}
+	err := json.NewDecoder(r.Body).Decode(in)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	drained, err := s.manager.Drained(ctx, in.Machine)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(&drainResponse{
+		Drained: drained,
+	})
+}
+
 func writeBadRequest(w http.ResponseWriter, err error) {
 	w.WriteHeader(500) // should retry
 	io.WriteString(w, err.Error())
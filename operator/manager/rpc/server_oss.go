@@ -97,5 +97,40 @@ func (Server) UploadBytes(ctx context.Context, step int64, b []byte) error {
 	return errors.New("not implemented")
 }
 
+// UploadArtifact uploads a build artifact
+func (Server) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	return errors.New("not implemented")
+}
+
+// UploadReport uploads a junit test report
+func (Server) UploadReport(ctx context.Context, step int64, r io.Reader) error {
+	return errors.New("not implemented")
+}
+
+// UploadOutputs uploads the step outputs
+func (Server) UploadOutputs(ctx context.Context, step int64, outputs []*core.Output) error {
+	return errors.New("not implemented")
+}
+
+// UploadAnnotations uploads the step annotations
+func (Server) UploadAnnotations(ctx context.Context, step int64, annotations []*core.Annotation, card *core.Card) error {
+	return errors.New("not implemented")
+}
+
+// RestoreCache returns the build cache matching the key
+func (Server) RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+// SaveCache saves the build cache
+func (Server) SaveCache(ctx context.Context, step int64, key string, r io.Reader) error {
+	return errors.New("not implemented")
+}
+
+// Drained watches for runner drain requests.
+func (Server) Drained(ctx context.Context, machine string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
 // ServeHTTP is an empty handler.
 func (Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
@@ -43,6 +43,17 @@ type writeRequest struct {
 	Line *core.Line
 }
 
+type outputsRequest struct {
+	Step    int64
+	Outputs []*core.Output
+}
+
+type annotationsRequest struct {
+	Step        int64
+	Annotations []*core.Annotation
+	Card        *core.Card
+}
+
 type watchRequest struct {
 	Build int64
 }
@@ -51,6 +62,14 @@ type watchResponse struct {
 	Done bool
 }
 
+type drainRequest struct {
+	Machine string
+}
+
+type drainResponse struct {
+	Drained bool
+}
+
 type buildContextToken struct {
 	Secret  string
 	Context *manager.Context
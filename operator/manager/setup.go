@@ -27,13 +27,14 @@ import (
 )
 
 type setup struct {
-	Builds core.BuildStore
-	Events core.Pubsub
-	Repos  core.RepositoryStore
-	Steps  core.StepStore
-	Stages core.StageStore
-	Status core.StatusService
-	Users  core.UserStore
+	Builds   core.BuildStore
+	Comments core.CommentService
+	Events   core.Pubsub
+	Repos    core.RepositoryStore
+	Steps    core.StepStore
+	Stages   core.StageStore
+	Status   core.StatusService
+	Users    core.UserStore
 }
 
 func (s *setup) do(ctx context.Context, stage *core.Stage) error {
@@ -140,9 +141,32 @@ func (s *setup) do(ctx context.Context, stage *core.Stage) error {
 		}
 	}
 
+	s.sendComment(repo, build, stages)
 	return nil
 }
 
+// sendComment is a helper function that posts or updates the
+// pull request comment when the stage starts. Errors are
+// logged and ignored.
+func (s *setup) sendComment(repo *core.Repository, build *core.Build, stages []*core.Stage) {
+	logger := logrus.WithField("build.id", build.ID)
+	user, err := s.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot find repository owner")
+		return
+	}
+	err = s.Comments.Send(noContext, user, &core.CommentInput{
+		Repo:   repo,
+		Build:  build,
+		Stages: stages,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot publish pull request comment")
+	}
+}
+
 // TODO(bradrydzewski) this should really be encapsulated into a single
 // function call that internally uses a database transaction so that we
 // can rollback if any operations fail.
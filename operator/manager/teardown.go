@@ -27,16 +27,23 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// notifyTimeout is the maximum time allowed to send the build
+// outcome notifications.
+const notifyTimeout = time.Minute * 5
+
 type teardown struct {
 	Builds    core.BuildStore
+	Comments  core.CommentService
 	Events    core.Pubsub
 	Logs      core.LogStream
+	Notifier  core.Notifier
 	Scheduler core.Scheduler
 	Repos     core.RepositoryStore
 	Steps     core.StepStore
 	Status    core.StatusService
 	Stages    core.StageStore
 	Users     core.UserStore
+	Webhook   core.WebhookSender
 }
 
 func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
@@ -87,12 +94,26 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		t.Logs.Delete(noContext, step.ID)
 	}
 
+	err = t.Webhook.Send(noContext, &core.WebhookData{
+		Event:  core.WebhookEventStage,
+		Action: core.WebhookActionFinished,
+		Repo:   repo,
+		Build:  build,
+		Stage:  stage,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot send stage webhook")
+	}
+
 	stages, err := t.Stages.ListSteps(noContext, build.ID)
 	if err != nil {
 		logger.WithError(err).Warnln("manager: cannot get stages")
 		return err
 	}
 
+	t.sendStageStatus(repo, build, stage)
+
 	//
 	//
 	//
@@ -117,6 +138,7 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 
 	if isBuildComplete(stages) == false {
 		logger.Debugln("manager: build pending completion of additional stages")
+		t.sendComment(repo, build, stages)
 		return nil
 	}
 
@@ -164,6 +186,13 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 			Warnln("manager: cannot publish build event")
 	}
 
+	// notifications are sent in the background to prevent
+	// a slow chat or smtp server from blocking the teardown.
+	// Copies are passed since the build is modified below.
+	notifyRepo, notifyBuild := *repo, *build
+	notifyRepo.Build = &notifyBuild
+	go t.notify(&notifyRepo, &notifyBuild)
+
 	user, err := t.Users.Find(noContext, repo.UserID)
 	if err != nil {
 		logger.WithError(err).
@@ -184,9 +213,79 @@ func (t *teardown) do(ctx context.Context, stage *core.Stage) error {
 		logger.WithError(err).
 			Warnln("manager: cannot publish status")
 	}
+
+	err = t.Comments.Send(noContext, user, &core.CommentInput{
+		Repo:   repo,
+		Build:  build,
+		Stages: stages,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot publish pull request comment")
+	}
 	return nil
 }
 
+// sendStageStatus is a helper function that sends the stage
+// commit status. Errors are logged and ignored.
+func (t *teardown) sendStageStatus(repo *core.Repository, build *core.Build, stage *core.Stage) {
+	logger := logrus.WithField("stage.id", stage.ID)
+	user, err := t.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot find repository owner")
+		return
+	}
+	err = t.Status.Send(noContext, user, &core.StatusInput{
+		Repo:  repo,
+		Build: build,
+		Stage: stage,
+	})
+	if err != nil && err != scm.ErrNotSupported {
+		logger.WithError(err).
+			Warnln("manager: cannot publish stage status")
+	}
+}
+
+// notify is a helper function that sends the build outcome
+// notifications with a bounded timeout. Errors are logged and
+// ignored.
+func (t *teardown) notify(repo *core.Repository, build *core.Build) {
+	ctx, cancel := context.WithTimeout(noContext, notifyTimeout)
+	defer cancel()
+	err := t.Notifier.Notify(ctx, &core.NotifyInput{
+		Repo:  repo,
+		Build: build,
+	})
+	if err != nil {
+		logrus.WithError(err).
+			WithField("build.id", build.ID).
+			Warnln("manager: cannot send build notifications")
+	}
+}
+
+// sendComment is a helper function that updates the pull
+// request comment with the build progress. Errors are logged
+// and ignored.
+func (t *teardown) sendComment(repo *core.Repository, build *core.Build, stages []*core.Stage) {
+	logger := logrus.WithField("build.id", build.ID)
+	user, err := t.Users.Find(noContext, repo.UserID)
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot find repository owner")
+		return
+	}
+	err = t.Comments.Send(noContext, user, &core.CommentInput{
+		Repo:   repo,
+		Build:  build,
+		Stages: stages,
+	})
+	if err != nil {
+		logger.WithError(err).
+			Warnln("manager: cannot publish pull request comment")
+	}
+}
+
 // cancelDownstream is a helper function that tests for
 // downstream stages and cancels them based on the overall
 // pipeline state.
@@ -24,12 +24,13 @@ import (
 )
 
 type updater struct {
-	Builds  core.BuildStore
-	Events  core.Pubsub
-	Repos   core.RepositoryStore
-	Steps   core.StepStore
-	Stages  core.StageStore
-	Webhook core.WebhookSender
+	Annotations core.AnnotationStore
+	Builds      core.BuildStore
+	Events      core.Pubsub
+	Repos       core.RepositoryStore
+	Steps       core.StepStore
+	Stages      core.StageStore
+	Webhook     core.WebhookSender
 }
 
 func (u *updater) do(ctx context.Context, step *core.Step) error {
@@ -65,33 +66,71 @@ func (u *updater) do(ctx context.Context, step *core.Step) error {
 		return nil
 	}
 
-	stages, err := u.Stages.ListSteps(noContext, build.ID)
+	payload := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionUpdated,
+		Repo:   repo,
+		Build:  build,
+	}
+	subscribed, err := u.Webhook.Subscribed(noContext, payload)
 	if err != nil {
-		logger.WithError(err).Warnln("manager: cannot list stages")
-		return nil
+		logger.WithError(err).Warnln("manager: cannot list webhook subscriptions")
 	}
 
-	repo.Build = build
-	repo.Build.Stages = stages
-	data, _ := json.Marshal(repo)
-	err = u.Events.Publish(noContext, &core.Message{
-		Repository: repo.Slug,
-		Visibility: repo.Visibility,
-		Data:       data,
-	})
-	if err != nil {
-		logger.WithError(err).Warnln("manager: cannot publish build event")
+	// the build event and the build webhook are skipped when
+	// there are no subscribers, to avoid querying the stages,
+	// annotations and cards on every step update.
+	if subscribed || u.Events.Subscribers() != 0 {
+		stages, err := u.Stages.ListSteps(noContext, build.ID)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot list stages")
+			return nil
+		}
+		repo.Build = build
+		repo.Build.Stages = stages
 	}
 
-	payload := &core.WebhookData{
-		Event:  core.WebhookEventBuild,
-		Action: core.WebhookActionUpdated,
+	if u.Events.Subscribers() != 0 {
+		data, _ := json.Marshal(repo)
+		err = u.Events.Publish(noContext, &core.Message{
+			Repository: repo.Slug,
+			Visibility: repo.Visibility,
+			Data:       data,
+		})
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot publish build event")
+		}
+	}
+
+	if subscribed {
+		payload.Annotations, err = u.Annotations.List(noContext, build.ID)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot list annotations")
+		}
+		payload.Cards, err = u.Annotations.ListCards(noContext, build.ID)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot list cards")
+		}
+		err = u.Webhook.Send(noContext, payload)
+		if err != nil {
+			logger.WithError(err).Warnln("manager: cannot send global webhook")
+		}
+	}
+
+	action := core.WebhookActionStarted
+	if step.IsDone() {
+		action = core.WebhookActionFinished
+	}
+	err = u.Webhook.Send(noContext, &core.WebhookData{
+		Event:  core.WebhookEventStep,
+		Action: action,
 		Repo:   repo,
 		Build:  build,
-	}
-	err = u.Webhook.Send(noContext, payload)
+		Stage:  stage,
+		Step:   step,
+	})
 	if err != nil {
-		logger.WithError(err).Warnln("manager: cannot send global webhook")
+		logger.WithError(err).Warnln("manager: cannot send step webhook")
 	}
 	return nil
 }
@@ -0,0 +1,101 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/pubsub"
+	"github.com/golang/mock/gomock"
+)
+
+// this test verifies that the stages, annotations and cards
+// are not queried, and the build webhook is not sent, when
+// there are no event subscribers or webhook subscriptions.
+func TestUpdater_NoSubscribers(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), int64(2)).Return(&core.Stage{ID: 2, BuildID: 3}, nil)
+
+	u, step := mockUpdater(controller, stages)
+
+	webhooks := mock.NewMockWebhookSender(controller)
+	webhooks.EXPECT().Subscribed(gomock.Any(), gomock.Any()).Return(false, nil)
+	webhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil).Do(
+		func(_ interface{}, in *core.WebhookData) {
+			if got, want := in.Event, core.WebhookEventStep; got != want {
+				t.Errorf("Want webhook event %s, got %s", want, got)
+			}
+		},
+	)
+	u.Webhook = webhooks
+
+	if err := u.do(noContext, step); err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the build webhook is sent with the
+// stages, annotations and cards when subscribed.
+func TestUpdater_Subscribed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	stages := mock.NewMockStageStore(controller)
+	stages.EXPECT().Find(gomock.Any(), int64(2)).Return(&core.Stage{ID: 2, BuildID: 3}, nil)
+	stages.EXPECT().ListSteps(gomock.Any(), int64(3)).Return([]*core.Stage{{ID: 2}}, nil)
+
+	u, step := mockUpdater(controller, stages)
+
+	annotations := mock.NewMockAnnotationStore(controller)
+	annotations.EXPECT().List(gomock.Any(), int64(3)).Return(nil, nil)
+	annotations.EXPECT().ListCards(gomock.Any(), int64(3)).Return(nil, nil)
+	u.Annotations = annotations
+
+	var events []string
+	webhooks := mock.NewMockWebhookSender(controller)
+	webhooks.EXPECT().Subscribed(gomock.Any(), gomock.Any()).Return(true, nil)
+	webhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil).Do(
+		func(_ interface{}, in *core.WebhookData) {
+			events = append(events, in.Event)
+		},
+	).Times(2)
+	u.Webhook = webhooks
+
+	if err := u.do(noContext, step); err != nil {
+		t.Error(err)
+	}
+	if len(events) != 2 || events[0] != core.WebhookEventBuild {
+		t.Errorf("Want build and step webhooks, got %v", events)
+	}
+}
+
+// helper function returns an updater with mock stores that
+// return the step, build and repository.
+func mockUpdater(controller *gomock.Controller, stages core.StageStore) (*updater, *core.Step) {
+	step := &core.Step{ID: 1, StageID: 2, Status: core.StatusRunning}
+
+	steps := mock.NewMockStepStore(controller)
+	steps.EXPECT().Update(gomock.Any(), step).Return(nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().Find(gomock.Any(), int64(3)).Return(&core.Build{ID: 3, RepoID: 4}, nil)
+
+	repos := mock.NewMockRepositoryStore(controller)
+	repos.EXPECT().Find(gomock.Any(), int64(4)).Return(&core.Repository{ID: 4}, nil)
+
+	return &updater{
+		Annotations: mock.NewMockAnnotationStore(controller),
+		Builds:      builds,
+		Events:      pubsub.New(),
+		Repos:       repos,
+		Steps:       steps,
+		Stages:      stages,
+	}, step
+}
@@ -15,6 +15,8 @@
 package manager
 
 import (
+	"strings"
+
 	"github.com/drone/drone/core"
 )
 
@@ -110,3 +112,52 @@ func depsComplete(stage *core.Stage, siblings []*core.Stage) bool {
 	}
 	return true
 }
+
+// helper function returns the outputs written by the stages
+// that the current stage depends on.
+func filterOutputs(outputs []*core.Output, stage *core.Stage, stages []*core.Stage) []*core.Output {
+	deps := map[int64]struct{}{}
+	for _, sibling := range stages {
+		if isDep(sibling, stage) {
+			deps[sibling.ID] = struct{}{}
+		}
+	}
+	var filtered []*core.Output
+	for _, output := range outputs {
+		if _, ok := deps[output.StageID]; ok {
+			filtered = append(filtered, output)
+		}
+	}
+	return filtered
+}
+
+// helper function returns the cache scope for the git
+// reference, for example heads/master or pull/42/head.
+// Characters that are not valid in a cache key are replaced.
+func cacheScope(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z',
+			r >= 'A' && r <= 'Z',
+			r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-', r == '/':
+			return r
+		}
+		return '_'
+	}, ref)
+}
+
+// helper function prefixes the cache key with the scope.
+func scopeCacheKey(scope, key string) string {
+	return scope + "/" + key
+}
+
+// helper function prefixes the cache keys with the scope.
+func scopeCacheKeys(scope string, keys []string) []string {
+	var scoped []string
+	for _, key := range keys {
+		scoped = append(scoped, scopeCacheKey(scope, key))
+	}
+	return scoped
+}
@@ -3,3 +3,45 @@
 // that can be found in the LICENSE file.
 
 package manager
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterOutputs(t *testing.T) {
+	stages := []*core.Stage{
+		{ID: 1, Name: "build"},
+		{ID: 2, Name: "test"},
+		{ID: 3, Name: "deploy", DependsOn: []string{"build"}},
+	}
+	outputs := []*core.Output{
+		{StageID: 1, Name: "VERSION", Value: "1.0.0"},
+		{StageID: 2, Name: "COVERAGE", Value: "80"},
+		{StageID: 3, Name: "URL", Value: "https://example.com"},
+	}
+	got := filterOutputs(outputs, stages[2], stages)
+	want := []*core.Output{outputs[0]}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestCacheScope(t *testing.T) {
+	tests := []struct {
+		ref   string
+		scope string
+	}{
+		{"refs/heads/master", "heads/master"},
+		{"refs/pull/42/head", "pull/42/head"},
+		{"refs/tags/v1.0.0", "tags/v1.0.0"},
+		{"refs/heads/feature/a+b", "heads/feature/a_b"},
+	}
+	for _, test := range tests {
+		if got, want := cacheScope(test.ref), test.scope; got != want {
+			t.Errorf("Want scope %q for ref %q, got %q", want, test.ref, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+)
+
+// cardFile is the well-known path of the file to which a
+// step writes its markdown summary card.
+const cardFile = "/tmp/drone-card"
+
+// card block markers written to the log stream when the
+// step exits, which are used to capture the card file.
+const (
+	cardBegin = "::drone-card-begin::"
+	cardEnd   = "::drone-card-end::"
+)
+
+// annotationPrefix is the prefix of the log directive used
+// by a step to emit an annotation, for example:
+//
+//	::drone-annotation file=main.go,line=12,severity=warning::unused variable
+const annotationPrefix = "::drone-annotation"
+
+// maxAnnotations is the maximum number of annotations that
+// are captured for a single step.
+const maxAnnotations = 50
+
+// helper function parses the annotation directives from the
+// step logs. Invalid directives are ignored.
+func parseAnnotations(lines []*runtime.Line) []*core.Annotation {
+	var annotations []*core.Annotation
+	for _, line := range lines {
+		annotation := parseAnnotation(line.Message)
+		if annotation == nil {
+			continue
+		}
+		if len(annotations) == maxAnnotations {
+			break
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations
+}
+
+// helper function parses a single annotation directive, or
+// returns nil if the line is not a valid directive.
+func parseAnnotation(line string) *core.Annotation {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, annotationPrefix) {
+		return nil
+	}
+	parts := strings.SplitN(
+		strings.TrimPrefix(line, annotationPrefix), "::", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	annotation := &core.Annotation{
+		Severity: core.SeverityNotice,
+		Message:  strings.TrimSpace(parts[1]),
+	}
+	for _, param := range strings.Split(parts[0], ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "file":
+			annotation.File = kv[1]
+		case "line":
+			annotation.Line, _ = strconv.Atoi(kv[1])
+		case "severity":
+			annotation.Severity = kv[1]
+		}
+	}
+	if annotation.Validate() != nil {
+		return nil
+	}
+	return annotation
+}
+
+// helper function splits the step logs into the summary
+// card and the remaining log lines. The card is nil if the
+// step did not write a card, and is truncated if it exceeds
+// the maximum size.
+func splitCard(lines []*runtime.Line) (*core.Card, []*runtime.Line) {
+	data, filtered := splitBlock(lines, cardBegin, cardEnd)
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return nil, filtered
+	}
+	if len(data) > core.MaxCardSize {
+		data = data[:core.MaxCardSize]
+	}
+	return &core.Card{Data: data}, filtered
+}
@@ -0,0 +1,106 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: "+ golint ./...\n"},
+		{Number: 1, Message: "::drone-annotation file=main.go,line=12,severity=warning::unused variable\n"},
+		{Number: 2, Message: "::drone-annotation::coverage 80%\n"},
+		{Number: 3, Message: "::drone-annotation severity=error::invalid severity\n"},
+		{Number: 4, Message: "::drone-annotation file=main.go::\n"},
+		{Number: 5, Message: "::drone-annotation missing message\n"},
+	}
+	want := []*core.Annotation{
+		{File: "main.go", Line: 12, Severity: core.SeverityWarning, Message: "unused variable"},
+		{Severity: core.SeverityNotice, Message: "coverage 80%"},
+	}
+	got := parseAnnotations(lines)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseAnnotations_Limit(t *testing.T) {
+	var lines []*runtime.Line
+	for i := 0; i < maxAnnotations+10; i++ {
+		lines = append(lines, &runtime.Line{
+			Number:  i,
+			Message: fmt.Sprintf("::drone-annotation::message %d\n", i),
+		})
+	}
+	if got, want := len(parseAnnotations(lines)), maxAnnotations; got != want {
+		t.Errorf("Want %d annotations, got %d", want, got)
+	}
+}
+
+func TestSplitCard(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: "+ go test -cover\n"},
+		{Number: 1, Message: cardBegin + "\n"},
+		{Number: 2, Message: "# Coverage\n"},
+		{Number: 3, Message: "\n"},
+		{Number: 4, Message: "80%\n"},
+		{Number: 5, Message: "\n"},
+		{Number: 6, Message: cardEnd + "\n"},
+	}
+	card, filtered := splitCard(lines)
+	if card == nil {
+		t.Errorf("Want card captured")
+		return
+	}
+	if got, want := card.Data, "# Coverage\n\n80%"; got != want {
+		t.Errorf("Want card data %q, got %q", want, got)
+	}
+	if diff := cmp.Diff(filtered, lines[:1]); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestSplitCard_Empty(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: "+ go test\n"},
+	}
+	card, filtered := splitCard(lines)
+	if card != nil {
+		t.Errorf("Want nil card")
+	}
+	if diff := cmp.Diff(filtered, lines); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestSplitCard_Truncate(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: cardBegin + "\n"},
+		{Number: 1, Message: strings.Repeat("x", core.MaxCardSize+10) + "\n"},
+		{Number: 2, Message: cardEnd + "\n"},
+	}
+	card, _ := splitCard(lines)
+	if got, want := len(card.Data), core.MaxCardSize; got != want {
+		t.Errorf("Want card truncated to %d bytes, got %d", want, got)
+	}
+}
+
+func TestOutputFilter_Card(t *testing.T) {
+	filter := new(outputFilter)
+	lines := []string{"+ go test\n", cardBegin + "\n", "# Coverage\n", cardEnd + "\n", "done\n"}
+	want := []bool{false, true, true, true, false}
+	for i, line := range lines {
+		if got := filter.filter(&runtime.Line{Message: line}); got != want[i] {
+			t.Errorf("Unexpected filter result at index %d", i)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/sirupsen/logrus"
+)
+
+// cacheSaveFile is the well-known path of the archive to
+// which a step writes its build cache, relative to the
+// working directory, for example:
+//
+//	tar -cf "$DRONE_CACHE_SAVE" node_modules
+//
+// The archive is copied from the step container after the
+// step exits. The build cache is restored by extracting the
+// archive into the working directory of the step container
+// before the step starts.
+const cacheSaveFile = "/tmp/drone-cache.tar"
+
+// helper function configures the cache archive path in each
+// step that defines a cache key.
+func setupCaches(spec *engine.Spec) {
+	for _, step := range spec.Steps {
+		if step.Envs["DRONE_CACHE_KEY"] == "" {
+			continue
+		}
+		step.Envs["DRONE_CACHE_SAVE"] = cacheSaveFile
+	}
+}
+
+// helper function returns the cache key and the fallback
+// key prefixes defined in the step environment.
+func cacheKeys(envs map[string]string) (string, []string) {
+	var prefixes []string
+	for _, prefix := range strings.Split(envs["DRONE_CACHE_RESTORE_KEYS"], ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return envs["DRONE_CACHE_KEY"], prefixes
+}
+
+// restoreCache streams the build cache into the working
+// directory of the step container. If the cache cannot be
+// restored the step runs without the cache, since the cache
+// is an optimization.
+func (r *Runner) restoreCache(ctx context.Context, logger logrus.FieldLogger, step int64, spec *engine.Step) {
+	key, prefixes := cacheKeys(spec.Envs)
+	if key == "" || spec.WorkingDir == "" || r.Copier == nil {
+		return
+	}
+	rc, err := r.Manager.RestoreCache(ctx, step, key, prefixes)
+	if err != nil {
+		logger.WithError(err).
+			WithField("cache", key).
+			Warnln("runner: cannot restore cache")
+		return
+	}
+	if rc == nil {
+		return
+	}
+	defer rc.Close()
+	err = r.Copier.CopyTo(ctx, spec.Metadata.UID, spec.WorkingDir, rc)
+	if err != nil {
+		logger.WithError(err).
+			WithField("cache", key).
+			Warnln("runner: cannot copy cache to container")
+	}
+}
+
+// saveCache copies the cache archive from the step container
+// after the step exits, and streams the archive to the
+// server. Errors are logged and do not fail the step.
+func (r *Runner) saveCache(ctx context.Context, logger logrus.FieldLogger, step int64, spec *engine.Step) {
+	key, _ := cacheKeys(spec.Envs)
+	if key == "" {
+		return
+	}
+	r.copyFiles(ctx, logger, spec.Metadata.UID, cacheSaveFile, func(name string, rd io.Reader) error {
+		return r.Manager.SaveCache(ctx, step, key, rd)
+	})
+}
+
+// cacheEngine wraps the engine and restores the build cache
+// after the step container is created, and before the step
+// starts.
+type cacheEngine struct {
+	engine.Engine
+	restore func(ctx context.Context, step *engine.Step)
+}
+
+func (e *cacheEngine) Create(ctx context.Context, spec *engine.Spec, step *engine.Step) error {
+	if err := e.Engine.Create(ctx, spec, step); err != nil {
+		return err
+	}
+	e.restore(ctx, step)
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone/operator/manager"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetupCaches(t *testing.T) {
+	spec := &engine.Spec{
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{UID: "abc", Name: "restore"},
+				Envs:     map[string]string{"DRONE_CACHE_KEY": "deps"},
+			},
+			{
+				Metadata: engine.Metadata{UID: "def", Name: "build"},
+				Envs:     map[string]string{},
+			},
+		},
+	}
+	setupCaches(spec)
+	if got, want := spec.Steps[0].Envs["DRONE_CACHE_SAVE"], cacheSaveFile; got != want {
+		t.Errorf("Want DRONE_CACHE_SAVE %s, got %s", want, got)
+	}
+	if _, ok := spec.Steps[1].Envs["DRONE_CACHE_SAVE"]; ok {
+		t.Errorf("Want no cache archive for steps without a cache key")
+	}
+}
+
+func TestCacheKeys(t *testing.T) {
+	key, prefixes := cacheKeys(map[string]string{
+		"DRONE_CACHE_KEY":          "deps-abc",
+		"DRONE_CACHE_RESTORE_KEYS": "deps-, ,deps",
+	})
+	if got, want := key, "deps-abc"; got != want {
+		t.Errorf("Want key %q, got %q", want, got)
+	}
+	if diff := cmp.Diff(prefixes, []string{"deps-", "deps"}); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRestoreCache(t *testing.T) {
+	m := &cacheManager{data: "hello world"}
+	copier := new(fileCopier)
+	r := &Runner{Manager: m, Copier: copier}
+	step := &engine.Step{
+		Metadata:   engine.Metadata{UID: "drone-abc123"},
+		WorkingDir: "/drone/src",
+		Envs: map[string]string{
+			"DRONE_CACHE_KEY":          "deps-abc",
+			"DRONE_CACHE_RESTORE_KEYS": "deps-",
+		},
+	}
+	r.restoreCache(context.Background(), logrus.StandardLogger(), 1, step)
+	if got, want := copier.copied, "hello world"; got != want {
+		t.Errorf("Want cache archive %q, got %q", want, got)
+	}
+	if got, want := copier.container, "drone-abc123"; got != want {
+		t.Errorf("Want cache copied to container %q, got %q", want, got)
+	}
+	if got, want := copier.path, "/drone/src"; got != want {
+		t.Errorf("Want cache extracted to %q, got %q", want, got)
+	}
+	if got, want := m.key, "deps-abc"; got != want {
+		t.Errorf("Want cache key %q, got %q", want, got)
+	}
+	if diff := cmp.Diff(m.prefixes, []string{"deps-"}); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestRestoreCache_NotFound(t *testing.T) {
+	copier := new(fileCopier)
+	r := &Runner{Manager: new(cacheManager), Copier: copier}
+	step := &engine.Step{
+		Metadata:   engine.Metadata{UID: "drone-abc123"},
+		WorkingDir: "/drone/src",
+		Envs:       map[string]string{"DRONE_CACHE_KEY": "deps"},
+	}
+	r.restoreCache(context.Background(), logrus.StandardLogger(), 1, step)
+	if copier.container != "" {
+		t.Errorf("Want no cache copied when cache not found")
+	}
+}
+
+func TestSaveCache(t *testing.T) {
+	copier := &fileCopier{
+		path: cacheSaveFile,
+		data: mockArchive(t, map[string]string{
+			"drone-cache.tar": "hello world",
+		}),
+	}
+	m := &fileManager{uploads: map[string]string{}}
+	r := &Runner{Manager: m, Copier: copier}
+	step := &engine.Step{
+		Metadata: engine.Metadata{UID: "drone-abc123"},
+		Envs:     map[string]string{"DRONE_CACHE_KEY": "deps"},
+	}
+	r.saveCache(context.Background(), logrus.StandardLogger(), 1, step)
+
+	want := map[string]string{
+		"cache:deps": "hello world",
+	}
+	if diff := cmp.Diff(m.uploads, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// the cache is not saved if the step does not define
+	// a cache key.
+	m.uploads = map[string]string{}
+	step.Envs = map[string]string{}
+	r.saveCache(context.Background(), logrus.StandardLogger(), 1, step)
+	if len(m.uploads) != 0 {
+		t.Errorf("Want no cache saved without a cache key")
+	}
+}
+
+func TestCacheEngine(t *testing.T) {
+	var restored *engine.Step
+	eng := &cacheEngine{
+		Engine: new(cacheEngineStub),
+		restore: func(ctx context.Context, step *engine.Step) {
+			restored = step
+		},
+	}
+	step := new(engine.Step)
+	if err := eng.Create(context.Background(), new(engine.Spec), step); err != nil {
+		t.Error(err)
+	}
+	if restored != step {
+		t.Errorf("Want cache restored after the container is created")
+	}
+}
+
+// cacheEngineStub is an engine that creates containers.
+type cacheEngineStub struct {
+	engine.Engine
+}
+
+func (e *cacheEngineStub) Create(context.Context, *engine.Spec, *engine.Step) error {
+	return nil
+}
+
+// cacheManager is a build manager that returns the cache
+// data, if not empty.
+type cacheManager struct {
+	manager.BuildManager
+	data     string
+	key      string
+	prefixes []string
+}
+
+func (m *cacheManager) RestoreCache(ctx context.Context, step int64, key string, prefixes []string) (io.ReadCloser, error) {
+	m.key, m.prefixes = key, prefixes
+	if m.data == "" {
+		return nil, nil
+	}
+	return ioutil.NopCloser(bytes.NewBufferString(m.data)), nil
+}
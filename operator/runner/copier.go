@@ -0,0 +1,104 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// Copier copies files to and from step containers.
+type Copier interface {
+	// CopyFrom returns a tar archive of the file or directory
+	// at the path in the named container. If the path does
+	// not exist a nil reader is returned. The caller is
+	// responsible for closing the reader.
+	CopyFrom(ctx context.Context, container, path string) (io.ReadCloser, error)
+
+	// CopyTo extracts the tar archive into the directory at
+	// the path in the named container. The container must be
+	// created, but need not be running.
+	CopyTo(ctx context.Context, container, path string, r io.Reader) error
+}
+
+// NewDockerCopier returns a Copier that copies files using
+// the Docker engine archive API. The Docker host is
+// configured from the DOCKER_HOST, DOCKER_CERT_PATH and
+// DOCKER_TLS_VERIFY environment variables.
+func NewDockerCopier() (Copier, error) {
+	server, client, err := dockerClient()
+	if err != nil {
+		return nil, err
+	}
+	return newDockerCopier(server, client), nil
+}
+
+func newDockerCopier(server string, client *http.Client) Copier {
+	return &dockerCopier{
+		server: server,
+		client: client,
+	}
+}
+
+type dockerCopier struct {
+	server string
+	client *http.Client
+}
+
+func (c *dockerCopier) CopyFrom(ctx context.Context, container, path string) (io.ReadCloser, error) {
+	uri := fmt.Sprintf("%s/%s/containers/%s/archive?path=%s",
+		c.server, dockerAPIVersion, url.PathEscape(container), url.QueryEscape(path))
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.StatusCode == 404:
+		res.Body.Close()
+		return nil, nil
+	case res.StatusCode > 299:
+		res.Body.Close()
+		return nil, fmt.Errorf("docker: cannot copy from container: %s", res.Status)
+	}
+	return res.Body, nil
+}
+
+func (c *dockerCopier) CopyTo(ctx context.Context, container, path string, r io.Reader) error {
+	uri := fmt.Sprintf("%s/%s/containers/%s/archive?path=%s",
+		c.server, dockerAPIVersion, url.PathEscape(container), url.QueryEscape(path))
+	req, err := http.NewRequest("PUT", uri, ioutil.NopCloser(r))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-tar")
+	res, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("docker: cannot copy to container: %s", res.Status)
+	}
+	return nil
+}
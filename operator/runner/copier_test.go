@@ -0,0 +1,104 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDockerCopier_CopyFrom(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, "GET"; got != want {
+			t.Errorf("Want method %q, got %q", want, got)
+		}
+		if got, want := r.URL.Path, "/v1.25/containers/drone-abc123/archive"; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := r.URL.Query().Get("path"), "/tmp/drone-artifacts"; got != want {
+			t.Errorf("Want archive path %q, got %q", want, got)
+		}
+		w.Write([]byte("archive"))
+	}))
+	defer ts.Close()
+
+	copier := newDockerCopier(ts.URL, ts.Client())
+	rc, err := copier.CopyFrom(context.Background(), "drone-abc123", "/tmp/drone-artifacts")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer rc.Close()
+	data, _ := ioutil.ReadAll(rc)
+	if got, want := string(data), "archive"; got != want {
+		t.Errorf("Want archive %q, got %q", want, got)
+	}
+}
+
+func TestDockerCopier_CopyFromNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	copier := newDockerCopier(ts.URL, ts.Client())
+	rc, err := copier.CopyFrom(context.Background(), "drone-abc123", "/tmp/drone-artifacts")
+	if err != nil {
+		t.Error(err)
+	}
+	if rc != nil {
+		t.Errorf("Want nil reader when the path does not exist")
+	}
+}
+
+func TestDockerCopier_CopyFromError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer ts.Close()
+
+	copier := newDockerCopier(ts.URL, ts.Client())
+	if _, err := copier.CopyFrom(context.Background(), "drone-abc123", "/tmp/drone-artifacts"); err == nil {
+		t.Errorf("Want error when the docker engine returns an error")
+	}
+}
+
+func TestDockerCopier_CopyTo(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, "PUT"; got != want {
+			t.Errorf("Want method %q, got %q", want, got)
+		}
+		if got, want := r.URL.Path, "/v1.25/containers/drone-abc123/archive"; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := r.URL.Query().Get("path"), "/drone/src"; got != want {
+			t.Errorf("Want archive path %q, got %q", want, got)
+		}
+		data, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(data), "archive"; got != want {
+			t.Errorf("Want archive %q, got %q", want, got)
+		}
+	}))
+	defer ts.Close()
+
+	copier := newDockerCopier(ts.URL, ts.Client())
+	err := copier.CopyTo(context.Background(), "drone-abc123", "/drone/src", strings.NewReader("archive"))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDockerCopier_CopyToError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	copier := newDockerCopier(ts.URL, ts.Client())
+	err := copier.CopyTo(context.Background(), "drone-abc123", "/drone/src", strings.NewReader("archive"))
+	if err == nil {
+		t.Errorf("Want error when the container does not exist")
+	}
+}
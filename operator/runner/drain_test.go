@@ -0,0 +1,103 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/operator/manager"
+)
+
+func TestStart_Drain(t *testing.T) {
+	m := &drainManager{drain: make(chan struct{})}
+	r := &Runner{Manager: m, Machine: "agent-1"}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- r.Start(context.Background(), 2)
+	}()
+	close(m.drain)
+
+	select {
+	case <-errc:
+	case <-time.After(time.Second):
+		t.Errorf("Want runner stopped when drained")
+	}
+}
+
+func TestStart_DrainTimeout(t *testing.T) {
+	// simulates a running stage that blocks the runner
+	// process until the stage is stopped.
+	stage, cancel := context.WithCancel(context.Background())
+	m := &drainManager{
+		drain:   make(chan struct{}),
+		busy:    stage.Done(),
+		polling: make(chan struct{}),
+	}
+	r := &Runner{Manager: m, DrainTimeout: time.Millisecond}
+	r.track(1, cancel)
+
+	ctx, stop := context.WithCancel(context.Background())
+	go func() {
+		<-m.polling
+		stop()
+	}()
+
+	r.Start(ctx, 1)
+
+	select {
+	case <-stage.Done():
+	case <-time.After(time.Second):
+		t.Errorf("Want running stage stopped when drain timeout exceeded")
+	}
+	if !r.isStopped() {
+		t.Errorf("Want runner stopped")
+	}
+}
+
+func TestTrack(t *testing.T) {
+	r := new(Runner)
+	_, cancel := context.WithCancel(context.Background())
+	r.track(1, cancel)
+	if len(r.running) != 1 {
+		t.Errorf("Want stage tracked")
+	}
+	r.untrack(1)
+	if len(r.running) != 0 {
+		t.Errorf("Want stage untracked")
+	}
+}
+
+// drainManager is a build manager that blocks when polling
+// for stages, and signals a drain when the drain channel is
+// closed.
+type drainManager struct {
+	manager.BuildManager
+	drain   chan struct{}
+	busy    <-chan struct{}
+	polling chan struct{}
+}
+
+func (m *drainManager) Request(ctx context.Context, args *manager.Request) (*core.Stage, error) {
+	if m.busy != nil {
+		close(m.polling)
+		<-m.busy
+		return nil, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (m *drainManager) Drained(ctx context.Context, machine string) (bool, error) {
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-m.drain:
+		return true, nil
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// artifactsDir is the well-known path of the directory to
+// which a step writes the files to publish as artifacts.
+// The directory is copied from the step container after
+// the step exits.
+const artifactsDir = "/tmp/drone-artifacts"
+
+// reportsDir is the well-known path of the directory to
+// which a step writes its junit xml test reports. The
+// directory is copied from the step container after the
+// step exits.
+const reportsDir = "/tmp/drone-reports"
+
+// maxFileSize is the maximum size, in bytes, of a single
+// file collected from a step.
+const maxFileSize = 100 << 20
+
+// collectArtifacts copies the artifacts directory from the
+// step container after the step exits, and streams each file
+// to the artifact store. Errors are logged and do not fail
+// the step.
+func (r *Runner) collectArtifacts(ctx context.Context, logger logrus.FieldLogger, step int64, container string) {
+	r.copyFiles(ctx, logger, container, artifactsDir, func(name string, rd io.Reader) error {
+		return r.Manager.UploadArtifact(ctx, step, name, rd)
+	})
+}
+
+// collectReports copies the reports directory from the step
+// container after the step exits, and streams each junit xml
+// report to the server. Errors are logged and do not fail
+// the step.
+func (r *Runner) collectReports(ctx context.Context, logger logrus.FieldLogger, step int64, container string) {
+	r.copyFiles(ctx, logger, container, reportsDir, func(name string, rd io.Reader) error {
+		if !strings.HasSuffix(name, ".xml") {
+			return nil
+		}
+		return r.Manager.UploadReport(ctx, step, rd)
+	})
+}
+
+// copyFiles copies the file or directory at the path from
+// the step container, and invokes the callback for each
+// regular file with the file name relative to the path.
+// Files are streamed from the tar archive returned by the
+// copier, and files that exceed the maximum size are
+// skipped. Files are not copied if the copier is nil.
+func (r *Runner) copyFiles(ctx context.Context, logger logrus.FieldLogger, container, dir string, fn func(name string, rd io.Reader) error) {
+	if r.Copier == nil {
+		return
+	}
+	rc, err := r.Copier.CopyFrom(ctx, container, dir)
+	if err != nil {
+		logger.WithError(err).
+			WithField("path", dir).
+			Warnln("runner: cannot copy files from container")
+		return
+	}
+	if rc == nil {
+		return
+	}
+	defer rc.Close()
+
+	// the archive entries are prefixed with the base name
+	// of the copied path.
+	prefix := path.Base(dir) + "/"
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			logger.WithError(err).
+				WithField("path", dir).
+				Warnln("runner: cannot read files from container")
+			return
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		name := strings.TrimPrefix(hdr.Name, prefix)
+		if hdr.Size > maxFileSize {
+			logger.WithField("file", name).
+				Warnln("runner: file exceeds the maximum size")
+			continue
+		}
+		if err := fn(name, tr); err != nil {
+			logger.WithError(err).
+				WithField("file", name).
+				Warnln("runner: cannot upload file")
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/drone/drone/operator/manager"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCollectArtifacts(t *testing.T) {
+	copier := &fileCopier{
+		path: artifactsDir,
+		data: mockArchive(t, map[string]string{
+			"drone-artifacts/dist/app.txt": "hello world",
+			"drone-artifacts/README.md":    "hello",
+		}),
+	}
+	m := &fileManager{uploads: map[string]string{}}
+	r := &Runner{Manager: m, Copier: copier}
+	r.collectArtifacts(context.Background(), logrus.StandardLogger(), 1, "drone-abc123")
+
+	want := map[string]string{
+		"artifact:dist/app.txt": "hello world",
+		"artifact:README.md":    "hello",
+	}
+	if diff := cmp.Diff(m.uploads, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if got, want := copier.container, "drone-abc123"; got != want {
+		t.Errorf("Want files copied from container %q, got %q", want, got)
+	}
+}
+
+func TestCollectArtifacts_NotFound(t *testing.T) {
+	m := &fileManager{uploads: map[string]string{}}
+	r := &Runner{Manager: m, Copier: new(fileCopier)}
+	r.collectArtifacts(context.Background(), logrus.StandardLogger(), 1, "drone-abc123")
+	if len(m.uploads) != 0 {
+		t.Errorf("Want no artifacts uploaded when the directory does not exist")
+	}
+
+	// files are not copied if the copier is nil.
+	r = &Runner{Manager: m}
+	r.collectArtifacts(context.Background(), logrus.StandardLogger(), 1, "drone-abc123")
+	if len(m.uploads) != 0 {
+		t.Errorf("Want no artifacts uploaded without a copier")
+	}
+}
+
+func TestCollectReports(t *testing.T) {
+	copier := &fileCopier{
+		path: reportsDir,
+		data: mockArchive(t, map[string]string{
+			"drone-reports/junit.xml":    "<testsuite/>",
+			"drone-reports/coverage.txt": "ignored",
+		}),
+	}
+	m := &fileManager{uploads: map[string]string{}}
+	r := &Runner{Manager: m, Copier: copier}
+	r.collectReports(context.Background(), logrus.StandardLogger(), 1, "drone-abc123")
+
+	want := map[string]string{
+		"report": "<testsuite/>",
+	}
+	if diff := cmp.Diff(m.uploads, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// helper function returns a tar archive with the named
+// files and their parent directories.
+func mockArchive(t *testing.T, files map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	dirs := map[string]bool{}
+	for name, data := range files {
+		dir := path.Dir(name)
+		if !dirs[dir] {
+			dirs[dir] = true
+			tw.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0755})
+		}
+		tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(data))})
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fileCopier is a copier that returns the archive if the
+// path matches, and records the container name and the
+// archive copied to the container.
+type fileCopier struct {
+	path      string
+	data      []byte
+	container string
+	copied    string
+}
+
+func (c *fileCopier) CopyFrom(ctx context.Context, container, path string) (io.ReadCloser, error) {
+	c.container = container
+	if path != c.path || c.data == nil {
+		return nil, nil
+	}
+	return ioutil.NopCloser(bytes.NewReader(c.data)), nil
+}
+
+func (c *fileCopier) CopyTo(ctx context.Context, container, path string, r io.Reader) error {
+	data, _ := ioutil.ReadAll(r)
+	c.container, c.path, c.copied = container, path, string(data)
+	return nil
+}
+
+// fileManager is a build manager that records the files
+// uploaded by the runner.
+type fileManager struct {
+	manager.BuildManager
+	uploads map[string]string
+}
+
+func (m *fileManager) UploadArtifact(ctx context.Context, step int64, name string, r io.Reader) error {
+	data, _ := ioutil.ReadAll(r)
+	m.uploads["artifact:"+name] = string(data)
+	return nil
+}
+
+func (m *fileManager) UploadReport(ctx context.Context, step int64, r io.Reader) error {
+	data, _ := ioutil.ReadAll(r)
+	m.uploads["report"] = string(data)
+	return nil
+}
+
+func (m *fileManager) SaveCache(ctx context.Context, step int64, key string, r io.Reader) error {
+	data, _ := ioutil.ReadAll(r)
+	m.uploads["cache:"+key] = string(data)
+	return nil
+}
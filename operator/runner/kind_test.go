@@ -0,0 +1,46 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/drone/drone-yaml/yaml"
+)
+
+func TestKindAndType(t *testing.T) {
+	r := new(Runner)
+	kind, typ := r.kindAndType()
+	if kind != "pipeline" || typ != "docker" {
+		t.Errorf("Want default kind and type, got %s/%s", kind, typ)
+	}
+
+	r = &Runner{Kind: "pipeline", Type: "exec"}
+	kind, typ = r.kindAndType()
+	if kind != "pipeline" || typ != "exec" {
+		t.Errorf("Want kind and type pipeline/exec, got %s/%s", kind, typ)
+	}
+}
+
+func TestMatchPipeline(t *testing.T) {
+	tests := []struct {
+		pipeline *yaml.Pipeline
+		typ      string
+		want     bool
+	}{
+		{pipeline: &yaml.Pipeline{}, typ: "docker", want: true},
+		{pipeline: &yaml.Pipeline{Kind: "pipeline"}, typ: "docker", want: true},
+		{pipeline: &yaml.Pipeline{Kind: "pipeline", Type: "docker"}, typ: "docker", want: true},
+		{pipeline: &yaml.Pipeline{Kind: "pipeline", Type: "exec"}, typ: "docker", want: false},
+		{pipeline: &yaml.Pipeline{Kind: "pipeline", Type: "exec"}, typ: "exec", want: true},
+		{pipeline: &yaml.Pipeline{Kind: "pipeline"}, typ: "exec", want: false},
+		{pipeline: &yaml.Pipeline{Kind: "secret"}, typ: "docker", want: false},
+	}
+	for i, test := range tests {
+		if got, want := matchPipeline(test.pipeline, "pipeline", test.typ), test.want; got != want {
+			t.Errorf("Unexpected results at index %d", i)
+		}
+	}
+}
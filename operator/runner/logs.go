@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+)
+
+// logLimiter enforces the log limits for the steps in a
+// single stage. It is not safe for concurrent use.
+type logLimiter struct {
+	limits core.LogLimits
+	stage  core.LogCounter
+	steps  map[string]*core.LogCounter
+}
+
+func newLogLimiter(limits core.LogLimits) *logLimiter {
+	return &logLimiter{
+		limits: limits,
+		steps:  map[string]*core.LogCounter{},
+	}
+}
+
+// accept returns true if the line is within the log limits.
+// If the line exceeds the limits the step logs are marked as
+// truncated, and all subsequent lines are rejected. The
+// truncated return value is true for the first rejected line
+// only, so that the caller writes the truncation marker once.
+func (l *logLimiter) accept(step string, line *runtime.Line) (ok, truncated bool) {
+	c := l.counter(step)
+	if c.Truncated {
+		return false, false
+	}
+	size := int64(len(line.Message))
+	if c.Exceeds(size, l.limits.StepBytes, l.limits.StepLines) ||
+		l.stage.Exceeds(size, l.limits.StageBytes, l.limits.StageLines) {
+		c.Truncated = true
+		return false, true
+	}
+	c.Add(size)
+	l.stage.Add(size)
+	return true, false
+}
+
+// truncate returns the step log lines accepted by the
+// limiter, followed by the truncation marker if the step
+// logs were truncated.
+func (l *logLimiter) truncate(step string, lines []*runtime.Line) []*runtime.Line {
+	c := l.counter(step)
+	if !c.Truncated {
+		return lines
+	}
+	n := c.Lines
+	if n > len(lines) {
+		n = len(lines)
+	}
+	marker := core.TruncatedLine(n, 0)
+	if n < len(lines) {
+		marker = core.TruncatedLine(lines[n].Number, lines[n].Timestamp)
+	}
+	return append(lines[:n:n], &runtime.Line{
+		Number:    marker.Number,
+		Message:   marker.Message,
+		Timestamp: marker.Timestamp,
+	})
+}
+
+func (l *logLimiter) counter(step string) *core.LogCounter {
+	c, ok := l.steps[step]
+	if !ok {
+		c = new(core.LogCounter)
+		l.steps[step] = c
+	}
+	return c
+}
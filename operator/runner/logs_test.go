@@ -0,0 +1,91 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogLimiter_Unlimited(t *testing.T) {
+	limiter := newLogLimiter(core.LogLimits{})
+	for i := 0; i < 100; i++ {
+		ok, truncated := limiter.accept("build", &runtime.Line{Number: i, Message: "hello\n"})
+		if !ok || truncated {
+			t.Errorf("Want line %d accepted", i)
+		}
+	}
+}
+
+func TestLogLimiter_StepLines(t *testing.T) {
+	limiter := newLogLimiter(core.LogLimits{StepLines: 2})
+	tests := []struct {
+		ok, truncated bool
+	}{
+		{true, false},
+		{true, false},
+		{false, true},
+		{false, false},
+	}
+	for i, test := range tests {
+		ok, truncated := limiter.accept("build", &runtime.Line{Number: i, Message: "hello\n"})
+		if ok != test.ok || truncated != test.truncated {
+			t.Errorf("Unexpected results at index %d", i)
+		}
+	}
+	// the limits apply to each step individually.
+	if ok, _ := limiter.accept("test", &runtime.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want line accepted for a different step")
+	}
+}
+
+func TestLogLimiter_StepBytes(t *testing.T) {
+	limiter := newLogLimiter(core.LogLimits{StepBytes: 10})
+	if ok, _ := limiter.accept("build", &runtime.Line{Message: "hello\n"}); !ok {
+		t.Errorf("Want first line accepted")
+	}
+	if ok, truncated := limiter.accept("build", &runtime.Line{Message: "world\n"}); ok || !truncated {
+		t.Errorf("Want second line truncated")
+	}
+}
+
+func TestLogLimiter_Stage(t *testing.T) {
+	limiter := newLogLimiter(core.LogLimits{StageLines: 3})
+	limiter.accept("build", &runtime.Line{Message: "hello\n"})
+	limiter.accept("build", &runtime.Line{Message: "world\n"})
+	limiter.accept("test", &runtime.Line{Message: "hello\n"})
+	if ok, truncated := limiter.accept("test", &runtime.Line{Message: "world\n"}); ok || !truncated {
+		t.Errorf("Want stage limit enforced across steps")
+	}
+}
+
+func TestLogLimiter_Truncate(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: "world\n", Timestamp: 2},
+		{Number: 2, Message: "!\n", Timestamp: 3},
+	}
+	limiter := newLogLimiter(core.LogLimits{StepLines: 1})
+	for _, line := range lines {
+		limiter.accept("build", line)
+	}
+	want := []*runtime.Line{
+		{Number: 0, Message: "hello\n", Timestamp: 1},
+		{Number: 1, Message: core.LogTruncated + "\n", Timestamp: 2},
+	}
+	got := limiter.truncate("build", lines)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+
+	// lines are returned unchanged when the step logs are
+	// not truncated.
+	if got := limiter.truncate("test", lines); len(got) != len(lines) {
+		t.Errorf("Want lines unchanged")
+	}
+}
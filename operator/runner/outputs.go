@@ -0,0 +1,213 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"strings"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+)
+
+// outputFile is the well-known path of the file to which a
+// step writes its outputs, one name=value pair per line.
+// Lines prefixed with secret: are masked in the build logs,
+// for example secret:TOKEN=value.
+const outputFile = "/tmp/drone-output"
+
+// maxOutputsSize is the maximum combined size, in bytes, of
+// the outputs written by a single step.
+const maxOutputsSize = 65536
+
+// output block markers written to the log stream when the
+// step exits, which are used to capture the outputs file.
+const (
+	outputBegin = "::drone-output-begin::"
+	outputEnd   = "::drone-output-end::"
+)
+
+// outputTrap is prepended to the step script. It writes the
+// contents of the outputs file and the summary card file to
+// the log stream when the script exits, so that the runner
+// can capture them.
+const outputTrap = `
+trap 'if [ -s "$DRONE_OUTPUT" ]; then echo "` + outputBegin + `"; cat "$DRONE_OUTPUT"; echo; echo "` + outputEnd + `"; fi; if [ -s "$DRONE_CARD" ]; then echo "` + cardBegin + `"; cat "$DRONE_CARD"; echo; echo "` + cardEnd + `"; fi' EXIT
+`
+
+// helper function prepends the outputs trap to the posix
+// shell script of each step.
+func setupOutputs(spec *engine.Spec) {
+	files := map[string]*engine.File{}
+	for _, file := range spec.Files {
+		files[file.Metadata.Name] = file
+	}
+	for _, step := range spec.Steps {
+		if step.Envs == nil {
+			step.Envs = map[string]string{}
+		}
+		step.Envs["DRONE_OUTPUT"] = outputFile
+		step.Envs["DRONE_CARD"] = cardFile
+		step.Envs["DRONE_ARTIFACTS"] = artifactsDir
+		step.Envs["DRONE_REPORTS"] = reportsDir
+
+		if step.Docker == nil || len(step.Docker.Command) == 0 ||
+			step.Docker.Command[0] != "/bin/sh" {
+			continue
+		}
+		for _, mount := range step.Files {
+			if mount.Path != "/usr/drone/bin/init" {
+				continue
+			}
+			if file, ok := files[mount.Name]; ok {
+				file.Data = append([]byte(outputTrap), file.Data...)
+			}
+		}
+	}
+}
+
+// outputFilter filters the output and card blocks from the
+// log stream of a single step.
+type outputFilter struct {
+	capture bool
+}
+
+// filter returns true if the line is part of the output or
+// card block and should be excluded from the logs.
+func (f *outputFilter) filter(line *runtime.Line) bool {
+	message := strings.TrimSpace(line.Message)
+	switch message {
+	case outputBegin, cardBegin:
+		f.capture = true
+		return true
+	case outputEnd, cardEnd:
+		f.capture = false
+		return true
+	}
+	return f.capture
+}
+
+// helper function splits the step logs into the contents
+// of the output block and the remaining log lines.
+func splitOutputs(lines []*runtime.Line) (string, []*runtime.Line) {
+	return splitBlock(lines, outputBegin, outputEnd)
+}
+
+// helper function splits the step logs into the contents of
+// the block delimited by the begin and end markers, and the
+// remaining log lines.
+func splitBlock(lines []*runtime.Line, begin, end string) (string, []*runtime.Line) {
+	var (
+		data     strings.Builder
+		filtered []*runtime.Line
+		capture  bool
+	)
+	for _, line := range lines {
+		switch strings.TrimSpace(line.Message) {
+		case begin:
+			capture = true
+		case end:
+			capture = false
+		default:
+			if capture {
+				data.WriteString(line.Message)
+			} else {
+				filtered = append(filtered, line)
+			}
+		}
+	}
+	return data.String(), filtered
+}
+
+// helper function parses the outputs file. Invalid lines,
+// and lines exceeding the size limits, are ignored.
+func parseOutputs(data string) []*core.Output {
+	var outputs []*core.Output
+	var size int
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		output := new(core.Output)
+		if strings.HasPrefix(line, "secret:") {
+			output.Secret = true
+			line = strings.TrimPrefix(line, "secret:")
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		output.Name = parts[0]
+		output.Value = parts[1]
+		if output.Validate() != nil {
+			continue
+		}
+		size += len(output.Value)
+		if size > maxOutputsSize {
+			break
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// helper function returns a replacer that masks the secret
+// output values, or nil if there are no secret outputs.
+func outputMasker(outputs []*core.Output) *strings.Replacer {
+	var oldnew []string
+	for _, output := range outputs {
+		if output.Secret && output.Value != "" {
+			oldnew = append(oldnew, output.Value, "********")
+		}
+	}
+	if len(oldnew) == 0 {
+		return nil
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// helper function returns the outputs as environment
+// variables. Outputs with reserved names are ignored.
+func outputEnviron(outputs []*core.Output) map[string]string {
+	env := map[string]string{}
+	for _, output := range outputs {
+		if output.IsReserved() {
+			continue
+		}
+		env[output.Name] = output.Value
+	}
+	return env
+}
+
+// helper function adds the outputs to the step environment.
+// Outputs never override existing environment variables or
+// secrets, which prevents a step from altering the behavior
+// of later steps, or from reading secrets it cannot access.
+func applyOutputs(step *engine.Step, outputs []*core.Output) {
+	secrets := map[string]struct{}{}
+	for _, secret := range step.Secrets {
+		secrets[secret.Env] = struct{}{}
+	}
+	for k, v := range outputEnviron(outputs) {
+		if _, ok := step.Envs[k]; ok {
+			continue
+		}
+		if _, ok := secrets[k]; ok {
+			continue
+		}
+		step.Envs[k] = v
+	}
+}
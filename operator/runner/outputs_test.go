@@ -0,0 +1,138 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-runtime/engine"
+	"github.com/drone/drone-runtime/runtime"
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetupOutputs(t *testing.T) {
+	spec := &engine.Spec{
+		Files: []*engine.File{
+			{Metadata: engine.Metadata{Name: "build"}, Data: []byte("go build")},
+		},
+		Steps: []*engine.Step{
+			{
+				Metadata: engine.Metadata{Name: "build"},
+				Docker:   &engine.DockerStep{Command: []string{"/bin/sh"}},
+				Files:    []*engine.FileMount{{Name: "build", Path: "/usr/drone/bin/init"}},
+			},
+			{
+				Metadata: engine.Metadata{Name: "publish"},
+				Docker:   &engine.DockerStep{},
+			},
+		},
+	}
+	setupOutputs(spec)
+
+	if got, want := string(spec.Files[0].Data), outputTrap+"go build"; got != want {
+		t.Errorf("Want outputs trap prepended to the script, got %q", got)
+	}
+	for _, step := range spec.Steps {
+		if got, want := step.Envs["DRONE_OUTPUT"], outputFile; got != want {
+			t.Errorf("Want DRONE_OUTPUT %q, got %q", want, got)
+		}
+		if got, want := step.Envs["DRONE_CARD"], cardFile; got != want {
+			t.Errorf("Want DRONE_CARD %q, got %q", want, got)
+		}
+	}
+}
+
+func TestSplitOutputs(t *testing.T) {
+	lines := []*runtime.Line{
+		{Number: 0, Message: "+ go build\n"},
+		{Number: 1, Message: outputBegin + "\n"},
+		{Number: 2, Message: "VERSION=1.0.0\n"},
+		{Number: 3, Message: "secret:TOKEN=correct-horse-battery-staple\n"},
+		{Number: 4, Message: "\n"},
+		{Number: 5, Message: outputEnd + "\n"},
+	}
+	data, filtered := splitOutputs(lines)
+	if got, want := data, "VERSION=1.0.0\nsecret:TOKEN=correct-horse-battery-staple\n\n"; got != want {
+		t.Errorf("Want output data %q, got %q", want, got)
+	}
+	if diff := cmp.Diff(filtered, lines[:1]); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	data := strings.Join([]string{
+		"VERSION=1.0.0",
+		"secret:TOKEN=a=b",
+		"",
+		"invalid line",
+		"INVALID-NAME=value",
+		"TOO_LARGE=" + strings.Repeat("x", core.MaxOutputSize+1),
+	}, "\n")
+	got := parseOutputs(data)
+	want := []*core.Output{
+		{Name: "VERSION", Value: "1.0.0"},
+		{Name: "TOKEN", Value: "a=b", Secret: true},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestParseOutputs_Limit(t *testing.T) {
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, "VALUE="+strings.Repeat("x", core.MaxOutputSize))
+	}
+	got := parseOutputs(strings.Join(lines, "\n"))
+	if want := maxOutputsSize / core.MaxOutputSize; len(got) != want {
+		t.Errorf("Want %d outputs within the size limit, got %d", want, len(got))
+	}
+}
+
+func TestOutputMasker(t *testing.T) {
+	if outputMasker(nil) != nil {
+		t.Errorf("Want nil masker when no secret outputs")
+	}
+	masker := outputMasker([]*core.Output{
+		{Name: "VERSION", Value: "1.0.0"},
+		{Name: "TOKEN", Value: "correct-horse-battery-staple", Secret: true},
+	})
+	got := masker.Replace("version 1.0.0 token correct-horse-battery-staple")
+	if want := "version 1.0.0 token ********"; got != want {
+		t.Errorf("Want masked output %q, got %q", want, got)
+	}
+}
+
+func TestApplyOutputs(t *testing.T) {
+	step := &engine.Step{
+		Envs: map[string]string{
+			"DRONE_BUILD_STATUS": "success",
+			"GOOS":               "linux",
+		},
+		Secrets: []*engine.SecretVar{
+			{Name: "docker_password", Env: "DOCKER_PASSWORD"},
+		},
+	}
+	outputs := []*core.Output{
+		{Name: "VERSION", Value: "1.0.0"},
+		{Name: "VERSION", Value: "1.0.1"},
+		{Name: "DRONE_BUILD_STATUS", Value: "failure"},
+		{Name: "PATH", Value: "/tmp"},
+		{Name: "GOOS", Value: "windows"},
+		{Name: "DOCKER_PASSWORD", Value: "correct-horse-battery-staple"},
+	}
+	applyOutputs(step, outputs)
+	want := map[string]string{
+		"DRONE_BUILD_STATUS": "success",
+		"GOOS":               "linux",
+		"VERSION":            "1.0.1",
+	}
+	if diff := cmp.Diff(step.Envs, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
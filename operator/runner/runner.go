@@ -37,12 +37,17 @@ import (
 	"github.com/drone/drone/plugin/registry"
 	"github.com/drone/drone/plugin/secret"
 	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/trace"
 	"github.com/drone/envsubst"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/sirupsen/logrus"
 )
 
+// errDrained is returned when a running stage is stopped
+// because the runner drain timeout is exceeded.
+var errDrained = errors.New("Stage stopped because the runner was shut down before the stage completed")
+
 // Limits defines runtime container limits.
 type Limits struct {
 	MemSwapLimit int64
@@ -71,6 +76,36 @@ type Runner struct {
 	Machine    string
 	Labels     map[string]string
 
+	// Sampler samples the resource usage of step containers.
+	// Resource usage is not reported if the sampler is nil.
+	Sampler Sampler
+
+	// Copier copies files from the step containers, which
+	// are uploaded as build artifacts. Files are not copied
+	// if the copier is nil.
+	Copier Copier
+
+	// LogLimits limits the size of the step and stage logs.
+	// Logs that exceed the limits are truncated.
+	LogLimits core.LogLimits
+
+	// Workspace is an optional host directory mounted as the
+	// pipeline workspace, in place of the cloned repository.
+	// This is intended for local pipeline execution.
+	Workspace string
+
+	// DrainTimeout is the maximum amount of time to wait for
+	// running stages to complete when the runner is stopped
+	// or drained. Stages still running when the timeout is
+	// exceeded are stopped and reported as errored. If zero,
+	// the runner waits for running stages indefinitely.
+	DrainTimeout time.Duration
+
+	// running stages, and the functions used to stop them
+	// when the drain timeout is exceeded.
+	running map[int64]context.CancelFunc
+	stopped bool
+
 	Kind     string
 	Type     string
 	Platform string
@@ -149,6 +184,14 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		},
 	)
 
+	// the stage execution continues the trace started when
+	// the build was triggered.
+	ctx, span := trace.Start(trace.Decode(ctx, m.Build.Trace), "runner")
+	span.SetAttribute("repo", m.Repo.Slug)
+	span.SetAttribute("stage", m.Stage.Name)
+	span.SetAttribute("machine", r.Machine)
+	defer span.End()
+
 	netrc, err := r.Manager.Netrc(ctx, m.Repo.ID)
 	if err != nil {
 		logger = logger.WithError(err)
@@ -195,8 +238,17 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		return err
 	}
 
+	// outputs written by the stages this stage depends on
+	// can be substituted in the yaml, but never override the
+	// system environment. Outputs are added to the step
+	// environment when each step starts.
+	substEnviron := combineEnviron(
+		outputEnviron(m.Outputs),
+		environ,
+	)
+
 	y, err = envsubst.Eval(y, func(name string) string {
-		env := environ[name]
+		env := substEnviron[name]
 		if strings.Contains(env, "\n") {
 			env = fmt.Sprintf("%q", env)
 		}
@@ -230,6 +282,17 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 
 	logger = logger.WithField("pipeline", pipeline.Name)
 
+	// the runner can only execute pipelines of the type it
+	// advertises to the queue. this should never happen since
+	// the queue routes stages by type, but stages created by
+	// older versions of the server may not record a type.
+	if kind, typ := r.kindAndType(); !matchPipeline(pipeline, kind, typ) {
+		logger.Errorln("runner: cannot execute pipeline type")
+		return r.handleError(ctx, m.Stage,
+			fmt.Errorf("cannot execute pipeline type %q", pipeline.Type),
+		)
+	}
+
 	err = linter.Lint(pipeline, m.Repo.Trusted)
 	if err != nil {
 		logger = logger.WithError(err)
@@ -248,6 +311,11 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 	)
 
 	comp := new(compiler.Compiler)
+	if r.Workspace != "" {
+		pipeline.Clone.Disable = true
+		comp.WorkspaceFunc = compiler.CreateHostWorkspace(r.Workspace)
+		comp.WorkspaceMountFunc = compiler.MountHostWorkspace
+	}
 	comp.PrivilegedFunc = compiler.DindFunc(
 		append(
 			r.Privileged,
@@ -340,6 +408,31 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		),
 	)
 	ir := comp.Compile(pipeline)
+	setupOutputs(ir)
+	setupCaches(ir)
+
+	// outputs written by the stages this stage depends on,
+	// and by the steps in this stage, with secret values
+	// masked in the logs.
+	outputs := append([]*core.Output(nil), m.Outputs...)
+	masker := outputMasker(outputs)
+	filters := map[string]*outputFilter{}
+	limiter := newLogLimiter(r.LogLimits)
+
+	// resource usage monitors and trace spans for the
+	// running steps.
+	monitors := map[string]*monitor{}
+	spans := map[string]*trace.Span{}
+	defer func() {
+		r.Lock()
+		for _, mon := range monitors {
+			mon.cancel()
+		}
+		for _, span := range spans {
+			span.End()
+		}
+		r.Unlock()
+	}()
 
 	steps := map[string]*core.Step{}
 	i := 0
@@ -402,6 +495,12 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 				s.Step.Envs["DRONE_STEP_NAME"] = step.Name
 				s.Step.Envs["DRONE_STEP_NUMBER"] = fmt.Sprint(step.Number)
 			}
+			if r.Sampler != nil {
+				monitors[s.Step.Metadata.Name] = startMonitor(
+					ctx, r.Sampler, s.Step.Metadata.UID, usageInterval)
+			}
+			_, spans[s.Step.Metadata.Name] = trace.Start(ctx, "step "+s.Step.Metadata.Name)
+			applyOutputs(s.Step, outputs)
 
 			stepClone := new(core.Step)
 			*stepClone = *step
@@ -420,6 +519,26 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		},
 
 		AfterEach: func(s *runtime.State) error {
+			r.Lock()
+			mon, sampled := monitors[s.Step.Metadata.Name]
+			delete(monitors, s.Step.Metadata.Name)
+			stepSpan := spans[s.Step.Metadata.Name]
+			delete(spans, s.Step.Metadata.Name)
+			r.Unlock()
+
+			stepSpan.SetAttribute("exit_code", strconv.Itoa(s.State.ExitCode))
+			if s.State.ExitCode != 0 && s.State.ExitCode != 78 {
+				stepSpan.SetError(fmt.Errorf("exit code %d", s.State.ExitCode))
+			}
+			stepSpan.End()
+
+			// stop sampling before acquiring the lock, since
+			// stopping waits for any in-flight sample.
+			var stats *usage
+			if sampled {
+				stats = mon.stop()
+			}
+
 			r.Lock()
 			step, ok := steps[s.Step.Metadata.Name]
 			if ok {
@@ -429,11 +548,21 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 				if s.State.ExitCode != 0 && s.State.ExitCode != 78 {
 					step.Status = core.StatusFailing
 				}
+				if stats != nil {
+					stats.apply(step)
+				}
 			}
 			stepClone := new(core.Step)
 			*stepClone = *step
 			r.Unlock()
 
+			// files are copied from the step container after
+			// the step exits, and before the step is reported
+			// as complete.
+			r.collectArtifacts(ctx, logger, stepClone.ID, s.Step.Metadata.UID)
+			r.collectReports(ctx, logger, stepClone.ID, s.Step.Metadata.UID)
+			r.saveCache(ctx, logger, stepClone.ID, s.Step)
+
 			err := r.Manager.After(ctx, stepClone)
 			if err != nil {
 				return err
@@ -449,11 +578,37 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		GotLine: func(s *runtime.State, line *runtime.Line) error {
 			r.Lock()
 			step, ok := steps[s.Step.Metadata.Name]
+			filter, exists := filters[s.Step.Metadata.Name]
+			if !exists {
+				filter = new(outputFilter)
+				filters[s.Step.Metadata.Name] = filter
+			}
+			mask := masker
 			r.Unlock()
 			if !ok {
 				// TODO log error
 				return nil
 			}
+			if filter.filter(line) {
+				return nil
+			}
+
+			r.Lock()
+			accepted, truncated := limiter.accept(s.Step.Metadata.Name, line)
+			if truncated {
+				step.Truncated = true
+			}
+			r.Unlock()
+			if truncated {
+				return r.Manager.Write(ctx, step.ID, core.TruncatedLine(line.Number, line.Timestamp))
+			}
+			if !accepted {
+				return nil
+			}
+
+			if mask != nil {
+				line.Message = mask.Replace(line.Message)
+			}
 			return r.Manager.Write(ctx, step.ID, convertLine(line))
 		},
 
@@ -465,6 +620,44 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 				// TODO log error
 				return nil
 			}
+
+			data, lines := splitOutputs(lines)
+			card, lines := splitCard(lines)
+
+			r.Lock()
+			lines = limiter.truncate(s.Step.Metadata.Name, lines)
+			r.Unlock()
+			if parsed := parseOutputs(data); len(parsed) != 0 {
+				err := r.Manager.UploadOutputs(ctx, step.ID, parsed)
+				if err != nil {
+					logger.WithError(err).Warnln("runner: cannot upload step outputs")
+				}
+				r.Lock()
+				outputs = append(outputs, parsed...)
+				masker = outputMasker(outputs)
+				r.Unlock()
+			}
+
+			r.Lock()
+			mask := masker
+			r.Unlock()
+			if mask != nil {
+				for _, line := range lines {
+					line.Message = mask.Replace(line.Message)
+				}
+				if card != nil {
+					card.Data = mask.Replace(card.Data)
+				}
+			}
+
+			annotations := parseAnnotations(lines)
+			if len(annotations) != 0 || card != nil {
+				err := r.Manager.UploadAnnotations(ctx, step.ID, annotations, card)
+				if err != nil {
+					logger.WithError(err).Warnln("runner: cannot upload step annotations")
+				}
+			}
+
 			raw, _ := json.Marshal(
 				convertLines(lines),
 			)
@@ -472,8 +665,22 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 		},
 	}
 
+	// the build cache is restored after the step container
+	// is created, and before the step starts.
+	eng := &cacheEngine{
+		Engine: r.Engine,
+		restore: func(ctx context.Context, s *engine.Step) {
+			r.Lock()
+			step, ok := steps[s.Metadata.Name]
+			r.Unlock()
+			if ok {
+				r.restoreCache(ctx, logger, step.ID, s)
+			}
+		},
+	}
+
 	runner := runtime.New(
-		runtime.WithEngine(r.Engine),
+		runtime.WithEngine(eng),
 		runtime.WithConfig(ir),
 		runtime.WithHooks(hooks),
 	)
@@ -494,7 +701,11 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 	logger.Infoln("runner: start execution")
 
 	err = runner.Run(timeout)
+	if err != nil && r.isStopped() {
+		err = errDrained
+	}
 	if err != nil && err != runtime.ErrInterrupt {
+		span.SetError(err)
 		logger = logger.WithError(err)
 		logger.Infoln("runner: execution failed")
 		return r.handleError(ctx, m.Stage, err)
@@ -518,15 +729,105 @@ func (r *Runner) Run(ctx context.Context, id int64) error {
 }
 
 // Start starts N build runner processes. Each process polls
-// the server for pending builds to execute.
+// the server for pending builds to execute. When the context
+// is cancelled, or the server asks the runner to drain, the
+// runner stops polling and waits for running stages to
+// complete, up to the drain timeout, before returning.
 func (r *Runner) Start(ctx context.Context, n int) error {
-	var g errgroup.Group
-	for i := 0; i < n; i++ {
-		g.Go(func() error {
-			return r.start(ctx)
-		})
+	ctx, drain := context.WithCancel(ctx)
+	defer drain()
+	go r.watch(ctx, drain)
+
+	errc := make(chan error, 1)
+	go func() {
+		var g errgroup.Group
+		for i := 0; i < n; i++ {
+			g.Go(func() error {
+				return r.start(ctx)
+			})
+		}
+		errc <- g.Wait()
+	}()
+
+	<-ctx.Done()
+	logrus.WithField("machine", r.Machine).
+		Infoln("runner: draining, waiting for running stages")
+
+	var timeout <-chan time.Time
+	if r.DrainTimeout > 0 {
+		timeout = time.After(r.DrainTimeout)
+	}
+	select {
+	case err := <-errc:
+		return err
+	case <-timeout:
+		logrus.WithField("machine", r.Machine).
+			Warnln("runner: drain timeout exceeded, stopping running stages")
+		r.stop()
+		return <-errc
+	}
+}
+
+// watch watches for drain requests from the server, and
+// drains the runner when requested.
+func (r *Runner) watch(ctx context.Context, drain context.CancelFunc) {
+	for {
+		drained, err := r.Manager.Drained(ctx, r.Machine)
+		if ctx.Err() != nil {
+			return
+		}
+		if drained {
+			logrus.WithField("machine", r.Machine).
+				Infoln("runner: received drain signal")
+			drain()
+			return
+		}
+		if err != nil && err != context.DeadlineExceeded {
+			// back off before retrying to prevent a tight loop
+			// when the server cannot deliver drain requests.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Minute):
+			}
+		}
+	}
+}
+
+// track tracks the running stage, so that it can be stopped
+// if the drain timeout is exceeded.
+func (r *Runner) track(id int64, cancel context.CancelFunc) {
+	r.Lock()
+	if r.running == nil {
+		r.running = map[int64]context.CancelFunc{}
 	}
-	return g.Wait()
+	r.running[id] = cancel
+	r.Unlock()
+}
+
+// untrack stops tracking the stage once it is complete.
+func (r *Runner) untrack(id int64) {
+	r.Lock()
+	delete(r.running, id)
+	r.Unlock()
+}
+
+// stop stops all running stages.
+func (r *Runner) stop() {
+	r.Lock()
+	r.stopped = true
+	for _, cancel := range r.running {
+		cancel()
+	}
+	r.Unlock()
+}
+
+// isStopped returns true if the running stages were stopped
+// because the drain timeout was exceeded.
+func (r *Runner) isStopped() bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.stopped
 }
 
 func (r *Runner) start(ctx context.Context) error {
@@ -544,6 +845,33 @@ func (r *Runner) start(ctx context.Context) error {
 	}
 }
 
+// helper function returns the pipeline kind and type that
+// the runner advertises to the queue, defaulting to docker
+// pipelines.
+func (r *Runner) kindAndType() (kind, typ string) {
+	kind, typ = r.Kind, r.Type
+	if kind == "" {
+		kind = "pipeline"
+	}
+	if typ == "" {
+		typ = "docker"
+	}
+	return kind, typ
+}
+
+// helper function returns true if the pipeline matches the
+// kind and type. A pipeline that does not define a type
+// defaults to a docker pipeline.
+func matchPipeline(pipeline *yaml.Pipeline, kind, typ string) bool {
+	if pipeline.Kind != "" && pipeline.Kind != kind {
+		return false
+	}
+	if pipeline.Type == "" {
+		return typ == "docker"
+	}
+	return pipeline.Type == typ
+}
+
 func (r *Runner) poll(ctx context.Context) error {
 	logger := logrus.WithFields(
 		logrus.Fields{
@@ -554,9 +882,10 @@ func (r *Runner) poll(ctx context.Context) error {
 	)
 
 	logger.Debugln("runner: polling queue")
+	kind, typ := r.kindAndType()
 	p, err := r.Manager.Request(ctx, &manager.Request{
-		Kind:    "pipeline",
-		Type:    "docker",
+		Kind:    kind,
+		Type:    typ,
 		OS:      r.OS,
 		Arch:    r.Arch,
 		Kernel:  r.Kernel,
@@ -572,6 +901,13 @@ func (r *Runner) poll(ctx context.Context) error {
 		return nil
 	}
 
+	// the runner may start draining while waiting for a
+	// stage, in which case the stage is not accepted and
+	// remains in the queue for another runner.
+	if ctx.Err() != nil {
+		return nil
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -588,6 +924,9 @@ func (r *Runner) poll(ctx context.Context) error {
 		return err
 	}
 
+	r.track(p.ID, cancel)
+	defer r.untrack(p.ID)
+
 	go func() {
 		logger.Debugln("runner: watch for cancel signal")
 		done, _ := r.Manager.Watch(ctx, p.BuildID)
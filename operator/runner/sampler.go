@@ -0,0 +1,211 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dockerAPIVersion is the Docker engine API version used to
+// request container statistics and to copy container files.
+const dockerAPIVersion = "v1.25"
+
+// NewDockerSampler returns a Sampler that reads container
+// resource usage from the Docker engine API. The Docker
+// host is configured from the DOCKER_HOST, DOCKER_CERT_PATH
+// and DOCKER_TLS_VERIFY environment variables.
+func NewDockerSampler() (Sampler, error) {
+	server, client, err := dockerClient()
+	if err != nil {
+		return nil, err
+	}
+	return newDockerSampler(server, client), nil
+}
+
+func newDockerSampler(server string, client *http.Client) Sampler {
+	return &dockerSampler{
+		server: server,
+		client: client,
+	}
+}
+
+type dockerSampler struct {
+	server string
+	client *http.Client
+}
+
+func (s *dockerSampler) Sample(ctx context.Context, container string) (*Sample, error) {
+	uri := fmt.Sprintf("%s/%s/containers/%s/stats?stream=false",
+		s.server, dockerAPIVersion, url.PathEscape(container))
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return nil, fmt.Errorf("docker: cannot get container stats: %s", res.Status)
+	}
+	stats := new(dockerStats)
+	err = json.NewDecoder(res.Body).Decode(stats)
+	if err != nil {
+		return nil, err
+	}
+	return convertStats(stats), nil
+}
+
+// helper function returns the Docker engine API address and
+// the http client used to connect to the Docker host, which
+// is configured from the DOCKER_HOST, DOCKER_CERT_PATH and
+// DOCKER_TLS_VERIFY environment variables.
+func dockerClient() (string, *http.Client, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = "unix:///var/run/docker.sock"
+	}
+	uri, err := url.Parse(host)
+	if err != nil {
+		return "", nil, err
+	}
+	switch uri.Scheme {
+	case "unix":
+		path := uri.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		}
+		return "http://docker", &http.Client{Transport: transport}, nil
+	case "tcp":
+		certs := os.Getenv("DOCKER_CERT_PATH")
+		if certs == "" {
+			return "http://" + uri.Host, http.DefaultClient, nil
+		}
+		config, err := dockerTLSConfig(certs, os.Getenv("DOCKER_TLS_VERIFY") != "")
+		if err != nil {
+			return "", nil, err
+		}
+		transport := &http.Transport{TLSClientConfig: config}
+		return "https://" + uri.Host, &http.Client{Transport: transport}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported docker host: %s", host)
+	}
+}
+
+// helper function returns a tls configuration that loads
+// the client certificates from the certificate directory.
+func dockerTLSConfig(path string, verify bool) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(
+		filepath.Join(path, "cert.pem"),
+		filepath.Join(path, "key.pem"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: !verify,
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(path, "ca.pem"))
+	if err == nil {
+		config.RootCAs = x509.NewCertPool()
+		config.RootCAs.AppendCertsFromPEM(ca)
+	}
+	return config, nil
+}
+
+type (
+	dockerStats struct {
+		CPUStats    dockerCPUStats    `json:"cpu_stats"`
+		PreCPUStats dockerCPUStats    `json:"precpu_stats"`
+		MemoryStats dockerMemoryStats `json:"memory_stats"`
+		BlkioStats  dockerBlkioStats  `json:"blkio_stats"`
+	}
+
+	dockerCPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	}
+
+	dockerMemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Stats map[string]uint64 `json:"stats"`
+	}
+
+	dockerBlkioStats struct {
+		IoServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value uint64 `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	}
+)
+
+// helper function converts the docker container stats to a
+// resource usage sample. CPU usage is calculated from the
+// delta between the current and previous readings, and page
+// cache is excluded from memory usage, matching the values
+// reported by the docker stats command.
+func convertStats(stats *dockerStats) *Sample {
+	sample := new(Sample)
+
+	cpus := uint64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = uint64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	sysDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && sysDelta > 0 {
+		sample.CPU = int64(cpuDelta / sysDelta * float64(cpus) * 1000)
+	}
+
+	memory := stats.MemoryStats.Usage
+	cache, ok := stats.MemoryStats.Stats["total_inactive_file"]
+	if !ok {
+		cache = stats.MemoryStats.Stats["inactive_file"]
+	}
+	if cache < memory {
+		memory -= cache
+	}
+	sample.Memory = int64(memory)
+
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			sample.IORead += int64(entry.Value)
+		case "write":
+			sample.IOWrite += int64(entry.Value)
+		}
+	}
+	return sample
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDockerSampler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Path, "/v1.25/containers/drone-abc123/stats"; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := r.URL.Query().Get("stream"), "false"; got != want {
+			t.Errorf("Want stream %q, got %q", want, got)
+		}
+		w.Write([]byte(mockStats))
+	}))
+	defer ts.Close()
+
+	sampler := newDockerSampler(ts.URL, ts.Client())
+	got, err := sampler.Sample(context.Background(), "drone-abc123")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := &Sample{
+		CPU:     500,
+		Memory:  104857600,
+		IORead:  4096,
+		IOWrite: 8192,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestDockerSampler_NotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	sampler := newDockerSampler(ts.URL, ts.Client())
+	_, err := sampler.Sample(context.Background(), "drone-abc123")
+	if err == nil {
+		t.Errorf("Want error when container not found")
+	}
+}
+
+func TestConvertStats_CgroupV2(t *testing.T) {
+	stats := new(dockerStats)
+	stats.CPUStats.OnlineCPUs = 4
+	stats.CPUStats.CPUUsage.TotalUsage = 2000
+	stats.CPUStats.SystemUsage = 8000
+	stats.MemoryStats.Usage = 2048
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 1024}
+
+	got := convertStats(stats)
+	if got.CPU != 1000 {
+		t.Errorf("Want cpu 1000 millicores, got %d", got.CPU)
+	}
+	if got.Memory != 1024 {
+		t.Errorf("Want memory excluding inactive file cache, got %d", got.Memory)
+	}
+}
+
+var mockStats = `{
+  "cpu_stats": {
+    "cpu_usage": { "total_usage": 3000, "percpu_usage": [1500, 1500] },
+    "system_cpu_usage": 12000,
+    "online_cpus": 2
+  },
+  "precpu_stats": {
+    "cpu_usage": { "total_usage": 1000 },
+    "system_cpu_usage": 4000
+  },
+  "memory_stats": {
+    "usage": 115343360,
+    "stats": { "total_inactive_file": 10485760 }
+  },
+  "blkio_stats": {
+    "io_service_bytes_recursive": [
+      { "major": 8, "minor": 0, "op": "Read", "value": 4096 },
+      { "major": 8, "minor": 0, "op": "Write", "value": 8192 },
+      { "major": 8, "minor": 0, "op": "Total", "value": 12288 }
+    ]
+  }
+}`
@@ -0,0 +1,129 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone/core"
+)
+
+// usageInterval defines the interval at which container
+// resource usage is sampled.
+const usageInterval = 5 * time.Second
+
+// Sample is a point-in-time sample of container resource
+// usage. CPU usage is measured in millicores. Memory usage
+// is measured in bytes, and I/O is the cumulative number
+// of bytes read and written by the container.
+type Sample struct {
+	CPU     int64
+	Memory  int64
+	IORead  int64
+	IOWrite int64
+}
+
+// Sampler samples container resource usage.
+type Sampler interface {
+	// Sample returns the current resource usage of the
+	// named container.
+	Sample(ctx context.Context, container string) (*Sample, error)
+}
+
+// usage accumulates container resource usage samples.
+type usage struct {
+	count   int64
+	cpuPeak int64
+	cpuSum  int64
+	memPeak int64
+	memSum  int64
+	ioRead  int64
+	ioWrite int64
+}
+
+// add adds the sample to the usage totals.
+func (u *usage) add(sample *Sample) {
+	u.count++
+	u.cpuSum += sample.CPU
+	u.memSum += sample.Memory
+	if sample.CPU > u.cpuPeak {
+		u.cpuPeak = sample.CPU
+	}
+	if sample.Memory > u.memPeak {
+		u.memPeak = sample.Memory
+	}
+	if sample.IORead > u.ioRead {
+		u.ioRead = sample.IORead
+	}
+	if sample.IOWrite > u.ioWrite {
+		u.ioWrite = sample.IOWrite
+	}
+}
+
+// apply copies the peak and average resource usage to the
+// step. The step is unchanged if no samples were taken.
+func (u *usage) apply(step *core.Step) {
+	if u.count == 0 {
+		return
+	}
+	step.CPUPeak = u.cpuPeak
+	step.CPUAvg = u.cpuSum / u.count
+	step.MemPeak = u.memPeak
+	step.MemAvg = u.memSum / u.count
+	step.IORead = u.ioRead
+	step.IOWrite = u.ioWrite
+}
+
+// monitor samples container resource usage in the background
+// until stopped.
+type monitor struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	usage  usage
+}
+
+// startMonitor starts sampling the container resource usage
+// at the given interval. Sampling errors are ignored, since
+// the container may not yet exist or may already be removed.
+func startMonitor(ctx context.Context, sampler Sampler, container string, interval time.Duration) *monitor {
+	ctx, cancel := context.WithCancel(ctx)
+	m := &monitor{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(m.done)
+		for {
+			sample, err := sampler.Sample(ctx, container)
+			if err == nil && sample != nil {
+				m.usage.add(sample)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return m
+}
+
+// stop stops sampling and returns the accumulated usage.
+func (m *monitor) stop() *usage {
+	m.cancel()
+	<-m.done
+	return &m.usage
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUsage(t *testing.T) {
+	u := new(usage)
+	u.add(&Sample{CPU: 500, Memory: 100, IORead: 10, IOWrite: 20})
+	u.add(&Sample{CPU: 1500, Memory: 300, IORead: 30, IOWrite: 40})
+	u.add(&Sample{CPU: 1000, Memory: 200, IORead: 30, IOWrite: 40})
+
+	got, want := new(core.Step), &core.Step{
+		CPUPeak: 1500,
+		CPUAvg:  1000,
+		MemPeak: 300,
+		MemAvg:  200,
+		IORead:  30,
+		IOWrite: 40,
+	}
+	u.apply(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestUsage_NoSamples(t *testing.T) {
+	u := new(usage)
+	got := &core.Step{CPUPeak: 1}
+	u.apply(got)
+	if got.CPUPeak != 1 {
+		t.Errorf("Want step unchanged when no samples are taken")
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	sampler := &mockSampler{
+		samples: []*Sample{
+			{CPU: 250, Memory: 1024},
+			{CPU: 750, Memory: 3072},
+		},
+	}
+	m := startMonitor(context.Background(), sampler, "test", time.Millisecond)
+	for sampler.calls() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	u := m.stop()
+	if got, want := u.count, int64(2); got != want {
+		t.Errorf("Want %d samples, got %d", want, got)
+	}
+	if got, want := u.cpuPeak, int64(750); got != want {
+		t.Errorf("Want peak cpu %d, got %d", want, got)
+	}
+	if got, want := sampler.container, "test"; got != want {
+		t.Errorf("Want container %q, got %q", want, got)
+	}
+}
+
+// mockSampler returns the samples in order, and then returns
+// an error once all samples are consumed.
+type mockSampler struct {
+	sync.Mutex
+	samples   []*Sample
+	container string
+	count     int
+}
+
+func (s *mockSampler) Sample(ctx context.Context, container string) (*Sample, error) {
+	s.Lock()
+	defer s.Unlock()
+	s.container = container
+	s.count++
+	if len(s.samples) == 0 {
+		return nil, errors.New("no such container")
+	}
+	sample := s.samples[0]
+	s.samples = s.samples[1:]
+	return sample, nil
+}
+
+func (s *mockSampler) calls() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.count
+}
@@ -42,12 +42,28 @@ func (p *jsonnetPlugin) Find(ctx context.Context, req *core.ConfigArgs) (*core.C
 		return nil, nil
 	}
 
+	// if the configuration path is a directory or glob
+	// pattern the files are handled by the multi-file plugin.
+	if isPattern(req.Repo.Config) {
+		return nil, nil
+	}
+
 	// get the file contents.
 	config, err := p.repos.Find(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	config.Data, err = evaluateJsonnet(req.Repo.Config, config.Data)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// helper function evaluates the named jsonnet snippet and
+// returns the resulting yaml documents.
+func evaluateJsonnet(name, data string) (string, error) {
 	// TODO(bradrydzewski) temporarily disable file imports
 	// TODO(bradrydzewski) handle object vs array output
 
@@ -59,9 +75,9 @@ func (p *jsonnetPlugin) Find(ctx context.Context, req *core.ConfigArgs) (*core.C
 
 	// convert the jsonnet file to yaml
 	buf := new(bytes.Buffer)
-	docs, err := vm.EvaluateSnippetStream(req.Repo.Config, config.Data)
+	docs, err := vm.EvaluateSnippetStream(name, data)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	// the jsonnet vm returns a stream of yaml documents
@@ -71,7 +87,5 @@ func (p *jsonnetPlugin) Find(ctx context.Context, req *core.ConfigArgs) (*core.C
 		buf.WriteString("\n")
 		buf.WriteString(doc)
 	}
-
-	config.Data = buf.String()
-	return config, nil
+	return buf.String(), nil
 }
@@ -0,0 +1,183 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/signer"
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// Multi returns a configuration service that fetches all
+// configuration files in a directory, or matching a glob
+// pattern (e.g. .drone/*.yml), and merges them into a single
+// multi-document yaml file. The file service must support
+// listing directory contents, which is not supported by all
+// source control providers (e.g. stash and gogs).
+func Multi(service core.FileService, jsonnet bool) core.ConfigService {
+	return &multi{
+		files:   service,
+		jsonnet: jsonnet,
+	}
+}
+
+type multi struct {
+	files   core.FileService
+	jsonnet bool
+}
+
+func (p *multi) Find(ctx context.Context, req *core.ConfigArgs) (*core.Config, error) {
+	// if the configuration path is not a directory or glob
+	// pattern we can skip this plugin by returning zero values.
+	if isPattern(req.Repo.Config) == false {
+		return nil, nil
+	}
+
+	dir, pattern := splitPattern(req.Repo.Config)
+	entries, err := p.files.List(ctx, req.User, req.Repo.Slug, req.Build.After, req.Build.Ref, dir)
+	if err == scm.ErrNotSupported {
+		return nil, fmt.Errorf("configuration: the source control provider does not support listing %s", req.Repo.Config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		if ok, _ := path.Match(pattern, entry.Path); !ok {
+			continue
+		}
+		if isSupported(entry.Path, p.jsonnet) {
+			paths = append(paths, entry.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("configuration: no files match %s", req.Repo.Config)
+	}
+
+	// files are merged in lexical order so that the resulting
+	// configuration is deterministic.
+	sort.Strings(paths)
+
+	// signatures are computed for a single file and cannot be
+	// verified against the merged file. Each file signature is
+	// verified individually, and if all files are verified the
+	// merged file is signed with the repository secret.
+	key := signer.KeyString(req.Repo.Secret)
+	verified := req.Repo.Protected && req.Build.Trigger == core.TriggerHook
+
+	buf := new(bytes.Buffer)
+	seen := map[string]string{}
+	for _, name := range paths {
+		file, err := p.files.Find(ctx, req.User, req.Repo.Slug, req.Build.After, req.Build.Ref, name)
+		if err != nil {
+			return nil, err
+		}
+		if verified {
+			verified, _ = signer.Verify(file.Data, key)
+		}
+		data := string(file.Data)
+		if strings.HasSuffix(name, ".jsonnet") {
+			data, err = evaluateJsonnet(name, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resources, err := yaml.ParseRawString(data)
+		if err != nil {
+			return nil, fmt.Errorf("configuration: %s: %s", name, err)
+		}
+		for _, resource := range resources {
+			if len(bytes.TrimSpace(resource.Data)) == 0 {
+				continue
+			}
+			if resource.Kind == yaml.KindSignature {
+				continue
+			}
+			key := resourceKey(resource)
+			if prev, ok := seen[key]; ok {
+				return nil, fmt.Errorf("configuration: duplicate %s in %s and %s", key, prev, name)
+			}
+			seen[key] = name
+
+			buf.WriteString("---\n")
+			buf.Write(resource.Data)
+		}
+	}
+
+	data := buf.Bytes()
+	if verified {
+		data, err = signer.SignUpdate(data, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &core.Config{
+		Data: string(data),
+	}, nil
+}
+
+// helper function returns true if the configuration path is
+// a directory or glob pattern.
+func isPattern(s string) bool {
+	return strings.HasSuffix(s, "/") || strings.ContainsAny(s, "*?[")
+}
+
+// helper function splits the configuration path into the
+// directory that is listed and the pattern that is used to
+// match files in the directory. Patterns are only supported
+// in the final path element.
+func splitPattern(s string) (dir, pattern string) {
+	if strings.HasSuffix(s, "/") {
+		dir = strings.TrimSuffix(s, "/")
+		return dir, path.Join(dir, "*")
+	}
+	return path.Dir(s), s
+}
+
+// helper function returns true if the file extension is a
+// supported configuration format.
+func isSupported(name string, jsonnet bool) bool {
+	switch path.Ext(name) {
+	case ".yml", ".yaml":
+		return true
+	case ".jsonnet":
+		return jsonnet
+	default:
+		return false
+	}
+}
+
+// helper function returns a key that uniquely identifies
+// the resource by kind and name.
+func resourceKey(resource *yaml.RawResource) string {
+	out := struct {
+		Name string
+	}{}
+	yamlv2.Unmarshal(resource.Data, &out)
+	kind := resource.Kind
+	if kind == "" {
+		kind = yaml.KindPipeline
+	}
+	if out.Name == "" {
+		out.Name = "default"
+	}
+	return fmt.Sprintf("%s %q", kind, out.Name)
+}
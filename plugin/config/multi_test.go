@@ -0,0 +1,230 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-yaml/yaml/signer"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
+
+	"github.com/golang/mock/gomock"
+)
+
+var mockFileBuild = []byte(`
+kind: pipeline
+name: build
+
+steps: []
+`)
+
+var mockFileTest = []byte(`
+kind: pipeline
+name: test
+
+steps: []
+`)
+
+func TestMulti(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone/*.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	entries := []*core.FileInfo{
+		{Path: ".drone/test.yml"},
+		{Path: ".drone/build.yml"},
+		{Path: ".drone/README.md"},
+		{Path: ".drone/templates", IsDir: true},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(entries, nil)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/build.yml").Return(&core.File{Data: mockFileBuild}, nil)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/test.yml").Return(&core.File{Data: mockFileTest}, nil)
+
+	service := Multi(files, false)
+	result, err := service.Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	build := strings.Index(result.Data, "name: build")
+	test := strings.Index(result.Data, "name: test")
+	if build == -1 || test == -1 {
+		t.Errorf("Want merged configuration, got %q", result.Data)
+	}
+	if build > test {
+		t.Errorf("Want files merged in lexical order")
+	}
+}
+
+func TestMulti_Directory(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone/"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	entries := []*core.FileInfo{
+		{Path: ".drone/build.yaml"},
+		{Path: ".drone/test.jsonnet"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(entries, nil)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/build.yaml").Return(&core.File{Data: mockFileBuild}, nil)
+
+	service := Multi(files, false)
+	result, err := service.Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := strings.Count(result.Data, "---"), 1; got != want {
+		t.Errorf("Want jsonnet files ignored when disabled, got %d documents", got)
+	}
+}
+
+func TestMulti_Duplicate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone/*.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	entries := []*core.FileInfo{
+		{Path: ".drone/a.yml"},
+		{Path: ".drone/b.yml"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(entries, nil)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/a.yml").Return(&core.File{Data: mockFile}, nil)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/b.yml").Return(&core.File{Data: mockFile}, nil)
+
+	service := Multi(files, false)
+	_, err := service.Find(noContext, args)
+	if err == nil {
+		t.Errorf("Want error when pipeline names are duplicated")
+	}
+}
+
+func TestMulti_NoMatch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone/*.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(nil, nil)
+
+	service := Multi(files, false)
+	_, err := service.Find(noContext, args)
+	if err == nil {
+		t.Errorf("Want error when no files match")
+	}
+}
+
+func TestMulti_Skip(t *testing.T) {
+	args := &core.ConfigArgs{
+		Repo: &core.Repository{Config: ".drone.yml"},
+	}
+	service := Multi(nil, false)
+	result, err := service.Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+	}
+	if result != nil {
+		t.Errorf("Want nil config when path is not a pattern")
+	}
+}
+
+func TestMulti_Signed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	key := signer.KeyString("IT2ZY4bNtZ3mdwOGs2vAsfUbRJtAd4Ze")
+	signedBuild, _ := signer.SignUpdate(mockFileBuild, key)
+	signedTest, _ := signer.SignUpdate(mockFileTest, key)
+
+	tests := []struct {
+		test     []byte
+		verified bool
+	}{
+		{test: signedTest, verified: true},
+		{test: mockFileTest, verified: false},
+	}
+	for i, test := range tests {
+		args := &core.ConfigArgs{
+			User:  &core.User{Login: "octocat"},
+			Repo:  &core.Repository{Slug: "octocat/hello-world", Config: ".drone/*.yml", Protected: true, Secret: string(key)},
+			Build: &core.Build{After: "6d144de7", Trigger: core.TriggerHook},
+		}
+
+		entries := []*core.FileInfo{
+			{Path: ".drone/test.yml"},
+			{Path: ".drone/build.yml"},
+		}
+
+		files := mock.NewMockFileService(controller)
+		files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(entries, nil)
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/build.yml").Return(&core.File{Data: signedBuild}, nil)
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone/test.yml").Return(&core.File{Data: test.test}, nil)
+
+		result, err := Multi(files, false).Find(noContext, args)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		verified, _ := signer.Verify([]byte(result.Data), key)
+		if got, want := verified, test.verified; got != want {
+			t.Errorf("Want merged file verified %v at index %d", want, i)
+		}
+	}
+}
+
+func TestMulti_NotSupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:  &core.User{Login: "octocat"},
+		Repo:  &core.Repository{Slug: "octocat/hello-world", Config: ".drone/"},
+		Build: &core.Build{After: "6d144de7"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().List(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone").Return(nil, scm.ErrNotSupported)
+
+	_, err := Multi(files, false).Find(noContext, args)
+	if err == nil || !strings.Contains(err.Error(), "does not support listing") {
+		t.Errorf("Want not supported error, got %v", err)
+	}
+}
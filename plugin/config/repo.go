@@ -16,22 +16,63 @@ package config
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/envsubst"
+	"github.com/drone/go-scm/scm"
 )
 
 // Repository returns a configuration service that fetches the yaml
-// directly from the source code management (scm) system.
-func Repository(service core.FileService) core.ConfigService {
-	return &repo{files: service}
+// directly from the source code management (scm) system. If the
+// configuration file is not found, the fallback paths configured
+// for the repository are tried in order, followed by the central
+// configuration repository configured for the namespace.
+func Repository(service core.FileService, central core.CentralConfigStore) core.ConfigService {
+	return &repo{files: service, central: central}
 }
 
 type repo struct {
-	files core.FileService
+	files   core.FileService
+	central core.CentralConfigStore
 }
 
 func (r *repo) Find(ctx context.Context, req *core.ConfigArgs) (*core.Config, error) {
-	raw, err := r.files.Find(ctx, req.User, req.Repo.Slug, req.Build.After, req.Build.Ref, req.Repo.Config)
+	config, err := r.find(ctx, req, req.Repo.Config)
+	if err != scm.ErrNotFound {
+		return config, err
+	}
+	// the fallback locations are only tried if the file does
+	// not exist. Other errors, for example authorization
+	// errors, rate limits or server errors, are returned to
+	// prevent the build from running an unexpected pipeline.
+	for _, path := range req.Repo.Fallback {
+		config, ferr := r.find(ctx, req, path)
+		if ferr != scm.ErrNotFound {
+			return config, ferr
+		}
+	}
+	central, cerr := r.central.Find(ctx, req.Repo.Namespace)
+	if cerr == sql.ErrNoRows {
+		return nil, err
+	}
+	if cerr != nil {
+		return nil, cerr
+	}
+	config, cerr = r.findCentral(ctx, req, central.Path)
+	if cerr != scm.ErrNotFound {
+		return config, cerr
+	}
+	// if the configuration file cannot be found in any of
+	// the fallback locations the original error is returned.
+	return nil, err
+}
+
+// helper function fetches the named configuration file from
+// the repository at the build commit.
+func (r *repo) find(ctx context.Context, req *core.ConfigArgs, path string) (*core.Config, error) {
+	raw, err := r.files.Find(ctx, req.User, req.Repo.Slug, req.Build.After, req.Build.Ref, path)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +80,49 @@ func (r *repo) Find(ctx context.Context, req *core.ConfigArgs) (*core.Config, er
 		Data: string(raw.Data),
 	}, err
 }
+
+// helper function fetches the configuration file from the
+// central configuration repository.
+func (r *repo) findCentral(ctx context.Context, req *core.ConfigArgs, spec string) (*core.Config, error) {
+	spec, err := envsubst.Eval(spec, func(name string) string {
+		switch name {
+		case "DRONE_REPO":
+			return req.Repo.Slug
+		case "DRONE_REPO_NAMESPACE", "DRONE_REPO_OWNER":
+			return req.Repo.Namespace
+		case "DRONE_REPO_NAME":
+			return req.Repo.Name
+		case "DRONE_REPO_BRANCH":
+			return req.Repo.Branch
+		default:
+			return ""
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	slug, ref, path := splitCentral(spec)
+	raw, err := r.files.Find(ctx, req.User, slug, ref, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	return &core.Config{
+		Data: string(raw.Data),
+	}, nil
+}
+
+// helper function splits the central configuration into the
+// repository slug, git reference and file path. The reference
+// defaults to master.
+func splitCentral(s string) (slug, ref, path string) {
+	parts := strings.SplitN(strings.TrimPrefix(s, "/"), "/", 3)
+	if len(parts) != 3 {
+		return s, "master", ""
+	}
+	slug, path = parts[0]+"/"+parts[1], parts[2]
+	ref = "master"
+	if i := strings.Index(slug, "@"); i != -1 {
+		slug, ref = slug[:i], slug[i+1:]
+	}
+	return slug, ref, path
+}
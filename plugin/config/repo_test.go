@@ -6,11 +6,13 @@ package config
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
 
 	"github.com/golang/mock/gomock"
 )
@@ -40,7 +42,7 @@ func TestRepository(t *testing.T) {
 	files := mock.NewMockFileService(controller)
 	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(resp, nil)
 
-	service := Repository(files)
+	service := Repository(files, nil)
 	result, err := service.Find(noContext, args)
 	if err != nil {
 		t.Error(err)
@@ -67,9 +69,171 @@ func TestRepositoryErr(t *testing.T) {
 	files := mock.NewMockFileService(controller)
 	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(nil, resp)
 
-	service := Repository(files)
+	service := Repository(files, nil)
 	_, err := service.Find(noContext, args)
 	if err != resp {
 		t.Errorf("expect error returned from file service")
 	}
 }
+
+func TestRepositoryFallback(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone.yml", Fallback: []string{".drone.yaml", ".ci/drone.yml"}},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	resp := &core.File{Data: mockFile}
+
+	files := mock.NewMockFileService(controller)
+	gomock.InOrder(
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotFound),
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yaml").Return(nil, scm.ErrNotFound),
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".ci/drone.yml").Return(resp, nil),
+	)
+
+	service := Repository(files, nil)
+	result, err := service.Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Data != string(resp.Data) {
+		t.Errorf("unexpected file contents")
+	}
+}
+
+// this test verifies that the fallback paths are not tried
+// if the configuration file cannot be fetched for a reason
+// other than the file not existing.
+func TestRepositoryFallbackErr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Slug: "octocat/hello-world", Config: ".drone.yml", Fallback: []string{".drone.yaml"}},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotAuthorized)
+
+	service := Repository(files, nil)
+	_, err := service.Find(noContext, args)
+	if err != scm.ErrNotAuthorized {
+		t.Errorf("expect error returned from file service")
+	}
+}
+
+func TestRepositoryCentral(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	resp := &core.File{Data: mockFile}
+
+	central := mock.NewMockCentralConfigStore(controller)
+	central.EXPECT().Find(noContext, "octocat").Return(&core.CentralConfig{Path: "octocat/drone-config@stable/pipelines/${DRONE_REPO_NAME}.yml"}, nil)
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotFound)
+	files.EXPECT().Find(noContext, args.User, "octocat/drone-config", "stable", "stable", "pipelines/hello-world.yml").Return(resp, nil)
+
+	service := Repository(files, central)
+	result, err := service.Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Data != string(resp.Data) {
+		t.Errorf("unexpected file contents")
+	}
+}
+
+func TestRepositoryCentralNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	central := mock.NewMockCentralConfigStore(controller)
+	central.EXPECT().Find(noContext, "octocat").Return(&core.CentralConfig{Path: "octocat/drone-config/drone.yml"}, nil)
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotFound)
+	files.EXPECT().Find(noContext, args.User, "octocat/drone-config", "master", "master", "drone.yml").Return(nil, scm.ErrNotFound)
+
+	service := Repository(files, central)
+	_, err := service.Find(noContext, args)
+	if err != scm.ErrNotFound {
+		t.Errorf("expect original error returned from file service")
+	}
+}
+
+func TestRepositoryCentralErr(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	resp := errors.New("")
+
+	central := mock.NewMockCentralConfigStore(controller)
+	central.EXPECT().Find(noContext, "octocat").Return(&core.CentralConfig{Path: "octocat/drone-config/drone.yml"}, nil)
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotFound)
+	files.EXPECT().Find(noContext, args.User, "octocat/drone-config", "master", "master", "drone.yml").Return(nil, resp)
+
+	service := Repository(files, central)
+	_, err := service.Find(noContext, args)
+	if err != resp {
+		t.Errorf("expect error returned from the central configuration repository")
+	}
+}
+
+func TestRepositoryNoCentral(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:   &core.User{Login: "octocat"},
+		Repo:   &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build:  &core.Build{After: "6d144de7"},
+		Config: nil,
+	}
+
+	central := mock.NewMockCentralConfigStore(controller)
+	central.EXPECT().Find(noContext, "octocat").Return(nil, sql.ErrNoRows)
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, ".drone.yml").Return(nil, scm.ErrNotFound)
+
+	service := Repository(files, central)
+	_, err := service.Find(noContext, args)
+	if err != scm.ErrNotFound {
+		t.Errorf("expect original error returned from file service")
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/signer"
+	"github.com/drone/drone/core"
+
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// KindTemplate defines the resource kind that references a
+// pipeline template stored in the database.
+const KindTemplate = "template"
+
+// Template returns a configuration service that fetches the
+// yaml file from the source configuration service and expands
+// template resources into pipelines, using the named and
+// versioned templates stored in the database for the repository
+// namespace. For example:
+//
+//	kind: template
+//	load: golang@1.0.0
+//	inputs:
+//	  image: golang:1.12
+//
+// The template data is a Go text/template that has access to
+// the inputs, the repository and the build. For protected
+// repositories the signature is verified before the templates
+// are expanded.
+func Template(source core.ConfigService, templates core.TemplateStore) core.ConfigService {
+	return &templatePlugin{
+		source:    source,
+		templates: templates,
+	}
+}
+
+type templatePlugin struct {
+	source    core.ConfigService
+	templates core.TemplateStore
+}
+
+type templateRef struct {
+	Load   string                 `yaml:"load"`
+	Inputs map[string]interface{} `yaml:"inputs"`
+}
+
+func (p *templatePlugin) Find(ctx context.Context, req *core.ConfigArgs) (*core.Config, error) {
+	config, err := p.source.Find(ctx, req)
+	if config == nil || err != nil {
+		return config, err
+	}
+
+	resources, err := yaml.ParseRawString(config.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	// if the configuration file does not reference any
+	// templates it is returned unchanged.
+	if !hasTemplate(resources) {
+		return config, nil
+	}
+
+	buf := new(bytes.Buffer)
+	for _, resource := range resources {
+		if resource.Kind != KindTemplate {
+			buf.WriteString("---\n")
+			buf.Write(resource.Data)
+			continue
+		}
+		out, err := p.expand(ctx, req, resource)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(out, "---") {
+			buf.WriteString("---\n")
+		}
+		buf.WriteString(out)
+		if !strings.HasSuffix(out, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	data := buf.String()
+
+	// the signature is verified against the raw configuration
+	// file, since the expanded templates are not signed by the
+	// author. If the raw file is verified, the expanded file is
+	// signed with the repository secret so that it is verified
+	// when the build is created.
+	if req.Repo.Protected && req.Build.Trigger == core.TriggerHook {
+		key := signer.KeyString(req.Repo.Secret)
+		if verified, _ := signer.Verify([]byte(config.Data), key); verified {
+			signed, err := signer.SignUpdate([]byte(data), key)
+			if err != nil {
+				return nil, err
+			}
+			data = string(signed)
+		}
+	}
+	config.Data = data
+	return config, nil
+}
+
+// helper function expands the template resource into one or
+// more yaml documents.
+func (p *templatePlugin) expand(ctx context.Context, req *core.ConfigArgs, resource *yaml.RawResource) (string, error) {
+	ref := new(templateRef)
+	err := yamlv2.Unmarshal(resource.Data, ref)
+	if err != nil {
+		return "", fmt.Errorf("template: %s", err)
+	}
+	if ref.Load == "" {
+		return "", fmt.Errorf("template: missing template name")
+	}
+
+	name, version := splitTemplate(ref.Load)
+	stored, err := p.templates.FindName(ctx, req.Repo.Namespace, name, version)
+	if err != nil {
+		return "", fmt.Errorf("template: cannot find template %s in namespace %s", ref.Load, req.Repo.Namespace)
+	}
+
+	tmpl, err := template.New(ref.Load).Option("missingkey=error").Parse(stored.Data)
+	if err != nil {
+		return "", fmt.Errorf("template: %s", err)
+	}
+
+	inputs := ref.Inputs
+	if inputs == nil {
+		inputs = map[string]interface{}{}
+	}
+	buf := new(bytes.Buffer)
+	err = tmpl.Execute(buf, map[string]interface{}{
+		"input": inputs,
+		"repo": map[string]interface{}{
+			"namespace": req.Repo.Namespace,
+			"name":      req.Repo.Name,
+			"slug":      req.Repo.Slug,
+			"branch":    req.Repo.Branch,
+		},
+		"build": map[string]interface{}{
+			"event":  req.Build.Event,
+			"branch": req.Build.Target,
+			"ref":    req.Build.Ref,
+			"commit": req.Build.After,
+			"target": req.Build.Deploy,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("template: %s", err)
+	}
+	return buf.String(), nil
+}
+
+// helper function returns true if the resource list includes
+// a template resource.
+func hasTemplate(resources []*yaml.RawResource) bool {
+	for _, resource := range resources {
+		if resource.Kind == KindTemplate {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function splits the template reference into the
+// template name and version. If the version is omitted the
+// latest version is used.
+func splitTemplate(s string) (name, version string) {
+	parts := strings.SplitN(s, "@", 2)
+	if len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return parts[0], ""
+}
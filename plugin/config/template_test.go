@@ -0,0 +1,226 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package config
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-yaml/yaml"
+	"github.com/drone/drone-yaml/yaml/signer"
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var mockTemplateRef = []byte(`
+kind: template
+load: golang@1.0.0
+inputs:
+  image: golang:1.12
+
+---
+kind: secret
+name: token
+get:
+  path: secret/data/github
+  name: token
+`)
+
+var mockTemplate = &core.Template{
+	Namespace: "octocat",
+	Name:      "golang",
+	Version:   "1.0.0",
+	Data: `kind: pipeline
+name: {{ .repo.name }}
+
+steps:
+- name: test
+  image: {{ .input.image }}
+  commands:
+  - go test ./...
+`,
+}
+
+func TestTemplate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:  &core.User{Login: "octocat"},
+		Repo:  &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build: &core.Build{After: "6d144de7"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(&core.File{Data: mockTemplateRef}, nil)
+
+	templates := mock.NewMockTemplateStore(controller)
+	templates.EXPECT().FindName(noContext, "octocat", "golang", "1.0.0").Return(mockTemplate, nil)
+
+	result, err := Template(Repository(files, nil), templates).Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	manifest, err := yaml.ParseString(result.Data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(manifest.Resources), 2; got != want {
+		t.Errorf("Want %d resources, got %d", want, got)
+		return
+	}
+	pipeline, ok := manifest.Resources[0].(*yaml.Pipeline)
+	if !ok {
+		t.Errorf("Want template expanded to pipeline")
+		return
+	}
+	if got, want := pipeline.Name, "hello-world"; got != want {
+		t.Errorf("Want pipeline name %q, got %q", want, got)
+	}
+	if got, want := pipeline.Steps[0].Image, "golang:1.12"; got != want {
+		t.Errorf("Want step image %q, got %q", want, got)
+	}
+}
+
+func TestTemplate_Signed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	key := signer.KeyString("IT2ZY4bNtZ3mdwOGs2vAsfUbRJtAd4Ze")
+	raw, err := signer.SignUpdate(mockTemplateRef, key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		data     []byte
+		verified bool
+	}{
+		{data: raw, verified: true},
+		{data: bytes.Replace(raw, []byte("golang:1.12"), []byte("golang:1.11"), 1), verified: false},
+	}
+	for i, test := range tests {
+		args := &core.ConfigArgs{
+			User:  &core.User{Login: "octocat"},
+			Repo:  &core.Repository{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Config: ".drone.yml", Protected: true, Secret: string(key)},
+			Build: &core.Build{After: "6d144de7", Trigger: core.TriggerHook},
+		}
+
+		files := mock.NewMockFileService(controller)
+		files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(&core.File{Data: test.data}, nil)
+
+		templates := mock.NewMockTemplateStore(controller)
+		templates.EXPECT().FindName(noContext, "octocat", "golang", "1.0.0").Return(mockTemplate, nil)
+
+		result, err := Template(Repository(files, nil), templates).Find(noContext, args)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		verified, _ := signer.Verify([]byte(result.Data), key)
+		if got, want := verified, test.verified; got != want {
+			t.Errorf("Want expanded file verified %v at index %d", want, i)
+		}
+	}
+}
+
+func TestTemplate_NoTemplate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:  &core.User{Login: "octocat"},
+		Repo:  &core.Repository{Namespace: "octocat", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build: &core.Build{After: "6d144de7"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(&core.File{Data: mockFile}, nil)
+
+	result, err := Template(Repository(files, nil), nil).Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := result.Data, string(mockFile); got != want {
+		t.Errorf("Want configuration returned unchanged")
+	}
+}
+
+func TestTemplate_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:  &core.User{Login: "octocat"},
+		Repo:  &core.Repository{Namespace: "octocat", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build: &core.Build{After: "6d144de7"},
+	}
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(&core.File{Data: mockTemplateRef}, nil)
+
+	templates := mock.NewMockTemplateStore(controller)
+	templates.EXPECT().FindName(noContext, "octocat", "golang", "1.0.0").Return(nil, sql.ErrNoRows)
+
+	_, err := Template(Repository(files, nil), templates).Find(noContext, args)
+	if err == nil || !strings.Contains(err.Error(), "golang@1.0.0") {
+		t.Errorf("Want template not found error, got %v", err)
+	}
+}
+
+func TestTemplate_MissingInput(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		User:  &core.User{Login: "octocat"},
+		Repo:  &core.Repository{Namespace: "octocat", Slug: "octocat/hello-world", Config: ".drone.yml"},
+		Build: &core.Build{After: "6d144de7"},
+	}
+
+	data := []byte("kind: template\nload: golang\n")
+
+	files := mock.NewMockFileService(controller)
+	files.EXPECT().Find(noContext, args.User, args.Repo.Slug, args.Build.After, args.Build.Ref, args.Repo.Config).Return(&core.File{Data: data}, nil)
+
+	templates := mock.NewMockTemplateStore(controller)
+	templates.EXPECT().FindName(noContext, "octocat", "golang", "").Return(mockTemplate, nil)
+
+	_, err := Template(Repository(files, nil), templates).Find(noContext, args)
+	if err == nil {
+		t.Errorf("Want error when template input is missing")
+	}
+}
+
+func TestTemplate_NoConfig(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	args := &core.ConfigArgs{
+		Repo: &core.Repository{Config: ".drone.yml"},
+	}
+
+	source := mock.NewMockConfigService(controller)
+	source.EXPECT().Find(noContext, args).Return(nil, nil)
+
+	result, err := Template(source, nil).Find(noContext, args)
+	if err != nil {
+		t.Error(err)
+	}
+	if result != nil {
+		t.Errorf("Want nil config when the source returns no config")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// chatMessage defines the chat webhook payload. The format is
+// accepted by Slack, Mattermost and Rocket.Chat incoming
+// webhooks.
+type chatMessage struct {
+	Text string `json:"text"`
+}
+
+// helper function posts the message to the chat webhook.
+func sendChat(ctx context.Context, client *http.Client, endpoint, text string) error {
+	buf := new(bytes.Buffer)
+	json.NewEncoder(buf).Encode(&chatMessage{Text: text})
+	req, err := http.NewRequest("POST", endpoint, buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
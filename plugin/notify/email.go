@@ -0,0 +1,119 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errSMTPNotConfigured = errors.New("smtp server is not configured")
+
+// smtpConfig provides the smtp server configuration.
+type smtpConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	SkipVerify bool
+}
+
+// smtpTimeout is the default time allowed to deliver the
+// message when the context has no deadline.
+const smtpTimeout = time.Minute
+
+// send sends a plain text email message to the recipient.
+// The connection is upgraded with STARTTLS if supported by
+// the server, and is closed when the context deadline is
+// exceeded.
+func (c smtpConfig) send(ctx context.Context, to, subject, body string) error {
+	if c.Host == "" {
+		return errSMTPNotConfigured
+	}
+	port := c.Port
+	if port == 0 {
+		port = 25
+	}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{
+			ServerName:         c.Host,
+			InsecureSkipVerify: c.SkipVerify,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if c.Username != "" {
+		auth := smtp.PlainAuth("", c.Username, c.Password, c.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message(c.From, to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// helper function returns the encoded email message. Line
+// breaks are removed from the subject to prevent header
+// injection.
+func message(from, to, subject, body string) []byte {
+	subject = strings.NewReplacer("\r", "", "\n", " ").Replace(subject)
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", to)
+	fmt.Fprintf(buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(buf, "Content-Type: text/plain; charset=UTF-8\r\n")
+	fmt.Fprintf(buf, "\r\n%s", body)
+	return buf.Bytes()
+}
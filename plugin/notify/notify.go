@@ -0,0 +1,167 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package notify
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// New returns a new Notifier that notifies the repository
+// and user subscriptions of build outcomes.
+func New(
+	config Config,
+	subscriptions core.SubscriptionStore,
+	builds core.BuildStore,
+	users core.UserStore,
+) core.Notifier {
+	return &notifier{
+		Subscriptions: subscriptions,
+		Builds:        builds,
+		Users:         users,
+		System:        config.System,
+		Client:        &http.Client{Timeout: time.Minute},
+		SMTP: smtpConfig{
+			Host:       config.SMTPHost,
+			Port:       config.SMTPPort,
+			Username:   config.SMTPUsername,
+			Password:   config.SMTPPassword,
+			From:       config.SMTPFrom,
+			SkipVerify: config.SMTPSkipVerify,
+		},
+	}
+}
+
+type notifier struct {
+	Subscriptions core.SubscriptionStore
+	Builds        core.BuildStore
+	Users         core.UserStore
+	System        *core.System
+	Client        *http.Client
+	SMTP          smtpConfig
+}
+
+// Notify sends the build outcome to the repository subscriptions,
+// and to the subscriptions of the user that triggered the build.
+// A target subscribed more than once is notified once.
+func (n *notifier) Notify(ctx context.Context, in *core.NotifyInput) error {
+	subscriptions, result := n.subscriptions(ctx, in)
+	if len(subscriptions) == 0 {
+		return result
+	}
+
+	prev, err := n.previous(ctx, in)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	data := newTemplateData(n.System, in.Repo, in.Build)
+
+	sent := map[string]struct{}{}
+	for _, subscription := range subscriptions {
+		if !subscription.Match(in.Build, prev) {
+			continue
+		}
+		key := subscription.Kind + ":" + subscription.Target
+		if _, ok := sent[key]; ok {
+			continue
+		}
+		sent[key] = struct{}{}
+
+		if err := n.send(ctx, subscription, data); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
+
+// subscriptions returns the repository subscriptions and the
+// subscriptions of the user that triggered the build. User
+// subscriptions the user is no longer permitted to send are
+// ignored.
+func (n *notifier) subscriptions(ctx context.Context, in *core.NotifyInput) ([]*core.Subscription, error) {
+	var result error
+	subscriptions, err := n.Subscriptions.List(ctx, in.Repo.ID)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	if in.Build.Sender == "" {
+		return subscriptions, result
+	}
+	user, err := n.Users.FindLogin(ctx, in.Build.Sender)
+	if err != nil {
+		// the build may be triggered by a user that is
+		// not registered with the system.
+		return subscriptions, result
+	}
+	personal, err := n.Subscriptions.ListUser(ctx, user.ID)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	// the user subscriptions are validated again before
+	// sending, since the user email or admin access may
+	// have changed since the subscription was created.
+	for _, subscription := range personal {
+		if subscription.ValidateUser(user) == nil {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, result
+}
+
+// previous returns the most recent completed build for the
+// same ref, used to determine if the build fixed a failure.
+func (n *notifier) previous(ctx context.Context, in *core.NotifyInput) (*core.Build, error) {
+	builds, err := n.Builds.ListRef(ctx, in.Repo.ID, in.Build.Ref, 25, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, build := range builds {
+		if build.Number >= in.Build.Number {
+			continue
+		}
+		switch build.Status {
+		case core.StatusPending,
+			core.StatusRunning,
+			core.StatusBlocked,
+			core.StatusWaiting:
+			continue
+		}
+		return build, nil
+	}
+	return nil, nil
+}
+
+// send renders and delivers the notification to the
+// subscription target.
+func (n *notifier) send(ctx context.Context, subscription *core.Subscription, data *templateData) error {
+	switch subscription.Kind {
+	case core.SubscriptionEmail:
+		subject, err := render(defaultSubject, data)
+		if err != nil {
+			return err
+		}
+		body, err := render(choose(subscription.Template, defaultEmail), data)
+		if err != nil {
+			return err
+		}
+		return n.SMTP.send(ctx, subscription.Target, subject, body)
+	case core.SubscriptionChat:
+		body, err := render(choose(subscription.Template, defaultChat), data)
+		if err != nil {
+			return err
+		}
+		return sendChat(ctx, n.Client, subscription.Target, body)
+	default:
+		return nil
+	}
+}
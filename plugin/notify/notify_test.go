@@ -0,0 +1,377 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/golang/mock/gomock"
+)
+
+var noContext = context.Background()
+
+var (
+	mockRepo = &core.Repository{
+		ID:   1,
+		Slug: "octocat/hello-world",
+	}
+	mockSystem = &core.System{
+		Link: "https://drone.company.com",
+	}
+)
+
+func TestNotify_Email(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := newSMTPServer(t)
+	defer server.Close()
+
+	build := &core.Build{
+		Number: 2,
+		Status: core.StatusFailing,
+		Ref:    "refs/heads/master",
+		Target: "master",
+		Sender: "spaceghost",
+		Author: "octocat",
+	}
+	subscriptions := []*core.Subscription{
+		{Kind: core.SubscriptionEmail, Target: "octocat@github.com", Trigger: core.NotifyFailure},
+		{Kind: core.SubscriptionEmail, Target: "fixed@github.com", Trigger: core.NotifyFixed},
+	}
+
+	store := mock.NewMockSubscriptionStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(subscriptions, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "spaceghost").Return(nil, errors.New("not found"))
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, build.Ref, gomock.Any(), 0).Return([]*core.Build{build}, nil)
+
+	host, port := server.Addr()
+	notifier := New(Config{
+		System:   mockSystem,
+		SMTPHost: host,
+		SMTPPort: port,
+		SMTPFrom: "drone@company.com",
+	}, store, builds, users)
+
+	err := notifier.Notify(noContext, &core.NotifyInput{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+	}
+
+	mail := server.Received()
+	if got, want := mail.to, "octocat@github.com"; got != want {
+		t.Errorf("Want recipient %s, got %s", want, got)
+	}
+	if got, want := mail.from, "drone@company.com"; got != want {
+		t.Errorf("Want sender %s, got %s", want, got)
+	}
+	if want := "Subject: [octocat/hello-world] Build #2 failure (master)"; !strings.Contains(mail.data, want) {
+		t.Errorf("Want message to contain %q, got %q", want, mail.data)
+	}
+	if want := "https://drone.company.com/octocat/hello-world/2"; !strings.Contains(mail.data, want) {
+		t.Errorf("Want message to contain link %q, got %q", want, mail.data)
+	}
+}
+
+// this test verifies that user subscriptions are notified when
+// the build fixes a failure, that a target subscribed more
+// than once is only notified once, and that user subscriptions
+// the user is not permitted to send are ignored.
+func TestNotify_Chat(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	var messages []*chatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in := new(chatMessage)
+		json.NewDecoder(r.Body).Decode(in)
+		messages = append(messages, in)
+	}))
+	defer server.Close()
+
+	build := &core.Build{
+		Number: 3,
+		Status: core.StatusPassing,
+		Ref:    "refs/heads/master",
+		Sender: "octocat",
+	}
+	prev := []*core.Build{
+		{Number: 4, Status: core.StatusRunning},
+		build,
+		{Number: 2, Status: core.StatusFailing},
+	}
+	repoSubscriptions := []*core.Subscription{
+		{Kind: core.SubscriptionChat, Target: server.URL, Trigger: core.NotifyAlways},
+	}
+	userSubscriptions := []*core.Subscription{
+		{Kind: core.SubscriptionChat, Target: server.URL, Trigger: core.NotifyFixed},
+		{Kind: core.SubscriptionChat, Target: server.URL + "/fixed", Trigger: core.NotifyFixed, Template: "fixed {{ .Link }}"},
+		{Kind: core.SubscriptionChat, Target: server.URL + "/failure", Trigger: core.NotifyFailure},
+		{Kind: core.SubscriptionEmail, Target: "spaceghost@github.com", Trigger: core.NotifyAlways},
+	}
+
+	store := mock.NewMockSubscriptionStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(repoSubscriptions, nil)
+	store.EXPECT().ListUser(gomock.Any(), int64(2)).Return(userSubscriptions, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(&core.User{ID: 2, Email: "octocat@github.com", Admin: true}, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, build.Ref, gomock.Any(), 0).Return(prev, nil)
+
+	notifier := New(Config{System: mockSystem}, store, builds, users)
+	err := notifier.Notify(noContext, &core.NotifyInput{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if got, want := len(messages), 2; got != want {
+		t.Errorf("Want %d chat messages, got %d", want, got)
+		return
+	}
+	if got, want := messages[1].Text, "fixed https://drone.company.com/octocat/hello-world/3"; got != want {
+		t.Errorf("Want custom template %q, got %q", want, got)
+	}
+}
+
+// this test verifies that user subscriptions are ignored
+// if the user no longer has admin access.
+func TestNotify_ChatForbidden(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	build := &core.Build{Number: 1, Status: core.StatusPassing, Sender: "octocat"}
+	userSubscriptions := []*core.Subscription{
+		{Kind: core.SubscriptionChat, Target: "http://127.0.0.1:1", Trigger: core.NotifyAlways},
+	}
+
+	store := mock.NewMockSubscriptionStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+	store.EXPECT().ListUser(gomock.Any(), int64(2)).Return(userSubscriptions, nil)
+
+	users := mock.NewMockUserStore(controller)
+	users.EXPECT().FindLogin(gomock.Any(), "octocat").Return(&core.User{ID: 2}, nil)
+
+	notifier := New(Config{System: mockSystem}, store, nil, users)
+	err := notifier.Notify(noContext, &core.NotifyInput{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNotify_ChatError(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	build := &core.Build{Number: 1, Status: core.StatusPassing}
+	subscriptions := []*core.Subscription{
+		{Kind: core.SubscriptionChat, Target: server.URL, Trigger: core.NotifyAlways},
+	}
+
+	store := mock.NewMockSubscriptionStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(subscriptions, nil)
+
+	builds := mock.NewMockBuildStore(controller)
+	builds.EXPECT().ListRef(gomock.Any(), mockRepo.ID, build.Ref, gomock.Any(), 0).Return(nil, nil)
+
+	notifier := New(Config{System: mockSystem}, store, builds, nil)
+	err := notifier.Notify(noContext, &core.NotifyInput{Repo: mockRepo, Build: build})
+	if err == nil {
+		t.Errorf("Expect error when the chat webhook returns a non-2xx status")
+	}
+}
+
+func TestNotify_NoSubscriptions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	store := mock.NewMockSubscriptionStore(controller)
+	store.EXPECT().List(gomock.Any(), mockRepo.ID).Return(nil, nil)
+
+	build := &core.Build{Number: 1, Status: core.StatusFailing}
+	notifier := New(Config{}, store, nil, nil)
+	err := notifier.Notify(noContext, &core.NotifyInput{Repo: mockRepo, Build: build})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that templates cannot render the
+// sensitive repository fields.
+func TestRender_Restricted(t *testing.T) {
+	repo := &core.Repository{
+		Slug:   "octocat/hello-world",
+		Signer: "signer",
+		Secret: "secret",
+	}
+	build := &core.Build{Number: 1}
+	data := newTemplateData(mockSystem, repo, build)
+
+	out, err := render("{{ .Repo.Slug }}#{{ .Build.Number }}", data)
+	if err != nil {
+		t.Error(err)
+	}
+	if got, want := out, "octocat/hello-world#1"; got != want {
+		t.Errorf("Want rendered template %q, got %q", want, got)
+	}
+	for _, text := range []string{"{{ .Repo.Secret }}", "{{ .Repo.Signer }}", "{{ .Build.Params }}"} {
+		if _, err := render(text, data); err == nil {
+			t.Errorf("Want error rendering %s", text)
+		}
+	}
+}
+
+func TestSend_Deadline(t *testing.T) {
+	// the listener accepts connections but never replies,
+	// simulating an unresponsive smtp server.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	config := smtpConfig{
+		Host: addr.IP.String(),
+		Port: addr.Port,
+		From: "drone@company.com",
+	}
+
+	ctx, cancel := context.WithTimeout(noContext, time.Millisecond*100)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- config.send(ctx, "octocat@github.com", "subject", "body")
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Errorf("Expect error when the deadline is exceeded")
+		}
+	case <-time.After(time.Second * 5):
+		t.Errorf("Expect send to return when the deadline is exceeded")
+	}
+}
+
+//
+// smtp server stand-in
+//
+
+type smtpMessage struct {
+	from string
+	to   string
+	data string
+}
+
+type smtpServer struct {
+	listener net.Listener
+	messages chan *smtpMessage
+}
+
+// newSMTPServer returns a minimal smtp server that accepts a
+// single message, without support for STARTTLS or AUTH.
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &smtpServer{
+		listener: listener,
+		messages: make(chan *smtpMessage, 1),
+	}
+	go server.serve()
+	return server
+}
+
+func (s *smtpServer) Addr() (string, int) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func (s *smtpServer) Close() {
+	s.listener.Close()
+}
+
+func (s *smtpServer) Received() *smtpMessage {
+	return <-s.messages
+}
+
+func (s *smtpServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+	msg := new(smtpMessage)
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(line, "MAIL FROM:"):
+			msg.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+			reply("250 OK")
+		case strings.HasPrefix(line, "RCPT TO:"):
+			msg.to = strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>")
+			reply("250 OK")
+		case line == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data []string
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data = append(data, line)
+			}
+			msg.data = strings.Join(data, "")
+			reply("250 OK")
+		case line == "QUIT":
+			reply("221 Bye")
+			s.messages <- msg
+			return
+		default:
+			reply("500 Unknown command")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/drone/drone/core"
+)
+
+// default email subject template.
+const defaultSubject = `[{{ .Repo.Slug }}] Build #{{ .Build.Number }} {{ .Build.Status }} ({{ .Build.Target }})`
+
+// default email body template.
+const defaultEmail = `Build #{{ .Build.Number }} of {{ .Repo.Slug }} finished with status {{ .Build.Status }}.
+
+Branch:  {{ .Build.Target }}
+Commit:  {{ .Build.After }}
+Author:  {{ .Build.Author }}
+Message: {{ .Build.Message }}
+
+{{ .Link }}
+`
+
+// default chat message template.
+const defaultChat = `{{ .Repo.Slug }} build #{{ .Build.Number }} {{ .Build.Status }} on {{ .Build.Target }} by {{ .Build.Author }}: {{ .Link }}`
+
+// templateData provides the data used to render the
+// notification templates. Templates are user-defined, and
+// therefore render a restricted view of the repository and
+// build that excludes internal and sensitive fields, such as
+// the repository signing key and webhook secret.
+type templateData struct {
+	System *core.System
+	Repo   *templateRepo
+	Build  *templateBuild
+	Link   string
+}
+
+// templateRepo provides the public repository fields used
+// to render the notification templates.
+type templateRepo struct {
+	Namespace string
+	Name      string
+	Slug      string
+	Link      string
+	Branch    string
+	Private   bool
+}
+
+// templateBuild provides the public build fields used to
+// render the notification templates.
+type templateBuild struct {
+	Number       int64
+	Status       string
+	Event        string
+	Link         string
+	Title        string
+	Message      string
+	Before       string
+	After        string
+	Ref          string
+	Source       string
+	Target       string
+	Author       string
+	AuthorName   string
+	AuthorAvatar string
+	Sender       string
+	Deploy       string
+	Started      int64
+	Finished     int64
+}
+
+// helper function returns the template data for the
+// repository and build.
+func newTemplateData(system *core.System, repo *core.Repository, build *core.Build) *templateData {
+	return &templateData{
+		System: system,
+		Repo: &templateRepo{
+			Namespace: repo.Namespace,
+			Name:      repo.Name,
+			Slug:      repo.Slug,
+			Link:      repo.Link,
+			Branch:    repo.Branch,
+			Private:   repo.Private,
+		},
+		Build: &templateBuild{
+			Number:       build.Number,
+			Status:       build.Status,
+			Event:        build.Event,
+			Link:         build.Link,
+			Title:        build.Title,
+			Message:      build.Message,
+			Before:       build.Before,
+			After:        build.After,
+			Ref:          build.Ref,
+			Source:       build.Source,
+			Target:       build.Target,
+			Author:       build.Author,
+			AuthorName:   build.AuthorName,
+			AuthorAvatar: build.AuthorAvatar,
+			Sender:       build.Sender,
+			Deploy:       build.Deploy,
+			Started:      build.Started,
+			Finished:     build.Finished,
+		},
+		Link: link(system, repo, build),
+	}
+}
+
+// helper function renders the template.
+func render(text string, data *templateData) (string, error) {
+	t, err := template.New("_").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// helper function returns the custom template, falling back
+// to the default template if empty.
+func choose(custom, fallback string) string {
+	if custom != "" {
+		return custom
+	}
+	return fallback
+}
+
+// helper function returns the build link.
+func link(system *core.System, repo *core.Repository, build *core.Build) string {
+	if system == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%d", strings.TrimSuffix(system.Link, "/"), repo.Slug, build.Number)
+}
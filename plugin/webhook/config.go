@@ -14,11 +14,20 @@
 
 package webhook
 
-import "github.com/drone/drone/core"
+import (
+	"time"
+
+	"github.com/drone/drone/core"
+)
 
 // Config provides the webhook configuration.
 type Config struct {
-	Endpoint []string
-	Secret   string
-	System   *core.System
+	Endpoint   []string
+	Events     []string
+	Secret     string
+	SkipVerify bool
+	System     *core.System
+	Attempts   int
+	Backoff    time.Duration
+	Retention  time.Duration
 }
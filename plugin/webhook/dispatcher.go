@@ -0,0 +1,334 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/drone/drone/core"
+
+	"github.com/99designs/httpsignatures-go"
+	"github.com/sirupsen/logrus"
+)
+
+// default retry configuration.
+const (
+	defaultAttempts = 5
+	defaultBackoff  = 30 * time.Second
+	maxBackoff      = time.Hour
+)
+
+// claimTimeout is the duration a delivery is reserved by the
+// server before it can be claimed by another server. It must
+// exceed the time allowed to deliver the webhook.
+const claimTimeout = 5 * time.Minute
+
+// purgeInterval is the interval at which completed deliveries
+// older than the retention period are purged.
+const purgeInterval = time.Hour
+
+// required http headers
+var headers = []string{
+	"date",
+	"digest",
+}
+
+var signer = httpsignatures.NewSigner(
+	httpsignatures.AlgorithmHmacSha256,
+	headers...,
+)
+
+// insecure http client used to deliver webhooks to endpoints
+// with ssl verification disabled.
+var insecureClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	},
+}
+
+// NewDispatcher returns a new webhook Dispatcher.
+func NewDispatcher(config Config, deliveries core.WebhookDeliveryStore, webhooks core.WebhookStore) *Dispatcher {
+	return &Dispatcher{
+		Deliveries: deliveries,
+		Webhooks:   webhooks,
+		Secret:     config.Secret,
+		SkipVerify: config.SkipVerify,
+		Attempts:   config.Attempts,
+		Backoff:    config.Backoff,
+		Retention:  config.Retention,
+	}
+}
+
+// Dispatcher delivers queued webhooks to their endpoints,
+// retrying failed deliveries with exponential backoff.
+// Global webhooks are signed with the global secret, and
+// repository and namespace webhooks are signed with the
+// webhook secret. Each delivery is claimed before it is sent
+// to prevent duplicate delivery when multiple servers share
+// the datastore.
+type Dispatcher struct {
+	Client     *http.Client
+	Deliveries core.WebhookDeliveryStore
+	Webhooks   core.WebhookStore
+	Secret     string
+	SkipVerify bool
+	Attempts   int
+	Backoff    time.Duration
+	Retention  time.Duration
+
+	mu     sync.Mutex
+	wg     sync.WaitGroup
+	active map[string]struct{}
+	purged time.Time
+}
+
+// Start starts the dispatcher and delivers pending webhooks
+// at the given interval until the context is canceled.
+func (d *Dispatcher) Start(ctx context.Context, dur time.Duration) error {
+	for {
+		select {
+		case <-ctx.Done():
+			d.wg.Wait()
+			return ctx.Err()
+		case <-time.After(dur):
+			d.run(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context) error {
+	d.purge(ctx)
+
+	deliveries, err := d.Deliveries.Pending(ctx, time.Now().Unix())
+	if err != nil {
+		logger := logrus.WithError(err)
+		logger.Errorln("webhook: cannot list pending deliveries")
+		return err
+	}
+
+	// deliveries are grouped by endpoint and each endpoint is
+	// processed independently, so that a slow or unavailable
+	// endpoint does not delay delivery to other endpoints.
+	var endpoints []string
+	groups := map[string][]*core.WebhookDelivery{}
+	for _, delivery := range deliveries {
+		if _, ok := groups[delivery.Endpoint]; !ok {
+			endpoints = append(endpoints, delivery.Endpoint)
+		}
+		groups[delivery.Endpoint] = append(groups[delivery.Endpoint], delivery)
+	}
+
+	for _, endpoint := range endpoints {
+		// skip endpoints with deliveries still in progress
+		// from a previous run.
+		if !d.acquire(endpoint) {
+			continue
+		}
+		d.wg.Add(1)
+		go func(endpoint string, deliveries []*core.WebhookDelivery) {
+			defer d.wg.Done()
+			defer d.release(endpoint)
+			for _, delivery := range deliveries {
+				if d.claim(ctx, delivery) {
+					d.deliver(ctx, delivery)
+				}
+			}
+		}(endpoint, groups[endpoint])
+	}
+	return nil
+}
+
+// claim reserves the delivery, returning false if the delivery
+// is claimed by another server or cannot be claimed.
+func (d *Dispatcher) claim(ctx context.Context, delivery *core.WebhookDelivery) bool {
+	until := time.Now().Add(claimTimeout).Unix()
+	ok, err := d.Deliveries.Claim(ctx, delivery, until)
+	if err != nil {
+		logrus.WithError(err).
+			WithField("delivery", delivery.ID).
+			Warnln("webhook: cannot claim delivery")
+		return false
+	}
+	return ok
+}
+
+// purge deletes completed deliveries older than the retention
+// period. Deliveries are retained indefinitely if the retention
+// period is not configured.
+func (d *Dispatcher) purge(ctx context.Context) {
+	if d.Retention <= 0 || time.Since(d.purged) < purgeInterval {
+		return
+	}
+	d.purged = time.Now()
+	before := time.Now().Add(-d.Retention).Unix()
+	err := d.Deliveries.Purge(ctx, before)
+	if err != nil {
+		logrus.WithError(err).
+			Warnln("webhook: cannot purge deliveries")
+	}
+}
+
+// deliver attempts to deliver the webhook and records the
+// result of the attempt.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *core.WebhookDelivery) error {
+	start := time.Now()
+	code, err := d.send(ctx, delivery)
+
+	delivery.Attempts++
+	delivery.Code = code
+	delivery.Latency = int64(time.Since(start) / time.Millisecond)
+	delivery.Updated = time.Now().Unix()
+	delivery.Error = ""
+	if err == nil && (code < 200 || code > 299) {
+		err = fmt.Errorf("unexpected status code %d", code)
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = core.WebhookDeliverySuccess
+		delivery.Next = 0
+	case delivery.Attempts >= d.attempts():
+		delivery.Status = core.WebhookDeliveryFailure
+		delivery.Error = err.Error()
+		delivery.Next = 0
+	default:
+		delivery.Error = err.Error()
+		delivery.Next = time.Now().Add(d.backoff(delivery.Attempts)).Unix()
+	}
+
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"delivery": delivery.ID,
+			"endpoint": delivery.Endpoint,
+			"attempts": delivery.Attempts,
+			"status":   delivery.Status,
+		},
+	)
+	if err != nil {
+		logger.WithError(err).Debugln("webhook: delivery failed")
+	} else {
+		logger.Debugln("webhook: delivery complete")
+	}
+
+	err = d.Deliveries.Update(ctx, delivery)
+	if err != nil {
+		logger.WithError(err).Warnln("webhook: cannot update delivery")
+	}
+	return err
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *core.WebhookDelivery) (int, error) {
+	secret, skipverify := d.Secret, d.SkipVerify
+	if delivery.Webhook != 0 {
+		webhook, err := d.Webhooks.Find(ctx, delivery.Webhook)
+		if err != nil {
+			return 0, fmt.Errorf("cannot find webhook %d: %s", delivery.Webhook, err)
+		}
+		secret, skipverify = webhook.Secret, webhook.SkipVerify
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	data := []byte(delivery.Payload)
+	buf := bytes.NewBuffer(data)
+	req, err := http.NewRequest("POST", delivery.Endpoint, buf)
+	if err != nil {
+		return 0, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Add("X-Drone-Event", delivery.Event)
+	req.Header.Add("X-Drone-Delivery", strconv.FormatInt(delivery.ID, 10))
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Digest", "SHA-256="+digest(data))
+	req.Header.Add("Date", time.Now().UTC().Format(http.TimeFormat))
+	err = signer.SignRequest("hmac-key", secret, req)
+	if err != nil {
+		return 0, err
+	}
+	res, err := d.client(skipverify).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	return res.StatusCode, nil
+}
+
+// acquire marks the endpoint as active, returning false if
+// the endpoint is already active.
+func (d *Dispatcher) acquire(endpoint string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.active == nil {
+		d.active = map[string]struct{}{}
+	}
+	if _, ok := d.active[endpoint]; ok {
+		return false
+	}
+	d.active[endpoint] = struct{}{}
+	return true
+}
+
+// release marks the endpoint as inactive.
+func (d *Dispatcher) release(endpoint string) {
+	d.mu.Lock()
+	delete(d.active, endpoint)
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) attempts() int {
+	if d.Attempts < 1 {
+		return defaultAttempts
+	}
+	return d.Attempts
+}
+
+// backoff returns the exponential backoff duration before
+// the next delivery attempt.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	backoff := d.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff = backoff * 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func (d *Dispatcher) client(skipverify bool) *http.Client {
+	switch {
+	case d.Client != nil:
+		return d.Client
+	case skipverify:
+		return insecureClient
+	default:
+		return http.DefaultClient
+	}
+}
+
+func digest(data []byte) string {
+	h := sha256.New()
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
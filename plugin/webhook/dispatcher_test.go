@@ -0,0 +1,319 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+
+	"github.com/99designs/httpsignatures-go"
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestDispatcher(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventUser,
+		Action: core.WebhookActionCreated,
+		User:   &core.User{Login: "octocat"},
+	}
+
+	matchSignature := func(r *http.Request, _ *gock.Request) (bool, error) {
+		signature, err := httpsignatures.FromRequest(r)
+		if err != nil {
+			return false, err
+		}
+		return signature.IsValid("GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im", r), nil
+	}
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		SetMatcher(gock.NewMatcher()).
+		AddMatcher(matchSignature).
+		MatchHeader("X-Drone-Event", "user").
+		MatchHeader("X-Drone-Delivery", "1").
+		MatchHeader("Content-Type", "application/json").
+		MatchHeader("Digest", "SHA-256=Rro8edtwJUjLl6e\\/xB9m1ykaymiaC9YxFGb\\/XNuXzO4=").
+		JSON(webhook).
+		Reply(200).
+		Type("application/json")
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Action:   core.WebhookActionCreated,
+		Payload:  `{"action":"created","user":{"id":0,"login":"octocat","email":"","machine":false,"admin":false,"active":false,"avatar":"","syncing":false,"synced":0,"created":0,"updated":0,"last_login":0}}`,
+		Status:   core.WebhookDeliveryPending,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Pending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, gomock.Any()).Return(true, nil)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+
+	config := Config{
+		Secret: "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+	}
+	dispatcher := NewDispatcher(config, deliveries, nil)
+	err := dispatcher.run(noContext)
+	if err != nil {
+		t.Error(err)
+	}
+	dispatcher.wg.Wait()
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+	if got, want := delivery.Status, core.WebhookDeliverySuccess; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+	if got, want := delivery.Code, 200; got != want {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := delivery.Attempts, 1; got != want {
+		t.Errorf("Want attempts %d, got %d", want, got)
+	}
+	if got, want := delivery.Next, int64(0); got != want {
+		t.Errorf("Want next attempt %d, got %d", want, got)
+	}
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(500)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Payload:  `{}`,
+		Status:   core.WebhookDeliveryPending,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+
+	dispatcher := NewDispatcher(Config{Backoff: time.Minute}, deliveries, nil)
+	before := time.Now().Unix()
+	dispatcher.deliver(noContext, delivery)
+
+	if got, want := delivery.Status, core.WebhookDeliveryPending; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+	if got, want := delivery.Code, 500; got != want {
+		t.Errorf("Want response code %d, got %d", want, got)
+	}
+	if got, want := delivery.Error, "unexpected status code 500"; got != want {
+		t.Errorf("Want error %q, got %q", want, got)
+	}
+	if delivery.Next < before+60 {
+		t.Errorf("Want next attempt scheduled after backoff")
+	}
+}
+
+func TestDispatcher_Failure(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		Reply(500)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventUser,
+		Payload:  `{}`,
+		Status:   core.WebhookDeliveryPending,
+		Attempts: 2,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+
+	dispatcher := NewDispatcher(Config{Attempts: 3}, deliveries, nil)
+	dispatcher.deliver(noContext, delivery)
+
+	if got, want := delivery.Status, core.WebhookDeliveryFailure; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+	if got, want := delivery.Attempts, 3; got != want {
+		t.Errorf("Want attempts %d, got %d", want, got)
+	}
+	if got, want := delivery.Next, int64(0); got != want {
+		t.Errorf("Want next attempt %d, got %d", want, got)
+	}
+}
+
+// this test verifies that deliveries are skipped for an
+// endpoint with deliveries already in progress.
+func TestDispatcher_Active(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Status:   core.WebhookDeliveryPending,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Pending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+
+	dispatcher := NewDispatcher(Config{}, deliveries, nil)
+	dispatcher.acquire("https://company.com/hooks")
+	dispatcher.run(noContext)
+	dispatcher.wg.Wait()
+
+	if got, want := delivery.Attempts, 0; got != want {
+		t.Errorf("Want delivery skipped")
+	}
+}
+
+// this test verifies that a delivery claimed by another
+// server is skipped.
+func TestDispatcher_Claimed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Status:   core.WebhookDeliveryPending,
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Pending(gomock.Any(), gomock.Any()).Return([]*core.WebhookDelivery{delivery}, nil)
+	deliveries.EXPECT().Claim(gomock.Any(), delivery, gomock.Any()).Return(false, nil)
+
+	dispatcher := NewDispatcher(Config{}, deliveries, nil)
+	dispatcher.run(noContext)
+	dispatcher.wg.Wait()
+
+	if got, want := delivery.Attempts, 0; got != want {
+		t.Errorf("Want delivery skipped")
+	}
+}
+
+// this test verifies that completed deliveries are purged
+// once per purge interval.
+func TestDispatcher_Purge(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+	deliveries.EXPECT().Pending(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+	dispatcher := NewDispatcher(Config{Retention: time.Hour}, deliveries, nil)
+	dispatcher.run(noContext)
+	dispatcher.run(noContext)
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	dispatcher := &Dispatcher{Backoff: time.Minute}
+	tests := []struct {
+		attempts int
+		backoff  time.Duration
+	}{
+		{1, time.Minute},
+		{2, time.Minute * 2},
+		{3, time.Minute * 4},
+		{10, time.Hour},
+	}
+	for _, test := range tests {
+		if got, want := dispatcher.backoff(test.attempts), test.backoff; got != want {
+			t.Errorf("Want backoff %v for attempt %d, got %v", want, test.attempts, got)
+		}
+	}
+}
+
+func TestDispatcher_Client(t *testing.T) {
+	dispatcher := new(Dispatcher)
+	if dispatcher.client(false) != http.DefaultClient {
+		t.Errorf("Expect default http client")
+	}
+	if dispatcher.client(true) != insecureClient {
+		t.Errorf("Expect insecure http client")
+	}
+
+	custom := &http.Client{}
+	dispatcher.Client = custom
+	if dispatcher.client(false) != custom {
+		t.Errorf("Expect custom http client")
+	}
+}
+
+// this test verifies that repository and namespace webhooks
+// are signed with the webhook secret.
+func TestDispatcher_Webhook(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	matchSignature := func(r *http.Request, _ *gock.Request) (bool, error) {
+		signature, err := httpsignatures.FromRequest(r)
+		if err != nil {
+			return false, err
+		}
+		return signature.IsValid("correct-horse-battery-staple", r), nil
+	}
+
+	gock.New("https://company.com").
+		Post("/hooks").
+		SetMatcher(gock.NewMatcher()).
+		AddMatcher(matchSignature).
+		Reply(200)
+
+	delivery := &core.WebhookDelivery{
+		ID:       1,
+		Endpoint: "https://company.com/hooks",
+		Event:    core.WebhookEventBuild,
+		Payload:  `{}`,
+		Status:   core.WebhookDeliveryPending,
+		Webhook:  2,
+	}
+	webhook := &core.Webhook{
+		ID:       2,
+		Endpoint: "https://company.com/hooks",
+		Secret:   "correct-horse-battery-staple",
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().Find(gomock.Any(), webhook.ID).Return(webhook, nil)
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Update(gomock.Any(), delivery).Return(nil)
+
+	config := Config{
+		Secret: "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+	}
+	dispatcher := NewDispatcher(config, deliveries, webhooks)
+	dispatcher.deliver(noContext, delivery)
+
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+	if got, want := delivery.Status, core.WebhookDeliverySuccess; got != want {
+		t.Errorf("Want status %s, got %s", want, got)
+	}
+}
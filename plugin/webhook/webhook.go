@@ -7,36 +7,24 @@
 package webhook
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/base64"
 	"encoding/json"
-	"net/http"
 	"time"
 
 	"github.com/drone/drone/core"
 
-	"github.com/99designs/httpsignatures-go"
+	"github.com/hashicorp/go-multierror"
 )
 
-// required http headers
-var headers = []string{
-	"date",
-	"digest",
-}
-
-var signer = httpsignatures.NewSigner(
-	httpsignatures.AlgorithmHmacSha256,
-	headers...,
-)
-
-// New returns a new Webhook sender.
-func New(config Config) core.WebhookSender {
+// New returns a new Webhook sender that queues webhooks for
+// asynchronous delivery.
+func New(config Config, deliveries core.WebhookDeliveryStore, webhooks core.WebhookStore) core.WebhookSender {
 	return &sender{
-		Endpoints: config.Endpoint,
-		Secret:    config.Secret,
-		System:    config.System,
+		Deliveries: deliveries,
+		Webhooks:   webhooks,
+		Endpoints:  config.Endpoint,
+		Events:     config.Events,
+		System:     config.System,
 	}
 }
 
@@ -45,66 +33,97 @@ type payload struct {
 	System *core.System `json:"system,omitempty"`
 }
 
+// target defines a webhook delivery target.
+type target struct {
+	endpoint string
+	webhook  int64
+}
+
 type sender struct {
-	Client    *http.Client
-	Endpoints []string
-	Secret    string
-	System    *core.System
+	Deliveries core.WebhookDeliveryStore
+	Webhooks   core.WebhookStore
+	Endpoints  []string
+	Events     []string
+	System     *core.System
 }
 
-// Send sends the JSON encoded webhook to the global
-// HTTP endpoints.
+// Send queues the JSON encoded webhook for delivery to the
+// global HTTP endpoints, and to the repository and namespace
+// webhooks subscribed to the event. Delivery is handled
+// asynchronously by the Dispatcher.
 func (s *sender) Send(ctx context.Context, in *core.WebhookData) error {
-	if len(s.Endpoints) == 0 {
-		return nil
+	targets, result := s.targets(ctx, in)
+	if len(targets) == 0 {
+		return result
 	}
 	wrapper := payload{
 		WebhookData: in,
 		System:      s.System,
 	}
 	data, _ := json.Marshal(wrapper)
-	for _, endpoint := range s.Endpoints {
-		err := s.send(endpoint, s.Secret, in.Event, data)
+	now := time.Now().Unix()
+
+	for _, target := range targets {
+		err := s.Deliveries.Create(ctx, &core.WebhookDelivery{
+			Endpoint: target.endpoint,
+			Event:    in.Event,
+			Action:   in.Action,
+			Payload:  string(data),
+			Status:   core.WebhookDeliveryPending,
+			Next:     now,
+			Webhook:  target.webhook,
+			Created:  now,
+			Updated:  now,
+		})
 		if err != nil {
-			return err
+			result = multierror.Append(result, err)
 		}
 	}
-	return nil
+	return result
 }
 
-func (s *sender) send(endpoint, secret, event string, data []byte) error {
-	ctx := context.Background()
-	ctx, cancel := context.WithTimeout(ctx, time.Minute)
-	defer cancel()
+// Subscribed returns true if the global endpoints, or the
+// repository and namespace webhooks, are subscribed to the
+// event.
+func (s *sender) Subscribed(ctx context.Context, in *core.WebhookData) (bool, error) {
+	targets, err := s.targets(ctx, in)
+	return len(targets) != 0, err
+}
 
-	buf := bytes.NewBuffer(data)
-	req, err := http.NewRequest("POST", endpoint, buf)
-	if err != nil {
-		return err
+// targets returns the global endpoints, and the repository
+// and namespace webhooks subscribed to the event. The global
+// endpoints are filtered using the global event filter.
+func (s *sender) targets(ctx context.Context, in *core.WebhookData) ([]target, error) {
+	var targets []target
+	global := &core.Webhook{Events: s.Events}
+	if global.Match(in.Event, in.Action) {
+		for _, endpoint := range s.Endpoints {
+			targets = append(targets, target{endpoint: endpoint})
+		}
 	}
 
-	req = req.WithContext(ctx)
-	req.Header.Add("X-Drone-Event", event)
-	req.Header.Add("Content-Type", "application/json")
-	req.Header.Add("Digest", "SHA-256="+digest(data))
-	req.Header.Add("Date", time.Now().UTC().Format(http.TimeFormat))
-	err = signer.SignRequest("hmac-key", s.Secret, req)
-	res, err := s.client().Do(req)
-	if res != nil {
-		res.Body.Close()
+	// user events are not associated with a repository and
+	// are only sent to the global endpoints.
+	if in.Repo == nil || s.Webhooks == nil {
+		return targets, nil
 	}
-	return err
-}
 
-func (s *sender) client() *http.Client {
-	if s.Client == nil {
-		return http.DefaultClient
+	var result error
+	webhooks, err := s.Webhooks.List(ctx, in.Repo.ID)
+	if err != nil {
+		result = multierror.Append(result, err)
 	}
-	return s.Client
-}
-
-func digest(data []byte) string {
-	h := sha256.New()
-	h.Write(data)
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	namespaced, err := s.Webhooks.ListNamespace(ctx, in.Repo.Namespace)
+	if err != nil {
+		result = multierror.Append(result, err)
+	}
+	for _, webhook := range append(webhooks, namespaced...) {
+		if webhook.Match(in.Event, in.Action) {
+			targets = append(targets, target{
+				endpoint: webhook.Endpoint,
+				webhook:  webhook.ID,
+			})
+		}
+	}
+	return targets, result
 }
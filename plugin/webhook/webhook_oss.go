@@ -18,17 +18,35 @@ package webhook
 
 import (
 	"context"
+	"time"
 
 	"github.com/drone/drone/core"
 )
 
 // New returns a no-op Webhook sender.
-func New(Config) core.WebhookSender {
+func New(Config, core.WebhookDeliveryStore, core.WebhookStore) core.WebhookSender {
 	return new(noop)
 }
 
+// NewDispatcher returns a no-op webhook Dispatcher.
+func NewDispatcher(Config, core.WebhookDeliveryStore, core.WebhookStore) *Dispatcher {
+	return new(Dispatcher)
+}
+
+// Dispatcher is a no-op webhook dispatcher.
+type Dispatcher struct{}
+
+// Start is a no-op.
+func (Dispatcher) Start(context.Context, time.Duration) error {
+	return nil
+}
+
 type noop struct{}
 
 func (noop) Send(context.Context, *core.WebhookData) error {
 	return nil
 }
+
+func (noop) Subscribed(context.Context, *core.WebhookData) (bool, error) {
+	return false, nil
+}
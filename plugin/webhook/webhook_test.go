@@ -8,19 +8,21 @@ package webhook
 
 import (
 	"context"
-	"net/http"
+	"errors"
 	"testing"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
 
-	"github.com/99designs/httpsignatures-go"
-	"github.com/h2non/gock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 )
 
 var noContext = context.Background()
 
 func TestWebhook(t *testing.T) {
-	defer gock.Off()
+	controller := gomock.NewController(t)
+	defer controller.Finish()
 
 	webhook := &core.WebhookData{
 		Event:  core.WebhookEventUser,
@@ -28,53 +30,194 @@ func TestWebhook(t *testing.T) {
 		User:   &core.User{Login: "octocat"},
 	}
 
-	matchSignature := func(r *http.Request, _ *gock.Request) (bool, error) {
-		signature, err := httpsignatures.FromRequest(r)
-		if err != nil {
-			return false, err
+	checkDelivery := func(_ context.Context, delivery *core.WebhookDelivery) error {
+		if got, want := delivery.Endpoint, "https://company.com/hooks"; got != want {
+			t.Errorf("Want endpoint %s, got %s", want, got)
 		}
-		return signature.IsValid("GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im", r), nil
+		if got, want := delivery.Event, core.WebhookEventUser; got != want {
+			t.Errorf("Want event %s, got %s", want, got)
+		}
+		if got, want := delivery.Action, core.WebhookActionCreated; got != want {
+			t.Errorf("Want action %s, got %s", want, got)
+		}
+		if got, want := delivery.Status, core.WebhookDeliveryPending; got != want {
+			t.Errorf("Want status %s, got %s", want, got)
+		}
+		if got, want := delivery.Payload, `{"action":"created","user":{"id":0,"login":"octocat","email":"","machine":false,"admin":false,"active":false,"avatar":"","syncing":false,"synced":0,"created":0,"updated":0,"last_login":0}}`; got != want {
+			t.Errorf("Want payload %s, got %s", want, got)
+		}
+		if delivery.Next == 0 {
+			t.Errorf("Want delivery scheduled")
+		}
+		return nil
 	}
 
-	gock.New("https://company.com").
-		Post("/hooks").
-		AddMatcher(matchSignature).
-		MatchHeader("X-Drone-Event", "user").
-		MatchHeader("Content-Type", "application/json").
-		MatchHeader("Digest", "SHA-256=Rro8edtwJUjLl6e\\/xB9m1ykaymiaC9YxFGb\\/XNuXzO4=").
-		JSON(webhook).
-		Reply(200).
-		Type("application/json")
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(checkDelivery)
 
 	config := Config{
 		Endpoint: []string{"https://company.com/hooks"},
 		Secret:   "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
 	}
-	sender := New(config)
+	sender := New(config, deliveries, nil)
 	err := sender.Send(noContext, webhook)
 	if err != nil {
 		t.Error(err)
 	}
+}
+
+// this test verifies that a failure to queue the webhook for
+// one endpoint does not prevent queueing for other endpoints.
+func TestWebhook_MultipleEndpoints(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventUser,
+		Action: core.WebhookActionCreated,
+		User:   &core.User{Login: "octocat"},
+	}
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("oops"))
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
 
-	if gock.IsPending() {
-		t.Errorf("Unfinished requests")
+	config := Config{
+		Endpoint: []string{
+			"https://company.com/hooks",
+			"https://example.com/hooks",
+		},
+	}
+	sender := New(config, deliveries, nil)
+	err := sender.Send(noContext, webhook)
+	if err == nil {
+		t.Errorf("Expect error queueing webhook")
 	}
 }
 
-func TestWebhook_CustomClient(t *testing.T) {
-	sender := new(sender)
-	if sender.client() != http.DefaultClient {
-		t.Errorf("Expect default http client")
+// this test verifies that build events are queued for the
+// repository and namespace webhooks subscribed to the event.
+func TestWebhook_Subscriptions(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionCreated,
+		Repo:   &core.Repository{ID: 1, Namespace: "octocat"},
+		Build:  &core.Build{Number: 1},
 	}
 
-	custom := &http.Client{}
-	sender.Client = custom
-	if sender.client() != custom {
-		t.Errorf("Expect custom http client")
+	repoHooks := []*core.Webhook{
+		{ID: 1, Endpoint: "https://company.com/hooks", Events: []string{"build:created"}},
+		{ID: 2, Endpoint: "https://company.com/repo", Events: []string{"repo"}},
+	}
+	namespaceHooks := []*core.Webhook{
+		{ID: 3, Endpoint: "https://example.com/hooks"},
+		{ID: 4, Endpoint: "https://example.com/disabled", Disabled: true},
+	}
+
+	var got []int64
+	recordDelivery := func(_ context.Context, delivery *core.WebhookDelivery) error {
+		got = append(got, delivery.Webhook)
+		return nil
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().List(gomock.Any(), int64(1)).Return(repoHooks, nil)
+	webhooks.EXPECT().ListNamespace(gomock.Any(), "octocat").Return(namespaceHooks, nil)
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(recordDelivery).Times(3)
+
+	config := Config{
+		Endpoint: []string{"https://company.com/global"},
+	}
+	sender := New(config, deliveries, webhooks)
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if diff := cmp.Diff(got, []int64{0, 1, 3}); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that step events are only queued for
+// the global endpoints and webhooks explicitly subscribed to
+// step events.
+func TestWebhook_StepEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventStep,
+		Action: core.WebhookActionStarted,
+		Repo:   &core.Repository{ID: 1, Namespace: "octocat"},
+		Build:  &core.Build{Number: 1},
+		Stage:  &core.Stage{Number: 1},
+		Step:   &core.Step{Number: 2},
+	}
+
+	repoHooks := []*core.Webhook{
+		{ID: 1, Endpoint: "https://company.com/hooks"},
+		{ID: 2, Endpoint: "https://company.com/steps", Events: []string{"step"}},
+	}
+
+	var got []string
+	recordDelivery := func(_ context.Context, delivery *core.WebhookDelivery) error {
+		got = append(got, delivery.Endpoint)
+		return nil
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().List(gomock.Any(), int64(1)).Return(repoHooks, nil)
+	webhooks.EXPECT().ListNamespace(gomock.Any(), "octocat").Return(nil, nil)
+
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+	deliveries.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(recordDelivery).Times(2)
+
+	config := Config{
+		Endpoint: []string{"https://company.com/global"},
+		Events:   []string{"build", "step:started"},
+	}
+	sender := New(config, deliveries, webhooks)
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	want := []string{"https://company.com/global", "https://company.com/steps"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that user events are only queued for
+// the global endpoints.
+func TestWebhook_UserEvent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventUser,
+		Action: core.WebhookActionCreated,
+		User:   &core.User{Login: "octocat"},
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	deliveries := mock.NewMockWebhookDeliveryStore(controller)
+
+	sender := New(Config{}, deliveries, webhooks)
+	err := sender.Send(noContext, webhook)
+	if err != nil {
+		t.Error(err)
 	}
 }
 
 func TestWebhook_NoEndpoints(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
 	webhook := &core.WebhookData{
 		Event:  core.WebhookEventUser,
 		Action: core.WebhookActionCreated,
@@ -85,9 +228,47 @@ func TestWebhook_NoEndpoints(t *testing.T) {
 		Endpoint: []string{},
 		Secret:   "correct-horse-battery-staple",
 	}
-	sender := New(config)
+	sender := New(config, mock.NewMockWebhookDeliveryStore(controller), nil)
 	err := sender.Send(noContext, webhook)
 	if err != nil {
 		t.Error(err)
 	}
 }
+
+func TestWebhook_Subscribed(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	webhook := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionUpdated,
+		Repo:   &core.Repository{ID: 1, Namespace: "octocat"},
+		Build:  &core.Build{Number: 1},
+	}
+
+	repoHooks := []*core.Webhook{
+		{ID: 1, Endpoint: "https://company.com/hooks", Events: []string{"build:created"}},
+	}
+
+	webhooks := mock.NewMockWebhookStore(controller)
+	webhooks.EXPECT().List(gomock.Any(), int64(1)).Return(repoHooks, nil).Times(2)
+	webhooks.EXPECT().ListNamespace(gomock.Any(), "octocat").Return(nil, nil).Times(2)
+
+	sender := New(Config{}, nil, webhooks)
+	subscribed, err := sender.Subscribed(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if subscribed {
+		t.Errorf("Want event not subscribed")
+	}
+
+	repoHooks[0].Events = []string{"build"}
+	subscribed, err = sender.Subscribed(noContext, webhook)
+	if err != nil {
+		t.Error(err)
+	}
+	if !subscribed {
+		t.Errorf("Want event subscribed")
+	}
+}
@@ -201,6 +201,14 @@ func (s *kubeScheduler) Stats(_ context.Context) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *kubeScheduler) Drain(context.Context, string) error {
+	return errors.New("not implemented")
+}
+
+func (s *kubeScheduler) Drained(context.Context, string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
 func (s *kubeScheduler) Pause(context.Context) error {
 	return errors.New("not implemented")
 }
@@ -206,6 +206,14 @@ func (s *nomadScheduler) Stats(context.Context) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (s *nomadScheduler) Drain(context.Context, string) error {
+	return errors.New("not implemented")
+}
+
+func (s *nomadScheduler) Drained(context.Context, string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
 func (s *nomadScheduler) Pause(context.Context) error {
 	return errors.New("not implemented")
 }
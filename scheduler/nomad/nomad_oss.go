@@ -49,6 +49,14 @@ func (noop) Stats(context.Context) (interface{}, error) {
 	return nil, nil
 }
 
+func (noop) Drain(context.Context, string) error {
+	return nil
+}
+
+func (noop) Drained(context.Context, string) (bool, error) {
+	return false, nil
+}
+
 func (noop) Pause(context.Context) error {
 	return nil
 }
@@ -0,0 +1,94 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type drainer struct {
+	sync.Mutex
+
+	subscribers map[chan struct{}]string
+	drained     map[string]time.Time
+}
+
+func newDrainer() *drainer {
+	return &drainer{
+		subscribers: make(map[chan struct{}]string),
+		drained:     make(map[string]time.Time),
+	}
+}
+
+func (d *drainer) Drain(ctx context.Context, machine string) error {
+	d.Lock()
+	d.drained[machine] = time.Now().Add(time.Minute * 5)
+	for subscriber, name := range d.subscribers {
+		if machine == name {
+			close(subscriber)
+			delete(d.subscribers, subscriber)
+		}
+	}
+	d.collect()
+	d.Unlock()
+	return nil
+}
+
+func (d *drainer) Drained(ctx context.Context, machine string) (bool, error) {
+	// the drain event is removed once it is received, which
+	// prevents the runner from draining again on restart.
+	d.Lock()
+	d.collect()
+	_, ok := d.drained[machine]
+	if ok {
+		delete(d.drained, machine)
+		d.Unlock()
+		return true, nil
+	}
+	subscriber := make(chan struct{})
+	d.subscribers[subscriber] = machine
+	d.Unlock()
+
+	defer func() {
+		d.Lock()
+		delete(d.subscribers, subscriber)
+		d.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-subscriber:
+		d.Lock()
+		delete(d.drained, machine)
+		d.Unlock()
+		return true, nil
+	}
+}
+
+func (d *drainer) collect() {
+	// the list of drained runners is stored with a ttl, and
+	// is not removed until the ttl is reached. This provides
+	// adequate window for runners with connectivity issues to
+	// reconnect and receive notification of drain events.
+	now := time.Now()
+	for machine, timestamp := range d.drained {
+		if now.After(timestamp) {
+			delete(d.drained, machine)
+		}
+	}
+}
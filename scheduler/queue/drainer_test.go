@@ -0,0 +1,83 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDrained(t *testing.T) {
+	d := newDrainer()
+	d.Drain(noContext, "agent-1")
+
+	ok, err := d.Drained(noContext, "agent-1")
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Expect runner drained")
+	}
+	if _, ok := d.drained["agent-1"]; ok {
+		t.Errorf("Expect drain event removed once received")
+	}
+}
+
+func TestDrained_Subscriber(t *testing.T) {
+	d := newDrainer()
+	go func() {
+		// wait for the subscriber before draining the runner
+		for {
+			d.Lock()
+			n := len(d.subscribers)
+			d.Unlock()
+			if n != 0 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		d.Drain(noContext, "agent-1")
+	}()
+
+	ok, err := d.Drained(noContext, "agent-1")
+	if err != nil {
+		t.Error(err)
+	}
+	if !ok {
+		t.Errorf("Expect runner drained")
+	}
+}
+
+func TestDrained_Timeout(t *testing.T) {
+	d := newDrainer()
+	d.Drain(noContext, "agent-2")
+
+	ctx, cancel := context.WithTimeout(noContext, time.Millisecond)
+	defer cancel()
+
+	ok, err := d.Drained(ctx, "agent-1")
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expect deadline exceeded, got %v", err)
+	}
+	if ok {
+		t.Errorf("Expect runner not drained")
+	}
+}
+
+func TestDrainCollect(t *testing.T) {
+	d := newDrainer()
+	d.Drain(noContext, "agent-1")
+	d.Drain(noContext, "agent-2")
+	d.drained["agent-2"] = time.Now().Add(time.Second * -1)
+	d.collect()
+
+	if _, ok := d.drained["agent-1"]; !ok {
+		t.Errorf("Expect runner [agent-1] retained")
+	}
+	if _, ok := d.drained["agent-2"]; ok {
+		t.Errorf("Expect runner [agent-2] removed")
+	}
+}
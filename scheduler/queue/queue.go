@@ -82,6 +82,8 @@ func (q *queue) Resume(ctx context.Context) error {
 
 func (q *queue) Request(ctx context.Context, params core.Filter) (*core.Stage, error) {
 	w := &worker{
+		kind:    params.Kind,
+		typ:     params.Type,
 		os:      params.OS,
 		arch:    params.Arch,
 		kernel:  params.Kernel,
@@ -144,6 +146,12 @@ func (q *queue) signal(ctx context.Context) error {
 
 	loop:
 		for w := range q.workers {
+			// the worker is specific to the pipeline kind and
+			// type. check to ensure the queue item matches the
+			// worker kind and type (e.g. docker, exec, ssh).
+			if !matchKind(item, w) {
+				continue
+			}
 			// the worker is platform-specific. check to ensure
 			// the queue item matches the worker platform.
 			if w.os != item.OS {
@@ -205,6 +213,8 @@ func (q *queue) start() error {
 }
 
 type worker struct {
+	kind    string
+	typ     string
 	os      string
 	arch    string
 	kernel  string
@@ -217,6 +227,24 @@ type counter struct {
 	counts map[string]int
 }
 
+// helper function returns true if the pipeline kind and type
+// match the worker kind and type. Stages created before the
+// kind and type were recorded, and workers that do not report
+// a kind and type, default to docker pipelines.
+func matchKind(stage *core.Stage, w *worker) bool {
+	return defaultTo(stage.Kind, "pipeline") == defaultTo(w.kind, "pipeline") &&
+		defaultTo(stage.Type, "docker") == defaultTo(w.typ, "docker")
+}
+
+// helper function returns the string s, or the default value
+// if the string is empty.
+func defaultTo(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
 func checkLabels(a, b map[string]string) bool {
 	if len(a) != len(b) {
 		return false
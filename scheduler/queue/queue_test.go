@@ -149,3 +149,71 @@ func TestWithinLimits(t *testing.T) {
 		}
 	}
 }
+
+func TestQueueKind(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	items := []*core.Stage{
+		{ID: 1, Kind: "pipeline", Type: "exec", OS: "linux", Arch: "amd64"},
+		{ID: 2, Kind: "pipeline", Type: "docker", OS: "linux", Arch: "amd64"},
+	}
+
+	ctx := context.Background()
+	store := mock.NewMockStageStore(controller)
+	store.EXPECT().ListIncomplete(ctx).Return(items, nil).AnyTimes()
+
+	q := newQueue(store)
+	next, err := q.Request(ctx, core.Filter{Kind: "pipeline", Type: "docker", OS: "linux", Arch: "amd64"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := next, items[1]; got != want {
+		t.Errorf("Want stage %d, got %d", want.ID, got.ID)
+	}
+}
+
+func TestMatchKind(t *testing.T) {
+	tests := []struct {
+		stage *core.Stage
+		w     *worker
+		want  bool
+	}{
+		{
+			stage: &core.Stage{},
+			w:     &worker{},
+			want:  true,
+		},
+		{
+			stage: &core.Stage{},
+			w:     &worker{kind: "pipeline", typ: "docker"},
+			want:  true,
+		},
+		{
+			stage: &core.Stage{Kind: "pipeline", Type: "docker"},
+			w:     &worker{},
+			want:  true,
+		},
+		{
+			stage: &core.Stage{Kind: "pipeline", Type: "exec"},
+			w:     &worker{kind: "pipeline", typ: "exec"},
+			want:  true,
+		},
+		{
+			stage: &core.Stage{Kind: "pipeline", Type: "exec"},
+			w:     &worker{},
+			want:  false,
+		},
+		{
+			stage: &core.Stage{},
+			w:     &worker{kind: "pipeline", typ: "ssh"},
+			want:  false,
+		},
+	}
+	for i, test := range tests {
+		if got, want := matchKind(test.stage, test.w), test.want; got != want {
+			t.Errorf("Unexpected results at index %d", i)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 type scheduler struct {
 	*queue
 	*canceller
+	*drainer
 }
 
 // New creates a new scheduler.
@@ -31,6 +32,7 @@ func New(store core.StageStore) core.Scheduler {
 	return &scheduler{
 		queue:     newQueue(store),
 		canceller: newCanceller(),
+		drainer:   newDrainer(),
 	}
 }
 
@@ -0,0 +1,293 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package comment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// rateLimitReserve defines the number of remaining requests
+// reserved for other api calls. Intermediate comment updates
+// are skipped when the remaining rate limit falls below this
+// threshold. The final comment update is always attempted.
+const rateLimitReserve = 100
+
+// Config configures the Comment service.
+type Config struct {
+	Base        string
+	Enabled     bool
+	FailureOnly bool
+	Lines       int
+}
+
+// New returns a new CommentService.
+func New(client *scm.Client, renew core.Renewer, logs core.LogStore, config Config) core.CommentService {
+	return &service{
+		client:      client,
+		renew:       renew,
+		logs:        logs,
+		base:        config.Base,
+		enabled:     config.Enabled,
+		failureOnly: config.FailureOnly,
+		lines:       config.Lines,
+		locks:       map[string]*pullLock{},
+	}
+}
+
+type service struct {
+	renew       core.Renewer
+	client      *scm.Client
+	logs        core.LogStore
+	base        string
+	enabled     bool
+	failureOnly bool
+	lines       int
+
+	mu    sync.Mutex
+	locks map[string]*pullLock
+}
+
+// pullLock serializes comment updates to a pull request.
+type pullLock struct {
+	sync.Mutex
+	refs int
+}
+
+// Send posts the build summary comment to the pull request.
+// The comment is posted when the first stage starts, and is
+// updated as each stage starts and completes. Intermediate
+// updates are skipped when the remaining rate limit falls
+// below the reserve, and the final update is always
+// attempted. An existing summary comment for the build is
+// edited if supported by the source code management api,
+// and is otherwise replaced by deleting it and creating a
+// new comment.
+func (s *service) Send(ctx context.Context, user *core.User, req *core.CommentInput) error {
+	if !s.enabled || req.Build.Event != core.EventPullRequest {
+		return nil
+	}
+	if s.failureOnly && !isFailed(req.Build, req.Stages) {
+		return nil
+	}
+	if !isDone(req.Build) && isRateLimited(s.client.Rate()) {
+		return nil
+	}
+	number, ok := parsePullRequest(req.Build.Ref)
+	if !ok {
+		return nil
+	}
+
+	// stages of the same build may complete concurrently.
+	// Updates are serialized to prevent duplicate comments.
+	unlock := s.lock(fmt.Sprintf("%s#%d", req.Repo.Slug, number))
+	defer unlock()
+
+	err := s.renew.Renew(ctx, user, false)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+
+	body := s.render(ctx, req)
+	prev, err := s.find(ctx, req, number)
+	if err == scm.ErrNotSupported {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if prev != nil && prev.Body == body {
+		return nil
+	}
+	if prev != nil {
+		err = s.update(ctx, req.Repo.Slug, number, prev.ID, body)
+		if err != scm.ErrNotSupported {
+			return err
+		}
+		_, err = s.client.PullRequests.DeleteComment(ctx, req.Repo.Slug, number, prev.ID)
+		if err != nil && err != scm.ErrNotSupported {
+			return err
+		}
+	}
+	_, _, err = s.client.PullRequests.CreateComment(ctx, req.Repo.Slug, number, &scm.CommentInput{
+		Body: body,
+	})
+	if err == scm.ErrNotSupported {
+		return nil
+	}
+	return err
+}
+
+// update edits the pull request comment. The source code
+// management api does not support editing comments, so the
+// github and gitlab apis are called directly. It returns
+// scm.ErrNotSupported for the remaining providers.
+func (s *service) update(ctx context.Context, repo string, number, id int, body string) error {
+	var req *scm.Request
+	switch s.client.Driver {
+	case scm.DriverGithub:
+		req = &scm.Request{
+			Method: "PATCH",
+			Path:   fmt.Sprintf("repos/%s/issues/comments/%d", repo, id),
+		}
+	case scm.DriverGitlab:
+		req = &scm.Request{
+			Method: "PUT",
+			Path:   fmt.Sprintf("api/v4/projects/%s/merge_requests/%d/notes/%d", url.PathEscape(repo), number, id),
+		}
+	default:
+		return scm.ErrNotSupported
+	}
+	data, _ := json.Marshal(map[string]string{"body": body})
+	req.Body = bytes.NewReader(data)
+	req.Header = http.Header{"Content-Type": {"application/json"}}
+	res, err := s.client.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.Status > 299 {
+		return fmt.Errorf("comment: unexpected status code %d", res.Status)
+	}
+	return nil
+}
+
+// lock acquires the lock for the pull request, and returns
+// a function that releases the lock.
+func (s *service) lock(key string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = new(pullLock)
+		s.locks[key] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(s.locks, key)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// find returns the existing summary comment for the build,
+// identified by the hidden marker included in the comment.
+func (s *service) find(ctx context.Context, req *core.CommentInput, number int) (*scm.Comment, error) {
+	marker := createMarker(req.Build)
+	opts := scm.ListOptions{Page: 1, Size: 100}
+	for {
+		comments, res, err := s.client.PullRequests.ListComments(ctx, req.Repo.Slug, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.Body, marker) {
+				return comment, nil
+			}
+		}
+		if res == nil || res.Page.Next == 0 {
+			return nil, nil
+		}
+		opts.Page = res.Page.Next
+	}
+}
+
+// render returns the markdown summary of the build, including
+// the stage results and the trailing log lines of failed steps.
+func (s *service) render(ctx context.Context, req *core.CommentInput) string {
+	link := fmt.Sprintf("%s/%s/%d", s.base, req.Repo.Slug, req.Build.Number)
+
+	var buf bytes.Buffer
+	buf.WriteString(createMarker(req.Build))
+	buf.WriteString("\n")
+	fmt.Fprintf(&buf, "**Build [#%d](%s) %s**\n\n", req.Build.Number, link, createDesc(req.Build.Status))
+	if len(req.Stages) == 0 {
+		return buf.String()
+	}
+
+	buf.WriteString("| Pipeline | Status | Duration |\n")
+	buf.WriteString("| --- | --- | --- |\n")
+	for _, stage := range req.Stages {
+		fmt.Fprintf(&buf, "| [%s](%s/%d) | %s | %s |\n",
+			stage.Name,
+			link,
+			stage.Number,
+			stage.Status,
+			duration(stage.Started, stage.Stopped),
+		)
+	}
+
+	for _, stage := range req.Stages {
+		for _, step := range stage.Steps {
+			if !isStepFailed(step) {
+				continue
+			}
+			fmt.Fprintf(&buf, "\n**%s / %s** exited with code %d\n", stage.Name, step.Name, step.ExitCode)
+			lines := s.tail(ctx, step)
+			if len(lines) == 0 {
+				continue
+			}
+			fence := createFence(lines)
+			buf.WriteString("\n" + fence + "\n")
+			for _, line := range lines {
+				buf.WriteString(strings.TrimRight(line.Message, "\n"))
+				buf.WriteString("\n")
+			}
+			buf.WriteString(fence + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// tail returns the last lines of the step logs. Errors are
+// ignored since the logs are not essential to the summary.
+func (s *service) tail(ctx context.Context, step *core.Step) []*core.Line {
+	if s.lines <= 0 {
+		return nil
+	}
+	rc, err := s.logs.Find(ctx, step.ID)
+	if err != nil {
+		return nil
+	}
+	defer rc.Close()
+
+	var lines []*core.Line
+	if err := json.NewDecoder(rc).Decode(&lines); err != nil {
+		return nil
+	}
+	if len(lines) > s.lines {
+		lines = lines[len(lines)-s.lines:]
+	}
+	return lines
+}
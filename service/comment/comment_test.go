@@ -0,0 +1,375 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package comment
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/drone/mock/mockscm"
+	"github.com/drone/go-scm/scm"
+	"github.com/drone/go-scm/scm/driver/github"
+
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+var noContext = context.Background()
+
+var mockRepo = &core.Repository{Slug: "octocat/hello-world"}
+
+func TestComment(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockLogs := mock.NewMockLogStore(controller)
+	mockLogs.EXPECT().Find(gomock.Any(), int64(3)).Return(ioutil.NopCloser(strings.NewReader(
+		`[{"pos":0,"out":"go test ./...\n"},{"pos":1,"out":"--- FAIL: TestFoo\n"},{"pos":2,"out":"FAIL\n"}]`,
+	)), nil)
+
+	var body string
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(nil, &scm.Response{}, nil)
+	mockPulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ int, in *scm.CommentInput) (*scm.Comment, *scm.Response, error) {
+			body = in.Body
+			return nil, nil, nil
+		},
+	)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer, mockLogs, Config{
+		Base:    "https://drone.company.com",
+		Enabled: true,
+		Lines:   2,
+	})
+	err := service.Send(noContext, mockUser, &core.CommentInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPullRequest,
+			Status: core.StatusFailing,
+			Ref:    "refs/pull/42/head",
+		},
+		Stages: []*core.Stage{
+			{
+				Number:  1,
+				Name:    "test",
+				Status:  core.StatusFailing,
+				Started: 1,
+				Stopped: 91,
+				Steps: []*core.Step{
+					{ID: 2, Name: "lint", Status: core.StatusFailing, ErrIgnore: true},
+					{ID: 3, Name: "test", Status: core.StatusFailing, ExitCode: 1},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, want := range []string{
+		"<!-- drone:build:1 -->",
+		"**Build [#1](https://drone.company.com/octocat/hello-world/1) is failing**",
+		"| [test](https://drone.company.com/octocat/hello-world/1/1) | failure | 1m30s |",
+		"**test / test** exited with code 1",
+		"--- FAIL: TestFoo\nFAIL\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Want comment to contain %q, got %q", want, body)
+		}
+	}
+	if strings.Contains(body, "go test") {
+		t.Errorf("Want comment limited to the last log lines")
+	}
+	if strings.Contains(body, "lint") {
+		t.Errorf("Want ignored step failures excluded from the comment")
+	}
+}
+
+// this test verifies that the summary comment is posted
+// when the first stage starts, and that log lines cannot
+// close the code fence.
+func TestComment_Running(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	mockLogs := mock.NewMockLogStore(controller)
+	mockLogs.EXPECT().Find(gomock.Any(), int64(2)).Return(ioutil.NopCloser(strings.NewReader(
+		`[{"pos":0,"out":"echo '`+"```"+`'\n"},{"pos":1,"out":"`+"````"+`\n"}]`,
+	)), nil)
+
+	var body string
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(nil, &scm.Response{}, nil)
+	mockPulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ int, in *scm.CommentInput) (*scm.Comment, *scm.Response, error) {
+			body = in.Body
+			return nil, nil, nil
+		},
+	)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer, mockLogs, Config{Enabled: true, Lines: 10})
+	err := service.Send(noContext, mockUser, &core.CommentInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPullRequest,
+			Status: core.StatusRunning,
+			Ref:    "refs/pull/42/head",
+		},
+		Stages: []*core.Stage{
+			{Number: 1, Name: "lint", Status: core.StatusFailing, Steps: []*core.Step{
+				{ID: 2, Name: "lint", Status: core.StatusFailing, ExitCode: 1},
+			}},
+			{Number: 2, Name: "test", Status: core.StatusRunning},
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	for _, want := range []string{
+		"**Build [#1](/octocat/hello-world/1) is running**",
+		"| [test](/octocat/hello-world/1/2) | running |",
+		"\n`````\necho '```'\n````\n`````\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Want comment to contain %q, got %q", want, body)
+		}
+	}
+}
+
+// this test verifies that the existing summary comment for
+// the build is replaced when the build summary changes.
+func TestComment_Replace(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	comments := []*scm.Comment{
+		{ID: 1, Body: "looks good to me"},
+		{ID: 2, Body: "<!-- drone:build:1 -->\n**Build is running**"},
+	}
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, scm.ListOptions{Page: 1, Size: 100}).Return(nil, &scm.Response{Page: scm.Page{Next: 2}}, nil)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, scm.ListOptions{Page: 2, Size: 100}).Return(comments, &scm.Response{}, nil)
+	mockPulls.EXPECT().DeleteComment(gomock.Any(), "octocat/hello-world", 42, 2).Return(nil, nil)
+	mockPulls.EXPECT().CreateComment(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer, nil, Config{Enabled: true})
+	err := service.Send(noContext, mockUser, &core.CommentInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPullRequest,
+			Status: core.StatusPassing,
+			Ref:    "refs/pull/42/head",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the comment is not replaced when
+// the build summary is unchanged.
+func TestComment_Unchanged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	comments := []*scm.Comment{
+		{ID: 2, Body: "<!-- drone:build:1 -->\n**Build [#1](/octocat/hello-world/1) is passing**\n\n"},
+	}
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(comments, &scm.Response{}, nil)
+
+	client := new(scm.Client)
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer, nil, Config{Enabled: true})
+	err := service.Send(noContext, mockUser, &core.CommentInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPullRequest,
+			Status: core.StatusPassing,
+			Ref:    "refs/pull/42/head",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that the existing summary comment for
+// the build is edited when supported by the provider.
+func TestComment_Update(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Patch("/repos/octocat/hello-world/issues/comments/2").
+		MatchType("json").
+		JSON(map[string]string{"body": "<!-- drone:build:1 -->\n**Build [#1](/octocat/hello-world/1) is passing**\n\n"}).
+		Reply(200)
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	comments := []*scm.Comment{
+		{ID: 2, Body: "<!-- drone:build:1 -->\n**Build is running**"},
+	}
+
+	mockPulls := mockscm.NewMockPullRequestService(controller)
+	mockPulls.EXPECT().ListComments(gomock.Any(), "octocat/hello-world", 42, gomock.Any()).Return(comments, &scm.Response{}, nil)
+
+	client, _ := github.New("https://api.github.com")
+	client.PullRequests = mockPulls
+
+	service := New(client, mockRenewer, nil, Config{Enabled: true})
+	err := service.Send(noContext, mockUser, &core.CommentInput{
+		Repo: mockRepo,
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPullRequest,
+			Status: core.StatusPassing,
+			Ref:    "refs/pull/42/head",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestComment_Skip(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	client := new(scm.Client)
+	client.SetRate(scm.Rate{
+		Limit:     5000,
+		Remaining: 10,
+		Reset:     time.Now().Add(time.Hour).Unix(),
+	})
+
+	tests := []struct {
+		config Config
+		build  *core.Build
+		stages []*core.Stage
+	}{
+		// comments disabled
+		{
+			config: Config{},
+			build:  &core.Build{Event: core.EventPullRequest, Status: core.StatusFailing},
+		},
+		// not a pull request
+		{
+			config: Config{Enabled: true},
+			build:  &core.Build{Event: core.EventPush, Status: core.StatusFailing},
+		},
+		// only comment on failure
+		{
+			config: Config{Enabled: true, FailureOnly: true},
+			build:  &core.Build{Event: core.EventPullRequest, Status: core.StatusPassing},
+		},
+		// only comment on failure while running
+		{
+			config: Config{Enabled: true, FailureOnly: true},
+			build:  &core.Build{Event: core.EventPullRequest, Status: core.StatusRunning},
+		},
+		// rate limit reserve exceeded
+		{
+			config: Config{Enabled: true},
+			build:  &core.Build{Event: core.EventPullRequest, Status: core.StatusRunning},
+			stages: []*core.Stage{{Status: core.StatusRunning}},
+		},
+	}
+	for i, test := range tests {
+		service := New(client, nil, nil, test.config)
+		err := service.Send(noContext, nil, &core.CommentInput{Repo: mockRepo, Build: test.build, Stages: test.stages})
+		if err != nil {
+			t.Errorf("Test %d: %s", i, err)
+		}
+	}
+}
+
+func TestCreateFence(t *testing.T) {
+	tests := []struct {
+		lines []*core.Line
+		fence string
+	}{
+		{nil, "```"},
+		{[]*core.Line{{Message: "go test ./..."}}, "```"},
+		{[]*core.Line{{Message: "```"}, {Message: "`` `"}}, "````"},
+		{[]*core.Line{{Message: "a ````` b"}}, "``````"},
+	}
+	for i, test := range tests {
+		if got, want := createFence(test.lines), test.fence; got != want {
+			t.Errorf("Want fence %q, got %q at index %d", want, got, i)
+		}
+	}
+}
+
+func TestParsePullRequest(t *testing.T) {
+	tests := []struct {
+		ref    string
+		number int
+		ok     bool
+	}{
+		{"refs/pull/42/head", 42, true},
+		{"refs/merge-requests/42/head", 42, true},
+		{"refs/pull-requests/42/from", 42, true},
+		{"refs/heads/master", 0, false},
+	}
+	for _, test := range tests {
+		number, ok := parsePullRequest(test.ref)
+		if number != test.number || ok != test.ok {
+			t.Errorf("Want pull request %d for ref %s, got %d", test.number, test.ref, number)
+		}
+	}
+}
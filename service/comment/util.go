@@ -0,0 +1,157 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package comment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// helper function returns the hidden marker used to find
+// the summary comment for the build.
+func createMarker(build *core.Build) string {
+	return fmt.Sprintf("<!-- drone:build:%d -->", build.Number)
+}
+
+// helper function returns a code fence that is longer than
+// any run of backticks in the log lines, which prevents the
+// log output from closing the fence.
+func createFence(lines []*core.Line) string {
+	longest := 2
+	for _, line := range lines {
+		run := 0
+		for _, c := range line.Message {
+			if c != '`' {
+				run = 0
+				continue
+			}
+			run++
+			if run > longest {
+				longest = run
+			}
+		}
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func createDesc(state string) string {
+	switch state {
+	case core.StatusBlocked:
+		return "is pending approval"
+	case core.StatusDeclined:
+		return "was declined"
+	case core.StatusError:
+		return "encountered an error"
+	case core.StatusFailing:
+		return "is failing"
+	case core.StatusKilled:
+		return "was killed"
+	case core.StatusPassing:
+		return "is passing"
+	case core.StatusWaiting,
+		core.StatusPending:
+		return "is pending"
+	case core.StatusRunning:
+		return "is running"
+	case core.StatusSkipped:
+		return "was skipped"
+	default:
+		return "is in an unknown state"
+	}
+}
+
+// helper function returns the elapsed time between the
+// started and stopped timestamps.
+func duration(started, stopped int64) string {
+	if started == 0 {
+		return "-"
+	}
+	if stopped == 0 {
+		stopped = time.Now().Unix()
+	}
+	return (time.Duration(stopped-started) * time.Second).String()
+}
+
+// helper function returns true if the build is complete.
+func isDone(build *core.Build) bool {
+	switch build.Status {
+	case core.StatusWaiting,
+		core.StatusPending,
+		core.StatusRunning,
+		core.StatusBlocked:
+		return false
+	default:
+		return true
+	}
+}
+
+// helper function returns true if the build, or any of the
+// build stages, failed.
+func isFailed(build *core.Build, stages []*core.Stage) bool {
+	switch build.Status {
+	case core.StatusFailing,
+		core.StatusKilled,
+		core.StatusError:
+		return true
+	}
+	for _, stage := range stages {
+		if stage.IsFailed() {
+			return true
+		}
+	}
+	return false
+}
+
+// helper function returns true if the step failed, and the
+// failure was not ignored.
+func isStepFailed(step *core.Step) bool {
+	if step.ErrIgnore {
+		return false
+	}
+	switch step.Status {
+	case core.StatusFailing,
+		core.StatusKilled,
+		core.StatusError:
+		return true
+	default:
+		return false
+	}
+}
+
+// helper function returns true if the remaining rate limit
+// is below the reserved threshold, and the rate limit window
+// has not been reset.
+func isRateLimited(rate scm.Rate) bool {
+	return rate.Limit != 0 &&
+		rate.Remaining < rateLimitReserve &&
+		rate.Reset > time.Now().Unix()
+}
+
+// helper function returns the pull request number parsed
+// from the git reference (e.g. refs/pull/42/head).
+func parsePullRequest(ref string) (int, bool) {
+	for _, part := range strings.Split(ref, "/") {
+		number, err := strconv.Atoi(part)
+		if err == nil && number > 0 {
+			return number, true
+		}
+	}
+	return 0, false
+}
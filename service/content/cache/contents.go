@@ -50,3 +50,7 @@ func (s *service) Find(ctx context.Context, user *core.User, repo, commit, ref,
 	s.cache.Add(key, file)
 	return file, nil
 }
+
+func (s *service) List(ctx context.Context, user *core.User, repo, commit, ref, path string) ([]*core.FileInfo, error) {
+	return s.service.List(ctx, user, repo, commit, ref, path)
+}
@@ -83,10 +83,12 @@ func (s *service) Find(ctx context.Context, user *core.User, repo, commit, ref,
 
 // helper function attempts to get the yaml configuration file
 // with backoff on failure. This may be required due to eventual
-// consistency issues with the github datastore.
+// consistency issues with the github datastore. If the file
+// does not exist, scm.ErrNotFound is returned.
 func (s *service) findRetry(ctx context.Context, repo, path, commit string) (content *scm.Content, err error) {
+	var res *scm.Response
 	for i := 0; i < s.attempts; i++ {
-		content, _, err = s.client.Contents.Find(ctx, repo, path, commit)
+		content, res, err = s.client.Contents.Find(ctx, repo, path, commit)
 		// if no error is returned we can exit immediately.
 		if err == nil {
 			return
@@ -96,5 +98,8 @@ func (s *service) findRetry(ctx context.Context, repo, path, commit string) (con
 		// try 3 x 15 seconds, giving a total of 45 seconds.
 		time.Sleep(s.wait)
 	}
+	if res != nil && res.Status == 404 {
+		err = scm.ErrNotFound
+	}
 	return
 }
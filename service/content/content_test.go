@@ -6,6 +6,7 @@ package contents
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/drone/drone/core"
@@ -77,6 +78,32 @@ func TestFind_Error(t *testing.T) {
 	}
 }
 
+// this test verifies that a 404 response from the provider
+// is converted to a not found error.
+func TestFind_NotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockContents := mockscm.NewMockContentService(controller)
+	mockContents.EXPECT().Find(gomock.Any(), "octocat/hello-world", ".drone.yml", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa").Return(nil, &scm.Response{Status: 404}, errors.New("Not Found"))
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	client := new(scm.Client)
+	client.Contents = mockContents
+
+	s := New(client, mockRenewer)
+	s.(*service).attempts = 1
+	s.(*service).wait = 0
+	_, err := s.Find(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master", ".drone.yml")
+	if err != scm.ErrNotFound {
+		t.Errorf("Expect not found error, got %s", err)
+	}
+}
+
 func TestFind_RenewalError(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
@@ -0,0 +1,174 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/go-scm/scm"
+)
+
+// List returns the files and directories in the named
+// directory. The scm client does not yet provide a method to
+// list directory contents, so this invokes the provider api
+// directly. Listing directory contents is supported for
+// github, gitea, gitlab and bitbucket cloud; other providers,
+// including bitbucket server (stash) and gogs, return
+// scm.ErrNotSupported.
+func (s *service) List(ctx context.Context, user *core.User, repo, commit, ref, dir string) ([]*core.FileInfo, error) {
+	err := s.renewer.Renew(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, scm.TokenKey{}, &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	})
+	dir = strings.Trim(dir, "/")
+	if dir == "." {
+		dir = ""
+	}
+	switch s.client.Driver {
+	case scm.DriverGithub:
+		return s.listGithub(ctx, "repos/%s/contents/%s?ref=%s", repo, dir, commit)
+	case scm.DriverGitea:
+		return s.listGithub(ctx, "api/v1/repos/%s/contents/%s?ref=%s", repo, dir, commit)
+	case scm.DriverGitlab:
+		return s.listGitlab(ctx, repo, dir, commit)
+	case scm.DriverBitbucket:
+		return s.listBitbucket(ctx, repo, dir, commit)
+	default:
+		return nil, scm.ErrNotSupported
+	}
+}
+
+// helper function lists the directory contents using the
+// github contents api, which is also implemented by gitea.
+// The contents api is not paginated, and returns up to 1000
+// entries.
+func (s *service) listGithub(ctx context.Context, pattern, repo, dir, commit string) ([]*core.FileInfo, error) {
+	var out []struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	}
+	endpoint := fmt.Sprintf(pattern, repo, dir, commit)
+	if _, err := s.do(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	var files []*core.FileInfo
+	for _, src := range out {
+		files = append(files, &core.FileInfo{
+			Path:  src.Path,
+			IsDir: src.Type == "dir",
+		})
+	}
+	return files, nil
+}
+
+// helper function lists the directory contents using the
+// gitlab repository tree api. The results are paginated, and
+// the next page is returned in the X-Next-Page header.
+func (s *service) listGitlab(ctx context.Context, repo, dir, commit string) ([]*core.FileInfo, error) {
+	var files []*core.FileInfo
+	for page := "1"; page != ""; {
+		var out []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		endpoint := fmt.Sprintf("api/v4/projects/%s/repository/tree?path=%s&ref=%s&per_page=100&page=%s",
+			strings.Replace(url.PathEscape(repo), "/", "%2F", -1), url.QueryEscape(dir), commit, page)
+		header, err := s.do(ctx, endpoint, &out)
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range out {
+			files = append(files, &core.FileInfo{
+				Path:  src.Path,
+				IsDir: src.Type == "tree",
+			})
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return files, nil
+}
+
+// helper function lists the directory contents using the
+// bitbucket cloud source api. The results are paginated, and
+// the next page is returned as a link in the response body.
+func (s *service) listBitbucket(ctx context.Context, repo, dir, commit string) ([]*core.FileInfo, error) {
+	var files []*core.FileInfo
+	endpoint := fmt.Sprintf("2.0/repositories/%s/src/%s/%s/?pagelen=100", repo, commit, dir)
+	for endpoint != "" {
+		var out struct {
+			Values []struct {
+				Path string `json:"path"`
+				Type string `json:"type"`
+			} `json:"values"`
+			Next string `json:"next"`
+		}
+		if _, err := s.do(ctx, endpoint, &out); err != nil {
+			return nil, err
+		}
+		for _, src := range out.Values {
+			files = append(files, &core.FileInfo{
+				Path:  path.Clean(src.Path),
+				IsDir: src.Type == "commit_directory",
+			})
+		}
+		endpoint = nextBitbucket(out.Next)
+	}
+	return files, nil
+}
+
+// helper function returns the api path of the next page link
+// returned by bitbucket cloud, relative to the api root.
+func nextBitbucket(next string) string {
+	if next == "" {
+		return ""
+	}
+	u, err := url.Parse(next)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.RequestURI(), "/")
+}
+
+// helper function sends the http request and decodes the
+// json response body into v. The response headers are
+// returned, and are used to paginate the results.
+func (s *service) do(ctx context.Context, endpoint string, v interface{}) (http.Header, error) {
+	res, err := s.client.Do(ctx, &scm.Request{
+		Method: "GET",
+		Path:   endpoint,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	switch {
+	case res.Status == 404:
+		return nil, scm.ErrNotFound
+	case res.Status > 299:
+		return nil, fmt.Errorf("contents: cannot list directory: status code %d", res.Status)
+	}
+	return res.Header, json.NewDecoder(res.Body).Decode(v)
+}
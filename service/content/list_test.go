@@ -0,0 +1,184 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package contents
+
+import (
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/mock"
+	"github.com/drone/go-scm/scm"
+	"github.com/drone/go-scm/scm/driver/bitbucket"
+	"github.com/drone/go-scm/scm/driver/github"
+	"github.com/drone/go-scm/scm/driver/gitlab"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
+)
+
+func TestList(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer func() {
+		gock.Off()
+		controller.Finish()
+	}()
+
+	gock.New("https://api.github.com").
+		Get("/repos/octocat/hello-world/contents/.drone").
+		MatchParam("ref", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa").
+		Reply(200).
+		JSON([]map[string]string{
+			{"path": ".drone/build.yml", "type": "file"},
+			{"path": ".drone/templates", "type": "dir"},
+		})
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	client, _ := github.New("https://api.github.com")
+
+	s := New(client, mockRenewer)
+	got, err := s.List(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master", ".drone/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := []*core.FileInfo{
+		{Path: ".drone/build.yml"},
+		{Path: ".drone/templates", IsDir: true},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+func TestList_GitlabPagination(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer func() {
+		gock.Off()
+		controller.Finish()
+	}()
+
+	gock.New("https://gitlab.com").
+		Get("/api/v4/projects/octocat/hello-world/repository/tree").
+		MatchParam("path", ".drone").
+		MatchParam("page", "1").
+		Reply(200).
+		SetHeader("X-Next-Page", "2").
+		JSON([]map[string]string{
+			{"path": ".drone/build.yml", "type": "blob"},
+		})
+
+	gock.New("https://gitlab.com").
+		Get("/api/v4/projects/octocat/hello-world/repository/tree").
+		MatchParam("path", ".drone").
+		MatchParam("page", "2").
+		Reply(200).
+		JSON([]map[string]string{
+			{"path": ".drone/templates", "type": "tree"},
+		})
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	client, _ := gitlab.New("https://gitlab.com")
+
+	s := New(client, mockRenewer)
+	got, err := s.List(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master", ".drone/")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := []*core.FileInfo{
+		{Path: ".drone/build.yml"},
+		{Path: ".drone/templates", IsDir: true},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestList_BitbucketPagination(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer func() {
+		gock.Off()
+		controller.Finish()
+	}()
+
+	gock.New("https://api.bitbucket.org").
+		Get("/2.0/repositories/octocat/hello-world/src/a6586b3db244fb6b1198f2b25c213ded5b44f9fa/.drone/").
+		MatchParam("pagelen", "100").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"values": []map[string]string{
+				{"path": ".drone/build.yml", "type": "commit_file"},
+			},
+			"next": "https://api.bitbucket.org/2.0/repositories/octocat/hello-world/src/a6586b3db244fb6b1198f2b25c213ded5b44f9fa/.drone/?pagelen=100&page=c2Vjb25k",
+		})
+
+	gock.New("https://api.bitbucket.org").
+		Get("/2.0/repositories/octocat/hello-world/src/a6586b3db244fb6b1198f2b25c213ded5b44f9fa/.drone/").
+		MatchParam("page", "c2Vjb25k").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"values": []map[string]string{
+				{"path": ".drone/templates", "type": "commit_directory"},
+			},
+		})
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	client, _ := bitbucket.New("https://api.bitbucket.org")
+
+	s := New(client, mockRenewer)
+	got, err := s.List(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master", ".drone")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := []*core.FileInfo{
+		{Path: ".drone/build.yml"},
+		{Path: ".drone/templates", IsDir: true},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf(diff)
+	}
+	if gock.IsPending() {
+		t.Errorf("Unfinished requests")
+	}
+}
+
+func TestList_NotSupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	client := new(scm.Client)
+	client.Driver = scm.DriverGogs
+
+	s := New(client, mockRenewer)
+	_, err := s.List(noContext, mockUser, "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", "master", ".drone")
+	if err != scm.ErrNotSupported {
+		t.Errorf("Expect not supported error, got %v", err)
+	}
+}
@@ -16,6 +16,10 @@ package orgs
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/drone/drone/core"
@@ -36,18 +40,10 @@ type service struct {
 }
 
 func (s *service) List(ctx context.Context, user *core.User) ([]*core.Organization, error) {
-	err := s.renewer.Renew(ctx, user, false)
+	ctx, err := s.withToken(ctx, user)
 	if err != nil {
 		return nil, err
 	}
-	token := &scm.Token{
-		Token:   user.Token,
-		Refresh: user.Refresh,
-	}
-	if user.Expiry != 0 {
-		token.Expires = time.Unix(user.Expiry, 0)
-	}
-	ctx = context.WithValue(ctx, scm.TokenKey{}, token)
 	out, _, err := s.client.Organizations.List(ctx, scm.ListOptions{Size: 100})
 	if err != nil {
 		return nil, err
@@ -61,3 +57,107 @@ func (s *service) List(ctx context.Context, user *core.User) ([]*core.Organizati
 	}
 	return orgs, nil
 }
+
+func (s *service) Membership(ctx context.Context, user *core.User, name string) (bool, bool, error) {
+	// the user owns the personal namespace.
+	if strings.EqualFold(user.Login, name) {
+		return true, true, nil
+	}
+	ctx, err := s.withToken(ctx, user)
+	if err != nil {
+		return false, false, err
+	}
+	switch s.client.Driver {
+	case scm.DriverGithub:
+		return s.membershipGithub(ctx, name)
+	case scm.DriverGitlab:
+		return s.membershipGitlab(ctx, name)
+	default:
+		// the remaining providers do not expose the role of
+		// the user in the organization. The user is never
+		// considered an admin, which limits organization
+		// administration to system administrators.
+		return false, false, nil
+	}
+}
+
+// helper function returns the github organization membership
+// of the authenticated user.
+func (s *service) membershipGithub(ctx context.Context, name string) (bool, bool, error) {
+	out := new(struct {
+		State string `json:"state"`
+		Role  string `json:"role"`
+	})
+	path := fmt.Sprintf("user/memberships/orgs/%s", url.PathEscape(name))
+	found, err := s.get(ctx, path, out)
+	if err != nil || !found || out.State != "active" {
+		return false, false, err
+	}
+	return true, out.Role == "admin", nil
+}
+
+// helper function returns the gitlab group membership of the
+// authenticated user. The user is a group admin if the user
+// has owner access to the group.
+func (s *service) membershipGitlab(ctx context.Context, name string) (bool, bool, error) {
+	var out []*struct {
+		FullPath string `json:"full_path"`
+	}
+	for _, level := range []int{gitlabOwner, gitlabGuest} {
+		path := fmt.Sprintf("api/v4/groups?min_access_level=%d&search=%s&per_page=100", level, url.QueryEscape(name))
+		_, err := s.get(ctx, path, &out)
+		if err != nil {
+			return false, false, err
+		}
+		for _, group := range out {
+			if strings.EqualFold(group.FullPath, name) {
+				return true, level == gitlabOwner, nil
+			}
+		}
+	}
+	return false, false, nil
+}
+
+// gitlab group access levels.
+const (
+	gitlabGuest = 10
+	gitlabOwner = 50
+)
+
+// helper function makes a GET request to the scm api and
+// decodes the json response. It returns false if the
+// resource does not exist.
+func (s *service) get(ctx context.Context, path string, out interface{}) (bool, error) {
+	res, err := s.client.Do(ctx, &scm.Request{
+		Method: "GET",
+		Path:   path,
+	})
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	switch {
+	case res.Status == 404:
+		return false, nil
+	case res.Status > 299:
+		return false, fmt.Errorf("orgs: unexpected status code %d", res.Status)
+	}
+	return true, json.NewDecoder(res.Body).Decode(out)
+}
+
+// helper function renews the user token and returns a
+// context with the token attached.
+func (s *service) withToken(ctx context.Context, user *core.User) (context.Context, error) {
+	err := s.renewer.Renew(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	token := &scm.Token{
+		Token:   user.Token,
+		Refresh: user.Refresh,
+	}
+	if user.Expiry != 0 {
+		token.Expires = time.Unix(user.Expiry, 0)
+	}
+	return context.WithValue(ctx, scm.TokenKey{}, token), nil
+}
@@ -6,6 +6,7 @@ package orgs
 
 import (
 	"context"
+	"net/url"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/golang/mock/gomock"
+	"github.com/h2non/gock"
 )
 
 var noContext = context.Background()
@@ -124,3 +126,117 @@ func TestList_RenewalError(t *testing.T) {
 		t.Errorf("Expect error refreshing token")
 	}
 }
+
+func TestMembership_Github(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Get("/user/memberships/orgs/github").
+		Reply(200).
+		JSON(map[string]string{"state": "active", "role": "admin"})
+
+	mockUser := &core.User{Login: "octocat"}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	service := New(mockClient(scm.DriverGithub, "https://api.github.com/"), mockRenewer)
+	member, admin, err := service.Membership(noContext, mockUser, "github")
+	if err != nil {
+		t.Error(err)
+	}
+	if !member || !admin {
+		t.Errorf("Want organization admin")
+	}
+}
+
+func TestMembership_GithubNotFound(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Get("/user/memberships/orgs/github").
+		Reply(404)
+
+	mockUser := &core.User{Login: "octocat"}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	service := New(mockClient(scm.DriverGithub, "https://api.github.com/"), mockRenewer)
+	member, admin, err := service.Membership(noContext, mockUser, "github")
+	if err != nil {
+		t.Error(err)
+	}
+	if member || admin {
+		t.Errorf("Want no organization membership")
+	}
+}
+
+func TestMembership_GitlabMember(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	defer gock.Off()
+
+	gock.New("https://gitlab.com").
+		Get("/api/v4/groups").
+		MatchParam("min_access_level", "50").
+		Reply(200).
+		JSON([]interface{}{})
+	gock.New("https://gitlab.com").
+		Get("/api/v4/groups").
+		MatchParam("min_access_level", "10").
+		Reply(200).
+		JSON([]map[string]string{{"full_path": "gitlab-org"}})
+
+	mockUser := &core.User{Login: "octocat"}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	service := New(mockClient(scm.DriverGitlab, "https://gitlab.com/"), mockRenewer)
+	member, admin, err := service.Membership(noContext, mockUser, "gitlab-org")
+	if err != nil {
+		t.Error(err)
+	}
+	if !member || admin {
+		t.Errorf("Want organization member without admin access")
+	}
+}
+
+func TestMembership_Personal(t *testing.T) {
+	mockUser := &core.User{Login: "octocat"}
+	service := New(nil, nil)
+	member, admin, err := service.Membership(noContext, mockUser, "octocat")
+	if err != nil {
+		t.Error(err)
+	}
+	if !member || !admin {
+		t.Errorf("Want user to own the personal namespace")
+	}
+}
+
+func TestMembership_Unsupported(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{Login: "octocat"}
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false)
+
+	service := New(mockClient(scm.DriverBitbucket, "https://api.bitbucket.org/"), mockRenewer)
+	_, admin, err := service.Membership(noContext, mockUser, "atlassian")
+	if err != nil {
+		t.Error(err)
+	}
+	if admin {
+		t.Errorf("Want no admin access when the provider is not supported")
+	}
+}
+
+func mockClient(driver scm.Driver, server string) *scm.Client {
+	client := new(scm.Client)
+	client.Driver = driver
+	client.BaseURL, _ = url.Parse(server)
+	return client
+}
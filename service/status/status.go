@@ -22,11 +22,14 @@ import (
 	"github.com/drone/go-scm/scm"
 )
 
-// Config configures the Status service.
+// Config configures the Status service. If Stages is true
+// a status is also sent for each stage (pipeline), in addition
+// to the aggregate build status.
 type Config struct {
 	Base     string
 	Name     string
 	Disabled bool
+	Stages   bool
 }
 
 // New returns a new StatusService
@@ -37,6 +40,7 @@ func New(client *scm.Client, renew core.Renewer, config Config) core.StatusServi
 		base:     config.Base,
 		name:     config.Name,
 		disabled: config.Disabled,
+		stages:   config.Stages,
 	}
 }
 
@@ -46,12 +50,16 @@ type service struct {
 	base     string
 	name     string
 	disabled bool
+	stages   bool
 }
 
 func (s *service) Send(ctx context.Context, user *core.User, req *core.StatusInput) error {
 	if s.disabled {
 		return nil
 	}
+	if req != nil && req.Stage != nil && !s.stages {
+		return nil
+	}
 
 	err := s.renew.Renew(ctx, user, false)
 	if err != nil {
@@ -63,12 +71,22 @@ func (s *service) Send(ctx context.Context, user *core.User, req *core.StatusInp
 		Refresh: user.Refresh,
 	})
 
-	_, _, err = s.client.Repositories.CreateStatus(ctx, req.Repo.Slug, req.Build.After, &scm.StatusInput{
+	input := &scm.StatusInput{
 		Desc:   createDesc(req.Build.Status),
 		Label:  createLabel(s.name, req.Build.Event),
 		State:  convertStatus(req.Build.Status),
 		Target: fmt.Sprintf("%s/%s/%d", s.base, req.Repo.Slug, req.Build.Number),
-	})
+	}
+	if req.Stage != nil {
+		input = &scm.StatusInput{
+			Desc:   createStageDesc(req.Stage.Status),
+			Label:  createStageLabel(s.name, req.Build.Event, req.Stage.Name),
+			State:  convertStatus(req.Stage.Status),
+			Target: fmt.Sprintf("%s/%s/%d/%d", s.base, req.Repo.Slug, req.Build.Number, req.Stage.Number),
+		}
+	}
+
+	_, _, err = s.client.Repositories.CreateStatus(ctx, req.Repo.Slug, req.Build.After, input)
 	if err == scm.ErrNotSupported {
 		return nil
 	}
@@ -55,6 +55,60 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestStatus_Stage(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUser := &core.User{}
+
+	mockRenewer := mock.NewMockRenewer(controller)
+	mockRenewer.EXPECT().Renew(gomock.Any(), mockUser, false).Return(nil)
+
+	statusInput := &scm.StatusInput{
+		State:  scm.StateFailure,
+		Label:  "continuous-integration/drone/push/test",
+		Desc:   "Pipeline is failing",
+		Target: "https://drone.company.com/octocat/hello-world/1/2",
+	}
+
+	mockRepos := mockscm.NewMockRepositoryService(controller)
+	mockRepos.EXPECT().CreateStatus(gomock.Any(), "octocat/hello-world", "a6586b3db244fb6b1198f2b25c213ded5b44f9fa", statusInput).Return(nil, nil, nil)
+
+	client := new(scm.Client)
+	client.Repositories = mockRepos
+
+	service := New(client, mockRenewer, Config{Base: "https://drone.company.com", Stages: true})
+	err := service.Send(noContext, mockUser, &core.StatusInput{
+		Repo: &core.Repository{Slug: "octocat/hello-world"},
+		Build: &core.Build{
+			Number: 1,
+			Event:  core.EventPush,
+			Status: core.StatusRunning,
+			After:  "a6586b3db244fb6b1198f2b25c213ded5b44f9fa",
+		},
+		Stage: &core.Stage{
+			Number: 2,
+			Name:   "test",
+			Status: core.StatusFailing,
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// this test verifies that stage statuses are ignored unless
+// per-stage statuses are enabled.
+func TestStatus_StageDisabled(t *testing.T) {
+	service := New(nil, nil, Config{})
+	err := service.Send(noContext, nil, &core.StatusInput{
+		Stage: &core.Stage{Name: "test"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestStatus_ErrNotSupported(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
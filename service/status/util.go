@@ -16,6 +16,7 @@ package status
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/go-scm/scm"
@@ -37,6 +38,17 @@ func createLabel(name, event string) string {
 	}
 }
 
+// helper function returns the stage status label, derived
+// from the build label and the stage name.
+func createStageLabel(name, event, stage string) string {
+	return fmt.Sprintf("%s/%s", createLabel(name, event), stage)
+}
+
+// helper function returns the stage status description.
+func createStageDesc(state string) string {
+	return strings.Replace(createDesc(state), "Build", "Pipeline", 1)
+}
+
 func createDesc(state string) string {
 	switch state {
 	case core.StatusBlocked:
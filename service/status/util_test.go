@@ -46,6 +46,21 @@ func TestCreateLabel(t *testing.T) {
 	}
 }
 
+func TestCreateStageLabel(t *testing.T) {
+	if got, want := createStageLabel("", core.EventPush, "lint"), "continuous-integration/drone/push/lint"; got != want {
+		t.Errorf("Want label %q, got %q", want, got)
+	}
+	if got, want := createStageLabel("drone", core.EventPullRequest, "test"), "drone/pr/test"; got != want {
+		t.Errorf("Want label %q, got %q", want, got)
+	}
+}
+
+func TestCreateStageDesc(t *testing.T) {
+	if got, want := createStageDesc(core.StatusFailing), "Pipeline is failing"; got != want {
+		t.Errorf("Want description %q, got %q", want, got)
+	}
+}
+
 func TestCreateDesc(t *testing.T) {
 	tests := []struct {
 		status string
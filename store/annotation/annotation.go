@@ -0,0 +1,191 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new AnnotationStore.
+func New(db *db.DB) core.AnnotationStore {
+	return &annotationStore{db}
+}
+
+type annotationStore struct {
+	db *db.DB
+}
+
+func (s *annotationStore) List(ctx context.Context, build int64) ([]*core.Annotation, error) {
+	var out []*core.Annotation
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"annotation_build_id": build}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *annotationStore) Create(ctx context.Context, annotations []*core.Annotation) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		for _, annotation := range annotations {
+			params := toParams(annotation)
+			if s.db.Driver() == db.Postgres {
+				stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+				if err != nil {
+					return err
+				}
+				err = execer.QueryRow(stmt, args...).Scan(&annotation.ID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			stmt, args, err := binder.BindNamed(stmtInsert, params)
+			if err != nil {
+				return err
+			}
+			res, err := execer.Exec(stmt, args...)
+			if err != nil {
+				return err
+			}
+			annotation.ID, err = res.LastInsertId()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *annotationStore) ListCards(ctx context.Context, build int64) ([]*core.Card, error) {
+	var out []*core.Card
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"card_build_id": build}
+		stmt, args, err := binder.BindNamed(queryCardBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanCardRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *annotationStore) CreateCard(ctx context.Context, card *core.Card) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toCardParams(card)
+		if s.db.Driver() == db.Postgres {
+			stmt, args, err := binder.BindNamed(stmtInsertCardPg, params)
+			if err != nil {
+				return err
+			}
+			return execer.QueryRow(stmt, args...).Scan(&card.ID)
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertCard, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		card.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+const queryBuild = `
+SELECT
+ annotation_id
+,annotation_build_id
+,annotation_stage_id
+,annotation_step_id
+,annotation_file
+,annotation_line
+,annotation_severity
+,annotation_message
+FROM annotations
+WHERE annotation_build_id = :annotation_build_id
+ORDER BY annotation_id
+`
+
+const stmtInsert = `
+INSERT INTO annotations (
+ annotation_build_id
+,annotation_stage_id
+,annotation_step_id
+,annotation_file
+,annotation_line
+,annotation_severity
+,annotation_message
+) VALUES (
+ :annotation_build_id
+,:annotation_stage_id
+,:annotation_step_id
+,:annotation_file
+,:annotation_line
+,:annotation_severity
+,:annotation_message
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING annotation_id
+`
+
+const queryCardBuild = `
+SELECT
+ card_id
+,card_build_id
+,card_stage_id
+,card_step_id
+,card_data
+FROM cards
+WHERE card_build_id = :card_build_id
+ORDER BY card_id
+`
+
+const stmtInsertCard = `
+INSERT INTO cards (
+ card_build_id
+,card_stage_id
+,card_step_id
+,card_data
+) VALUES (
+ :card_build_id
+,:card_stage_id
+,:card_step_id
+,:card_data
+)
+`
+
+const stmtInsertCardPg = stmtInsertCard + `
+RETURNING card_id
+`
@@ -0,0 +1,98 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package annotation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestAnnotation(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy build and stage
+	stage := &core.Stage{Number: 1}
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, []*core.Stage{stage})
+
+	store := New(conn).(*annotationStore)
+
+	annotations := []*core.Annotation{
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 1, File: "main.go", Line: 12, Severity: core.SeverityWarning, Message: "unused variable"},
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 2, Severity: core.SeverityNotice, Message: "coverage 80%"},
+	}
+	cards := []*core.Card{
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 2, Data: "# Coverage\n\n80%"},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		err := store.Create(noContext, annotations)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, annotation := range annotations {
+			if annotation.ID == 0 {
+				t.Errorf("Want annotation ID assigned")
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, abuild.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, annotations); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("CreateCard", func(t *testing.T) {
+		err := store.CreateCard(noContext, cards[0])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if cards[0].ID == 0 {
+			t.Errorf("Want card ID assigned")
+		}
+	})
+
+	t.Run("ListCards", func(t *testing.T) {
+		list, err := store.ListCards(noContext, abuild.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, cards); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+}
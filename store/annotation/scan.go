@@ -0,0 +1,110 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package annotation
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Annotation structure to a set
+// of named query parameters.
+func toParams(annotation *core.Annotation) map[string]interface{} {
+	return map[string]interface{}{
+		"annotation_id":       annotation.ID,
+		"annotation_build_id": annotation.BuildID,
+		"annotation_stage_id": annotation.StageID,
+		"annotation_step_id":  annotation.StepID,
+		"annotation_file":     annotation.File,
+		"annotation_line":     annotation.Line,
+		"annotation_severity": annotation.Severity,
+		"annotation_message":  annotation.Message,
+	}
+}
+
+// helper function converts the Card structure to a set of
+// named query parameters.
+func toCardParams(card *core.Card) map[string]interface{} {
+	return map[string]interface{}{
+		"card_id":       card.ID,
+		"card_build_id": card.BuildID,
+		"card_stage_id": card.StageID,
+		"card_step_id":  card.StepID,
+		"card_data":     card.Data,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Annotation) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.BuildID,
+		&dst.StageID,
+		&dst.StepID,
+		&dst.File,
+		&dst.Line,
+		&dst.Severity,
+		&dst.Message,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Annotation, error) {
+	defer rows.Close()
+
+	annotations := []*core.Annotation{}
+	for rows.Next() {
+		annotation := new(core.Annotation)
+		err := scanRow(rows, annotation)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, annotation)
+	}
+	return annotations, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanCardRow(scanner db.Scanner, dst *core.Card) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.BuildID,
+		&dst.StageID,
+		&dst.StepID,
+		&dst.Data,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanCardRows(rows *sql.Rows) ([]*core.Card, error) {
+	defer rows.Close()
+
+	cards := []*core.Card{}
+	for rows.Next() {
+		card := new(core.Card)
+		err := scanCardRow(rows, card)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -0,0 +1,329 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+	"io"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new ArtifactStore that persists the artifact
+// contents to the database.
+func New(db *db.DB) core.ArtifactStore {
+	return &artifactStore{
+		db:    db,
+		blobs: &dbBlobs{db},
+	}
+}
+
+// NewFS returns a new ArtifactStore that persists the artifact
+// contents to the local filesystem, in the root directory.
+func NewFS(db *db.DB, root string) core.ArtifactStore {
+	return &artifactStore{
+		db:    db,
+		blobs: &fsBlobs{root},
+	}
+}
+
+// blobStore persists the artifact contents to storage.
+type blobStore interface {
+	find(ctx context.Context, id int64) (io.ReadCloser, error)
+	create(ctx context.Context, id int64, r io.Reader) error
+	delete(ctx context.Context, id int64) error
+}
+
+type artifactStore struct {
+	db    *db.DB
+	blobs blobStore
+}
+
+func (s *artifactStore) List(ctx context.Context, build int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_build_id": build}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) ListStage(ctx context.Context, stage int64) ([]*core.Artifact, error) {
+	var out []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"artifact_stage_id": stage}
+		stmt, args, err := binder.BindNamed(queryStage, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *artifactStore) Find(ctx context.Context, id int64) (*core.Artifact, error) {
+	out := &core.Artifact{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *artifactStore) FindName(ctx context.Context, stage int64, name string) (*core.Artifact, error) {
+	out := &core.Artifact{StageID: stage, Name: name}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryName, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *artifactStore) Open(ctx context.Context, artifact *core.Artifact) (io.ReadCloser, error) {
+	return s.blobs.find(ctx, artifact.ID)
+}
+
+func (s *artifactStore) Create(ctx context.Context, artifact *core.Artifact, r io.Reader) error {
+	// an artifact uploaded with the same name as an existing
+	// artifact in the stage replaces the existing artifact.
+	if existing, err := s.FindName(ctx, artifact.StageID, artifact.Name); err == nil {
+		if err := s.Delete(ctx, existing); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if s.db.Driver() == db.Postgres {
+		err = s.createPostgres(ctx, artifact)
+	} else {
+		err = s.create(ctx, artifact)
+	}
+	if err != nil {
+		return err
+	}
+
+	counter := &countingReader{reader: r}
+	err = s.blobs.create(ctx, artifact.ID, counter)
+	if err != nil {
+		s.deleteRow(ctx, artifact)
+		return err
+	}
+	artifact.Size = counter.count
+	return s.update(ctx, artifact)
+}
+
+func (s *artifactStore) create(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		artifact.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *artifactStore) createPostgres(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&artifact.ID)
+	})
+}
+
+func (s *artifactStore) update(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *artifactStore) Delete(ctx context.Context, artifact *core.Artifact) error {
+	err := s.blobs.delete(ctx, artifact.ID)
+	if err != nil {
+		return err
+	}
+	return s.deleteRow(ctx, artifact)
+}
+
+func (s *artifactStore) Purge(ctx context.Context, repo, number int64) error {
+	var artifacts []*core.Artifact
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"artifact_repo_id": repo,
+			"build_number":     number,
+		}
+		stmt, args, err := binder.BindNamed(queryPurge, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		artifacts, err = scanRows(rows)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		err := s.Delete(ctx, artifact)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *artifactStore) deleteRow(ctx context.Context, artifact *core.Artifact) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(artifact)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+// countingReader counts the number of bytes read from the
+// underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+const queryBase = `
+SELECT
+ artifact_id
+,artifact_repo_id
+,artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+`
+
+const queryKey = queryBase + `
+FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const queryName = queryBase + `
+FROM artifacts
+WHERE artifact_stage_id = :artifact_stage_id
+  AND artifact_name = :artifact_name
+`
+
+const queryBuild = queryBase + `
+FROM artifacts
+WHERE artifact_build_id = :artifact_build_id
+ORDER BY artifact_stage_id, artifact_name
+`
+
+const queryStage = queryBase + `
+FROM artifacts
+WHERE artifact_stage_id = :artifact_stage_id
+ORDER BY artifact_name
+`
+
+const queryPurge = queryBase + `
+FROM artifacts
+INNER JOIN builds ON artifact_build_id = build_id
+WHERE artifact_repo_id = :artifact_repo_id
+  AND build_number < :build_number
+`
+
+const stmtUpdate = `
+UPDATE artifacts
+SET artifact_size = :artifact_size
+WHERE artifact_id = :artifact_id
+`
+
+const stmtDelete = `
+DELETE FROM artifacts
+WHERE artifact_id = :artifact_id
+`
+
+const stmtInsert = `
+INSERT INTO artifacts (
+ artifact_repo_id
+,artifact_build_id
+,artifact_stage_id
+,artifact_step_id
+,artifact_name
+,artifact_size
+,artifact_created
+) VALUES (
+ :artifact_repo_id
+,:artifact_build_id
+,:artifact_stage_id
+,:artifact_step_id
+,:artifact_name
+,:artifact_size
+,:artifact_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING artifact_id
+`
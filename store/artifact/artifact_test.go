@@ -0,0 +1,289 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+)
+
+var noContext = context.TODO()
+
+func TestArtifact(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	artifact := seed(conn)
+	store := New(conn).(*artifactStore)
+	t.Run("Create", testArtifactCreate(store, artifact))
+	t.Run("Find", testArtifactFind(store, artifact))
+	t.Run("FindName", testArtifactFindName(store, artifact))
+	t.Run("List", testArtifactList(store, artifact))
+	t.Run("ListStage", testArtifactListStage(store, artifact))
+	t.Run("Open", testArtifactOpen(store, artifact))
+	t.Run("Replace", testArtifactReplace(store, artifact))
+	t.Run("Purge", testArtifactPurge(store, artifact))
+	t.Run("Delete", testArtifactDelete(store, artifact))
+}
+
+func TestArtifact_FS(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	dir, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	artifact := seed(conn)
+	store := NewFS(conn, dir).(*artifactStore)
+	t.Run("Create", testArtifactCreate(store, artifact))
+	t.Run("Open", testArtifactOpen(store, artifact))
+	t.Run("Delete", testArtifactDelete(store, artifact))
+
+	if _, err := os.Stat(store.blobs.(*fsBlobs).path(artifact.ID)); !os.IsNotExist(err) {
+		t.Errorf("Want artifact file removed from the filesystem")
+	}
+}
+
+// helper function seeds the database with a dummy repository,
+// build, stage and step, and returns an artifact for the step.
+func seed(conn *db.DB) *core.Artifact {
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy stage
+	stage := &core.Stage{Number: 1}
+	stages := []*core.Stage{stage}
+
+	// seed with a dummy build
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, stages)
+
+	// seed with a dummy step
+	astep := &core.Step{Number: 1, StageID: stage.ID}
+	steps := step.New(conn)
+	steps.Create(noContext, astep)
+
+	artifact := &core.Artifact{
+		RepoID:  arepo.ID,
+		BuildID: abuild.ID,
+		StageID: stage.ID,
+		StepID:  astep.ID,
+		Name:    "dist/hello-world",
+	}
+	return artifact
+}
+
+func testArtifactCreate(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		buf := bytes.NewBufferString("hello world")
+		err := store.Create(noContext, artifact, buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if artifact.ID == 0 {
+			t.Errorf("Want artifact ID assigned, got %d", artifact.ID)
+		}
+		if got, want := artifact.Size, int64(11); got != want {
+			t.Errorf("Want artifact size %d, got %d", want, got)
+		}
+	}
+}
+
+func testArtifactFind(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.Find(noContext, artifact.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		testArtifact(t, result, artifact)
+	}
+}
+
+func testArtifactFindName(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		result, err := store.FindName(noContext, artifact.StageID, artifact.Name)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		testArtifact(t, result, artifact)
+	}
+}
+
+func testArtifactList(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, artifact.BuildID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		testArtifact(t, list[0], artifact)
+	}
+}
+
+func testArtifactListStage(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListStage(noContext, artifact.StageID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+			return
+		}
+		testArtifact(t, list[0], artifact)
+	}
+}
+
+func testArtifactOpen(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		r, err := store.Open(noContext, artifact)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := string(data), "hello world"; got != want {
+			t.Errorf("Want artifact output stream %q, got %q", want, got)
+		}
+	}
+}
+
+func testArtifactReplace(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		before := artifact.ID
+		buf := bytes.NewBufferString("hola mundo")
+		err := store.Create(noContext, artifact, buf)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if artifact.ID == before {
+			t.Errorf("Want replaced artifact assigned a new ID")
+		}
+		if _, err := store.Find(noContext, before); err != sql.ErrNoRows {
+			t.Errorf("Want replaced artifact removed, got error %v", err)
+		}
+		list, err := store.ListStage(noContext, artifact.StageID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		}
+		if got, want := artifact.Size, int64(10); got != want {
+			t.Errorf("Want artifact size %d, got %d", want, got)
+		}
+	}
+}
+
+func testArtifactPurge(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		// the artifact belongs to build number 1, and should
+		// not be purged.
+		err := store.Purge(noContext, artifact.RepoID, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := store.Find(noContext, artifact.ID); err != nil {
+			t.Errorf("Want artifact retained, got error %v", err)
+			return
+		}
+		err = store.Purge(noContext, artifact.RepoID, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, err := store.Find(noContext, artifact.ID); err != sql.ErrNoRows {
+			t.Errorf("Want artifact purged, got error %v", err)
+		}
+		// re-create the artifact for subsequent tests.
+		err = store.Create(noContext, artifact, bytes.NewBufferString("hello world"))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func testArtifactDelete(store *artifactStore, artifact *core.Artifact) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := store.Delete(noContext, artifact)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, artifact.ID)
+		if got, want := err, sql.ErrNoRows; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
+
+func testArtifact(t *testing.T, got, want *core.Artifact) {
+	if got.ID != want.ID {
+		t.Errorf("Want ID %d, got %d", want.ID, got.ID)
+	}
+	if got.BuildID != want.BuildID {
+		t.Errorf("Want BuildID %d, got %d", want.BuildID, got.BuildID)
+	}
+	if got.StageID != want.StageID {
+		t.Errorf("Want StageID %d, got %d", want.StageID, got.StageID)
+	}
+	if got.StepID != want.StepID {
+		t.Errorf("Want StepID %d, got %d", want.StepID, got.StepID)
+	}
+	if got.Name != want.Name {
+		t.Errorf("Want Name %q, got %q", want.Name, got.Name)
+	}
+	if got.Size != want.Size {
+		t.Errorf("Want Size %d, got %d", want.Size, got.Size)
+	}
+}
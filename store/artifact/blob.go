@@ -0,0 +1,144 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/drone/drone/store/shared/db"
+)
+
+// dbBlobs persists the artifact contents to the database.
+type dbBlobs struct {
+	db *db.DB
+}
+
+func (s *dbBlobs) find(ctx context.Context, id int64) (io.ReadCloser, error) {
+	out := &blob{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		query, args, err := binder.BindNamed(queryData, out)
+		if err != nil {
+			return err
+		}
+		return queryer.QueryRow(query, args...).Scan(&out.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(
+		bytes.NewBuffer(out.Data),
+	), nil
+}
+
+func (s *dbBlobs) create(ctx context.Context, id int64, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := &blob{
+			ID:   id,
+			Data: data,
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertData, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *dbBlobs) delete(ctx context.Context, id int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := &blob{ID: id}
+		stmt, args, err := binder.BindNamed(stmtDeleteData, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+type blob struct {
+	ID   int64  `db:"artifact_data_id"`
+	Data []byte `db:"artifact_data"`
+}
+
+const queryData = `
+SELECT artifact_data
+FROM artifact_data
+WHERE artifact_data_id = :artifact_data_id
+`
+
+const stmtInsertData = `
+INSERT INTO artifact_data (
+ artifact_data_id
+,artifact_data
+) VALUES (
+ :artifact_data_id
+,:artifact_data
+)
+`
+
+const stmtDeleteData = `
+DELETE FROM artifact_data
+WHERE artifact_data_id = :artifact_data_id
+`
+
+// fsBlobs persists the artifact contents to the local
+// filesystem.
+type fsBlobs struct {
+	root string
+}
+
+func (s *fsBlobs) find(ctx context.Context, id int64) (io.ReadCloser, error) {
+	return os.Open(s.path(id))
+}
+
+func (s *fsBlobs) create(ctx context.Context, id int64, r io.Reader) error {
+	err := os.MkdirAll(s.root, 0700)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(s.path(id))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *fsBlobs) delete(ctx context.Context, id int64) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *fsBlobs) path(id int64) string {
+	return filepath.Join(s.root, fmt.Sprint(id))
+}
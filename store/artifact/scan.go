@@ -0,0 +1,69 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Artifact structure to a set
+// of named query parameters.
+func toParams(artifact *core.Artifact) map[string]interface{} {
+	return map[string]interface{}{
+		"artifact_id":       artifact.ID,
+		"artifact_repo_id":  artifact.RepoID,
+		"artifact_build_id": artifact.BuildID,
+		"artifact_stage_id": artifact.StageID,
+		"artifact_step_id":  artifact.StepID,
+		"artifact_name":     artifact.Name,
+		"artifact_size":     artifact.Size,
+		"artifact_created":  artifact.Created,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Artifact) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.BuildID,
+		&dst.StageID,
+		&dst.StepID,
+		&dst.Name,
+		&dst.Size,
+		&dst.Created,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Artifact, error) {
+	defer rows.Close()
+
+	artifacts := []*core.Artifact{}
+	for rows.Next() {
+		artifact := new(core.Artifact)
+		err := scanRow(rows, artifact)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, nil
+}
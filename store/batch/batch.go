@@ -178,6 +178,7 @@ const stmtInsertBase = `
 ,repo_branch
 ,repo_counter
 ,repo_config
+,repo_config_fallback
 ,repo_timeout
 ,repo_trusted
 ,repo_protected
@@ -205,6 +206,7 @@ const stmtInsertBase = `
 ,:repo_branch
 ,:repo_counter
 ,:repo_config
+,:repo_config_fallback
 ,:repo_timeout
 ,:repo_trusted
 ,:repo_protected
@@ -293,11 +293,67 @@ func (s *buildStore) Count(ctx context.Context) (i int64, err error) {
 	return
 }
 
+// CountStatus returns a count of builds created since the
+// given timestamp, grouped by status and event.
+func (s *buildStore) CountStatus(ctx context.Context, since int64) ([]*core.BuildCount, error) {
+	var out []*core.BuildCount
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"build_created": since}
+		stmt, args, err := binder.BindNamed(queryCountStatus, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			count := new(core.BuildCount)
+			err := rows.Scan(&count.Status, &count.Event, &count.Count)
+			if err != nil {
+				return err
+			}
+			out = append(out, count)
+		}
+		return rows.Err()
+	})
+	return out, err
+}
+
+// CountRepos returns a count of distinct repositories with
+// builds created since the given timestamp.
+func (s *buildStore) CountRepos(ctx context.Context, since int64) (i int64, err error) {
+	err = s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"build_created": since}
+		query, args, err := binder.BindNamed(queryCountRepos, params)
+		if err != nil {
+			return err
+		}
+		return queryer.QueryRow(query, args...).Scan(&i)
+	})
+	return
+}
+
 const queryCount = `
 SELECT COUNT(*)
 FROM builds
 `
 
+const queryCountStatus = `
+SELECT build_status, build_event, COUNT(*)
+FROM builds
+WHERE build_created >= :build_created
+GROUP BY build_status, build_event
+ORDER BY build_status, build_event
+`
+
+const queryCountRepos = `
+SELECT COUNT(DISTINCT build_repo_id)
+FROM builds
+WHERE build_created >= :build_created
+`
+
 const queryBase = `
 SELECT
  build_id
@@ -327,6 +383,7 @@ SELECT
 ,build_params
 ,build_cron
 ,build_deploy
+,build_trace
 ,build_started
 ,build_finished
 ,build_created
@@ -427,6 +484,7 @@ UPDATE builds SET
 ,build_params = :build_params
 ,build_cron = :build_cron
 ,build_deploy = :build_deploy
+,build_trace = :build_trace
 ,build_started = :build_started
 ,build_finished = :build_finished
 ,build_updated = :build_updated
@@ -463,6 +521,7 @@ INSERT INTO builds (
 ,build_params
 ,build_cron
 ,build_deploy
+,build_trace
 ,build_started
 ,build_finished
 ,build_created
@@ -495,6 +554,7 @@ INSERT INTO builds (
 ,:build_params
 ,:build_cron
 ,:build_deploy
+,:build_trace
 ,:build_started
 ,:build_finished
 ,:build_created
@@ -13,6 +13,7 @@ import (
 	"github.com/drone/drone/core"
 
 	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/google/go-cmp/cmp"
 )
 
 var noContext = context.TODO()
@@ -32,6 +33,8 @@ func TestBuild(t *testing.T) {
 	t.Run("Create", testBuildCreate(store))
 	t.Run("Purge", testBuildPurge(store))
 	t.Run("Count", testBuildCount(store))
+	t.Run("CountStatus", testBuildCountStatus(store))
+	t.Run("Stats", testBuildStats(store))
 	t.Run("Pending", testBuildPending(store))
 	t.Run("Running", testBuildRunning(store))
 }
@@ -42,6 +45,7 @@ func testBuildCreate(store *buildStore) func(t *testing.T) {
 			RepoID: 1,
 			Number: 99,
 			Ref:    "refs/heads/master",
+			Trace:  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
 		}
 		stage := &core.Stage{
 			RepoID: 42,
@@ -253,6 +257,149 @@ func testBuildCount(store *buildStore) func(t *testing.T) {
 	}
 }
 
+func testBuildCountStatus(store *buildStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.db.Update(func(execer db.Execer, binder db.Binder) error {
+			_, err := execer.Exec("DELETE FROM builds")
+			return err
+		})
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 1, Status: core.StatusPassing, Event: core.EventPush, Created: 100}, nil)
+		store.Create(noContext, &core.Build{RepoID: 1, Number: 2, Status: core.StatusPassing, Event: core.EventPush, Created: 200}, nil)
+		store.Create(noContext, &core.Build{RepoID: 2, Number: 1, Status: core.StatusFailing, Event: core.EventPullRequest, Created: 300}, nil)
+		store.Create(noContext, &core.Build{RepoID: 3, Number: 1, Status: core.StatusPassing, Event: core.EventPush, Created: 10}, nil)
+
+		counts, err := store.CountStatus(noContext, 100)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := []*core.BuildCount{
+			{Status: core.StatusFailing, Event: core.EventPullRequest, Count: 1},
+			{Status: core.StatusPassing, Event: core.EventPush, Count: 2},
+		}
+		if diff := cmp.Diff(counts, want); diff != "" {
+			t.Errorf(diff)
+		}
+
+		repos, err := store.CountRepos(noContext, 100)
+		if err != nil {
+			t.Error(err)
+		} else if got, want := repos, int64(2); got != want {
+			t.Errorf("Want repo count %d, got %d", want, got)
+		}
+	}
+}
+
+func testBuildStats(store *buildStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		store.db.Update(func(execer db.Execer, binder db.Binder) error {
+			_, err := execer.Exec("DELETE FROM builds")
+			return err
+		})
+		builds := []*core.Build{
+			{Number: 1, Target: "master", Event: core.EventPush, Status: core.StatusPassing, Created: 100, Started: 100, Finished: 160},
+			{Number: 2, Target: "master", Event: core.EventPush, Status: core.StatusFailing, Created: 200, Started: 200, Finished: 230},
+			{Number: 3, Target: "master", Event: core.EventPush, Status: core.StatusFailing, Created: 300, Started: 300, Finished: 330},
+			{Number: 4, Target: "master", Event: core.EventPush, Status: core.StatusPassing, Created: 400, Started: 400, Finished: 520},
+			{Number: 5, Target: "master", Event: core.EventPush, Status: core.StatusError, Created: 500, Started: 500, Finished: 510},
+			{Number: 6, Target: "master", Event: core.EventPush, Status: core.StatusKilled, Created: 600, Finished: 600},
+			{Number: 7, Target: "develop", Event: core.EventPush, Status: core.StatusPassing, Created: 150, Started: 150, Finished: 200},
+			{Number: 8, Target: "master", Event: core.EventPush, Status: core.StatusRunning, Created: 700, Started: 700},
+			{Number: 9, Target: "master", Event: core.EventPullRequest, Status: core.StatusFailing, Created: 250, Started: 250, Finished: 260},
+		}
+		// the test pipeline passes in build 3, which fails
+		// due to another pipeline.
+		stages := map[int64][]*core.Stage{
+			1: {{Number: 1, Name: "test", Status: core.StatusPassing, Stopped: 160}},
+			2: {{Number: 1, Name: "test", Status: core.StatusFailing, Stopped: 230}},
+			3: {{Number: 1, Name: "test", Status: core.StatusPassing, Stopped: 330}},
+			4: {{Number: 1, Name: "test", Status: core.StatusPassing, Stopped: 520}},
+			5: {{Number: 1, Name: "test", Status: core.StatusError, Stopped: 510}},
+			9: {{Number: 1, Name: "test", Status: core.StatusFailing, Stopped: 260}},
+		}
+		for _, build := range builds {
+			build.RepoID = 1
+			for _, stage := range stages[build.Number] {
+				stage.RepoID = 1
+			}
+			store.Create(noContext, build, stages[build.Number])
+		}
+
+		tests := []struct {
+			filter *core.AnalyticsFilter
+			want   *core.BuildStats
+		}{
+			{
+				filter: &core.AnalyticsFilter{Repo: 1, Since: 0, Until: 1000},
+				want: &core.BuildStats{
+					Total:      8,
+					Passed:     3,
+					Failed:     4,
+					Duration:   core.Percentiles{P50: 30, P90: 120, P99: 120},
+					Recoveries: 1,
+					MTTR:       290,
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 1, Branch: "master", Pipeline: "test", Since: 0, Until: 1000},
+				want: &core.BuildStats{
+					Total:      5,
+					Passed:     3,
+					Failed:     2,
+					Duration:   core.Percentiles{P50: 30, P90: 120, P99: 120},
+					Recoveries: 1,
+					MTTR:       100,
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 1, Pipeline: "test", Since: 0, Until: 1000},
+				want: &core.BuildStats{
+					Total:      6,
+					Passed:     3,
+					Failed:     3,
+					Duration:   core.Percentiles{P50: 30, P90: 120, P99: 120},
+					Recoveries: 1,
+					MTTR:       100,
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 1, Branch: "master", Since: 0, Until: 1000},
+				want: &core.BuildStats{
+					Total:      6,
+					Passed:     2,
+					Failed:     3,
+					Duration:   core.Percentiles{P50: 30, P90: 120, P99: 120},
+					Recoveries: 1,
+					MTTR:       290,
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 1, Since: 350, Until: 1000},
+				want: &core.BuildStats{
+					Total:    3,
+					Passed:   1,
+					Failed:   1,
+					Duration: core.Percentiles{P50: 10, P90: 120, P99: 120},
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 2, Since: 0, Until: 1000},
+				want:   &core.BuildStats{},
+			},
+		}
+		for _, test := range tests {
+			got, err := store.Stats(noContext, test.filter)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf(diff)
+			}
+		}
+	}
+}
+
 func testBuildPending(store *buildStore) func(t *testing.T) {
 	return func(t *testing.T) {
 		store.db.Update(func(execer db.Execer, binder db.Binder) error {
@@ -318,5 +465,8 @@ func testBuild(item *core.Build) func(t *testing.T) {
 		if got, want := item.Ref, "refs/heads/master"; got != want {
 			t.Errorf("Want build ref %q, got %q", want, got)
 		}
+		if got, want := item.Trace, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; got != want {
+			t.Errorf("Want build trace %q, got %q", want, got)
+		}
 	}
 }
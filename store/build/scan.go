@@ -55,6 +55,7 @@ func toParams(build *core.Build) map[string]interface{} {
 		"build_params":        encodeParams(build.Params),
 		"build_cron":          build.Cron,
 		"build_deploy":        build.Deploy,
+		"build_trace":         build.Trace,
 		"build_started":       build.Started,
 		"build_finished":      build.Finished,
 		"build_created":       build.Created,
@@ -138,6 +139,7 @@ func scanRow(scanner db.Scanner, dest *core.Build) error {
 		&paramsJSON,
 		&dest.Cron,
 		&dest.Deploy,
+		&dest.Trace,
 		&dest.Started,
 		&dest.Finished,
 		&dest.Created,
@@ -0,0 +1,239 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// Stats returns aggregate build statistics for the repository,
+// branch and time window of the filter. If the filter includes
+// a pipeline, the success rate and time to recovery are
+// measured using the status of the named pipeline.
+func (s *buildStore) Stats(ctx context.Context, filter *core.AnalyticsFilter) (*core.BuildStats, error) {
+	out := new(core.BuildStats)
+	params := map[string]interface{}{
+		"build_repo_id": filter.Repo,
+		"build_target":  filter.Branch,
+		"stage_name":    filter.Pipeline,
+		"since":         filter.Since,
+		"until":         filter.Until,
+	}
+	where := queryStatsWhere
+	if filter.Branch != "" {
+		where = where + queryStatsBranch
+	}
+	count := queryStatsCount + where + queryStatsFinished
+	recovery := queryStatsRecovery + where + queryStatsRecoveryWhere
+	if filter.Pipeline != "" {
+		count = queryStatsPipelineCount + where + queryStatsPipelineFinished
+		recovery = queryStatsPipelineRecovery + where + queryStatsPipelineRecoveryWhere
+	}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		query, args, err := binder.BindNamed(count, params)
+		if err != nil {
+			return err
+		}
+		err = queryer.QueryRow(query, args...).Scan(
+			&out.Total,
+			&out.Passed,
+			&out.Failed,
+		)
+		if err != nil {
+			return err
+		}
+
+		durations, err := db.Percentiles(queryer, binder,
+			queryStatsDurationCount+where+queryStatsDurationWhere,
+			queryStatsDuration+where+queryStatsDurationWhere+queryStatsRank,
+			params, 50, 90, 99,
+		)
+		if err != nil {
+			return err
+		}
+		out.Duration.P50 = durations[0]
+		out.Duration.P90 = durations[1]
+		out.Duration.P99 = durations[2]
+
+		var total int64
+		query, args, err = binder.BindNamed(recovery, params)
+		if err != nil {
+			return err
+		}
+		err = queryer.QueryRow(query, args...).Scan(&out.Recoveries, &total)
+		if err != nil {
+			return err
+		}
+		if out.Recoveries != 0 {
+			out.MTTR = total / out.Recoveries
+		}
+		return nil
+	})
+	return out, err
+}
+
+const queryStatsWhere = `
+WHERE build_repo_id = :build_repo_id
+AND build_created >= :since
+AND build_created < :until
+`
+
+// queryStatsBranch limits the statistics to builds of the
+// branch. Pull request builds are excluded since they are
+// not builds of the target branch.
+const queryStatsBranch = `
+AND build_target = :build_target
+AND build_event IN ('push', 'cron', 'custom')
+`
+
+const queryStatsFinished = `
+AND build_status IN ('success', 'failure', 'error', 'killed')
+`
+
+const queryStatsCount = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(CASE WHEN build_status = 'success' THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN build_status IN ('failure', 'error') THEN 1 ELSE 0 END), 0)
+FROM builds
+`
+
+const queryStatsDurationCount = `
+SELECT COUNT(*)
+FROM builds
+`
+
+const queryStatsDuration = `
+SELECT build_finished - build_started
+FROM builds
+`
+
+const queryStatsDurationWhere = queryStatsFinished + `
+AND build_started > 0
+AND build_finished >= build_started
+`
+
+const queryStatsRank = `
+ORDER BY 1
+LIMIT 1 OFFSET :offset
+`
+
+// queryStatsRecovery returns the number of recoveries and
+// the total time to recovery. A recovery is measured from
+// the first failed build of a failure streak on a branch to
+// the next successful build on the same branch. Pull request
+// builds are excluded from the failure streaks.
+const queryStatsRecovery = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(recovered - failed), 0)
+FROM (
+SELECT
+ build_finished AS failed
+,(
+	SELECT MIN(succ.build_finished)
+	FROM builds succ
+	WHERE succ.build_repo_id = builds.build_repo_id
+	AND succ.build_target = builds.build_target
+	AND succ.build_event IN ('push', 'cron', 'custom')
+	AND succ.build_number > builds.build_number
+	AND succ.build_status = 'success'
+) AS recovered
+FROM builds
+`
+
+const queryStatsRecoveryWhere = `
+AND build_event IN ('push', 'cron', 'custom')
+AND build_status IN ('failure', 'error')
+AND COALESCE((
+	SELECT prev.build_status
+	FROM builds prev
+	WHERE prev.build_repo_id = builds.build_repo_id
+	AND prev.build_target = builds.build_target
+	AND prev.build_event IN ('push', 'cron', 'custom')
+	AND prev.build_number < builds.build_number
+	AND prev.build_status IN ('success', 'failure', 'error')
+	ORDER BY prev.build_number DESC
+	LIMIT 1
+), 'success') = 'success'
+) recovery
+WHERE recovered IS NOT NULL
+AND recovered >= failed
+`
+
+const queryStatsPipelineFinished = `
+AND stage_name = :stage_name
+AND stage_status IN ('success', 'failure', 'error', 'killed')
+`
+
+const queryStatsPipelineCount = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(CASE WHEN stage_status = 'success' THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN stage_status IN ('failure', 'error') THEN 1 ELSE 0 END), 0)
+FROM stages
+INNER JOIN builds ON builds.build_id = stages.stage_build_id
+`
+
+// queryStatsPipelineRecovery returns the number of recoveries
+// and the total time to recovery of the pipeline, measured
+// from the first failed stage of a failure streak on a branch
+// to the next successful stage on the same branch.
+const queryStatsPipelineRecovery = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(recovered - failed), 0)
+FROM (
+SELECT
+ stage_stopped AS failed
+,(
+	SELECT MIN(succ.stage_stopped)
+	FROM stages succ
+	INNER JOIN builds succ_build ON succ_build.build_id = succ.stage_build_id
+	WHERE succ_build.build_repo_id = builds.build_repo_id
+	AND succ_build.build_target = builds.build_target
+	AND succ_build.build_event IN ('push', 'cron', 'custom')
+	AND succ_build.build_number > builds.build_number
+	AND succ.stage_name = stages.stage_name
+	AND succ.stage_status = 'success'
+) AS recovered
+FROM stages
+INNER JOIN builds ON builds.build_id = stages.stage_build_id
+`
+
+const queryStatsPipelineRecoveryWhere = `
+AND build_event IN ('push', 'cron', 'custom')
+AND stage_name = :stage_name
+AND stage_status IN ('failure', 'error')
+AND COALESCE((
+	SELECT prev.stage_status
+	FROM stages prev
+	INNER JOIN builds prev_build ON prev_build.build_id = prev.stage_build_id
+	WHERE prev_build.build_repo_id = builds.build_repo_id
+	AND prev_build.build_target = builds.build_target
+	AND prev_build.build_event IN ('push', 'cron', 'custom')
+	AND prev_build.build_number < builds.build_number
+	AND prev.stage_name = stages.stage_name
+	AND prev.stage_status IN ('success', 'failure', 'error')
+	ORDER BY prev_build.build_number DESC
+	LIMIT 1
+), 'success') = 'success'
+) recovery
+WHERE recovered IS NOT NULL
+AND recovered >= failed
+`
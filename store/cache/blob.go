@@ -0,0 +1,61 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// fsBlobs persists the cache contents to the local
+// filesystem.
+type fsBlobs struct {
+	root string
+}
+
+func (s *fsBlobs) find(ctx context.Context, id int64) (io.ReadCloser, error) {
+	return os.Open(s.path(id))
+}
+
+func (s *fsBlobs) create(ctx context.Context, id int64, r io.Reader) error {
+	err := os.MkdirAll(s.root, 0700)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(s.path(id))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (s *fsBlobs) delete(ctx context.Context, id int64) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *fsBlobs) path(id int64) string {
+	return filepath.Join(s.root, fmt.Sprint(id))
+}
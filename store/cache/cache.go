@@ -0,0 +1,329 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// NewFS returns a new CacheStore that persists the cache
+// contents to the local filesystem, in the root directory.
+// The quota defines the maximum combined size of caches for
+// a repository, in bytes, where zero is unlimited.
+func NewFS(db *db.DB, root string, quota int64) core.CacheStore {
+	return &cacheStore{
+		db:    db,
+		blobs: &fsBlobs{root},
+		quota: quota,
+	}
+}
+
+// blobStore persists the cache contents to storage.
+type blobStore interface {
+	find(ctx context.Context, id int64) (io.ReadCloser, error)
+	create(ctx context.Context, id int64, r io.Reader) error
+	delete(ctx context.Context, id int64) error
+}
+
+type cacheStore struct {
+	db    *db.DB
+	blobs blobStore
+	quota int64
+}
+
+func (s *cacheStore) List(ctx context.Context, repo int64) ([]*core.Cache, error) {
+	var out []*core.Cache
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"cache_repo_id": repo}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *cacheStore) Find(ctx context.Context, repo int64, key string, prefixes []string) (*core.Cache, error) {
+	out := &core.Cache{RepoID: repo, Key: key}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	if err != sql.ErrNoRows || len(prefixes) == 0 {
+		return out, err
+	}
+
+	// if there is no exact match, fallback to the most
+	// recently saved cache matching a key prefix. The list
+	// is sorted by creation date, most recent first.
+	caches, err := s.List(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, prefix := range prefixes {
+		for _, cache := range caches {
+			if strings.HasPrefix(cache.Key, prefix) {
+				return cache, nil
+			}
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (s *cacheStore) Open(ctx context.Context, cache *core.Cache) (io.ReadCloser, error) {
+	rc, err := s.blobs.find(ctx, cache.ID)
+	if err != nil {
+		return nil, err
+	}
+	cache.Accessed = time.Now().Unix()
+	err = s.update(ctx, cache)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return rc, nil
+}
+
+func (s *cacheStore) Save(ctx context.Context, cache *core.Cache, r io.Reader) error {
+	// a cache saved with the same key as an existing cache
+	// replaces the existing cache.
+	if existing, err := s.Find(ctx, cache.RepoID, cache.Key, nil); err == nil {
+		if err := s.Delete(ctx, existing); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().Unix()
+	cache.Created = now
+	cache.Accessed = now
+	cache.Size = 0
+
+	var err error
+	if s.db.Driver() == db.Postgres {
+		err = s.createPostgres(ctx, cache)
+	} else {
+		err = s.create(ctx, cache)
+	}
+	if err != nil {
+		return err
+	}
+
+	counter := &countingReader{reader: r}
+	err = s.blobs.create(ctx, cache.ID, counter)
+	if err != nil {
+		s.deleteRow(ctx, cache)
+		return err
+	}
+	cache.Size = counter.count
+	err = s.update(ctx, cache)
+	if err != nil {
+		return err
+	}
+	return s.evict(ctx, cache)
+}
+
+// evict deletes the least recently used caches for the
+// repository until the repository is within its quota. The
+// cache that was most recently saved is never evicted.
+func (s *cacheStore) evict(ctx context.Context, saved *core.Cache) error {
+	if s.quota <= 0 {
+		return nil
+	}
+	caches, err := s.List(ctx, saved.RepoID)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, cache := range caches {
+		total += cache.Size
+	}
+	sort.SliceStable(caches, func(i, j int) bool {
+		return caches[i].Accessed < caches[j].Accessed
+	})
+	for _, cache := range caches {
+		if total <= s.quota {
+			break
+		}
+		if cache.ID == saved.ID {
+			continue
+		}
+		err := s.Delete(ctx, cache)
+		if err != nil {
+			return err
+		}
+		total -= cache.Size
+	}
+	return nil
+}
+
+func (s *cacheStore) Delete(ctx context.Context, cache *core.Cache) error {
+	err := s.blobs.delete(ctx, cache.ID)
+	if err != nil {
+		return err
+	}
+	return s.deleteRow(ctx, cache)
+}
+
+func (s *cacheStore) Clear(ctx context.Context, repo int64) error {
+	caches, err := s.List(ctx, repo)
+	if err != nil {
+		return err
+	}
+	for _, cache := range caches {
+		err := s.Delete(ctx, cache)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *cacheStore) create(ctx context.Context, cache *core.Cache) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(cache)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		cache.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *cacheStore) createPostgres(ctx context.Context, cache *core.Cache) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(cache)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&cache.ID)
+	})
+}
+
+func (s *cacheStore) update(ctx context.Context, cache *core.Cache) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(cache)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *cacheStore) deleteRow(ctx context.Context, cache *core.Cache) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(cache)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+// countingReader counts the number of bytes read from the
+// underlying reader.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
+
+const queryBase = `
+SELECT
+ cache_id
+,cache_repo_id
+,cache_key
+,cache_size
+,cache_created
+,cache_accessed
+`
+
+const queryKey = queryBase + `
+FROM caches
+WHERE cache_repo_id = :cache_repo_id
+  AND cache_key = :cache_key
+`
+
+const queryRepo = queryBase + `
+FROM caches
+WHERE cache_repo_id = :cache_repo_id
+ORDER BY cache_created DESC, cache_id DESC
+`
+
+const stmtUpdate = `
+UPDATE caches
+SET
+ cache_size = :cache_size
+,cache_accessed = :cache_accessed
+WHERE cache_id = :cache_id
+`
+
+const stmtDelete = `
+DELETE FROM caches
+WHERE cache_id = :cache_id
+`
+
+const stmtInsert = `
+INSERT INTO caches (
+ cache_repo_id
+,cache_key
+,cache_size
+,cache_created
+,cache_accessed
+) VALUES (
+ :cache_repo_id
+,:cache_key
+,:cache_size
+,:cache_created
+,:cache_accessed
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING cache_id
+`
@@ -0,0 +1,175 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestCache(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	dir, err := ioutil.TempDir("", "caches")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewFS(conn, dir, 0).(*cacheStore)
+
+	cache := &core.Cache{RepoID: 1, Key: "node_modules-master-a1b2c3"}
+	t.Run("Save", func(t *testing.T) {
+		err := store.Save(noContext, cache, bytes.NewBufferString("hello world"))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if cache.ID == 0 {
+			t.Errorf("Want cache ID assigned")
+		}
+		if got, want := cache.Size, int64(11); got != want {
+			t.Errorf("Want cache size %d, got %d", want, got)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		result, err := store.Find(noContext, 1, cache.Key, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := result.ID, cache.ID; got != want {
+			t.Errorf("Want cache ID %d, got %d", want, got)
+		}
+	})
+
+	t.Run("FindPrefix", func(t *testing.T) {
+		result, err := store.Find(noContext, 1, "node_modules-develop-d4e5f6", []string{"node_modules-develop-", "node_modules-"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := result.ID, cache.ID; got != want {
+			t.Errorf("Want cache ID %d, got %d", want, got)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := store.Find(noContext, 1, "vendor-master", []string{"vendor-"})
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		rc, err := store.Open(noContext, cache)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer rc.Close()
+		data, _ := ioutil.ReadAll(rc)
+		if got, want := string(data), "hello world"; got != want {
+			t.Errorf("Want cache contents %q, got %q", want, got)
+		}
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		before := cache.ID
+		err := store.Save(noContext, cache, bytes.NewBufferString("hola mundo"))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d caches, got %d", want, got)
+		}
+		if _, err := os.Stat(store.blobs.(*fsBlobs).path(before)); !os.IsNotExist(err) {
+			t.Errorf("Want replaced cache removed from the filesystem")
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		err := store.Clear(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, _ := store.List(noContext, 1)
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d caches, got %d", want, got)
+		}
+	})
+}
+
+func TestCache_Evict(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	dir, err := ioutil.TempDir("", "caches")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewFS(conn, dir, 20).(*cacheStore)
+
+	a := &core.Cache{RepoID: 1, Key: "a"}
+	b := &core.Cache{RepoID: 1, Key: "b"}
+	c := &core.Cache{RepoID: 1, Key: "c"}
+	store.Save(noContext, a, bytes.NewBufferString("0123456789"))
+	store.Save(noContext, b, bytes.NewBufferString("0123456789"))
+
+	// access cache a, so that cache b is the least
+	// recently used cache.
+	a.Accessed = b.Accessed + 1
+	store.update(noContext, a)
+
+	err = store.Save(noContext, c, bytes.NewBufferString("0123456789"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := store.Find(noContext, 1, "b", nil); err != sql.ErrNoRows {
+		t.Errorf("Want least recently used cache evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := store.Find(noContext, 1, key, nil); err != nil {
+			t.Errorf("Want cache %s retained, got error %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// NewS3Env returns a new CacheStore that persists the cache
+// contents to S3. The quota defines the maximum combined size
+// of caches for a repository, in bytes, where zero is unlimited.
+func NewS3Env(db *db.DB, bucket, prefix, endpoint string, pathStyle bool, quota int64) core.CacheStore {
+	disableSSL := false
+
+	if endpoint != "" {
+		disableSSL = !strings.HasPrefix(endpoint, "https://")
+	}
+
+	return &cacheStore{
+		db:    db,
+		quota: quota,
+		blobs: &s3Blobs{
+			bucket: bucket,
+			prefix: prefix,
+			session: session.Must(
+				session.NewSession(&aws.Config{
+					Endpoint:         aws.String(endpoint),
+					DisableSSL:       aws.Bool(disableSSL),
+					S3ForcePathStyle: aws.Bool(pathStyle),
+				}),
+			),
+		},
+	}
+}
+
+type s3Blobs struct {
+	bucket  string
+	prefix  string
+	session *session.Session
+}
+
+func (s *s3Blobs) find(ctx context.Context, id int64) (io.ReadCloser, error) {
+	svc := s3.New(s.session)
+	out, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(id)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *s3Blobs) create(ctx context.Context, id int64, r io.Reader) error {
+	uploader := s3manager.NewUploader(s.session)
+	input := &s3manager.UploadInput{
+		ACL:    aws.String("private"),
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(id)),
+		Body:   r,
+	}
+	_, err := uploader.Upload(input)
+	return err
+}
+
+func (s *s3Blobs) delete(ctx context.Context, id int64) error {
+	svc := s3.New(s.session)
+	_, err := svc.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(id)),
+	})
+	return err
+}
+
+func (s *s3Blobs) key(id int64) string {
+	return path.Join("/", s.prefix, fmt.Sprint(id))
+}
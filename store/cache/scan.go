@@ -0,0 +1,65 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Cache structure to a set
+// of named query parameters.
+func toParams(cache *core.Cache) map[string]interface{} {
+	return map[string]interface{}{
+		"cache_id":       cache.ID,
+		"cache_repo_id":  cache.RepoID,
+		"cache_key":      cache.Key,
+		"cache_size":     cache.Size,
+		"cache_created":  cache.Created,
+		"cache_accessed": cache.Accessed,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Cache) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Key,
+		&dst.Size,
+		&dst.Created,
+		&dst.Accessed,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Cache, error) {
+	defer rows.Close()
+
+	caches := []*core.Cache{}
+	for rows.Next() {
+		cache := new(core.Cache)
+		err := scanRow(rows, cache)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, cache)
+	}
+	return caches, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new CentralConfig database store.
+func New(db *db.DB) core.CentralConfigStore {
+	return &centralStore{db}
+}
+
+type centralStore struct {
+	db *db.DB
+}
+
+func (s *centralStore) Find(ctx context.Context, namespace string) (*core.CentralConfig, error) {
+	out := &core.CentralConfig{Namespace: namespace}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *centralStore) Create(ctx context.Context, config *core.CentralConfig) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, config)
+	}
+	return s.create(ctx, config)
+}
+
+func (s *centralStore) create(ctx context.Context, config *core.CentralConfig) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(config)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		config.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *centralStore) createPostgres(ctx context.Context, config *core.CentralConfig) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(config)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&config.ID)
+	})
+}
+
+func (s *centralStore) Update(ctx context.Context, config *core.CentralConfig) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(config)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *centralStore) Delete(ctx context.Context, config *core.CentralConfig) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(config)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryNamespace = `
+SELECT
+ central_id
+,central_namespace
+,central_path
+,central_created
+,central_updated
+FROM central_configs
+WHERE central_namespace = :central_namespace
+LIMIT 1
+`
+
+const stmtInsert = `
+INSERT INTO central_configs (
+ central_namespace
+,central_path
+,central_created
+,central_updated
+) VALUES (
+ :central_namespace
+,:central_path
+,:central_created
+,:central_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING central_id
+`
+
+const stmtUpdate = `
+UPDATE central_configs SET
+ central_path = :central_path
+,central_updated = :central_updated
+WHERE central_id = :central_id
+`
+
+const stmtDelete = `
+DELETE FROM central_configs
+WHERE central_id = :central_id
+`
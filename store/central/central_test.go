@@ -0,0 +1,118 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestCentral(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*centralStore)
+	t.Run("Create", testCentralCreate(store))
+}
+
+func testCentralCreate(store *centralStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.CentralConfig{
+			Namespace: "octocat",
+			Path:      "octocat/drone-config/${DRONE_REPO_NAME}.yml",
+			Created:   1,
+			Updated:   1,
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want central config ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testCentralFind(store))
+		t.Run("Update", testCentralUpdate(store))
+		t.Run("Delete", testCentralDelete(store))
+	}
+}
+
+func testCentralFind(store *centralStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := item.Path, "octocat/drone-config/${DRONE_REPO_NAME}.yml"; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := item.Created, int64(1); got != want {
+			t.Errorf("Want created %d, got %d", want, got)
+		}
+	}
+}
+
+func testCentralUpdate(store *centralStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		before, err := store.Find(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		before.Path = "octocat/drone-config@develop/.drone.yml"
+		before.Updated = 2
+		err = store.Update(noContext, before)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		after, err := store.Find(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := after.Path, before.Path; got != want {
+			t.Errorf("Want path %q, got %q", want, got)
+		}
+		if got, want := after.Updated, int64(2); got != want {
+			t.Errorf("Want updated %d, got %d", want, got)
+		}
+	}
+}
+
+func testCentralDelete(store *centralStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = store.Delete(noContext, item)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, "octocat")
+		if got, want := err, sql.ErrNoRows; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+		}
+	}
+}
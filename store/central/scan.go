@@ -0,0 +1,36 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package central
+
+import (
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the CentralConfig structure to a
+// set of named query parameters.
+func toParams(config *core.CentralConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"central_id":        config.ID,
+		"central_namespace": config.Namespace,
+		"central_path":      config.Path,
+		"central_created":   config.Created,
+		"central_updated":   config.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.CentralConfig) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Namespace,
+		&dst.Path,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
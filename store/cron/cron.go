@@ -156,6 +156,7 @@ SELECT
 ,cron_event
 ,cron_branch
 ,cron_target
+,cron_params
 ,cron_disabled
 ,cron_created
 ,cron_updated
@@ -197,6 +198,7 @@ UPDATE cron SET
 ,cron_event = :cron_event
 ,cron_branch = :cron_branch
 ,cron_target = :cron_target
+,cron_params = :cron_params
 ,cron_disabled = :cron_disabled
 ,cron_created = :cron_created
 ,cron_updated = :cron_updated
@@ -219,6 +221,7 @@ INSERT INTO cron (
 ,cron_event
 ,cron_branch
 ,cron_target
+,cron_params
 ,cron_disabled
 ,cron_created
 ,cron_updated
@@ -232,6 +235,7 @@ INSERT INTO cron (
 ,:cron_event
 ,:cron_branch
 ,:cron_target
+,:cron_params
 ,:cron_disabled
 ,:cron_created
 ,:cron_updated
@@ -8,9 +8,12 @@ package cron
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
 )
 
 // helper function converts the User structure to a set
@@ -26,6 +29,7 @@ func toParams(cron *core.Cron) map[string]interface{} {
 		"cron_event":    cron.Event,
 		"cron_branch":   cron.Branch,
 		"cron_target":   cron.Target,
+		"cron_params":   encodeParams(cron.Params),
 		"cron_disabled": cron.Disabled,
 		"cron_created":  cron.Created,
 		"cron_updated":  cron.Updated,
@@ -33,10 +37,17 @@ func toParams(cron *core.Cron) map[string]interface{} {
 	}
 }
 
+// helper function encodes the cron parameters as json.
+func encodeParams(v map[string]string) types.JSONText {
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
 // helper function scans the sql.Row and copies the column
 // values to the destination object.
 func scanRow(scanner db.Scanner, dst *core.Cron) error {
-	return scanner.Scan(
+	paramsJSON := types.JSONText{}
+	err := scanner.Scan(
 		&dst.ID,
 		&dst.RepoID,
 		&dst.Name,
@@ -46,11 +57,15 @@ func scanRow(scanner db.Scanner, dst *core.Cron) error {
 		&dst.Event,
 		&dst.Branch,
 		&dst.Target,
+		&paramsJSON,
 		&dst.Disabled,
 		&dst.Created,
 		&dst.Updated,
 		&dst.Version,
 	)
+	dst.Params = map[string]string{}
+	json.Unmarshal(paramsJSON, &dst.Params)
+	return err
 }
 
 // helper function scans the sql.Row and copies the column
@@ -0,0 +1,318 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new WebhookDeliveryStore.
+func New(db *db.DB) core.WebhookDeliveryStore {
+	return &deliveryStore{db}
+}
+
+type deliveryStore struct {
+	db *db.DB
+}
+
+func (s *deliveryStore) List(ctx context.Context, limit, offset int) ([]*core.WebhookDelivery, error) {
+	var out []*core.WebhookDelivery
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"limit":  limit,
+			"offset": offset,
+		}
+		stmt, args, err := binder.BindNamed(queryList, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Pending(ctx context.Context, before int64) ([]*core.WebhookDelivery, error) {
+	out := []*core.WebhookDelivery{}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status": core.WebhookDeliveryPending,
+			"delivery_next":   before,
+		}
+		stmt, args, err := binder.BindNamed(queryPendingEndpoints, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		endpoints, err := scanEndpoints(rows)
+		if err != nil {
+			return err
+		}
+
+		// pending deliveries are queried per endpoint, oldest
+		// first, so that an endpoint with a large backlog does
+		// not starve the remaining endpoints.
+		for _, endpoint := range endpoints {
+			params["delivery_endpoint"] = endpoint
+			stmt, args, err := binder.BindNamed(queryPending, params)
+			if err != nil {
+				return err
+			}
+			rows, err := queryer.Query(stmt, args...)
+			if err != nil {
+				return err
+			}
+			deliveries, err := scanRows(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, deliveries...)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Claim(ctx context.Context, delivery *core.WebhookDelivery, until int64) (bool, error) {
+	var claimed bool
+	err := s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_id":     delivery.ID,
+			"delivery_status": core.WebhookDeliveryPending,
+			"delivery_next":   delivery.Next,
+			"delivery_until":  until,
+		}
+		stmt, args, err := binder.BindNamed(stmtClaim, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		effected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		claimed = effected == 1
+		return nil
+	})
+	if err == nil && claimed {
+		delivery.Next = until
+	}
+	return claimed, err
+}
+
+func (s *deliveryStore) Find(ctx context.Context, id int64) (*core.WebhookDelivery, error) {
+	out := &core.WebhookDelivery{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *deliveryStore) Create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, delivery)
+	}
+	return s.create(ctx, delivery)
+}
+
+func (s *deliveryStore) create(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		delivery.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *deliveryStore) createPostgres(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&delivery.ID)
+	})
+}
+
+func (s *deliveryStore) Update(ctx context.Context, delivery *core.WebhookDelivery) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(delivery)
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *deliveryStore) Purge(ctx context.Context, before int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"delivery_status":  core.WebhookDeliveryPending,
+			"delivery_updated": before,
+		}
+		stmt, args, err := binder.BindNamed(stmtPurge, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ delivery_id
+,delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_payload
+,delivery_status
+,delivery_attempts
+,delivery_code
+,delivery_latency
+,delivery_error
+,delivery_next
+,delivery_webhook_id
+,delivery_created
+,delivery_updated
+`
+
+const queryKey = queryBase + `
+FROM deliveries
+WHERE delivery_id = :delivery_id
+LIMIT 1
+`
+
+const queryList = queryBase + `
+FROM deliveries
+ORDER BY delivery_id DESC
+LIMIT :limit OFFSET :offset
+`
+
+const queryPendingEndpoints = `
+SELECT delivery_endpoint
+FROM deliveries
+WHERE delivery_status = :delivery_status
+  AND delivery_next <= :delivery_next
+GROUP BY delivery_endpoint
+ORDER BY MIN(delivery_id)
+LIMIT 100
+`
+
+const queryPending = queryBase + `
+FROM deliveries
+WHERE delivery_endpoint = :delivery_endpoint
+  AND delivery_status = :delivery_status
+  AND delivery_next <= :delivery_next
+ORDER BY delivery_id
+LIMIT 10
+`
+
+const stmtClaim = `
+UPDATE deliveries SET
+ delivery_next = :delivery_until
+WHERE delivery_id = :delivery_id
+  AND delivery_status = :delivery_status
+  AND delivery_next = :delivery_next
+`
+
+const stmtPurge = `
+DELETE FROM deliveries
+WHERE delivery_status != :delivery_status
+  AND delivery_updated < :delivery_updated
+`
+
+const stmtUpdate = `
+UPDATE deliveries SET
+ delivery_endpoint = :delivery_endpoint
+,delivery_event = :delivery_event
+,delivery_action = :delivery_action
+,delivery_payload = :delivery_payload
+,delivery_status = :delivery_status
+,delivery_attempts = :delivery_attempts
+,delivery_code = :delivery_code
+,delivery_latency = :delivery_latency
+,delivery_error = :delivery_error
+,delivery_next = :delivery_next
+,delivery_webhook_id = :delivery_webhook_id
+,delivery_created = :delivery_created
+,delivery_updated = :delivery_updated
+WHERE delivery_id = :delivery_id
+`
+
+const stmtInsert = `
+INSERT INTO deliveries (
+ delivery_endpoint
+,delivery_event
+,delivery_action
+,delivery_payload
+,delivery_status
+,delivery_attempts
+,delivery_code
+,delivery_latency
+,delivery_error
+,delivery_next
+,delivery_webhook_id
+,delivery_created
+,delivery_updated
+) VALUES (
+ :delivery_endpoint
+,:delivery_event
+,:delivery_action
+,:delivery_payload
+,:delivery_status
+,:delivery_attempts
+,:delivery_code
+,:delivery_latency
+,:delivery_error
+,:delivery_next
+,:delivery_webhook_id
+,:delivery_created
+,:delivery_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING delivery_id
+`
@@ -0,0 +1,236 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestDelivery_PendingFairness(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*deliveryStore)
+
+	// the first endpoint has a backlog that exceeds the
+	// per-endpoint limit, queued before the second endpoint.
+	for i := 0; i < 15; i++ {
+		store.Create(noContext, &core.WebhookDelivery{
+			Endpoint: "https://company.com/hooks",
+			Status:   core.WebhookDeliveryPending,
+		})
+	}
+	store.Create(noContext, &core.WebhookDelivery{
+		Endpoint: "https://example.com/hooks",
+		Status:   core.WebhookDeliveryPending,
+	})
+
+	list, err := store.Pending(noContext, 1000)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	counts := map[string]int{}
+	for _, delivery := range list {
+		counts[delivery.Endpoint]++
+	}
+	if got, want := counts["https://company.com/hooks"], 10; got != want {
+		t.Errorf("Want %d deliveries for the first endpoint, got %d", want, got)
+	}
+	if got, want := counts["https://example.com/hooks"], 1; got != want {
+		t.Errorf("Want %d deliveries for the second endpoint, got %d", want, got)
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*deliveryStore)
+
+	deliveries := []*core.WebhookDelivery{
+		{
+			Endpoint: "https://company.com/hooks",
+			Event:    core.WebhookEventBuild,
+			Action:   core.WebhookActionCreated,
+			Payload:  `{"action":"created"}`,
+			Status:   core.WebhookDeliveryPending,
+			Next:     1000,
+			Created:  1000,
+			Updated:  1000,
+		},
+		{
+			Endpoint: "https://company.com/hooks",
+			Event:    core.WebhookEventUser,
+			Action:   core.WebhookActionDeleted,
+			Payload:  `{"action":"deleted"}`,
+			Status:   core.WebhookDeliveryPending,
+			Next:     2000,
+			Webhook:  1,
+			Created:  1000,
+			Updated:  1000,
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, delivery := range deliveries {
+			err := store.Create(noContext, delivery)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if delivery.ID == 0 {
+				t.Errorf("Want delivery ID assigned")
+			}
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		got, err := store.Find(noContext, deliveries[0].ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, deliveries[0]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Pending", func(t *testing.T) {
+		list, err := store.Pending(noContext, 1500)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, deliveries[:1]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		delivery := deliveries[0]
+		delivery.Status = core.WebhookDeliverySuccess
+		delivery.Attempts = 1
+		delivery.Code = 200
+		delivery.Latency = 25
+		delivery.Updated = 1001
+		err := store.Update(noContext, delivery)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got, err := store.Find(noContext, delivery.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, delivery); diff != "" {
+			t.Errorf(diff)
+		}
+
+		list, err := store.Pending(noContext, 3000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, deliveries[1:]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Claim", func(t *testing.T) {
+		delivery := deliveries[1]
+		claimed, err := store.Claim(noContext, delivery, 4000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !claimed {
+			t.Errorf("Want delivery claimed")
+		}
+		if got, want := delivery.Next, int64(4000); got != want {
+			t.Errorf("Want next %d, got %d", want, got)
+		}
+
+		// the delivery cannot be claimed using a stale copy.
+		stale := *delivery
+		stale.Next = 2000
+		claimed, err = store.Claim(noContext, &stale, 5000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if claimed {
+			t.Errorf("Want stale delivery not claimed")
+		}
+
+		list, err := store.Pending(noContext, 3000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(list) != 0 {
+			t.Errorf("Want claimed delivery excluded from pending")
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, 25, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := []*core.WebhookDelivery{deliveries[1], deliveries[0]}
+		if diff := cmp.Diff(list, want); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		err := store.Purge(noContext, 2000)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// the completed delivery is purged, and the pending
+		// delivery is retained.
+		if _, err := store.Find(noContext, deliveries[0].ID); err != sql.ErrNoRows {
+			t.Errorf("Want completed delivery purged")
+		}
+		if _, err := store.Find(noContext, deliveries[1].ID); err != nil {
+			t.Errorf("Want pending delivery retained")
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := store.Find(noContext, -1)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows, got %v", err)
+		}
+	})
+}
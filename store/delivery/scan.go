@@ -0,0 +1,98 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the WebhookDelivery structure to
+// a set of named query parameters.
+func toParams(delivery *core.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"delivery_id":         delivery.ID,
+		"delivery_endpoint":   delivery.Endpoint,
+		"delivery_event":      delivery.Event,
+		"delivery_action":     delivery.Action,
+		"delivery_payload":    delivery.Payload,
+		"delivery_status":     delivery.Status,
+		"delivery_attempts":   delivery.Attempts,
+		"delivery_code":       delivery.Code,
+		"delivery_latency":    delivery.Latency,
+		"delivery_error":      delivery.Error,
+		"delivery_next":       delivery.Next,
+		"delivery_webhook_id": delivery.Webhook,
+		"delivery_created":    delivery.Created,
+		"delivery_updated":    delivery.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.WebhookDelivery) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Endpoint,
+		&dst.Event,
+		&dst.Action,
+		&dst.Payload,
+		&dst.Status,
+		&dst.Attempts,
+		&dst.Code,
+		&dst.Latency,
+		&dst.Error,
+		&dst.Next,
+		&dst.Webhook,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := []*core.WebhookDelivery{}
+	for rows.Next() {
+		delivery := new(core.WebhookDelivery)
+		err := scanRow(rows, delivery)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// helper function scans the sql.Rows and returns the list
+// of endpoints.
+func scanEndpoints(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	var endpoints []string
+	for rows.Next() {
+		var endpoint string
+		err := rows.Scan(&endpoint)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, rows.Err()
+}
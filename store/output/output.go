@@ -0,0 +1,130 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new OutputStore. Output values are encrypted
+// at rest, since they may contain sensitive data. Secret
+// outputs are not persisted if encryption is disabled.
+func New(db *db.DB, enc encrypt.Encrypter) core.OutputStore {
+	return &outputStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type outputStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *outputStore) List(ctx context.Context, build int64) ([]*core.Output, error) {
+	var out []*core.Output
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"output_build_id": build}
+		stmt, args, err := binder.BindNamed(queryBuild, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *outputStore) Create(ctx context.Context, outputs []*core.Output) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		for _, output := range outputs {
+			if output.Secret && encrypt.Plaintext(s.enc) {
+				continue
+			}
+			params, err := toParams(s.enc, output)
+			if err != nil {
+				return err
+			}
+			if s.db.Driver() == db.Postgres {
+				stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+				if err != nil {
+					return err
+				}
+				err = execer.QueryRow(stmt, args...).Scan(&output.ID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			stmt, args, err := binder.BindNamed(stmtInsert, params)
+			if err != nil {
+				return err
+			}
+			res, err := execer.Exec(stmt, args...)
+			if err != nil {
+				return err
+			}
+			output.ID, err = res.LastInsertId()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+const queryBuild = `
+SELECT
+ output_id
+,output_build_id
+,output_stage_id
+,output_step_id
+,output_name
+,output_value
+,output_secret
+FROM outputs
+WHERE output_build_id = :output_build_id
+ORDER BY output_id
+`
+
+const stmtInsert = `
+INSERT INTO outputs (
+ output_build_id
+,output_stage_id
+,output_step_id
+,output_name
+,output_value
+,output_secret
+) VALUES (
+ :output_build_id
+,:output_stage_id
+,:output_step_id
+,:output_name
+,:output_value
+,:output_secret
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING output_id
+`
@@ -0,0 +1,117 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package output
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestOutput(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with a dummy build and stage
+	stage := &core.Stage{Number: 1}
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds := build.New(conn)
+	builds.Create(noContext, abuild, []*core.Stage{stage})
+
+	enc, _ := encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	store := New(conn, enc).(*outputStore)
+
+	outputs := []*core.Output{
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 1, Name: "VERSION", Value: "1.0.0"},
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 1, Name: "TOKEN", Value: "correct-horse-battery-staple", Secret: true},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		err := store.Create(noContext, outputs)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, output := range outputs {
+			if output.ID == 0 {
+				t.Errorf("Want output ID assigned")
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, abuild.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, outputs); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+}
+
+func TestOutput_Plaintext(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository, build and stage
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos.New(conn).Create(noContext, arepo)
+	stage := &core.Stage{Number: 1}
+	abuild := &core.Build{Number: 1, RepoID: arepo.ID}
+	build.New(conn).Create(noContext, abuild, []*core.Stage{stage})
+
+	// secret outputs are not persisted when encryption
+	// is disabled.
+	enc, _ := encrypt.New("")
+	store := New(conn, enc).(*outputStore)
+
+	outputs := []*core.Output{
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 1, Name: "VERSION", Value: "1.0.0"},
+		{BuildID: abuild.ID, StageID: stage.ID, StepID: 1, Name: "TOKEN", Value: "correct-horse-battery-staple", Secret: true},
+	}
+	err = store.Create(noContext, outputs)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	list, err := store.List(noContext, abuild.ID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(list, outputs[:1]); diff != "" {
+		t.Errorf(diff)
+	}
+}
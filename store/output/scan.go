@@ -0,0 +1,82 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Output structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, output *core.Output) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(output.Value)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"output_id":       output.ID,
+		"output_build_id": output.BuildID,
+		"output_stage_id": output.StageID,
+		"output_step_id":  output.StepID,
+		"output_name":     output.Name,
+		"output_value":    ciphertext,
+		"output_secret":   output.Secret,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Output) error {
+	var ciphertext []byte
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.BuildID,
+		&dst.StageID,
+		&dst.StepID,
+		&dst.Name,
+		&ciphertext,
+		&dst.Secret,
+	)
+	if err != nil {
+		return err
+	}
+	plaintext, err := encrypt.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	dst.Value = plaintext
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Output, error) {
+	defer rows.Close()
+
+	outputs := []*core.Output{}
+	for rows.Next() {
+		output := new(core.Output)
+		err := scanRow(encrypt, rows, output)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
@@ -261,6 +261,7 @@ SELECT
 ,repo_branch
 ,repo_counter
 ,repo_config
+,repo_config_fallback
 ,repo_timeout
 ,repo_trusted
 ,repo_protected
@@ -347,6 +348,7 @@ INSERT INTO repos (
 ,repo_branch
 ,repo_counter
 ,repo_config
+,repo_config_fallback
 ,repo_timeout
 ,repo_trusted
 ,repo_protected
@@ -374,6 +376,7 @@ INSERT INTO repos (
 ,:repo_branch
 ,:repo_counter
 ,:repo_config
+,:repo_config_fallback
 ,:repo_timeout
 ,:repo_trusted
 ,:repo_protected
@@ -420,6 +423,7 @@ UPDATE repos SET
 ,repo_visibility = :repo_visibility
 ,repo_active = :repo_active
 ,repo_config = :repo_config
+,repo_config_fallback = :repo_config_fallback
 ,repo_trusted = :repo_trusted
 ,repo_protected = :repo_protected
 ,repo_no_forks = :repo_no_forks
@@ -225,6 +225,7 @@ func testRepoUpdate(repos *repoStore) func(t *testing.T) {
 
 		version := before.Version
 		before.Private = true
+		before.Fallback = []string{".drone.yaml", ".ci/drone.yml"}
 		err = repos.Update(noContext, before)
 		if err != nil {
 			t.Error(err)
@@ -241,6 +242,9 @@ func testRepoUpdate(repos *repoStore) func(t *testing.T) {
 		if got, want := before.Private, after.Private; got != want {
 			t.Errorf("Want updated Repo private %v, got %v", want, got)
 		}
+		if diff := cmp.Diff(before.Fallback, after.Fallback); diff != "" {
+			t.Errorf("Want updated Repo config fallback, diff %s", diff)
+		}
 	}
 }
 
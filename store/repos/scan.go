@@ -16,49 +16,76 @@ package repos
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/drone/drone/core"
 	"github.com/drone/drone/store/shared/db"
+
+	"github.com/jmoiron/sqlx/types"
 )
 
 // ToParams converts the Repository structure to a set
 // of named query parameters.
 func ToParams(v *core.Repository) map[string]interface{} {
 	return map[string]interface{}{
-		"repo_id":         v.ID,
-		"repo_uid":        v.UID,
-		"repo_user_id":    v.UserID,
-		"repo_namespace":  v.Namespace,
-		"repo_name":       v.Name,
-		"repo_slug":       v.Slug,
-		"repo_scm":        v.SCM,
-		"repo_clone_url":  v.HTTPURL,
-		"repo_ssh_url":    v.SSHURL,
-		"repo_html_url":   v.Link,
-		"repo_branch":     v.Branch,
-		"repo_private":    v.Private,
-		"repo_visibility": v.Visibility,
-		"repo_active":     v.Active,
-		"repo_config":     v.Config,
-		"repo_trusted":    v.Trusted,
-		"repo_protected":  v.Protected,
-		"repo_no_forks":   v.IgnoreForks,
-		"repo_no_pulls":   v.IgnorePulls,
-		"repo_timeout":    v.Timeout,
-		"repo_counter":    v.Counter,
-		"repo_synced":     v.Synced,
-		"repo_created":    v.Created,
-		"repo_updated":    v.Updated,
-		"repo_version":    v.Version,
-		"repo_signer":     v.Signer,
-		"repo_secret":     v.Secret,
+		"repo_id":              v.ID,
+		"repo_uid":             v.UID,
+		"repo_user_id":         v.UserID,
+		"repo_namespace":       v.Namespace,
+		"repo_name":            v.Name,
+		"repo_slug":            v.Slug,
+		"repo_scm":             v.SCM,
+		"repo_clone_url":       v.HTTPURL,
+		"repo_ssh_url":         v.SSHURL,
+		"repo_html_url":        v.Link,
+		"repo_branch":          v.Branch,
+		"repo_private":         v.Private,
+		"repo_visibility":      v.Visibility,
+		"repo_active":          v.Active,
+		"repo_config":          v.Config,
+		"repo_config_fallback": encodeFallback(v.Fallback),
+		"repo_trusted":         v.Trusted,
+		"repo_protected":       v.Protected,
+		"repo_no_forks":        v.IgnoreForks,
+		"repo_no_pulls":        v.IgnorePulls,
+		"repo_timeout":         v.Timeout,
+		"repo_counter":         v.Counter,
+		"repo_synced":          v.Synced,
+		"repo_created":         v.Created,
+		"repo_updated":         v.Updated,
+		"repo_version":         v.Version,
+		"repo_signer":          v.Signer,
+		"repo_secret":          v.Secret,
+	}
+}
+
+// helper function encodes the fallback configuration paths
+// as json for storage in a single column.
+func encodeFallback(v []string) types.JSONText {
+	if len(v) == 0 {
+		return types.JSONText("")
+	}
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function decodes the fallback configuration paths
+// stored as json. An empty column decodes to an empty list.
+func decodeFallback(raw types.JSONText) ([]string, error) {
+	switch string(raw) {
+	case "", "{}", "null":
+		return nil, nil
 	}
+	var v []string
+	err := json.Unmarshal(raw, &v)
+	return v, err
 }
 
 // helper function scans the sql.Row and copies the column
 // values to the destination object.
 func scanRow(scanner db.Scanner, dest *core.Repository) error {
-	return scanner.Scan(
+	fallbackJSON := types.JSONText{}
+	err := scanner.Scan(
 		&dest.ID,
 		&dest.UID,
 		&dest.UserID,
@@ -75,6 +102,7 @@ func scanRow(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Branch,
 		&dest.Counter,
 		&dest.Config,
+		&fallbackJSON,
 		&dest.Timeout,
 		&dest.Trusted,
 		&dest.Protected,
@@ -87,6 +115,11 @@ func scanRow(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Signer,
 		&dest.Secret,
 	)
+	if err != nil {
+		return err
+	}
+	dest.Fallback, err = decodeFallback(fallbackJSON)
+	return err
 }
 
 // helper function scans the sql.Row and copies the column
@@ -110,6 +143,7 @@ func scanRows(rows *sql.Rows) ([]*core.Repository, error) {
 // values to the destination object.
 func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 	build := new(nullBuild)
+	fallbackJSON := types.JSONText{}
 	err := scanner.Scan(
 		&dest.ID,
 		&dest.UID,
@@ -127,6 +161,7 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&dest.Branch,
 		&dest.Counter,
 		&dest.Config,
+		&fallbackJSON,
 		&dest.Timeout,
 		&dest.Trusted,
 		&dest.Protected,
@@ -172,10 +207,17 @@ func scanRowBuild(scanner db.Scanner, dest *core.Repository) error {
 		&build.Updated,
 		&build.Version,
 	)
+	if err != nil {
+		return err
+	}
+	dest.Fallback, err = decodeFallback(fallbackJSON)
+	if err != nil {
+		return err
+	}
 	if build.ID.Int64 != 0 {
 		dest.Build = build.value()
 	}
-	return err
+	return nil
 }
 
 // helper function scans the sql.Row and copies the column
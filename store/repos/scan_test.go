@@ -3,3 +3,39 @@
 // that can be found in the LICENSE file.
 
 package repos
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+func TestDecodeFallback(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{raw: "", want: nil},
+		{raw: "{}", want: nil},
+		{raw: "null", want: nil},
+		{raw: `[".drone.yaml",".ci.yml"]`, want: []string{".drone.yaml", ".ci.yml"}},
+	}
+	for _, test := range tests {
+		got, err := decodeFallback(types.JSONText(test.raw))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Want fallback %v, got %v", test.want, got)
+		}
+	}
+}
+
+func TestDecodeFallback_Invalid(t *testing.T) {
+	_, err := decodeFallback(types.JSONText(`"invalid"`))
+	if err == nil {
+		t.Errorf("Expect error decoding invalid fallback")
+	}
+}
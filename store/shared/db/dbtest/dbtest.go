@@ -52,6 +52,18 @@ func Reset(d *db.DB) {
 		tx.Exec("DELETE FROM repos")
 		tx.Exec("DELETE FROM users")
 		tx.Exec("DELETE FROM orgsecrets")
+		tx.Exec("DELETE FROM templates")
+		tx.Exec("DELETE FROM central_configs")
+		tx.Exec("DELETE FROM artifact_data")
+		tx.Exec("DELETE FROM artifacts")
+		tx.Exec("DELETE FROM tests")
+		tx.Exec("DELETE FROM outputs")
+		tx.Exec("DELETE FROM annotations")
+		tx.Exec("DELETE FROM cards")
+		tx.Exec("DELETE FROM deliveries")
+		tx.Exec("DELETE FROM webhooks")
+		tx.Exec("DELETE FROM subscriptions")
+		tx.Exec("DELETE FROM caches")
 		return nil
 	})
 }
@@ -0,0 +1,71 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+// Percentiles returns the values at the given percentiles of
+// an ordered result set, using the nearest rank method. The
+// count query returns the size of the result set, and the
+// rank query returns the single value at the :offset
+// position of the ordered result set. A zero value is
+// returned for each percentile if the result set is empty.
+func Percentiles(queryer Queryer, binder Binder, count, rank string, params map[string]interface{}, percentiles ...int) ([]int64, error) {
+	query, args, err := binder.BindNamed(count, params)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	err = queryer.QueryRow(query, args...).Scan(&size)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]int64, len(percentiles))
+	if size == 0 {
+		return out, nil
+	}
+
+	// copy the parameters to avoid modifying the
+	// caller's parameter map.
+	ranked := map[string]interface{}{}
+	for k, v := range params {
+		ranked[k] = v
+	}
+	for i, p := range percentiles {
+		ranked["offset"] = Rank(p, size)
+		query, args, err := binder.BindNamed(rank, ranked)
+		if err != nil {
+			return nil, err
+		}
+		err = queryer.QueryRow(query, args...).Scan(&out[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// Rank returns the zero-based offset of the p-th percentile
+// in an ordered set of n values, using the nearest rank
+// method.
+func Rank(p int, n int64) int64 {
+	rank := (int64(p)*n + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > n {
+		rank = n
+	}
+	return rank - 1
+}
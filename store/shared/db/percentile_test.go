@@ -0,0 +1,32 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package db
+
+import "testing"
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		p    int
+		n    int64
+		want int64
+	}{
+		{50, 1, 0},
+		{99, 1, 0},
+		{50, 4, 1},
+		{90, 4, 3},
+		{50, 10, 4},
+		{90, 10, 8},
+		{99, 10, 9},
+		{50, 101, 50},
+		{99, 1000, 989},
+		{0, 10, 0},
+		{100, 10, 9},
+	}
+	for _, test := range tests {
+		if got := Rank(test.p, test.n); got != test.want {
+			t.Errorf("Want rank %d for p%d of %d values, got %d", test.want, test.p, test.n, got)
+		}
+	}
+}
@@ -45,3 +45,10 @@ func New(key string) (Encrypter, error) {
 	}
 	return &aesgcm{block: block}, nil
 }
+
+// Plaintext returns true if the encrypter stores values in
+// plain text, because no encryption key is specified.
+func Plaintext(enc Encrypter) bool {
+	_, ok := enc.(*none)
+	return ok
+}
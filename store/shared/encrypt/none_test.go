@@ -20,3 +20,14 @@ func TestNone(t *testing.T) {
 		t.Errorf("Want plaintext %q, got %q", want, got)
 	}
 }
+
+func TestPlaintext(t *testing.T) {
+	n, _ := New("")
+	if !Plaintext(n) {
+		t.Errorf("Want plaintext when no key is specified")
+	}
+	a, _ := New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	if Plaintext(a) {
+		t.Errorf("Want encrypted when a key is specified")
+	}
+}
@@ -0,0 +1,3 @@
+-- name: alter-table-cron-add-column-params
+
+ALTER TABLE cron ADD COLUMN cron_params VARCHAR(4000) NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- name: create-table-templates
+
+CREATE TABLE IF NOT EXISTS templates (
+ template_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,template_namespace VARCHAR(50)
+,template_name      VARCHAR(200)
+,template_version   VARCHAR(50)
+,template_data      MEDIUMTEXT
+,template_created   INTEGER
+,template_updated   INTEGER
+,UNIQUE(template_namespace, template_name, template_version)
+);
@@ -0,0 +1,24 @@
+-- name: create-table-artifacts
+
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,artifact_repo_id  INTEGER
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     VARCHAR(500)
+,artifact_size     BIGINT
+,artifact_created  INTEGER
+,UNIQUE(artifact_stage_id, artifact_name)
+);
+
+-- name: create-index-artifacts-build
+
+CREATE INDEX ix_artifacts_build ON artifacts (artifact_build_id);
+
+-- name: create-table-artifact-data
+
+CREATE TABLE IF NOT EXISTS artifact_data (
+ artifact_data_id INTEGER PRIMARY KEY
+,artifact_data    LONGBLOB
+);
@@ -0,0 +1,27 @@
+-- name: create-table-tests
+
+CREATE TABLE IF NOT EXISTS tests (
+ test_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,test_repo_id  INTEGER
+,test_build_id INTEGER
+,test_stage_id INTEGER
+,test_step_id  INTEGER
+,test_suite    VARCHAR(250)
+,test_name     VARCHAR(250)
+,test_status   VARCHAR(50)
+,test_duration INTEGER
+,test_message  TEXT
+,test_created  INTEGER
+);
+
+-- name: create-index-tests-build
+
+CREATE INDEX ix_tests_build ON tests (test_build_id);
+
+-- name: create-index-tests-stage
+
+CREATE INDEX ix_tests_stage ON tests (test_stage_id);
+
+-- name: create-index-tests-repo-name
+
+CREATE INDEX ix_tests_repo_name ON tests (test_repo_id, test_suite, test_name);
@@ -0,0 +1,15 @@
+-- name: create-table-outputs
+
+CREATE TABLE IF NOT EXISTS outputs (
+ output_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,output_build_id INTEGER
+,output_stage_id INTEGER
+,output_step_id  INTEGER
+,output_name     VARCHAR(250)
+,output_value    BLOB
+,output_secret   BOOLEAN
+);
+
+-- name: create-index-outputs-build
+
+CREATE INDEX ix_outputs_build ON outputs (output_build_id);
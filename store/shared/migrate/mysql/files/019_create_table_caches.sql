@@ -0,0 +1,11 @@
+-- name: create-table-caches
+
+CREATE TABLE IF NOT EXISTS caches (
+ cache_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,cache_repo_id  INTEGER
+,cache_key      VARCHAR(250)
+,cache_size     BIGINT
+,cache_created  INTEGER
+,cache_accessed INTEGER
+,UNIQUE(cache_repo_id, cache_key)
+);
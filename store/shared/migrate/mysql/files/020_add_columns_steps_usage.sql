@@ -0,0 +1,23 @@
+-- name: alter-table-steps-add-column-cpu-peak
+
+ALTER TABLE steps ADD COLUMN step_cpu_peak BIGINT NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-cpu-avg
+
+ALTER TABLE steps ADD COLUMN step_cpu_avg BIGINT NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-mem-peak
+
+ALTER TABLE steps ADD COLUMN step_mem_peak BIGINT NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-mem-avg
+
+ALTER TABLE steps ADD COLUMN step_mem_avg BIGINT NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-io-read
+
+ALTER TABLE steps ADD COLUMN step_io_read BIGINT NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-io-write
+
+ALTER TABLE steps ADD COLUMN step_io_write BIGINT NOT NULL DEFAULT 0;
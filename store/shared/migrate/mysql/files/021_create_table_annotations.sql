@@ -0,0 +1,16 @@
+-- name: create-table-annotations
+
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,annotation_build_id INTEGER
+,annotation_stage_id INTEGER
+,annotation_step_id  INTEGER
+,annotation_file     VARCHAR(1000)
+,annotation_line     INTEGER
+,annotation_severity VARCHAR(50)
+,annotation_message  TEXT
+);
+
+-- name: create-index-annotations-build
+
+CREATE INDEX ix_annotations_build ON annotations (annotation_build_id);
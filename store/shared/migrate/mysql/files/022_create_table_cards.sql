@@ -0,0 +1,13 @@
+-- name: create-table-cards
+
+CREATE TABLE IF NOT EXISTS cards (
+ card_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,card_build_id INTEGER
+,card_stage_id INTEGER
+,card_step_id  INTEGER
+,card_data     MEDIUMTEXT
+);
+
+-- name: create-index-cards-build
+
+CREATE INDEX ix_cards_build ON cards (card_build_id);
@@ -0,0 +1,21 @@
+-- name: create-table-deliveries
+
+CREATE TABLE IF NOT EXISTS deliveries (
+ delivery_id       INTEGER PRIMARY KEY AUTO_INCREMENT
+,delivery_endpoint VARCHAR(2000)
+,delivery_event    VARCHAR(50)
+,delivery_action   VARCHAR(50)
+,delivery_payload  MEDIUMTEXT
+,delivery_status   VARCHAR(50)
+,delivery_attempts INTEGER
+,delivery_code     INTEGER
+,delivery_latency  INTEGER
+,delivery_error    VARCHAR(2000)
+,delivery_next     INTEGER
+,delivery_created  INTEGER
+,delivery_updated  INTEGER
+);
+
+-- name: create-index-deliveries-status
+
+CREATE INDEX ix_deliveries_status ON deliveries (delivery_status, delivery_next);
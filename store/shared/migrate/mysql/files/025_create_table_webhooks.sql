@@ -0,0 +1,22 @@
+-- name: create-table-webhooks
+
+CREATE TABLE IF NOT EXISTS webhooks (
+ webhook_id          INTEGER PRIMARY KEY AUTO_INCREMENT
+,webhook_repo_id     INTEGER
+,webhook_namespace   VARCHAR(250)
+,webhook_endpoint    VARCHAR(2000)
+,webhook_secret      BLOB
+,webhook_skip_verify BOOLEAN
+,webhook_events      VARCHAR(2000)
+,webhook_disabled    BOOLEAN
+,webhook_created     INTEGER
+,webhook_updated     INTEGER
+);
+
+-- name: create-index-webhooks-repo
+
+CREATE INDEX ix_webhooks_repo ON webhooks (webhook_repo_id);
+
+-- name: create-index-webhooks-namespace
+
+CREATE INDEX ix_webhooks_namespace ON webhooks (webhook_namespace);
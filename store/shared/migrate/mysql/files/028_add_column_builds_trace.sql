@@ -0,0 +1,3 @@
+-- name: alter-table-builds-add-column-trace
+
+ALTER TABLE builds ADD COLUMN build_trace VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+-- name: create-table-central-configs
+
+CREATE TABLE IF NOT EXISTS central_configs (
+ central_id        INTEGER PRIMARY KEY AUTO_INCREMENT
+,central_namespace VARCHAR(50)
+,central_path      VARCHAR(500)
+,central_created   INTEGER
+,central_updated   INTEGER
+,UNIQUE(central_namespace)
+);
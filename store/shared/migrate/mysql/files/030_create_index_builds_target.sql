@@ -0,0 +1,3 @@
+-- name: create-index-builds-target
+
+CREATE INDEX ix_build_target ON builds (build_repo_id, build_target, build_number);
@@ -120,6 +120,150 @@ var migrations = []struct {
 		name: "create-table-org-secrets",
 		stmt: createTableOrgSecrets,
 	},
+	{
+		name: "alter-table-cron-add-column-params",
+		stmt: alterTableCronAddColumnParams,
+	},
+	{
+		name: "create-table-templates",
+		stmt: createTableTemplates,
+	},
+	{
+		name: "alter-table-repos-add-column-config-fallback",
+		stmt: alterTableReposAddColumnConfigFallback,
+	},
+	{
+		name: "create-table-artifacts",
+		stmt: createTableArtifacts,
+	},
+	{
+		name: "create-index-artifacts-build",
+		stmt: createIndexArtifactsBuild,
+	},
+	{
+		name: "create-table-artifact-data",
+		stmt: createTableArtifactData,
+	},
+	{
+		name: "create-table-tests",
+		stmt: createTableTests,
+	},
+	{
+		name: "create-index-tests-build",
+		stmt: createIndexTestsBuild,
+	},
+	{
+		name: "create-index-tests-stage",
+		stmt: createIndexTestsStage,
+	},
+	{
+		name: "create-index-tests-repo-name",
+		stmt: createIndexTestsRepoName,
+	},
+	{
+		name: "create-table-outputs",
+		stmt: createTableOutputs,
+	},
+	{
+		name: "create-index-outputs-build",
+		stmt: createIndexOutputsBuild,
+	},
+	{
+		name: "create-table-caches",
+		stmt: createTableCaches,
+	},
+	{
+		name: "alter-table-steps-add-column-cpu-peak",
+		stmt: alterTableStepsAddColumnCpuPeak,
+	},
+	{
+		name: "alter-table-steps-add-column-cpu-avg",
+		stmt: alterTableStepsAddColumnCpuAvg,
+	},
+	{
+		name: "alter-table-steps-add-column-mem-peak",
+		stmt: alterTableStepsAddColumnMemPeak,
+	},
+	{
+		name: "alter-table-steps-add-column-mem-avg",
+		stmt: alterTableStepsAddColumnMemAvg,
+	},
+	{
+		name: "alter-table-steps-add-column-io-read",
+		stmt: alterTableStepsAddColumnIoRead,
+	},
+	{
+		name: "alter-table-steps-add-column-io-write",
+		stmt: alterTableStepsAddColumnIoWrite,
+	},
+	{
+		name: "create-table-annotations",
+		stmt: createTableAnnotations,
+	},
+	{
+		name: "create-index-annotations-build",
+		stmt: createIndexAnnotationsBuild,
+	},
+	{
+		name: "create-table-cards",
+		stmt: createTableCards,
+	},
+	{
+		name: "create-index-cards-build",
+		stmt: createIndexCardsBuild,
+	},
+	{
+		name: "alter-table-steps-add-column-truncated",
+		stmt: alterTableStepsAddColumnTruncated,
+	},
+	{
+		name: "create-table-deliveries",
+		stmt: createTableDeliveries,
+	},
+	{
+		name: "create-index-deliveries-status",
+		stmt: createIndexDeliveriesStatus,
+	},
+	{
+		name: "create-table-webhooks",
+		stmt: createTableWebhooks,
+	},
+	{
+		name: "create-index-webhooks-repo",
+		stmt: createIndexWebhooksRepo,
+	},
+	{
+		name: "create-index-webhooks-namespace",
+		stmt: createIndexWebhooksNamespace,
+	},
+	{
+		name: "alter-table-deliveries-add-column-webhook-id",
+		stmt: alterTableDeliveriesAddColumnWebhookId,
+	},
+	{
+		name: "create-table-subscriptions",
+		stmt: createTableSubscriptions,
+	},
+	{
+		name: "create-index-subscriptions-repo",
+		stmt: createIndexSubscriptionsRepo,
+	},
+	{
+		name: "create-index-subscriptions-user",
+		stmt: createIndexSubscriptionsUser,
+	},
+	{
+		name: "alter-table-builds-add-column-trace",
+		stmt: alterTableBuildsAddColumnTrace,
+	},
+	{
+		name: "create-table-central-configs",
+		stmt: createTableCentralConfigs,
+	},
+	{
+		name: "create-index-builds-target",
+		stmt: createIndexBuildsTarget,
+	},
 }
 
 // Migrate performs the database migration. If the migration fails
@@ -555,3 +699,327 @@ CREATE TABLE IF NOT EXISTS orgsecrets (
 ,UNIQUE(secret_namespace, secret_name)
 );
 `
+
+//
+// 013_add_column_cron_params.sql
+//
+
+var alterTableCronAddColumnParams = `
+ALTER TABLE cron ADD COLUMN cron_params VARCHAR(4000) NOT NULL DEFAULT '';
+`
+
+//
+// 014_create_table_templates.sql
+//
+
+var createTableTemplates = `
+CREATE TABLE IF NOT EXISTS templates (
+ template_id        SERIAL PRIMARY KEY
+,template_namespace VARCHAR(50)
+,template_name      VARCHAR(200)
+,template_version   VARCHAR(50)
+,template_data      TEXT
+,template_created   INTEGER
+,template_updated   INTEGER
+,UNIQUE(template_namespace, template_name, template_version)
+);
+`
+
+//
+// 015_add_column_repos_config_fallback.sql
+//
+
+var alterTableReposAddColumnConfigFallback = `
+ALTER TABLE repos ADD COLUMN repo_config_fallback VARCHAR(2000) NOT NULL DEFAULT '';
+`
+
+//
+// 016_create_table_artifacts.sql
+//
+
+var createTableArtifacts = `
+CREATE TABLE IF NOT EXISTS artifacts (
+ artifact_id       SERIAL PRIMARY KEY
+,artifact_repo_id  INTEGER
+,artifact_build_id INTEGER
+,artifact_stage_id INTEGER
+,artifact_step_id  INTEGER
+,artifact_name     VARCHAR(500)
+,artifact_size     BIGINT
+,artifact_created  INTEGER
+,UNIQUE(artifact_stage_id, artifact_name)
+);
+`
+
+var createIndexArtifactsBuild = `
+CREATE INDEX IF NOT EXISTS ix_artifacts_build ON artifacts (artifact_build_id);
+`
+
+var createTableArtifactData = `
+CREATE TABLE IF NOT EXISTS artifact_data (
+ artifact_data_id INTEGER PRIMARY KEY
+,artifact_data    BYTEA
+);
+`
+
+//
+// 017_create_table_tests.sql
+//
+
+var createTableTests = `
+CREATE TABLE IF NOT EXISTS tests (
+ test_id       SERIAL PRIMARY KEY
+,test_repo_id  INTEGER
+,test_build_id INTEGER
+,test_stage_id INTEGER
+,test_step_id  INTEGER
+,test_suite    VARCHAR(250)
+,test_name     VARCHAR(250)
+,test_status   VARCHAR(50)
+,test_duration INTEGER
+,test_message  TEXT
+,test_created  INTEGER
+);
+`
+
+var createIndexTestsBuild = `
+CREATE INDEX IF NOT EXISTS ix_tests_build ON tests (test_build_id);
+`
+
+var createIndexTestsStage = `
+CREATE INDEX IF NOT EXISTS ix_tests_stage ON tests (test_stage_id);
+`
+
+var createIndexTestsRepoName = `
+CREATE INDEX IF NOT EXISTS ix_tests_repo_name ON tests (test_repo_id, test_suite, test_name);
+`
+
+//
+// 018_create_table_outputs.sql
+//
+
+var createTableOutputs = `
+CREATE TABLE IF NOT EXISTS outputs (
+ output_id       SERIAL PRIMARY KEY
+,output_build_id INTEGER
+,output_stage_id INTEGER
+,output_step_id  INTEGER
+,output_name     VARCHAR(250)
+,output_value    BYTEA
+,output_secret   BOOLEAN
+);
+`
+
+var createIndexOutputsBuild = `
+CREATE INDEX IF NOT EXISTS ix_outputs_build ON outputs (output_build_id);
+`
+
+//
+// 019_create_table_caches.sql
+//
+
+var createTableCaches = `
+CREATE TABLE IF NOT EXISTS caches (
+ cache_id       SERIAL PRIMARY KEY
+,cache_repo_id  INTEGER
+,cache_key      VARCHAR(250)
+,cache_size     BIGINT
+,cache_created  INTEGER
+,cache_accessed INTEGER
+,UNIQUE(cache_repo_id, cache_key)
+);
+`
+
+//
+// 020_add_columns_steps_usage.sql
+//
+
+var alterTableStepsAddColumnCpuPeak = `
+ALTER TABLE steps ADD COLUMN step_cpu_peak BIGINT NOT NULL DEFAULT 0;
+`
+
+var alterTableStepsAddColumnCpuAvg = `
+ALTER TABLE steps ADD COLUMN step_cpu_avg BIGINT NOT NULL DEFAULT 0;
+`
+
+var alterTableStepsAddColumnMemPeak = `
+ALTER TABLE steps ADD COLUMN step_mem_peak BIGINT NOT NULL DEFAULT 0;
+`
+
+var alterTableStepsAddColumnMemAvg = `
+ALTER TABLE steps ADD COLUMN step_mem_avg BIGINT NOT NULL DEFAULT 0;
+`
+
+var alterTableStepsAddColumnIoRead = `
+ALTER TABLE steps ADD COLUMN step_io_read BIGINT NOT NULL DEFAULT 0;
+`
+
+var alterTableStepsAddColumnIoWrite = `
+ALTER TABLE steps ADD COLUMN step_io_write BIGINT NOT NULL DEFAULT 0;
+`
+
+//
+// 021_create_table_annotations.sql
+//
+
+var createTableAnnotations = `
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id       SERIAL PRIMARY KEY
+,annotation_build_id INTEGER
+,annotation_stage_id INTEGER
+,annotation_step_id  INTEGER
+,annotation_file     VARCHAR(1000)
+,annotation_line     INTEGER
+,annotation_severity VARCHAR(50)
+,annotation_message  TEXT
+);
+`
+
+var createIndexAnnotationsBuild = `
+CREATE INDEX IF NOT EXISTS ix_annotations_build ON annotations (annotation_build_id);
+`
+
+//
+// 022_create_table_cards.sql
+//
+
+var createTableCards = `
+CREATE TABLE IF NOT EXISTS cards (
+ card_id       SERIAL PRIMARY KEY
+,card_build_id INTEGER
+,card_stage_id INTEGER
+,card_step_id  INTEGER
+,card_data     TEXT
+);
+`
+
+var createIndexCardsBuild = `
+CREATE INDEX IF NOT EXISTS ix_cards_build ON cards (card_build_id);
+`
+
+//
+// 023_add_column_steps_truncated.sql
+//
+
+var alterTableStepsAddColumnTruncated = `
+ALTER TABLE steps ADD COLUMN step_truncated BOOLEAN NOT NULL DEFAULT false;
+`
+
+//
+// 024_create_table_deliveries.sql
+//
+
+var createTableDeliveries = `
+CREATE TABLE IF NOT EXISTS deliveries (
+ delivery_id       SERIAL PRIMARY KEY
+,delivery_endpoint VARCHAR(2000)
+,delivery_event    VARCHAR(50)
+,delivery_action   VARCHAR(50)
+,delivery_payload  TEXT
+,delivery_status   VARCHAR(50)
+,delivery_attempts INTEGER
+,delivery_code     INTEGER
+,delivery_latency  INTEGER
+,delivery_error    VARCHAR(2000)
+,delivery_next     INTEGER
+,delivery_created  INTEGER
+,delivery_updated  INTEGER
+);
+`
+
+var createIndexDeliveriesStatus = `
+CREATE INDEX IF NOT EXISTS ix_deliveries_status ON deliveries (delivery_status, delivery_next);
+`
+
+//
+// 025_create_table_webhooks.sql
+//
+
+var createTableWebhooks = `
+CREATE TABLE IF NOT EXISTS webhooks (
+ webhook_id          SERIAL PRIMARY KEY
+,webhook_repo_id     INTEGER
+,webhook_namespace   VARCHAR(250)
+,webhook_endpoint    VARCHAR(2000)
+,webhook_secret      BYTEA
+,webhook_skip_verify BOOLEAN
+,webhook_events      VARCHAR(2000)
+,webhook_disabled    BOOLEAN
+,webhook_created     INTEGER
+,webhook_updated     INTEGER
+);
+`
+
+var createIndexWebhooksRepo = `
+CREATE INDEX IF NOT EXISTS ix_webhooks_repo ON webhooks (webhook_repo_id);
+`
+
+var createIndexWebhooksNamespace = `
+CREATE INDEX IF NOT EXISTS ix_webhooks_namespace ON webhooks (webhook_namespace);
+`
+
+//
+// 026_add_column_deliveries_webhook.sql
+//
+
+var alterTableDeliveriesAddColumnWebhookId = `
+ALTER TABLE deliveries ADD COLUMN delivery_webhook_id INTEGER NOT NULL DEFAULT 0;
+`
+
+//
+// 027_create_table_subscriptions.sql
+//
+
+var createTableSubscriptions = `
+CREATE TABLE IF NOT EXISTS subscriptions (
+ subscription_id       SERIAL PRIMARY KEY
+,subscription_repo_id  INTEGER
+,subscription_user_id  INTEGER
+,subscription_kind     VARCHAR(50)
+,subscription_target   BYTEA
+,subscription_trigger  VARCHAR(50)
+,subscription_template TEXT
+,subscription_disabled BOOLEAN
+,subscription_created  INTEGER
+,subscription_updated  INTEGER
+);
+`
+
+var createIndexSubscriptionsRepo = `
+CREATE INDEX IF NOT EXISTS ix_subscriptions_repo ON subscriptions (subscription_repo_id);
+`
+
+var createIndexSubscriptionsUser = `
+CREATE INDEX IF NOT EXISTS ix_subscriptions_user ON subscriptions (subscription_user_id);
+`
+
+//
+// 028_add_column_builds_trace.sql
+//
+
+var alterTableBuildsAddColumnTrace = `
+ALTER TABLE builds ADD COLUMN build_trace VARCHAR(100) NOT NULL DEFAULT '';
+`
+
+//
+// 029_create_table_central_configs.sql
+//
+
+var createTableCentralConfigs = `
+CREATE TABLE IF NOT EXISTS central_configs (
+ central_id        SERIAL PRIMARY KEY
+,central_namespace VARCHAR(50)
+,central_path      VARCHAR(500)
+,central_created   INTEGER
+,central_updated   INTEGER
+,UNIQUE(central_namespace)
+);
+`
+
+//
+// 030_create_index_builds_target.sql
+//
+
+var createIndexBuildsTarget = `
+CREATE INDEX IF NOT EXISTS ix_build_target ON builds (build_repo_id, build_target, build_number);
+`
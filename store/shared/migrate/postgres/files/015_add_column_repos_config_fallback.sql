@@ -0,0 +1,3 @@
+-- name: alter-table-repos-add-column-config-fallback
+
+ALTER TABLE repos ADD COLUMN repo_config_fallback VARCHAR(2000) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- name: create-table-caches
+
+CREATE TABLE IF NOT EXISTS caches (
+ cache_id       SERIAL PRIMARY KEY
+,cache_repo_id  INTEGER
+,cache_key      VARCHAR(250)
+,cache_size     BIGINT
+,cache_created  INTEGER
+,cache_accessed INTEGER
+,UNIQUE(cache_repo_id, cache_key)
+);
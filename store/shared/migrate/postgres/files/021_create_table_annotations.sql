@@ -0,0 +1,16 @@
+-- name: create-table-annotations
+
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id       SERIAL PRIMARY KEY
+,annotation_build_id INTEGER
+,annotation_stage_id INTEGER
+,annotation_step_id  INTEGER
+,annotation_file     VARCHAR(1000)
+,annotation_line     INTEGER
+,annotation_severity VARCHAR(50)
+,annotation_message  TEXT
+);
+
+-- name: create-index-annotations-build
+
+CREATE INDEX IF NOT EXISTS ix_annotations_build ON annotations (annotation_build_id);
@@ -0,0 +1,13 @@
+-- name: create-table-cards
+
+CREATE TABLE IF NOT EXISTS cards (
+ card_id       SERIAL PRIMARY KEY
+,card_build_id INTEGER
+,card_stage_id INTEGER
+,card_step_id  INTEGER
+,card_data     TEXT
+);
+
+-- name: create-index-cards-build
+
+CREATE INDEX IF NOT EXISTS ix_cards_build ON cards (card_build_id);
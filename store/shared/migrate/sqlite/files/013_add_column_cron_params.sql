@@ -0,0 +1,3 @@
+-- name: alter-table-cron-add-column-params
+
+ALTER TABLE cron ADD COLUMN cron_params TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+-- name: create-table-templates
+
+CREATE TABLE IF NOT EXISTS templates (
+ template_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,template_namespace TEXT COLLATE NOCASE
+,template_name      TEXT COLLATE NOCASE
+,template_version   TEXT
+,template_data      TEXT
+,template_created   INTEGER
+,template_updated   INTEGER
+,UNIQUE(template_namespace, template_name, template_version)
+);
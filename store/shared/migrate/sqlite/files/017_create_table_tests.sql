@@ -0,0 +1,28 @@
+-- name: create-table-tests
+
+CREATE TABLE IF NOT EXISTS tests (
+ test_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,test_repo_id  INTEGER
+,test_build_id INTEGER
+,test_stage_id INTEGER
+,test_step_id  INTEGER
+,test_suite    TEXT
+,test_name     TEXT
+,test_status   TEXT
+,test_duration INTEGER
+,test_message  TEXT
+,test_created  INTEGER
+,FOREIGN KEY(test_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-tests-build
+
+CREATE INDEX IF NOT EXISTS ix_tests_build ON tests (test_build_id);
+
+-- name: create-index-tests-stage
+
+CREATE INDEX IF NOT EXISTS ix_tests_stage ON tests (test_stage_id);
+
+-- name: create-index-tests-repo-name
+
+CREATE INDEX IF NOT EXISTS ix_tests_repo_name ON tests (test_repo_id, test_suite, test_name);
@@ -0,0 +1,16 @@
+-- name: create-table-outputs
+
+CREATE TABLE IF NOT EXISTS outputs (
+ output_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,output_build_id INTEGER
+,output_stage_id INTEGER
+,output_step_id  INTEGER
+,output_name     TEXT
+,output_value    BLOB
+,output_secret   BOOLEAN
+,FOREIGN KEY(output_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-outputs-build
+
+CREATE INDEX IF NOT EXISTS ix_outputs_build ON outputs (output_build_id);
@@ -0,0 +1,11 @@
+-- name: create-table-caches
+
+CREATE TABLE IF NOT EXISTS caches (
+ cache_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,cache_repo_id  INTEGER
+,cache_key      TEXT
+,cache_size     INTEGER
+,cache_created  INTEGER
+,cache_accessed INTEGER
+,UNIQUE(cache_repo_id, cache_key)
+);
@@ -0,0 +1,23 @@
+-- name: alter-table-steps-add-column-cpu-peak
+
+ALTER TABLE steps ADD COLUMN step_cpu_peak INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-cpu-avg
+
+ALTER TABLE steps ADD COLUMN step_cpu_avg INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-mem-peak
+
+ALTER TABLE steps ADD COLUMN step_mem_peak INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-mem-avg
+
+ALTER TABLE steps ADD COLUMN step_mem_avg INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-io-read
+
+ALTER TABLE steps ADD COLUMN step_io_read INTEGER NOT NULL DEFAULT 0;
+
+-- name: alter-table-steps-add-column-io-write
+
+ALTER TABLE steps ADD COLUMN step_io_write INTEGER NOT NULL DEFAULT 0;
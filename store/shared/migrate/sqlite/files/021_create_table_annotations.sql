@@ -0,0 +1,17 @@
+-- name: create-table-annotations
+
+CREATE TABLE IF NOT EXISTS annotations (
+ annotation_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,annotation_build_id INTEGER
+,annotation_stage_id INTEGER
+,annotation_step_id  INTEGER
+,annotation_file     TEXT
+,annotation_line     INTEGER
+,annotation_severity TEXT
+,annotation_message  TEXT
+,FOREIGN KEY(annotation_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-annotations-build
+
+CREATE INDEX IF NOT EXISTS ix_annotations_build ON annotations (annotation_build_id);
@@ -0,0 +1,14 @@
+-- name: create-table-cards
+
+CREATE TABLE IF NOT EXISTS cards (
+ card_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,card_build_id INTEGER
+,card_stage_id INTEGER
+,card_step_id  INTEGER
+,card_data     TEXT
+,FOREIGN KEY(card_stage_id) REFERENCES stages(stage_id) ON DELETE CASCADE
+);
+
+-- name: create-index-cards-build
+
+CREATE INDEX IF NOT EXISTS ix_cards_build ON cards (card_build_id);
@@ -0,0 +1,3 @@
+-- name: alter-table-steps-add-column-truncated
+
+ALTER TABLE steps ADD COLUMN step_truncated BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- name: create-table-webhooks
+
+CREATE TABLE IF NOT EXISTS webhooks (
+ webhook_id          INTEGER PRIMARY KEY AUTOINCREMENT
+,webhook_repo_id     INTEGER
+,webhook_namespace   TEXT
+,webhook_endpoint    TEXT
+,webhook_secret      BLOB
+,webhook_skip_verify BOOLEAN
+,webhook_events      TEXT
+,webhook_disabled    BOOLEAN
+,webhook_created     INTEGER
+,webhook_updated     INTEGER
+);
+
+-- name: create-index-webhooks-repo
+
+CREATE INDEX IF NOT EXISTS ix_webhooks_repo ON webhooks (webhook_repo_id);
+
+-- name: create-index-webhooks-namespace
+
+CREATE INDEX IF NOT EXISTS ix_webhooks_namespace ON webhooks (webhook_namespace);
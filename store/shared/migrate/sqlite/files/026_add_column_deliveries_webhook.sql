@@ -0,0 +1,3 @@
+-- name: alter-table-deliveries-add-column-webhook-id
+
+ALTER TABLE deliveries ADD COLUMN delivery_webhook_id INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- name: create-table-subscriptions
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+ subscription_id       INTEGER PRIMARY KEY AUTOINCREMENT
+,subscription_repo_id  INTEGER
+,subscription_user_id  INTEGER
+,subscription_kind     TEXT
+,subscription_target   BLOB
+,subscription_trigger  TEXT
+,subscription_template TEXT
+,subscription_disabled BOOLEAN
+,subscription_created  INTEGER
+,subscription_updated  INTEGER
+);
+
+-- name: create-index-subscriptions-repo
+
+CREATE INDEX IF NOT EXISTS ix_subscriptions_repo ON subscriptions (subscription_repo_id);
+
+-- name: create-index-subscriptions-user
+
+CREATE INDEX IF NOT EXISTS ix_subscriptions_user ON subscriptions (subscription_user_id);
@@ -0,0 +1,10 @@
+-- name: create-table-central-configs
+
+CREATE TABLE IF NOT EXISTS central_configs (
+ central_id        INTEGER PRIMARY KEY AUTOINCREMENT
+,central_namespace TEXT COLLATE NOCASE
+,central_path      TEXT
+,central_created   INTEGER
+,central_updated   INTEGER
+,UNIQUE(central_namespace)
+);
@@ -148,6 +148,13 @@ func scanRowStep(scanner db.Scanner, stage *core.Stage, step *nullStep) error {
 		&step.Started,
 		&step.Stopped,
 		&step.Version,
+		&step.CPUPeak,
+		&step.CPUAvg,
+		&step.MemPeak,
+		&step.MemAvg,
+		&step.IORead,
+		&step.IOWrite,
+		&step.Truncated,
 	)
 	json.Unmarshal(depJSON, &stage.DependsOn)
 	json.Unmarshal(labJSON, &stage.Labels)
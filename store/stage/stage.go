@@ -321,6 +321,13 @@ SELECT
 ,step_started
 ,step_stopped
 ,step_version
+,step_cpu_peak
+,step_cpu_avg
+,step_mem_peak
+,step_mem_avg
+,step_io_read
+,step_io_write
+,step_truncated
 FROM stages
   LEFT JOIN steps
 	ON stages.stage_id=steps.step_stage_id
@@ -427,6 +434,13 @@ INSERT INTO steps (
 ,step_started
 ,step_stopped
 ,step_version
+,step_cpu_peak
+,step_cpu_avg
+,step_mem_peak
+,step_mem_avg
+,step_io_read
+,step_io_write
+,step_truncated
 ) VALUES (
  :step_stage_id
 ,:step_number
@@ -438,5 +452,12 @@ INSERT INTO steps (
 ,:step_started
 ,:step_stopped
 ,:step_version
+,:step_cpu_peak
+,:step_cpu_avg
+,:step_mem_peak
+,:step_mem_avg
+,:step_io_read
+,:step_io_write
+,:step_truncated
 )
 `
@@ -15,6 +15,8 @@ import (
 	"github.com/drone/drone/store/repos"
 	"github.com/drone/drone/store/shared/db"
 	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/step"
+	"github.com/google/go-cmp/cmp"
 )
 
 var noContext = context.TODO()
@@ -43,6 +45,7 @@ func TestStage(t *testing.T) {
 	store := New(conn).(*stageStore)
 	t.Run("Create", testStageCreate(store, abuild))
 	t.Run("ListState", testStageListStatus(store, abuild))
+	t.Run("Stats", testStageStats(store, builds, step.New(conn)))
 }
 
 func testStageCreate(store *stageStore, build *core.Build) func(t *testing.T) {
@@ -248,3 +251,95 @@ func testStage(item *core.Stage) func(t *testing.T) {
 		}
 	}
 }
+
+func testStageStats(store *stageStore, builds core.BuildStore, steps core.StepStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		seed := []struct {
+			build  *core.Build
+			stages []*core.Stage
+			steps  [][]*core.Step
+		}{
+			{
+				build: &core.Build{RepoID: 99, Number: 1, Target: "master", Created: 100},
+				stages: []*core.Stage{
+					{Number: 1, Name: "default", Status: core.StatusFailing, Created: 100, Started: 110, Stopped: 170},
+					{Number: 2, Name: "lint", Status: core.StatusPassing, Created: 100, Started: 130, Stopped: 140},
+				},
+				steps: [][]*core.Step{
+					{
+						{Number: 1, Name: "build", Status: core.StatusPassing},
+						{Number: 2, Name: "test", Status: core.StatusFailing},
+					},
+					nil,
+				},
+			},
+			{
+				build: &core.Build{RepoID: 99, Number: 2, Target: "develop", Created: 200},
+				stages: []*core.Stage{
+					{Number: 1, Name: "default", Status: core.StatusFailing, Created: 200, Started: 205, Stopped: 305},
+				},
+				steps: [][]*core.Step{
+					{
+						{Number: 1, Name: "test", Status: core.StatusFailing},
+						{Number: 2, Name: "deploy", Status: core.StatusError},
+					},
+				},
+			},
+		}
+		for _, item := range seed {
+			builds.Create(noContext, item.build, nil)
+			for i, stage := range item.stages {
+				stage.RepoID = item.build.RepoID
+				stage.BuildID = item.build.ID
+				store.Create(noContext, stage)
+				for _, step := range item.steps[i] {
+					step.StageID = stage.ID
+					steps.Create(noContext, step)
+				}
+			}
+		}
+
+		tests := []struct {
+			filter *core.AnalyticsFilter
+			want   *core.StageStats
+		}{
+			{
+				filter: &core.AnalyticsFilter{Repo: 99, Since: 0, Until: 1000},
+				want: &core.StageStats{
+					Duration:  core.Percentiles{P50: 60, P90: 100, P99: 100},
+					QueueTime: core.Percentiles{P50: 10, P90: 30, P99: 30},
+					FailingSteps: []*core.StepFailure{
+						{Pipeline: "default", Step: "test", Count: 2},
+						{Pipeline: "default", Step: "deploy", Count: 1},
+					},
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 99, Branch: "master", Pipeline: "default", Since: 0, Until: 1000},
+				want: &core.StageStats{
+					Duration:  core.Percentiles{P50: 60, P90: 60, P99: 60},
+					QueueTime: core.Percentiles{P50: 10, P90: 10, P99: 10},
+					FailingSteps: []*core.StepFailure{
+						{Pipeline: "default", Step: "test", Count: 1},
+					},
+				},
+			},
+			{
+				filter: &core.AnalyticsFilter{Repo: 99, Since: 1000, Until: 2000},
+				want: &core.StageStats{
+					FailingSteps: []*core.StepFailure{},
+				},
+			},
+		}
+		for _, test := range tests {
+			got, err := store.Stats(noContext, test.filter)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if diff := cmp.Diff(got, test.want); diff != "" {
+				t.Errorf(diff)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stage
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// failingStepLimit is the maximum number of failing steps
+// returned with the stage statistics.
+const failingStepLimit = 10
+
+// Stats returns aggregate stage and step statistics for the
+// repository, branch, pipeline and time window of the filter.
+func (s *stageStore) Stats(ctx context.Context, filter *core.AnalyticsFilter) (*core.StageStats, error) {
+	out := new(core.StageStats)
+	params := map[string]interface{}{
+		"build_repo_id": filter.Repo,
+		"build_target":  filter.Branch,
+		"stage_name":    filter.Pipeline,
+		"since":         filter.Since,
+		"until":         filter.Until,
+		"limit":         failingStepLimit,
+	}
+	where := queryStatsWhere
+	if filter.Branch != "" {
+		where = where + queryStatsBranch
+	}
+	if filter.Pipeline != "" {
+		where = where + queryStatsPipeline
+	}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		durations, err := db.Percentiles(queryer, binder,
+			queryStatsCount+where+queryStatsDurationWhere,
+			queryStatsDuration+where+queryStatsDurationWhere+queryStatsRank,
+			params, 50, 90, 99,
+		)
+		if err != nil {
+			return err
+		}
+		out.Duration.P50 = durations[0]
+		out.Duration.P90 = durations[1]
+		out.Duration.P99 = durations[2]
+
+		queued, err := db.Percentiles(queryer, binder,
+			queryStatsCount+where+queryStatsQueueWhere,
+			queryStatsQueue+where+queryStatsQueueWhere+queryStatsRank,
+			params, 50, 90, 99,
+		)
+		if err != nil {
+			return err
+		}
+		out.QueueTime.P50 = queued[0]
+		out.QueueTime.P90 = queued[1]
+		out.QueueTime.P99 = queued[2]
+
+		query, args, err := binder.BindNamed(queryStatsSteps+where+queryStatsStepsGroup, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		out.FailingSteps = []*core.StepFailure{}
+		for rows.Next() {
+			step := new(core.StepFailure)
+			err := rows.Scan(&step.Pipeline, &step.Step, &step.Count)
+			if err != nil {
+				return err
+			}
+			out.FailingSteps = append(out.FailingSteps, step)
+		}
+		return rows.Err()
+	})
+	return out, err
+}
+
+const queryStatsWhere = `
+INNER JOIN builds ON builds.build_id = stages.stage_build_id
+WHERE build_repo_id = :build_repo_id
+AND build_created >= :since
+AND build_created < :until
+`
+
+const queryStatsBranch = `
+AND build_target = :build_target
+`
+
+const queryStatsPipeline = `
+AND stage_name = :stage_name
+`
+
+const queryStatsCount = `
+SELECT COUNT(*)
+FROM stages
+`
+
+const queryStatsDuration = `
+SELECT stage_stopped - stage_started
+FROM stages
+`
+
+const queryStatsDurationWhere = `
+AND stage_status IN ('success', 'failure', 'error', 'killed')
+AND stage_started > 0
+AND stage_stopped >= stage_started
+`
+
+const queryStatsQueue = `
+SELECT stage_started - stage_created
+FROM stages
+`
+
+const queryStatsQueueWhere = `
+AND stage_started > 0
+AND stage_started >= stage_created
+`
+
+const queryStatsRank = `
+ORDER BY 1
+LIMIT 1 OFFSET :offset
+`
+
+const queryStatsSteps = `
+SELECT stage_name, step_name, COUNT(*)
+FROM steps
+INNER JOIN stages ON stages.stage_id = steps.step_stage_id
+`
+
+const queryStatsStepsGroup = `
+AND step_status IN ('failure', 'error')
+GROUP BY stage_name, step_name
+ORDER BY 3 DESC, 1, 2
+LIMIT :limit
+`
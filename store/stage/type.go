@@ -32,6 +32,13 @@ type nullStep struct {
 	Started   sql.NullInt64
 	Stopped   sql.NullInt64
 	Version   sql.NullInt64
+	CPUPeak   sql.NullInt64
+	CPUAvg    sql.NullInt64
+	MemPeak   sql.NullInt64
+	MemAvg    sql.NullInt64
+	IORead    sql.NullInt64
+	IOWrite   sql.NullInt64
+	Truncated sql.NullBool
 }
 
 func (s *nullStep) value() *core.Step {
@@ -47,5 +54,12 @@ func (s *nullStep) value() *core.Step {
 		Started:   s.Started.Int64,
 		Stopped:   s.Stopped.Int64,
 		Version:   s.Version.Int64,
+		CPUPeak:   s.CPUPeak.Int64,
+		CPUAvg:    s.CPUAvg.Int64,
+		MemPeak:   s.MemPeak.Int64,
+		MemAvg:    s.MemAvg.Int64,
+		IORead:    s.IORead.Int64,
+		IOWrite:   s.IOWrite.Int64,
+		Truncated: s.Truncated.Bool,
 	}
 }
@@ -36,6 +36,13 @@ func toParams(from *core.Step) map[string]interface{} {
 		"step_started":   from.Started,
 		"step_stopped":   from.Stopped,
 		"step_version":   from.Version,
+		"step_cpu_peak":  from.CPUPeak,
+		"step_cpu_avg":   from.CPUAvg,
+		"step_mem_peak":  from.MemPeak,
+		"step_mem_avg":   from.MemAvg,
+		"step_io_read":   from.IORead,
+		"step_io_write":  from.IOWrite,
+		"step_truncated": from.Truncated,
 	}
 }
 
@@ -54,6 +61,13 @@ func scanRow(scanner db.Scanner, dest *core.Step) error {
 		&dest.Started,
 		&dest.Stopped,
 		&dest.Version,
+		&dest.CPUPeak,
+		&dest.CPUAvg,
+		&dest.MemPeak,
+		&dest.MemAvg,
+		&dest.IORead,
+		&dest.IOWrite,
+		&dest.Truncated,
 	)
 }
 
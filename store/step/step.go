@@ -156,6 +156,13 @@ SELECT
 ,step_started
 ,step_stopped
 ,step_version
+,step_cpu_peak
+,step_cpu_avg
+,step_mem_peak
+,step_mem_avg
+,step_io_read
+,step_io_write
+,step_truncated
 `
 
 const queryKey = queryBase + `
@@ -185,6 +192,13 @@ SET
 ,step_started = :step_started
 ,step_stopped = :step_stopped
 ,step_version = :step_version_new
+,step_cpu_peak = :step_cpu_peak
+,step_cpu_avg = :step_cpu_avg
+,step_mem_peak = :step_mem_peak
+,step_mem_avg = :step_mem_avg
+,step_io_read = :step_io_read
+,step_io_write = :step_io_write
+,step_truncated = :step_truncated
 WHERE step_id = :step_id
   AND step_version = :step_version_old
 `
@@ -201,6 +215,13 @@ INSERT INTO steps (
 ,step_started
 ,step_stopped
 ,step_version
+,step_cpu_peak
+,step_cpu_avg
+,step_mem_peak
+,step_mem_avg
+,step_io_read
+,step_io_write
+,step_truncated
 ) VALUES (
  :step_stage_id
 ,:step_number
@@ -212,6 +233,13 @@ INSERT INTO steps (
 ,:step_started
 ,:step_stopped
 ,:step_version
+,:step_cpu_peak
+,:step_cpu_avg
+,:step_mem_peak
+,:step_mem_avg
+,:step_io_read
+,:step_io_write
+,:step_truncated
 )
 `
 
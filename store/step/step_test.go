@@ -118,15 +118,22 @@ func testStepList(store *stepStore, stage *core.Stage) func(t *testing.T) {
 func testStepUpdate(store *stepStore, step *core.Step) func(t *testing.T) {
 	return func(t *testing.T) {
 		before := &core.Step{
-			ID:       step.ID,
-			StageID:  step.StageID,
-			Number:   2,
-			Name:     "clone",
-			ExitCode: 255,
-			Started:  1522878684,
-			Stopped:  1522878690,
-			Status:   core.StatusFailing,
-			Version:  step.Version,
+			ID:        step.ID,
+			StageID:   step.StageID,
+			Number:    2,
+			Name:      "clone",
+			ExitCode:  255,
+			Started:   1522878684,
+			Stopped:   1522878690,
+			Status:    core.StatusFailing,
+			Version:   step.Version,
+			CPUPeak:   1500,
+			CPUAvg:    750,
+			MemPeak:   268435456,
+			MemAvg:    134217728,
+			IORead:    4096,
+			IOWrite:   8192,
+			Truncated: true,
 		}
 		err := store.Update(noContext, before)
 		if err != nil {
@@ -153,6 +160,18 @@ func testStepUpdate(store *stepStore, step *core.Step) func(t *testing.T) {
 		if got, want := after.Stopped, before.Stopped; got != want {
 			t.Errorf("Want updated Stopped %v, got %v", want, got)
 		}
+		if got, want := after.CPUPeak, before.CPUPeak; got != want {
+			t.Errorf("Want updated CPUPeak %v, got %v", want, got)
+		}
+		if got, want := after.MemPeak, before.MemPeak; got != want {
+			t.Errorf("Want updated MemPeak %v, got %v", want, got)
+		}
+		if got, want := after.IOWrite, before.IOWrite; got != want {
+			t.Errorf("Want updated IOWrite %v, got %v", want, got)
+		}
+		if got, want := after.Truncated, before.Truncated; got != want {
+			t.Errorf("Want updated Truncated %v, got %v", want, got)
+		}
 	}
 }
 
@@ -0,0 +1,88 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// helper function converts the Subscription structure to a
+// set of named query parameters.
+func toParams(encrypt encrypt.Encrypter, subscription *core.Subscription) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(subscription.Target)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"subscription_id":       subscription.ID,
+		"subscription_repo_id":  subscription.RepoID,
+		"subscription_user_id":  subscription.UserID,
+		"subscription_kind":     subscription.Kind,
+		"subscription_target":   ciphertext,
+		"subscription_trigger":  subscription.Trigger,
+		"subscription_template": subscription.Template,
+		"subscription_disabled": subscription.Disabled,
+		"subscription_created":  subscription.Created,
+		"subscription_updated":  subscription.Updated,
+	}, nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Subscription) error {
+	var ciphertext []byte
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.UserID,
+		&dst.Kind,
+		&ciphertext,
+		&dst.Trigger,
+		&dst.Template,
+		&dst.Disabled,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return err
+	}
+	plaintext, err := encrypt.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	dst.Target = plaintext
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Subscription, error) {
+	defer rows.Close()
+
+	subscriptions := []*core.Subscription{}
+	for rows.Next() {
+		subscription := new(core.Subscription)
+		err := scanRow(encrypt, rows, subscription)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
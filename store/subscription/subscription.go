@@ -0,0 +1,237 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+)
+
+// New returns a new SubscriptionStore. Subscription targets,
+// which may include chat webhook tokens, are encrypted at rest.
+func New(db *db.DB, enc encrypt.Encrypter) core.SubscriptionStore {
+	return &subscriptionStore{
+		db:  db,
+		enc: enc,
+	}
+}
+
+type subscriptionStore struct {
+	db  *db.DB
+	enc encrypt.Encrypter
+}
+
+func (s *subscriptionStore) List(ctx context.Context, repo int64) ([]*core.Subscription, error) {
+	var out []*core.Subscription
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"subscription_repo_id": repo}
+		stmt, args, err := binder.BindNamed(queryRepo, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *subscriptionStore) ListUser(ctx context.Context, user int64) ([]*core.Subscription, error) {
+	var out []*core.Subscription
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"subscription_user_id": user}
+		stmt, args, err := binder.BindNamed(queryUser, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(s.enc, rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *subscriptionStore) Find(ctx context.Context, id int64) (*core.Subscription, error) {
+	out := &core.Subscription{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params, err := toParams(s.enc, out)
+		if err != nil {
+			return err
+		}
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(s.enc, row, out)
+	})
+	return out, err
+}
+
+func (s *subscriptionStore) Create(ctx context.Context, subscription *core.Subscription) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, subscription)
+	}
+	return s.create(ctx, subscription)
+}
+
+func (s *subscriptionStore) create(ctx context.Context, subscription *core.Subscription) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, subscription)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		subscription.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *subscriptionStore) createPostgres(ctx context.Context, subscription *core.Subscription) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, subscription)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&subscription.ID)
+	})
+}
+
+func (s *subscriptionStore) Update(ctx context.Context, subscription *core.Subscription) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, subscription)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtUpdate, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+func (s *subscriptionStore) Delete(ctx context.Context, subscription *core.Subscription) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params, err := toParams(s.enc, subscription)
+		if err != nil {
+			return err
+		}
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ subscription_id
+,subscription_repo_id
+,subscription_user_id
+,subscription_kind
+,subscription_target
+,subscription_trigger
+,subscription_template
+,subscription_disabled
+,subscription_created
+,subscription_updated
+`
+
+const queryKey = queryBase + `
+FROM subscriptions
+WHERE subscription_id = :subscription_id
+LIMIT 1
+`
+
+const queryRepo = queryBase + `
+FROM subscriptions
+WHERE subscription_repo_id = :subscription_repo_id
+ORDER BY subscription_id
+`
+
+const queryUser = queryBase + `
+FROM subscriptions
+WHERE subscription_user_id = :subscription_user_id
+  AND subscription_repo_id = 0
+ORDER BY subscription_id
+`
+
+const stmtUpdate = `
+UPDATE subscriptions SET
+ subscription_kind = :subscription_kind
+,subscription_target = :subscription_target
+,subscription_trigger = :subscription_trigger
+,subscription_template = :subscription_template
+,subscription_disabled = :subscription_disabled
+,subscription_updated = :subscription_updated
+WHERE subscription_id = :subscription_id
+`
+
+const stmtDelete = `
+DELETE FROM subscriptions
+WHERE subscription_id = :subscription_id
+`
+
+const stmtInsert = `
+INSERT INTO subscriptions (
+ subscription_repo_id
+,subscription_user_id
+,subscription_kind
+,subscription_target
+,subscription_trigger
+,subscription_template
+,subscription_disabled
+,subscription_created
+,subscription_updated
+) VALUES (
+ :subscription_repo_id
+,:subscription_user_id
+,:subscription_kind
+,:subscription_target
+,:subscription_trigger
+,:subscription_template
+,:subscription_disabled
+,:subscription_created
+,:subscription_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING subscription_id
+`
@@ -0,0 +1,133 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestSubscription(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	enc, _ := encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	store := New(conn, enc).(*subscriptionStore)
+
+	subscriptions := []*core.Subscription{
+		{
+			RepoID:   1,
+			Kind:     core.SubscriptionChat,
+			Target:   "https://chat.company.com/hooks/GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+			Trigger:  core.NotifyFailure,
+			Template: "{{ .Repo.Slug }} failed",
+			Created:  1000,
+			Updated:  1000,
+		},
+		{
+			UserID:  2,
+			Kind:    core.SubscriptionEmail,
+			Target:  "octocat@github.com",
+			Trigger: core.NotifyAlways,
+			Created: 1000,
+			Updated: 1000,
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, subscription := range subscriptions {
+			err := store.Create(noContext, subscription)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if subscription.ID == 0 {
+				t.Errorf("Want subscription ID assigned")
+			}
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		got, err := store.Find(noContext, subscriptions[0].ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, subscriptions[0]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, subscriptions[:1]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("ListUser", func(t *testing.T) {
+		list, err := store.ListUser(noContext, 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, subscriptions[1:]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		subscription := subscriptions[0]
+		subscription.Trigger = core.NotifyFixed
+		subscription.Template = ""
+		subscription.Disabled = true
+		subscription.Updated = 1001
+		err := store.Update(noContext, subscription)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got, err := store.Find(noContext, subscription.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, subscription); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := store.Delete(noContext, subscriptions[0])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, subscriptions[0].ID)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package template
+
+import (
+	"database/sql"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// helper function converts the Template structure to a set
+// of named query parameters.
+func toParams(template *core.Template) map[string]interface{} {
+	return map[string]interface{}{
+		"template_id":        template.ID,
+		"template_namespace": template.Namespace,
+		"template_name":      template.Name,
+		"template_version":   template.Version,
+		"template_data":      template.Data,
+		"template_created":   template.Created,
+		"template_updated":   template.Updated,
+	}
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(scanner db.Scanner, dst *core.Template) error {
+	return scanner.Scan(
+		&dst.ID,
+		&dst.Namespace,
+		&dst.Name,
+		&dst.Version,
+		&dst.Data,
+		&dst.Created,
+		&dst.Updated,
+	)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(rows *sql.Rows) ([]*core.Template, error) {
+	defer rows.Close()
+
+	templates := []*core.Template{}
+	for rows.Next() {
+		template := new(core.Template)
+		err := scanRow(rows, template)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
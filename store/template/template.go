@@ -0,0 +1,203 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package template
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new Template database store.
+func New(db *db.DB) core.TemplateStore {
+	return &templateStore{db}
+}
+
+type templateStore struct {
+	db *db.DB
+}
+
+func (s *templateStore) List(ctx context.Context, namespace string) ([]*core.Template, error) {
+	var out []*core.Template
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{"template_namespace": namespace}
+		stmt, args, err := binder.BindNamed(queryNamespace, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *templateStore) ListAll(ctx context.Context) ([]*core.Template, error) {
+	var out []*core.Template
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		rows, err := queryer.Query(queryAll)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *templateStore) Find(ctx context.Context, id int64) (*core.Template, error) {
+	out := &core.Template{ID: id}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query, args, err := binder.BindNamed(queryKey, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *templateStore) FindName(ctx context.Context, namespace, name, version string) (*core.Template, error) {
+	out := &core.Template{Namespace: namespace, Name: name, Version: version}
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := toParams(out)
+		query := queryName
+		if version == "" {
+			query = queryLatest
+		}
+		query, args, err := binder.BindNamed(query, params)
+		if err != nil {
+			return err
+		}
+		row := queryer.QueryRow(query, args...)
+		return scanRow(row, out)
+	})
+	return out, err
+}
+
+func (s *templateStore) Create(ctx context.Context, template *core.Template) error {
+	if s.db.Driver() == db.Postgres {
+		return s.createPostgres(ctx, template)
+	}
+	return s.create(ctx, template)
+}
+
+func (s *templateStore) create(ctx context.Context, template *core.Template) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(template)
+		stmt, args, err := binder.BindNamed(stmtInsert, params)
+		if err != nil {
+			return err
+		}
+		res, err := execer.Exec(stmt, args...)
+		if err != nil {
+			return err
+		}
+		template.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+func (s *templateStore) createPostgres(ctx context.Context, template *core.Template) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(template)
+		stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+		if err != nil {
+			return err
+		}
+		return execer.QueryRow(stmt, args...).Scan(&template.ID)
+	})
+}
+
+func (s *templateStore) Delete(ctx context.Context, template *core.Template) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := toParams(template)
+		stmt, args, err := binder.BindNamed(stmtDelete, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ template_id
+,template_namespace
+,template_name
+,template_version
+,template_data
+,template_created
+,template_updated
+`
+
+const queryKey = queryBase + `
+FROM templates
+WHERE template_id = :template_id
+LIMIT 1
+`
+
+const queryAll = queryBase + `
+FROM templates
+ORDER BY template_namespace, template_name, template_id
+`
+
+const queryNamespace = queryBase + `
+FROM templates
+WHERE template_namespace = :template_namespace
+ORDER BY template_name, template_id
+`
+
+const queryName = queryBase + `
+FROM templates
+WHERE template_namespace = :template_namespace
+  AND template_name = :template_name
+  AND template_version = :template_version
+LIMIT 1
+`
+
+const queryLatest = queryBase + `
+FROM templates
+WHERE template_namespace = :template_namespace
+  AND template_name = :template_name
+ORDER BY template_id DESC
+LIMIT 1
+`
+
+const stmtDelete = `
+DELETE FROM templates
+WHERE template_id = :template_id
+`
+
+const stmtInsert = `
+INSERT INTO templates (
+ template_namespace
+,template_name
+,template_version
+,template_data
+,template_created
+,template_updated
+) VALUES (
+ :template_namespace
+,:template_name
+,:template_version
+,:template_data
+,:template_created
+,:template_updated
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING template_id
+`
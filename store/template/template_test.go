@@ -0,0 +1,186 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+// +build !oss
+
+package template
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestTemplate(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	store := New(conn).(*templateStore)
+	t.Run("Create", testTemplateCreate(store))
+}
+
+func testTemplateCreate(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Template{
+			Namespace: "octocat",
+			Name:      "golang",
+			Version:   "1.0.0",
+			Data:      "kind: pipeline",
+		}
+		err := store.Create(noContext, item)
+		if err != nil {
+			t.Error(err)
+		}
+		if item.ID == 0 {
+			t.Errorf("Want template ID assigned, got %d", item.ID)
+		}
+
+		t.Run("Find", testTemplateFind(store, item))
+		t.Run("FindName", testTemplateFindName(store))
+		t.Run("FindLatest", testTemplateFindLatest(store))
+		t.Run("List", testTemplateList(store))
+		t.Run("ListAll", testTemplateListAll(store))
+		t.Run("Duplicate", testTemplateDuplicate(store))
+		t.Run("Delete", testTemplateDelete(store))
+	}
+}
+
+func testTemplateFind(store *templateStore, template *core.Template) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.Find(noContext, template.ID)
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testTemplate(item))
+		}
+	}
+}
+
+func testTemplateFindName(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item, err := store.FindName(noContext, "octocat", "golang", "1.0.0")
+		if err != nil {
+			t.Error(err)
+		} else {
+			t.Run("Fields", testTemplate(item))
+		}
+	}
+}
+
+func testTemplateFindLatest(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		next := &core.Template{
+			Namespace: "octocat",
+			Name:      "golang",
+			Version:   "2.0.0",
+			Data:      "kind: pipeline",
+		}
+		err := store.Create(noContext, next)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer store.Delete(noContext, next)
+
+		item, err := store.FindName(noContext, "octocat", "golang", "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := item.Version, "2.0.0"; got != want {
+			t.Errorf("Want latest template version %q, got %q", want, got)
+		}
+	}
+}
+
+func testTemplateList(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.List(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testTemplate(list[0]))
+		}
+	}
+}
+
+func testTemplateListAll(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		list, err := store.ListAll(noContext)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want count %d, got %d", want, got)
+		} else {
+			t.Run("Fields", testTemplate(list[0]))
+		}
+	}
+}
+
+func testTemplateDuplicate(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		item := &core.Template{
+			Namespace: "octocat",
+			Name:      "golang",
+			Version:   "1.0.0",
+			Data:      "kind: pipeline\nname: default",
+		}
+		err := store.Create(noContext, item)
+		if err == nil {
+			t.Errorf("Want error creating a duplicate template version")
+		}
+	}
+}
+
+func testTemplateDelete(store *templateStore) func(t *testing.T) {
+	return func(t *testing.T) {
+		template, err := store.FindName(noContext, "octocat", "golang", "1.0.0")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = store.Delete(noContext, template)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, template.ID)
+		if got, want := sql.ErrNoRows, err; got != want {
+			t.Errorf("Want sql.ErrNoRows, got %v", got)
+			return
+		}
+	}
+}
+
+func testTemplate(item *core.Template) func(t *testing.T) {
+	return func(t *testing.T) {
+		if got, want := item.Namespace, "octocat"; got != want {
+			t.Errorf("Want template namespace %q, got %q", want, got)
+		}
+		if got, want := item.Name, "golang"; got != want {
+			t.Errorf("Want template name %q, got %q", want, got)
+		}
+		if got, want := item.Version, "1.0.0"; got != want {
+			t.Errorf("Want template version %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+)
+
+// New returns a new TestStore.
+func New(db *db.DB) core.TestStore {
+	return &testStore{db}
+}
+
+type testStore struct {
+	db *db.DB
+}
+
+func (s *testStore) List(ctx context.Context, build int64) ([]*core.TestCase, error) {
+	params := map[string]interface{}{"test_build_id": build}
+	return s.list(ctx, queryBuild, params)
+}
+
+func (s *testStore) ListStage(ctx context.Context, stage int64) ([]*core.TestCase, error) {
+	params := map[string]interface{}{"test_stage_id": stage}
+	return s.list(ctx, queryStage, params)
+}
+
+func (s *testStore) ListFailed(ctx context.Context, build int64) ([]*core.TestCase, error) {
+	params := map[string]interface{}{
+		"test_build_id": build,
+		"test_status":   core.TestFailed,
+	}
+	return s.list(ctx, queryStatus, params)
+}
+
+func (s *testStore) History(ctx context.Context, repo int64, suite, name string, limit int) ([]*core.TestCase, error) {
+	params := map[string]interface{}{
+		"test_repo_id": repo,
+		"test_suite":   suite,
+		"test_name":    name,
+		"limit":        limit,
+	}
+	return s.list(ctx, queryHistory, params)
+}
+
+func (s *testStore) list(ctx context.Context, query string, params map[string]interface{}) ([]*core.TestCase, error) {
+	var out []*core.TestCase
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		stmt, args, err := binder.BindNamed(query, params)
+		if err != nil {
+			return err
+		}
+		rows, err := queryer.Query(stmt, args...)
+		if err != nil {
+			return err
+		}
+		out, err = scanRows(rows)
+		return err
+	})
+	return out, err
+}
+
+func (s *testStore) Summary(ctx context.Context, build int64) (*core.TestSummary, error) {
+	out := new(core.TestSummary)
+	err := s.db.View(func(queryer db.Queryer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"test_build_id": build,
+			"passed":        core.TestPassed,
+			"failed":        core.TestFailed,
+			"skipped":       core.TestSkipped,
+		}
+		stmt, args, err := binder.BindNamed(querySummary, params)
+		if err != nil {
+			return err
+		}
+		return queryer.QueryRow(stmt, args...).Scan(
+			&out.Total,
+			&out.Passed,
+			&out.Failed,
+			&out.Skipped,
+		)
+	})
+	return out, err
+}
+
+func (s *testStore) Create(ctx context.Context, tests []*core.TestCase) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		for _, test := range tests {
+			params := toParams(test)
+			if s.db.Driver() == db.Postgres {
+				stmt, args, err := binder.BindNamed(stmtInsertPg, params)
+				if err != nil {
+					return err
+				}
+				err = execer.QueryRow(stmt, args...).Scan(&test.ID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			stmt, args, err := binder.BindNamed(stmtInsert, params)
+			if err != nil {
+				return err
+			}
+			res, err := execer.Exec(stmt, args...)
+			if err != nil {
+				return err
+			}
+			test.ID, err = res.LastInsertId()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *testStore) Purge(ctx context.Context, repo, number int64) error {
+	return s.db.Lock(func(execer db.Execer, binder db.Binder) error {
+		params := map[string]interface{}{
+			"test_repo_id": repo,
+			"build_number": number,
+		}
+		stmt, args, err := binder.BindNamed(stmtPurge, params)
+		if err != nil {
+			return err
+		}
+		_, err = execer.Exec(stmt, args...)
+		return err
+	})
+}
+
+const queryBase = `
+SELECT
+ test_id
+,test_repo_id
+,test_build_id
+,test_stage_id
+,test_step_id
+,build_number
+,test_suite
+,test_name
+,test_status
+,test_duration
+,test_message
+,test_created
+FROM tests
+INNER JOIN builds ON test_build_id = build_id
+`
+
+const queryBuild = queryBase + `
+WHERE test_build_id = :test_build_id
+ORDER BY test_suite, test_name
+`
+
+const queryStage = queryBase + `
+WHERE test_stage_id = :test_stage_id
+ORDER BY test_suite, test_name
+`
+
+const queryStatus = queryBase + `
+WHERE test_build_id = :test_build_id
+  AND test_status = :test_status
+ORDER BY test_suite, test_name
+`
+
+const queryHistory = queryBase + `
+WHERE test_repo_id = :test_repo_id
+  AND test_suite = :test_suite
+  AND test_name = :test_name
+ORDER BY test_id DESC
+LIMIT :limit
+`
+
+const querySummary = `
+SELECT
+ COUNT(*)
+,COALESCE(SUM(CASE WHEN test_status = :passed THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN test_status = :failed THEN 1 ELSE 0 END), 0)
+,COALESCE(SUM(CASE WHEN test_status = :skipped THEN 1 ELSE 0 END), 0)
+FROM tests
+WHERE test_build_id = :test_build_id
+`
+
+const stmtInsert = `
+INSERT INTO tests (
+ test_repo_id
+,test_build_id
+,test_stage_id
+,test_step_id
+,test_suite
+,test_name
+,test_status
+,test_duration
+,test_message
+,test_created
+) VALUES (
+ :test_repo_id
+,:test_build_id
+,:test_stage_id
+,:test_step_id
+,:test_suite
+,:test_name
+,:test_status
+,:test_duration
+,:test_message
+,:test_created
+)
+`
+
+const stmtInsertPg = stmtInsert + `
+RETURNING test_id
+`
+
+const stmtPurge = `
+DELETE FROM tests
+WHERE test_repo_id = :test_repo_id
+AND test_build_id IN (
+  SELECT build_id FROM builds
+  WHERE build_repo_id = :test_repo_id
+  AND build_number < :build_number
+)
+`
@@ -0,0 +1,161 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/build"
+	"github.com/drone/drone/store/repos"
+	"github.com/drone/drone/store/shared/db/dbtest"
+)
+
+var noContext = context.TODO()
+
+func TestTestCase(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	// seed with a dummy repository
+	arepo := &core.Repository{UID: "1", Slug: "octocat/hello-world"}
+	repos := repos.New(conn)
+	repos.Create(noContext, arepo)
+
+	// seed with two dummy builds, each with a single stage
+	builds := build.New(conn)
+	stage1 := &core.Stage{Number: 1}
+	build1 := &core.Build{Number: 1, RepoID: arepo.ID}
+	builds.Create(noContext, build1, []*core.Stage{stage1})
+	stage2 := &core.Stage{Number: 1}
+	build2 := &core.Build{Number: 2, RepoID: arepo.ID}
+	builds.Create(noContext, build2, []*core.Stage{stage2})
+
+	store := New(conn).(*testStore)
+	t.Run("Create", func(t *testing.T) {
+		tests := []*core.TestCase{
+			{RepoID: arepo.ID, BuildID: build1.ID, StageID: stage1.ID, Suite: "math", Name: "TestAdd", Status: core.TestPassed},
+			{RepoID: arepo.ID, BuildID: build2.ID, StageID: stage2.ID, Suite: "math", Name: "TestAdd", Status: core.TestFailed, Message: "1 + 1 != 3"},
+			{RepoID: arepo.ID, BuildID: build2.ID, StageID: stage2.ID, Suite: "math", Name: "TestSub", Status: core.TestPassed},
+			{RepoID: arepo.ID, BuildID: build2.ID, StageID: stage2.ID, Suite: "math", Name: "TestDiv", Status: core.TestSkipped},
+		}
+		err := store.Create(noContext, tests)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, test := range tests {
+			if test.ID == 0 {
+				t.Errorf("Want test case ID assigned")
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, build2.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 3; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Name, "TestAdd"; got != want {
+			t.Errorf("Want test cases sorted by name, got %s", got)
+		}
+		if got, want := list[0].Number, build2.Number; got != want {
+			t.Errorf("Want build number %d, got %d", want, got)
+		}
+	})
+
+	t.Run("ListStage", func(t *testing.T) {
+		list, err := store.ListStage(noContext, stage1.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+		}
+	})
+
+	t.Run("ListFailed", func(t *testing.T) {
+		list, err := store.ListFailed(noContext, build2.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 1; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Message, "1 + 1 != 3"; got != want {
+			t.Errorf("Want failure message %q, got %q", want, got)
+		}
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		summary, err := store.Summary(noContext, build2.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		want := &core.TestSummary{Total: 3, Passed: 1, Failed: 1, Skipped: 1}
+		if *summary != *want {
+			t.Errorf("Want summary %v, got %v", want, summary)
+		}
+	})
+
+	t.Run("History", func(t *testing.T) {
+		list, err := store.History(noContext, arepo.ID, "math", "TestAdd", 25)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 2; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+			return
+		}
+		if got, want := list[0].Number, build2.Number; got != want {
+			t.Errorf("Want most recent build first, got build %d", got)
+		}
+		if got, want := list[1].Status, core.TestPassed; got != want {
+			t.Errorf("Want status %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		err := store.Purge(noContext, arepo.ID, build2.Number)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := store.List(noContext, build1.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 0; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+		}
+		list, err = store.List(noContext, build2.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if got, want := len(list), 3; got != want {
+			t.Errorf("Want %d test cases, got %d", want, got)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// helper function converts the Webhook structure to a set
+// of named query parameters.
+func toParams(encrypt encrypt.Encrypter, webhook *core.Webhook) (map[string]interface{}, error) {
+	ciphertext, err := encrypt.Encrypt(webhook.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"webhook_id":          webhook.ID,
+		"webhook_repo_id":     webhook.RepoID,
+		"webhook_namespace":   webhook.Namespace,
+		"webhook_endpoint":    webhook.Endpoint,
+		"webhook_secret":      ciphertext,
+		"webhook_skip_verify": webhook.SkipVerify,
+		"webhook_events":      encodeSlice(webhook.Events),
+		"webhook_disabled":    webhook.Disabled,
+		"webhook_created":     webhook.Created,
+		"webhook_updated":     webhook.Updated,
+	}, nil
+}
+
+func encodeSlice(v []string) types.JSONText {
+	if v == nil {
+		v = []string{}
+	}
+	raw, _ := json.Marshal(v)
+	return types.JSONText(raw)
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRow(encrypt encrypt.Encrypter, scanner db.Scanner, dst *core.Webhook) error {
+	var ciphertext []byte
+	eventsJSON := types.JSONText{}
+	err := scanner.Scan(
+		&dst.ID,
+		&dst.RepoID,
+		&dst.Namespace,
+		&dst.Endpoint,
+		&ciphertext,
+		&dst.SkipVerify,
+		&eventsJSON,
+		&dst.Disabled,
+		&dst.Created,
+		&dst.Updated,
+	)
+	if err != nil {
+		return err
+	}
+	plaintext, err := encrypt.Decrypt(ciphertext)
+	if err != nil {
+		return err
+	}
+	dst.Secret = plaintext
+	dst.Events = []string{}
+	json.Unmarshal(eventsJSON, &dst.Events)
+	return nil
+}
+
+// helper function scans the sql.Row and copies the column
+// values to the destination object.
+func scanRows(encrypt encrypt.Encrypter, rows *sql.Rows) ([]*core.Webhook, error) {
+	defer rows.Close()
+
+	webhooks := []*core.Webhook{}
+	for rows.Next() {
+		webhook := new(core.Webhook)
+		err := scanRow(encrypt, rows, webhook)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/drone/drone/core"
+	"github.com/drone/drone/store/shared/db/dbtest"
+	"github.com/drone/drone/store/shared/encrypt"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var noContext = context.TODO()
+
+func TestWebhook(t *testing.T) {
+	conn, err := dbtest.Connect()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer func() {
+		dbtest.Reset(conn)
+		dbtest.Disconnect(conn)
+	}()
+
+	enc, _ := encrypt.New("fb4b4d6267c8a5ce8231f8b186dbca92")
+	store := New(conn, enc).(*webhookStore)
+
+	webhooks := []*core.Webhook{
+		{
+			RepoID:     1,
+			Endpoint:   "https://company.com/hooks",
+			Secret:     "correct-horse-battery-staple",
+			SkipVerify: true,
+			Events:     []string{"build:created", "build:updated"},
+			Created:    1000,
+			Updated:    1000,
+		},
+		{
+			Namespace: "octocat",
+			Endpoint:  "https://example.com/hooks",
+			Secret:    "GMEuUHQfmrMRsseWxi9YlIeBtn9lm6im",
+			Events:    []string{},
+			Created:   1000,
+			Updated:   1000,
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		for _, webhook := range webhooks {
+			err := store.Create(noContext, webhook)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if webhook.ID == 0 {
+				t.Errorf("Want webhook ID assigned")
+			}
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		got, err := store.Find(noContext, webhooks[0].ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, webhooks[0]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		list, err := store.List(noContext, 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, webhooks[:1]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("ListNamespace", func(t *testing.T) {
+		list, err := store.ListNamespace(noContext, "octocat")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(list, webhooks[1:]); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		webhook := webhooks[0]
+		webhook.Endpoint = "https://company.com/hooks/v2"
+		webhook.Events = []string{"repo"}
+		webhook.Disabled = true
+		webhook.Updated = 1001
+		err := store.Update(noContext, webhook)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got, err := store.Find(noContext, webhook.ID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if diff := cmp.Diff(got, webhook); diff != "" {
+			t.Errorf(diff)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := store.Delete(noContext, webhooks[0])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = store.Find(noContext, webhooks[0].ID)
+		if err != sql.ErrNoRows {
+			t.Errorf("Want sql.ErrNoRows, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,243 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// batch configuration.
+const (
+	batchSize     = 256
+	batchInterval = 5 * time.Second
+	queueSize     = 4096
+)
+
+// OTLP returns an Exporter that exports spans to an
+// OpenTelemetry collector using the OTLP/HTTP protocol with
+// json encoding. Spans are exported in batches, in the
+// background. Spans are dropped if the export queue is full.
+func OTLP(endpoint, service string, headers map[string]string) Exporter {
+	e := newOTLP(endpoint, service, headers)
+	go e.start()
+	return e
+}
+
+func newOTLP(endpoint, service string, headers map[string]string) *otlp {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint = endpoint + "/v1/traces"
+	}
+	return &otlp{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		endpoint: endpoint,
+		service:  service,
+		headers:  headers,
+		queue:    make(chan *Span, queueSize),
+	}
+}
+
+type otlp struct {
+	client   *http.Client
+	endpoint string
+	service  string
+	headers  map[string]string
+	queue    chan *Span
+}
+
+// Export queues the span for export.
+func (e *otlp) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		logrus.Traceln("trace: export queue is full, dropping span")
+	}
+}
+
+func (e *otlp) start() {
+	ticker := time.NewTicker(batchInterval)
+	defer ticker.Stop()
+
+	var spans []*Span
+	for {
+		select {
+		case span := <-e.queue:
+			spans = append(spans, span)
+			if len(spans) < batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(spans) == 0 {
+				continue
+			}
+		}
+		if err := e.send(context.Background(), spans); err != nil {
+			logrus.WithError(err).Debugln("trace: cannot export spans")
+		}
+		spans = nil
+	}
+}
+
+// send exports the batch of spans to the collector.
+func (e *otlp) send(ctx context.Context, spans []*Span) error {
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(e.convert(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", e.endpoint, buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		return fmt.Errorf("trace: collector responded with status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+//
+// otlp json encoding
+//
+
+type (
+	exportRequest struct {
+		ResourceSpans []*resourceSpans `json:"resourceSpans"`
+	}
+
+	resourceSpans struct {
+		Resource   *resource     `json:"resource"`
+		ScopeSpans []*scopeSpans `json:"scopeSpans"`
+	}
+
+	resource struct {
+		Attributes []*keyValue `json:"attributes"`
+	}
+
+	scopeSpans struct {
+		Scope *scope      `json:"scope"`
+		Spans []*spanData `json:"spans"`
+	}
+
+	scope struct {
+		Name string `json:"name"`
+	}
+
+	spanData struct {
+		TraceID           string      `json:"traceId"`
+		SpanID            string      `json:"spanId"`
+		ParentSpanID      string      `json:"parentSpanId,omitempty"`
+		Name              string      `json:"name"`
+		Kind              int         `json:"kind"`
+		StartTimeUnixNano string      `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+		Attributes        []*keyValue `json:"attributes,omitempty"`
+		Status            *status     `json:"status,omitempty"`
+	}
+
+	keyValue struct {
+		Key   string    `json:"key"`
+		Value *anyValue `json:"value"`
+	}
+
+	anyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+
+	status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+// otlp span kind and status codes.
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+func (e *otlp) convert(spans []*Span) *exportRequest {
+	var out []*spanData
+	for _, span := range spans {
+		span.Lock()
+		data := &spanData{
+			TraceID:           hex.EncodeToString(span.Context.TraceID[:]),
+			SpanID:            hex.EncodeToString(span.Context.SpanID[:]),
+			Name:              span.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.Started.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.Stopped.UnixNano(), 10),
+			Attributes:        convertAttributes(span.Attributes),
+		}
+		if span.ParentID != [8]byte{} {
+			data.ParentSpanID = hex.EncodeToString(span.ParentID[:])
+		}
+		if span.Error != "" {
+			data.Status = &status{Code: statusCodeError, Message: span.Error}
+		}
+		span.Unlock()
+		out = append(out, data)
+	}
+	return &exportRequest{
+		ResourceSpans: []*resourceSpans{
+			{
+				Resource: &resource{
+					Attributes: convertAttributes(map[string]string{
+						"service.name": e.service,
+					}),
+				},
+				ScopeSpans: []*scopeSpans{
+					{
+						Scope: &scope{Name: "github.com/drone/drone/trace"},
+						Spans: out,
+					},
+				},
+			},
+		},
+	}
+}
+
+// helper function converts the attribute map to a list of
+// key value pairs, sorted by key.
+func convertAttributes(attrs map[string]string) []*keyValue {
+	var out []*keyValue
+	for k, v := range attrs {
+		out = append(out, &keyValue{Key: k, Value: &anyValue{StringValue: v}})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
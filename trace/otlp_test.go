@@ -0,0 +1,85 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLP(t *testing.T) {
+	var got *exportRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(404)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		got = new(exportRequest)
+		json.NewDecoder(r.Body).Decode(got)
+	}))
+	defer server.Close()
+
+	rec := new(recorder)
+	SetExporter(rec)
+	ctx, parent := Start(noContext, "hook")
+	_, child := Start(ctx, "trigger")
+	child.SetAttribute("repo", "octocat/hello-world")
+	child.SetError(errors.New("not found"))
+	child.End()
+	parent.End()
+	SetExporter(nil)
+
+	exporter := newOTLP(server.URL, "drone-server", map[string]string{"Authorization": "Bearer token"})
+	err := exporter.send(noContext, rec.spans)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if got, want := auth, "Bearer token"; got != want {
+		t.Errorf("Want authorization header %s, got %s", want, got)
+	}
+	if got == nil || len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Errorf("Want a single resource and scope")
+		return
+	}
+	if got, want := got.ResourceSpans[0].Resource.Attributes[0].Value.StringValue, "drone-server"; got != want {
+		t.Errorf("Want service name %s, got %s", want, got)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Errorf("Want 2 spans, got %d", len(spans))
+		return
+	}
+	if spans[0].Name != "trigger" || spans[0].ParentSpanID != spans[1].SpanID {
+		t.Errorf("Want child span with parent span id")
+	}
+	if spans[0].Status == nil || spans[0].Status.Code != statusCodeError {
+		t.Errorf("Want error status for failed span")
+	}
+	if spans[1].ParentSpanID != "" || spans[1].Status != nil {
+		t.Errorf("Want root span without parent or error status")
+	}
+	if got, want := len(spans[0].TraceID), 32; got != want {
+		t.Errorf("Want hex encoded trace id")
+	}
+}
+
+func TestOTLP_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(500)
+	}))
+	defer server.Close()
+
+	exporter := newOTLP(server.URL+"/v1/traces", "drone-server", nil)
+	if err := exporter.send(noContext, nil); err == nil {
+		t.Errorf("Expect error when the collector returns a non-2xx status")
+	}
+}
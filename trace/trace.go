@@ -0,0 +1,227 @@
+// Copyright 2019 Drone IO, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trace provides lightweight distributed tracing. Spans
+// are propagated across process boundaries using the W3C trace
+// context format, and exported to an OpenTelemetry collector.
+// Tracing is disabled by default.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// header is the W3C trace context http header.
+const header = "traceparent"
+
+type spanKey struct{}
+
+var (
+	mu       sync.RWMutex
+	exporter Exporter
+)
+
+// Exporter exports completed spans.
+type Exporter interface {
+	Export(*Span)
+}
+
+// SetExporter sets the exporter used to export completed
+// spans. Tracing is disabled if the exporter is nil.
+func SetExporter(e Exporter) {
+	mu.Lock()
+	exporter = e
+	mu.Unlock()
+}
+
+func getExporter() Exporter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return exporter
+}
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+// IsValid returns true if the trace and span identifiers
+// are both non-zero.
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != [16]byte{} && c.SpanID != [8]byte{}
+}
+
+// String returns the span context encoded in the W3C trace
+// context traceparent format.
+func (c SpanContext) String() string {
+	return fmt.Sprintf("00-%x-%x-01", c.TraceID, c.SpanID)
+}
+
+// Span represents a single timed operation within a trace.
+type Span struct {
+	sync.Mutex
+
+	Context    SpanContext
+	ParentID   [8]byte
+	Name       string
+	Started    time.Time
+	Stopped    time.Time
+	Attributes map[string]string
+	Error      string
+
+	exporter Exporter
+}
+
+// SetAttribute sets the span attribute.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	s.Attributes[key] = value
+	s.Unlock()
+}
+
+// SetError records the error on the span. A nil error is
+// ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Lock()
+	s.Error = err.Error()
+	s.Unlock()
+}
+
+// End completes the span and exports it.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	if s.Stopped.IsZero() {
+		s.Stopped = time.Now()
+	}
+	s.Unlock()
+	s.exporter.Export(s)
+}
+
+// Start starts a new span, as a child of the span in the
+// context, if any. If tracing is disabled, the context is
+// returned unmodified and the span is nil. It is safe to
+// invoke methods on a nil span.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	e := getExporter()
+	if e == nil {
+		return ctx, nil
+	}
+	span := &Span{
+		Name:       name,
+		Started:    time.Now(),
+		Attributes: map[string]string{},
+		exporter:   e,
+	}
+	parent := fromContext(ctx)
+	if parent.IsValid() {
+		span.Context.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		rand.Read(span.Context.TraceID[:])
+	}
+	rand.Read(span.Context.SpanID[:])
+	return context.WithValue(ctx, spanKey{}, span.Context), span
+}
+
+// Record records a completed span, as a child of the span in
+// the context, with the provided start and stop time.
+func Record(ctx context.Context, name string, started, stopped time.Time) {
+	_, span := Start(ctx, name)
+	if span == nil {
+		return
+	}
+	span.Started = started
+	span.Stopped = stopped
+	span.End()
+}
+
+// Encode returns the span context in the context encoded in
+// the W3C trace context traceparent format, or an empty
+// string if the context is not traced.
+func Encode(ctx context.Context) string {
+	if c := fromContext(ctx); c.IsValid() {
+		return c.String()
+	}
+	return ""
+}
+
+// Decode returns a new context with the span context decoded
+// from the W3C trace context traceparent format. If the value
+// cannot be decoded the context is returned unmodified.
+func Decode(ctx context.Context, value string) context.Context {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ctx
+	}
+	var c SpanContext
+	if _, err := hex.Decode(c.TraceID[:], []byte(parts[1])); err != nil {
+		return ctx
+	}
+	if _, err := hex.Decode(c.SpanID[:], []byte(parts[2])); err != nil {
+		return ctx
+	}
+	if !c.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey{}, c)
+}
+
+// Detach returns a new background context that carries the
+// span context, but not the deadline or cancellation, of the
+// parent context.
+func Detach(ctx context.Context) context.Context {
+	if c := fromContext(ctx); c.IsValid() {
+		return context.WithValue(context.Background(), spanKey{}, c)
+	}
+	return context.Background()
+}
+
+// Inject writes the span context in the context to the http
+// request headers.
+func Inject(ctx context.Context, h http.Header) {
+	if value := Encode(ctx); value != "" {
+		h.Set(header, value)
+	}
+}
+
+// Extract returns the request context with the span context
+// read from the http request headers. The boolean value is
+// false if the request headers do not include a valid span
+// context.
+func Extract(r *http.Request) (context.Context, bool) {
+	ctx := Decode(r.Context(), r.Header.Get(header))
+	return ctx, fromContext(ctx).IsValid()
+}
+
+func fromContext(ctx context.Context) SpanContext {
+	c, _ := ctx.Value(spanKey{}).(SpanContext)
+	return c
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Drone.IO Inc. All rights reserved.
+// Use of this source code is governed by the Drone Non-Commercial License
+// that can be found in the LICENSE file.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var noContext = context.Background()
+
+type recorder struct {
+	spans []*Span
+}
+
+func (r *recorder) Export(span *Span) {
+	r.spans = append(r.spans, span)
+}
+
+func TestStart(t *testing.T) {
+	rec := new(recorder)
+	SetExporter(rec)
+	defer SetExporter(nil)
+
+	ctx, parent := Start(noContext, "hook")
+	_, child := Start(ctx, "trigger")
+	child.SetAttribute("repo", "octocat/hello-world")
+	child.SetError(errors.New("not found"))
+	child.End()
+	parent.End()
+
+	if got, want := len(rec.spans), 2; got != want {
+		t.Errorf("Want %d exported spans, got %d", want, got)
+		return
+	}
+	if got, want := child.Context.TraceID, parent.Context.TraceID; got != want {
+		t.Errorf("Want child span in the parent trace")
+	}
+	if got, want := child.ParentID, parent.Context.SpanID; got != want {
+		t.Errorf("Want child span parent id %x, got %x", want, got)
+	}
+	if parent.ParentID != [8]byte{} {
+		t.Errorf("Want root span without a parent id")
+	}
+	if got, want := child.Attributes["repo"], "octocat/hello-world"; got != want {
+		t.Errorf("Want attribute %s, got %s", want, got)
+	}
+	if got, want := child.Error, "not found"; got != want {
+		t.Errorf("Want error %s, got %s", want, got)
+	}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx, span := Start(noContext, "hook")
+	if span != nil {
+		t.Errorf("Want nil span when tracing is disabled")
+	}
+	if ctx != noContext {
+		t.Errorf("Want unmodified context when tracing is disabled")
+	}
+	// methods are safe to invoke on a nil span.
+	span.SetAttribute("repo", "octocat/hello-world")
+	span.SetError(errors.New("not found"))
+	span.End()
+}
+
+func TestRecord(t *testing.T) {
+	rec := new(recorder)
+	SetExporter(rec)
+	defer SetExporter(nil)
+
+	started := time.Unix(1556000000, 0)
+	stopped := time.Unix(1556000030, 0)
+	Record(noContext, "queue", started, stopped)
+
+	if got, want := len(rec.spans), 1; got != want {
+		t.Errorf("Want %d exported spans, got %d", want, got)
+		return
+	}
+	if got := rec.spans[0].Stopped.Sub(rec.spans[0].Started); got != 30*time.Second {
+		t.Errorf("Want span duration 30s, got %s", got)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := Decode(noContext, value)
+	if got := Encode(ctx); got != value {
+		t.Errorf("Want encoded trace context %s, got %s", value, got)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if got := Encode(Decode(noContext, invalid)); got != "" {
+			t.Errorf("Want invalid trace context %q ignored, got %s", invalid, got)
+		}
+	}
+}
+
+func TestDetach(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx, cancel := context.WithCancel(Decode(noContext, value))
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Errorf("Want detached context without cancellation")
+	}
+	if got := Encode(detached); got != value {
+		t.Errorf("Want detached context with trace context %s, got %s", value, got)
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	value := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	r := httptest.NewRequest("POST", "/rpc/v1/before", nil)
+	Inject(Decode(noContext, value), r.Header)
+
+	if got := r.Header.Get("traceparent"); got != value {
+		t.Errorf("Want traceparent header %s, got %s", value, got)
+	}
+
+	ctx, ok := Extract(r)
+	if !ok {
+		t.Errorf("Want trace context extracted from request")
+	}
+	if got := Encode(ctx); got != value {
+		t.Errorf("Want extracted trace context %s, got %s", value, got)
+	}
+
+	_, ok = Extract(httptest.NewRequest("POST", "/rpc/v1/request", nil))
+	if ok {
+		t.Errorf("Want no trace context for request without header")
+	}
+
+	h := http.Header{}
+	Inject(noContext, h)
+	if len(h) != 0 {
+		t.Errorf("Want no header injected for untraced context")
+	}
+}
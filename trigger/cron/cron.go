@@ -8,7 +8,6 @@ package cron
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/drone/drone/core"
@@ -142,22 +141,7 @@ func (s *Scheduler) run(ctx context.Context) error {
 			continue
 		}
 
-		hook := &core.Hook{
-			Trigger:      core.TriggerCron,
-			Event:        core.EventPush,
-			Link:         commit.Link,
-			Timestamp:    commit.Author.Date,
-			Message:      commit.Message,
-			After:        commit.Sha,
-			Ref:          fmt.Sprintf("refs/heads/%s", job.Branch),
-			Target:       job.Branch,
-			Author:       commit.Author.Login,
-			AuthorName:   commit.Author.Name,
-			AuthorEmail:  commit.Author.Email,
-			AuthorAvatar: commit.Author.Avatar,
-			Cron:         job.Name,
-			Sender:       commit.Author.Login,
-		}
+		hook := job.Hook(commit)
 
 		_, err = s.trigger.Trigger(ctx, repo, hook)
 		if err != nil {
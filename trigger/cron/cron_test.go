@@ -420,6 +420,8 @@ var (
 		Next:   2000000000,
 		Prev:   1000000000,
 		Branch: "master",
+		Target: "production",
+		Params: map[string]string{"nightly": "true"},
 	}
 
 	dummyCronInvalid = &core.Cron{
@@ -460,8 +462,10 @@ var (
 		AuthorEmail:  "octocat@hello-world.com",
 		AuthorAvatar: "https://avatars3.githubusercontent.com/u/583231",
 		Sender:       "octocat",
+		Deployment:   "production",
 		Cron:         "nightly",
 		Trigger:      "@cron",
+		Params:       map[string]string{"nightly": "true"},
 	}
 
 	dummyCommit = &core.Commit{
@@ -26,6 +26,7 @@ import (
 	"github.com/drone/drone-yaml/yaml/signer"
 
 	"github.com/drone/drone/core"
+	"github.com/drone/drone/trace"
 	"github.com/drone/drone/trigger/dag"
 
 	"github.com/sirupsen/logrus"
@@ -76,6 +77,12 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	)
 
 	logger.Debugln("trigger: received")
+
+	ctx, span := trace.Start(ctx, "trigger")
+	span.SetAttribute("repo", repo.Slug)
+	span.SetAttribute("event", base.Event)
+	defer span.End()
+
 	defer func() {
 		// taking the paranoid approach to recover from
 		// a panic that should absolutely never happen.
@@ -85,18 +92,214 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		}
 	}()
 
+	result, err := t.evaluate(ctx, logger, repo, base)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	if result.skip != "" {
+		span.SetAttribute("skip", result.skip)
+		return nil, nil
+	}
+	if result.error != "" {
+		return t.createBuildError(ctx, repo, base, result.error)
+	}
+	if len(result.matched) == 0 {
+		logger.Infoln("trigger: skipping build, no matching pipelines")
+		return nil, nil
+	}
+
+	user := result.user
+	repo, err = t.repos.Increment(ctx, repo)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Errorln("trigger: cannot increment build sequence")
+		span.SetError(err)
+		return nil, err
+	}
+
+	build := &core.Build{
+		RepoID:  repo.ID,
+		Trigger: base.Trigger,
+		Number:  repo.Counter,
+		Parent:  base.Parent,
+		Status:  core.StatusPending,
+		Event:   base.Event,
+		Action:  base.Action,
+		Link:    base.Link,
+		// Timestamp:    base.Timestamp,
+		Title:        trunc(base.Title, 2000),
+		Message:      trunc(base.Message, 2000),
+		Before:       base.Before,
+		After:        base.After,
+		Ref:          base.Ref,
+		Fork:         base.Fork,
+		Source:       base.Source,
+		Target:       base.Target,
+		Author:       base.Author,
+		AuthorName:   base.AuthorName,
+		AuthorEmail:  base.AuthorEmail,
+		AuthorAvatar: base.AuthorAvatar,
+		Params:       base.Params,
+		Deploy:       base.Deployment,
+		Sender:       base.Sender,
+		Created:      time.Now().Unix(),
+		Updated:      time.Now().Unix(),
+	}
+
+	// the trace context is stored with the build so that the
+	// runner can continue the trace.
+	build.Trace = trace.Encode(ctx)
+
+	stages := createStages(repo, result)
+
+	err = t.builds.Create(ctx, build, stages)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Errorln("trigger: cannot create build")
+		span.SetError(err)
+		return nil, err
+	}
+
+	statusCtx, statusSpan := trace.Start(ctx, "trigger.status")
+	err = t.status.Send(statusCtx, user, &core.StatusInput{
+		Repo:  repo,
+		Build: build,
+	})
+	statusSpan.SetError(err)
+	statusSpan.End()
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("trigger: cannot create status")
+	}
+
+	for _, stage := range stages {
+		if stage.Status != core.StatusPending {
+			continue
+		}
+		err = t.sched.Schedule(ctx, stage)
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Errorln("trigger: cannot enqueue build")
+			span.SetError(err)
+			return nil, err
+		}
+	}
+
+	payload := &core.WebhookData{
+		Event:  core.WebhookEventBuild,
+		Action: core.WebhookActionCreated,
+		User:   user,
+		Repo:   repo,
+		Build:  build,
+	}
+	err = t.hooks.Send(ctx, payload)
+	if err != nil {
+		logger = logger.WithError(err)
+		logger.Warnln("trigger: cannot send webhook")
+	}
+
+	// stages that require approval are created in a blocked
+	// state, and a stage webhook is sent for each blocked
+	// stage so that approvers can be notified.
+	for _, stage := range stages {
+		if stage.Status != core.StatusBlocked {
+			continue
+		}
+		err = t.hooks.Send(ctx, &core.WebhookData{
+			Event:  core.WebhookEventStage,
+			Action: core.WebhookActionBlocked,
+			User:   user,
+			Repo:   repo,
+			Build:  build,
+			Stage:  stage,
+		})
+		if err != nil {
+			logger = logger.WithError(err)
+			logger.Warnln("trigger: cannot send stage webhook")
+		}
+	}
+	// err = t.hooks.SendEndpoint(ctx, payload, repo.Endpoints.Webhook)
+	// if err != nil {
+	// 	logger.Warn().Err(err).
+	// 		Int64("build", build.Number).
+	// 		Msg("cannot send user-defined webhook")
+	// }
+
+	// // we should only synchronize the cronjob list on push
+	// // events to the default branch.
+	// if build.Event == core.EventPush &&
+	// 	build.Target == repo.Branch {
+	// 	err = t.cron.Sync(ctx, repo, manifest)
+	// 	if err != nil {
+	// 		logger.Warn().Err(err).
+	// 			Msg("cannot sync cronjobs")
+	// 	}
+	// }
+
+	return build, nil
+}
+
+// Evaluate evaluates the pipeline configuration for the hook
+// and returns the stages that would be created, without
+// creating the build or incrementing the build sequence.
+func (t *triggerer) Evaluate(ctx context.Context, repo *core.Repository, base *core.Hook) (*core.Evaluation, error) {
+	logger := logrus.WithFields(
+		logrus.Fields{
+			"repo":   repo.Slug,
+			"ref":    base.Ref,
+			"event":  base.Event,
+			"commit": base.After,
+			"dryrun": true,
+		},
+	)
+
+	result, err := t.evaluate(ctx, logger, repo, base)
+	if err != nil {
+		return nil, err
+	}
+	out := &core.Evaluation{
+		Skip:    result.skip,
+		Error:   result.error,
+		Stages:  []*core.Stage{},
+		Skipped: result.skipped,
+	}
+	if result.skip == "" && result.error == "" {
+		out.Stages = createStages(repo, result)
+	}
+	return out, nil
+}
+
+// evaluation stores the result of evaluating the pipeline
+// configuration for a hook.
+type evaluation struct {
+	user     *core.User
+	skip     string
+	error    string
+	matched  []*yaml.Pipeline
+	skipped  []*core.PipelineSkip
+	dag      *dag.Dag
+	verified bool
+}
+
+// helper function fetches, converts, lints and evaluates the
+// pipeline configuration for the hook. It returns the matching
+// pipelines and the reason each remaining pipeline is skipped.
+// The evaluation is free of side-effects, with the exception
+// of calls to the remote version control system.
+func (t *triggerer) evaluate(ctx context.Context, logger *logrus.Entry, repo *core.Repository, base *core.Hook) (*evaluation, error) {
 	if skipMessage(base) {
 		logger.Infoln("trigger: skipping hook. found skip directive")
-		return nil, nil
+		return &evaluation{skip: "found skip directive"}, nil
 	}
 	if base.Event == core.EventPullRequest {
 		if repo.IgnorePulls {
 			logger.Infoln("trigger: skipping hook. project ignores pull requests")
-			return nil, nil
+			return &evaluation{skip: "project ignores pull requests"}, nil
 		}
 		if repo.IgnoreForks && !strings.EqualFold(base.Fork, repo.Slug) {
 			logger.Infoln("trigger: skipping hook. project ignores forks")
-			return nil, nil
+			return &evaluation{skip: "project ignores forks"}, nil
 		}
 	}
 
@@ -109,14 +312,17 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 
 	if user.Active == false {
 		logger.Infoln("trigger: skipping hook. repository owner is inactive")
-		return nil, nil
+		return &evaluation{skip: "repository owner is inactive"}, nil
 	}
 
 	// if the commit message is not included we should
 	// make an optional API call to the version control
 	// system to augment the available information.
 	if base.Message == "" && base.After != "" {
-		commit, err := t.commits.Find(ctx, user, repo.Slug, base.After)
+		commitCtx, commitSpan := trace.Start(ctx, "trigger.commit")
+		commit, err := t.commits.Find(commitCtx, user, repo.Slug, base.After)
+		commitSpan.SetError(err)
+		commitSpan.End()
 		if err == nil && commit != nil {
 			base.Message = commit.Message
 			if base.AuthorEmail == "" {
@@ -187,7 +393,10 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		},
 	}
 
-	raw, err := t.config.Find(ctx, req)
+	configCtx, configSpan := trace.Start(ctx, "trigger.config")
+	raw, err := t.config.Find(configCtx, req)
+	configSpan.SetError(err)
+	configSpan.End()
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("trigger: cannot find yaml")
@@ -196,10 +405,13 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 
 	// this code is temporarily in place to detect and convert
 	// the legacy yaml configuration file to the new format.
+	_, convertSpan := trace.Start(ctx, "trigger.convert")
 	raw.Data, err = converter.ConvertString(raw.Data, converter.Metadata{
 		Filename: repo.Config,
 		Ref:      base.Ref,
 	})
+	convertSpan.SetError(err)
+	convertSpan.End()
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("trigger: cannot convert yaml")
@@ -210,14 +422,14 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("trigger: cannot parse yaml")
-		return t.createBuildError(ctx, repo, base, err.Error())
+		return &evaluation{user: user, error: err.Error()}, nil
 	}
 
 	err = linter.Manifest(manifest, repo.Trusted)
 	if err != nil {
 		logger = logger.WithError(err)
 		logger.Warnln("trigger: yaml linting error")
-		return t.createBuildError(ctx, repo, base, err.Error())
+		return &evaluation{user: user, error: err.Error()}, nil
 	}
 
 	verified := true
@@ -234,8 +446,12 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 	// 		Msg("cannot fetch changeset")
 	// }
 
-	var matched []*yaml.Pipeline
-	var dag = dag.New()
+	result := &evaluation{
+		user:     user,
+		dag:      dag.New(),
+		verified: verified,
+		skipped:  []*core.PipelineSkip{},
+	}
 	for _, document := range manifest.Resources {
 		pipeline, ok := document.(*yaml.Pipeline)
 		if !ok {
@@ -249,77 +465,48 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		if name == "" {
 			name = "default"
 		}
-		node := dag.Add(pipeline.Name, pipeline.DependsOn...)
+		node := result.dag.Add(pipeline.Name, pipeline.DependsOn...)
 		node.Skip = true
 
+		var reason string
 		if skipBranch(pipeline, base.Target) {
-			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match branch")
+			reason = "does not match branch"
 		} else if skipEvent(pipeline, base.Event) {
-			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match event")
+			reason = "does not match event"
 		} else if skipRef(pipeline, base.Ref) {
-			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match ref")
+			reason = "does not match ref"
 		} else if skipRepo(pipeline, repo.Slug) {
-			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match repo")
+			reason = "does not match repo"
 		} else if skipTarget(pipeline, base.Deployment) {
-			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match deploy target")
+			reason = "does not match deploy target"
 		} else if skipCron(pipeline, base.Cron) {
+			reason = "does not match cron job"
+		}
+
+		if reason != "" {
 			logger = logger.WithField("pipeline", pipeline.Name)
-			logger.Infoln("trigger: skipping pipeline, does not match cron job")
+			logger.Infoln("trigger: skipping pipeline, " + reason)
+			result.skipped = append(result.skipped, &core.PipelineSkip{
+				Name:   name,
+				Reason: reason,
+			})
 		} else {
-			matched = append(matched, pipeline)
+			result.matched = append(result.matched, pipeline)
 			node.Skip = false
 		}
 	}
 
-	if dag.DetectCycles() {
-		return t.createBuildError(ctx, repo, base, "Error: Dependency cycle detected in Pipeline")
-	}
-
-	if len(matched) == 0 {
-		logger.Infoln("trigger: skipping build, no matching pipelines")
-		return nil, nil
+	if result.dag.DetectCycles() {
+		result.error = "Error: Dependency cycle detected in Pipeline"
 	}
+	return result, nil
+}
 
-	repo, err = t.repos.Increment(ctx, repo)
-	if err != nil {
-		logger = logger.WithError(err)
-		logger.Errorln("trigger: cannot increment build sequence")
-		return nil, err
-	}
-
-	build := &core.Build{
-		RepoID:  repo.ID,
-		Trigger: base.Trigger,
-		Number:  repo.Counter,
-		Parent:  base.Parent,
-		Status:  core.StatusPending,
-		Event:   base.Event,
-		Action:  base.Action,
-		Link:    base.Link,
-		// Timestamp:    base.Timestamp,
-		Title:        trunc(base.Title, 2000),
-		Message:      trunc(base.Message, 2000),
-		Before:       base.Before,
-		After:        base.After,
-		Ref:          base.Ref,
-		Fork:         base.Fork,
-		Source:       base.Source,
-		Target:       base.Target,
-		Author:       base.Author,
-		AuthorName:   base.AuthorName,
-		AuthorEmail:  base.AuthorEmail,
-		AuthorAvatar: base.AuthorAvatar,
-		Params:       base.Params,
-		Deploy:       base.Deployment,
-		Sender:       base.Sender,
-		Created:      time.Now().Unix(),
-		Updated:      time.Now().Unix(),
-	}
+// helper function creates the build stages for the matching
+// pipelines in the evaluation.
+func createStages(repo *core.Repository, result *evaluation) []*core.Stage {
+	matched := result.matched
+	verified := result.verified
 
 	stages := make([]*core.Stage, len(matched))
 	for i, match := range matched {
@@ -333,8 +520,8 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			RepoID:    repo.ID,
 			Number:    i + 1,
 			Name:      match.Name,
-			Kind:      "",
-			Type:      "",
+			Kind:      match.Kind,
+			Type:      match.Type,
 			OS:        match.Platform.OS,
 			Arch:      match.Platform.Arch,
 			Variant:   match.Platform.Variant,
@@ -349,6 +536,12 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 			Updated:   time.Now().Unix(),
 		}
 
+		if stage.Kind == "" {
+			stage.Kind = "pipeline"
+		}
+		if stage.Type == "" {
+			stage.Type = "docker"
+		}
 		if stage.OS == "" {
 			stage.OS = "linux"
 		}
@@ -371,7 +564,7 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		// here we re-work the dependencies for the stage to
 		// account for the fact that some steps may be skipped
 		// and may otherwise break the dependnecy chain.
-		stage.DependsOn = dag.Dependencies(stage.Name)
+		stage.DependsOn = result.dag.Dependencies(stage.Name)
 
 		// if the stage is pending dependencies, but those
 		// dependencies are skipped, the stage can be executed
@@ -382,65 +575,7 @@ func (t *triggerer) Trigger(ctx context.Context, repo *core.Repository, base *co
 		}
 	}
 
-	err = t.builds.Create(ctx, build, stages)
-	if err != nil {
-		logger = logger.WithError(err)
-		logger.Errorln("trigger: cannot create build")
-		return nil, err
-	}
-
-	err = t.status.Send(ctx, user, &core.StatusInput{
-		Repo:  repo,
-		Build: build,
-	})
-	if err != nil {
-		logger = logger.WithError(err)
-		logger.Warnln("trigger: cannot create status")
-	}
-
-	for _, stage := range stages {
-		if stage.Status != core.StatusPending {
-			continue
-		}
-		err = t.sched.Schedule(ctx, stage)
-		if err != nil {
-			logger = logger.WithError(err)
-			logger.Errorln("trigger: cannot enqueue build")
-			return nil, err
-		}
-	}
-
-	payload := &core.WebhookData{
-		Event:  core.WebhookEventBuild,
-		Action: core.WebhookActionCreated,
-		User:   user,
-		Repo:   repo,
-		Build:  build,
-	}
-	err = t.hooks.Send(ctx, payload)
-	if err != nil {
-		logger = logger.WithError(err)
-		logger.Warnln("trigger: cannot send webhook")
-	}
-	// err = t.hooks.SendEndpoint(ctx, payload, repo.Endpoints.Webhook)
-	// if err != nil {
-	// 	logger.Warn().Err(err).
-	// 		Int64("build", build.Number).
-	// 		Msg("cannot send user-defined webhook")
-	// }
-
-	// // we should only synchronize the cronjob list on push
-	// // events to the default branch.
-	// if build.Event == core.EventPush &&
-	// 	build.Target == repo.Branch {
-	// 	err = t.cron.Sync(ctx, repo, manifest)
-	// 	if err != nil {
-	// 		logger.Warn().Err(err).
-	// 			Msg("cannot sync cronjobs")
-	// 	}
-	// }
-
-	return build, nil
+	return stages
 }
 
 func trunc(s string, i int) string {
@@ -93,6 +93,73 @@ func TestTrigger(t *testing.T) {
 	}
 }
 
+// this test verifies that stages are blocked when the yaml
+// of a protected repository is not signed, and that a stage
+// webhook is sent for each blocked stage.
+func TestTrigger_Blocked(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	protectedRepo := *dummyRepo
+	protectedRepo.Protected = true
+
+	protectedHook := *dummyHook
+	protectedHook.Trigger = core.TriggerHook
+
+	checkStages := func(_ context.Context, _ *core.Build, stages []*core.Stage) {
+		for _, stage := range stages {
+			if got, want := stage.Status, core.StatusBlocked; got != want {
+				t.Errorf("Want stage status %s, got %s", want, got)
+			}
+		}
+	}
+
+	var actions []string
+	checkWebhook := func(_ context.Context, in *core.WebhookData) {
+		actions = append(actions, in.Event+":"+in.Action)
+	}
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockRepos := mock.NewMockRepositoryStore(controller)
+	mockRepos.EXPECT().Increment(gomock.Any(), &protectedRepo).Return(&protectedRepo, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	mockStatus := mock.NewMockStatusService(controller)
+	mockStatus.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	mockBuilds := mock.NewMockBuildStore(controller)
+	mockBuilds.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Do(checkStages).Return(nil)
+
+	mockWebhooks := mock.NewMockWebhookSender(controller)
+	mockWebhooks.EXPECT().Send(gomock.Any(), gomock.Any()).Do(checkWebhook).Return(nil).Times(2)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		mockStatus,
+		mockBuilds,
+		nil,
+		mockRepos,
+		mockUsers,
+		mockWebhooks,
+	)
+
+	_, err := triggerer.Trigger(noContext, &protectedRepo, &protectedHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := []string{"build:created", "stage:blocked"}
+	if diff := cmp.Diff(actions, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
 // this test verifies that hook is ignored if the commit
 // message includes the [CI SKIP] keyword.
 func TestTrigger_SkipCI(t *testing.T) {
@@ -391,6 +458,140 @@ func TestTrigger_ErrorEnqueue(t *testing.T) {
 	// 	}
 }
 
+// this test verifies that a dry-run evaluation returns the
+// matching stages without incrementing the build sequence or
+// creating the build.
+func TestEvaluate(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYaml, nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	result, err := triggerer.Evaluate(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if diff := cmp.Diff(result.Stages, dummyStages, ignoreStageFields); diff != "" {
+		t.Errorf(diff)
+	}
+	if len(result.Skipped) != 0 {
+		t.Errorf("Want no skipped pipelines, got %d", len(result.Skipped))
+	}
+}
+
+// this test verifies that a dry-run evaluation returns the
+// reason a pipeline is skipped.
+func TestEvaluate_SkipBranch(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlSkipBranch, nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	result, err := triggerer.Evaluate(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := len(result.Stages), 0; got != want {
+		t.Errorf("Want %d stages, got %d", want, got)
+	}
+	want := []*core.PipelineSkip{
+		{Name: "default", Reason: "does not match branch"},
+	}
+	if diff := cmp.Diff(result.Skipped, want); diff != "" {
+		t.Errorf(diff)
+	}
+}
+
+// this test verifies that a dry-run evaluation returns yaml
+// errors instead of creating a failed build.
+func TestEvaluate_ErrorYaml(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockUsers := mock.NewMockUserStore(controller)
+	mockUsers.EXPECT().Find(gomock.Any(), dummyRepo.UserID).Return(dummyUser, nil)
+
+	mockConfigService := mock.NewMockConfigService(controller)
+	mockConfigService.EXPECT().Find(gomock.Any(), gomock.Any()).Return(dummyYamlInvalid, nil)
+
+	triggerer := New(
+		mockConfigService,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		mockUsers,
+		nil,
+	)
+
+	result, err := triggerer.Evaluate(noContext, dummyRepo, dummyHook)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result.Error == "" {
+		t.Errorf("Want yaml error returned")
+	}
+}
+
+// this test verifies that a dry-run evaluation reports the
+// skip directive in the commit message.
+func TestEvaluate_SkipCI(t *testing.T) {
+	triggerer := New(
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	dummyHookSkip := *dummyHook
+	dummyHookSkip.Message = "foo [CI SKIP] bar"
+	result, err := triggerer.Evaluate(noContext, dummyRepo, &dummyHookSkip)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got, want := result.Skip, "found skip directive"; got != want {
+		t.Errorf("Want skip reason %q, got %q", want, got)
+	}
+}
+
 var (
 	dummyHook = &core.Hook{
 		Event:        core.EventPush,
@@ -454,6 +655,8 @@ var (
 		RepoID:    1,
 		Name:      "default",
 		Number:    1,
+		Kind:      "pipeline",
+		Type:      "docker",
 		OS:        "linux",
 		Arch:      "amd64",
 		OnSuccess: true,
@@ -480,11 +683,11 @@ var (
 	}
 
 	dummyYamlSkipBranch = &core.Config{
-		Data: "kind: pipeline\ntrigger: { branch: { exclude: master } }",
+		Data: "kind: pipeline\ntrigger: { branch: { exclude: [ master ] } }",
 	}
 
 	dummyYamlSkipEvent = &core.Config{
-		Data: "kind: pipeline\ntrigger: { event: { exclude: push } }",
+		Data: "kind: pipeline\ntrigger: { event: { exclude: [ push ] } }",
 	}
 
 	ignoreBuildFields = cmpopts.IgnoreFields(core.Build{},